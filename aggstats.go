@@ -0,0 +1,199 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrStatsUnavailable is returned by AggregateResult.Stats when the result was not created
+// by a Collection aggregation, so there is no pipeline to explain
+var ErrStatsUnavailable = errors.New("aggregate result has no pipeline to explain")
+
+// AggregateStats summarizes the execution of an aggregation as reported by explain
+type AggregateStats struct {
+	// ExecutionTime is the server-side execution time of the whole pipeline
+	ExecutionTime time.Duration `json:"execution_time"`
+	// DocsExamined is the number of documents read from the collection
+	DocsExamined int64 `json:"docs_examined"`
+	// KeysExamined is the number of index keys scanned
+	KeysExamined int64 `json:"keys_examined"`
+	// UsedDisk reports whether any stage spilled to disk (see AllowDiskUse)
+	UsedDisk bool `json:"used_disk"`
+	// Explain is the complete explain output
+	Explain bson.Raw `json:"-"`
+}
+
+// Stats runs explain with executionStats verbosity on the pipeline of this result, using the
+// same allowDiskUse, let, hint and collation options, and returns its execution statistics.
+// Explain executes the pipeline again without returning documents, so call it when tuning a
+// heavy aggregation rather than on every request.
+//
+// Example:
+//
+//	result, err := col.AggregateWithOptions(ctx, p, &mongodb.AggregateOptions{AllowDiskUse: true})
+//	...
+//	stats, err := result.Stats(ctx)
+//	if err == nil && stats.UsedDisk {
+//	    log.Printf("report pipeline spilled to disk after %s", stats.ExecutionTime)
+//	}
+func (r *AggregateResult) Stats(ctx context.Context) (*AggregateStats, error) {
+	if r.col == nil {
+		return nil, ErrStatsUnavailable
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	cmd, err := explainAggregateCommand(r.col.name, r.pipeline, r.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := r.col.collection.Database().RunCommand(ctx, cmd).Raw()
+	if err != nil {
+		r.col.logger(ctx).Error("Failed to explain aggregation",
+			"error", err.Error(),
+			"collection", r.col.name)
+		return nil, fmt.Errorf("failed to explain aggregation: %w", err)
+	}
+
+	return decodeAggregateStats(raw), nil
+}
+
+// explainAggregateCommand builds the explain command for an aggregation, carrying over the
+// options that affect how the pipeline is planned and executed
+func explainAggregateCommand(collection string, pipelineDoc bson.A, opts []options.Lister[options.AggregateOptions]) (bson.D, error) {
+	resolved := &options.AggregateOptions{}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		for _, apply := range opt.List() {
+			if err := apply(resolved); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	aggregate := bson.D{
+		{Key: "aggregate", Value: collection},
+		{Key: "pipeline", Value: pipelineDoc},
+		{Key: "cursor", Value: bson.D{}},
+	}
+	if resolved.AllowDiskUse != nil {
+		aggregate = append(aggregate, bson.E{Key: "allowDiskUse", Value: *resolved.AllowDiskUse})
+	}
+	if resolved.Let != nil {
+		aggregate = append(aggregate, bson.E{Key: "let", Value: resolved.Let})
+	}
+	if resolved.Hint != nil {
+		aggregate = append(aggregate, bson.E{Key: "hint", Value: resolved.Hint})
+	}
+	if resolved.Collation != nil {
+		aggregate = append(aggregate, bson.E{Key: "collation", Value: collationDocument(resolved.Collation)})
+	}
+
+	return bson.D{
+		{Key: "explain", Value: aggregate},
+		{Key: "verbosity", Value: "executionStats"},
+	}, nil
+}
+
+// collationDocument converts driver collation options to the server document form
+func collationDocument(c *options.Collation) bson.D {
+	doc := bson.D{{Key: "locale", Value: c.Locale}}
+	if c.CaseLevel {
+		doc = append(doc, bson.E{Key: "caseLevel", Value: true})
+	}
+	if c.CaseFirst != "" {
+		doc = append(doc, bson.E{Key: "caseFirst", Value: c.CaseFirst})
+	}
+	if c.Strength != 0 {
+		doc = append(doc, bson.E{Key: "strength", Value: c.Strength})
+	}
+	if c.NumericOrdering {
+		doc = append(doc, bson.E{Key: "numericOrdering", Value: true})
+	}
+	if c.Alternate != "" {
+		doc = append(doc, bson.E{Key: "alternate", Value: c.Alternate})
+	}
+	if c.MaxVariable != "" {
+		doc = append(doc, bson.E{Key: "maxVariable", Value: c.MaxVariable})
+	}
+	if c.Normalization {
+		doc = append(doc, bson.E{Key: "normalization", Value: true})
+	}
+	if c.Backwards {
+		doc = append(doc, bson.E{Key: "backwards", Value: true})
+	}
+	return doc
+}
+
+// decodeAggregateStats extracts execution statistics from an aggregate explain document.
+// The layout differs between pipelines pushed down to the query layer (top-level
+// executionStats), pipelines with a $cursor stage followed by further stages, and sharded
+// clusters (per-shard explain output), so the whole document is walked: document and key
+// counts are summed over every executionStats section, the execution time is the largest
+// reported time (stage estimates are cumulative), and any stage reporting usedDisk or spills
+// marks the aggregation as having used disk.
+func decodeAggregateStats(explain bson.Raw) *AggregateStats {
+	stats := &AggregateStats{Explain: explain}
+	var maxMillis int64
+
+	var walk func(doc bson.Raw)
+	walk = func(doc bson.Raw) {
+		elements, err := doc.Elements()
+		if err != nil {
+			return
+		}
+		for _, element := range elements {
+			value := element.Value()
+			switch element.Key() {
+			case "executionStats":
+				if section, ok := value.DocumentOK(); ok {
+					stats.DocsExamined += rawInt(section.Lookup("totalDocsExamined"))
+					stats.KeysExamined += rawInt(section.Lookup("totalKeysExamined"))
+				}
+			case "executionTimeMillis", "executionTimeMillisEstimate":
+				maxMillis = max(maxMillis, rawInt(value))
+			case "usedDisk":
+				if used, ok := value.BooleanOK(); ok && used {
+					stats.UsedDisk = true
+				}
+			case "spills":
+				if rawInt(value) > 0 {
+					stats.UsedDisk = true
+				}
+			}
+
+			if nested, ok := value.DocumentOK(); ok {
+				walk(nested)
+			} else if array, ok := value.ArrayOK(); ok {
+				walk(bson.Raw(array))
+			}
+		}
+	}
+	walk(explain)
+
+	stats.ExecutionTime = time.Duration(maxMillis) * time.Millisecond
+	return stats
+}
+
+// rawInt returns a numeric BSON value as int64, or 0 if it is missing or not a number
+func rawInt(value bson.RawValue) int64 {
+	if n, ok := value.AsInt64OK(); ok {
+		return n
+	}
+	if f, ok := value.DoubleOK(); ok {
+		return int64(f)
+	}
+	return 0
+}
@@ -0,0 +1,161 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestDecodeAggregateStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		explain  bson.M
+		expected AggregateStats
+	}{
+		{
+			name: "Pipeline pushed down to the query layer",
+			explain: bson.M{
+				"queryPlanner": bson.M{"winningPlan": bson.M{"stage": "COLLSCAN"}},
+				"executionStats": bson.M{
+					"nReturned":           int32(12),
+					"executionTimeMillis": int32(35),
+					"totalKeysExamined":   int32(0),
+					"totalDocsExamined":   int32(5000),
+				},
+				"ok": 1.0,
+			},
+			expected: AggregateStats{ExecutionTime: 35 * time.Millisecond, DocsExamined: 5000},
+		},
+		{
+			name: "Group stage spilling to disk",
+			explain: bson.M{
+				"stages": bson.A{
+					bson.M{
+						"$cursor": bson.M{
+							"queryPlanner": bson.M{"winningPlan": bson.M{"stage": "IXSCAN"}},
+							"executionStats": bson.M{
+								"executionTimeMillis": int32(120),
+								"totalKeysExamined":   int32(80000),
+								"totalDocsExamined":   int32(80000),
+							},
+						},
+						"executionTimeMillisEstimate": int64(118),
+					},
+					bson.M{
+						"$group":                      bson.M{"_id": "$customer_id"},
+						"usedDisk":                    true,
+						"spills":                      int64(3),
+						"executionTimeMillisEstimate": int64(940),
+					},
+					bson.M{
+						"$sort":                       bson.M{"sortKey": bson.M{"total": -1}},
+						"usedDisk":                    false,
+						"executionTimeMillisEstimate": int64(1015),
+					},
+				},
+				"ok": 1.0,
+			},
+			expected: AggregateStats{
+				ExecutionTime: 1015 * time.Millisecond,
+				DocsExamined:  80000,
+				KeysExamined:  80000,
+				UsedDisk:      true,
+			},
+		},
+		{
+			name: "Sharded cluster sums shards and detects spills",
+			explain: bson.M{
+				"shards": bson.M{
+					"rs0": bson.M{
+						"stages": bson.A{
+							bson.M{"$cursor": bson.M{"executionStats": bson.M{
+								"executionTimeMillis": int32(40),
+								"totalDocsExamined":   int32(300),
+								"totalKeysExamined":   int32(300),
+							}}},
+						},
+					},
+					"rs1": bson.M{
+						"executionStats": bson.M{
+							"executionTimeMillis": int32(55),
+							"totalDocsExamined":   int32(200),
+							"executionStages":     bson.M{"stage": "group", "spills": int64(1)},
+						},
+					},
+				},
+				"ok": 1.0,
+			},
+			expected: AggregateStats{
+				ExecutionTime: 55 * time.Millisecond,
+				DocsExamined:  500,
+				KeysExamined:  300,
+				UsedDisk:      true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := bson.Marshal(tt.explain)
+			if err != nil {
+				t.Fatalf("Failed to marshal explain fixture: %v", err)
+			}
+
+			stats := decodeAggregateStats(raw)
+			if stats.ExecutionTime != tt.expected.ExecutionTime {
+				t.Errorf("Expected execution time %s, got %s", tt.expected.ExecutionTime, stats.ExecutionTime)
+			}
+			if stats.DocsExamined != tt.expected.DocsExamined || stats.KeysExamined != tt.expected.KeysExamined {
+				t.Errorf("Expected %d docs / %d keys examined, got %d / %d",
+					tt.expected.DocsExamined, tt.expected.KeysExamined, stats.DocsExamined, stats.KeysExamined)
+			}
+			if stats.UsedDisk != tt.expected.UsedDisk {
+				t.Errorf("Expected used disk %v, got %v", tt.expected.UsedDisk, stats.UsedDisk)
+			}
+			if len(stats.Explain) == 0 {
+				t.Error("Expected the raw explain output to be kept")
+			}
+		})
+	}
+}
+
+func TestExplainAggregateCommand(t *testing.T) {
+	pipelineDoc := bson.A{bson.M{"$group": bson.M{"_id": "$status"}}}
+	opts := []options.Lister[options.AggregateOptions]{
+		(&AggregateOptions{AllowDiskUse: true, Let: bson.M{"min": 10}}).toDriverOptions(),
+		options.Aggregate().SetHint("status_1").SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+	}
+
+	cmd, err := explainAggregateCommand("orders", pipelineDoc, opts)
+	if err != nil {
+		t.Fatalf("explainAggregateCommand failed: %v", err)
+	}
+
+	expected := bson.D{
+		{Key: "explain", Value: bson.D{
+			{Key: "aggregate", Value: "orders"},
+			{Key: "pipeline", Value: pipelineDoc},
+			{Key: "cursor", Value: bson.D{}},
+			{Key: "allowDiskUse", Value: true},
+			{Key: "let", Value: bson.M{"min": 10}},
+			{Key: "hint", Value: "status_1"},
+			{Key: "collation", Value: bson.D{{Key: "locale", Value: "en"}, {Key: "strength", Value: 2}}},
+		}},
+		{Key: "verbosity", Value: "executionStats"},
+	}
+	if !reflect.DeepEqual(cmd, expected) {
+		t.Errorf("Unexpected explain command:\n%v\nexpected:\n%v", cmd, expected)
+	}
+}
+
+func TestAggregateStatsUnavailable(t *testing.T) {
+	result := &AggregateResult{}
+	if _, err := result.Stats(context.Background()); !errors.Is(err, ErrStatsUnavailable) {
+		t.Errorf("Expected ErrStatsUnavailable, got %v", err)
+	}
+}
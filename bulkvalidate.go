@@ -0,0 +1,187 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ErrInvalidBulkModel is wrapped by the BulkModelError returned by ValidateBulk when a model
+// would be rejected by the driver or the server
+var ErrInvalidBulkModel = errors.New("invalid bulk write model")
+
+// maxBSONDocumentSize is the server limit on the size of a document
+const maxBSONDocumentSize = 16 * 1024 * 1024
+
+// BulkModelError reports the first model of a bulk write that failed validation
+type BulkModelError struct {
+	// Index is the position of the offending model in the slice passed to ValidateBulk
+	Index int
+	// Err describes the problem; it wraps ErrInvalidBulkModel or ErrDocumentTooLarge
+	Err error
+}
+
+// Error implements the error interface
+func (e *BulkModelError) Error() string {
+	return fmt.Sprintf("bulk write model %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error, for errors.Is and errors.As
+func (e *BulkModelError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateBulk checks bulk write models on the client without sending anything to the server,
+// so that a mistake in a large batch is found before part of it has been applied. It returns
+// a *BulkModelError for the first offending model:
+//
+//   - a nil model or a model type BulkWrite does not support
+//   - update, replace and delete models without a filter (an empty filter is allowed)
+//   - inserts and replacements without a document, and replacements using update operators
+//   - updates that are empty or do not use update operators ($set, $inc, ...) or a pipeline
+//   - documents, replacements and updates larger than WithMaxDocumentSize, or the 16MB server
+//     limit when no size is configured (ErrDocumentTooLarge)
+//
+// Example:
+//
+//	if err := col.ValidateBulk(models); err != nil {
+//	    var modelErr *mongodb.BulkModelError
+//	    if errors.As(err, &modelErr) {
+//	        log.Printf("rejecting batch, model %d: %v", modelErr.Index, modelErr.Err)
+//	    }
+//	    return err
+//	}
+//	result, err := col.BulkWrite(ctx, models)
+func (col *Collection) ValidateBulk(models []mongo.WriteModel) error {
+	limit := col.client.config.MaxDocumentSize
+	if limit <= 0 {
+		limit = maxBSONDocumentSize
+	}
+
+	for i, model := range models {
+		if err := validateBulkModel(model, limit); err != nil {
+			return &BulkModelError{Index: i, Err: err}
+		}
+	}
+	return nil
+}
+
+// validateBulkModel checks a single bulk write model
+func validateBulkModel(model mongo.WriteModel, limit int) error {
+	switch m := model.(type) {
+	case *mongo.InsertOneModel:
+		if m == nil || m.Document == nil {
+			return fmt.Errorf("%w: insert has no document", ErrInvalidBulkModel)
+		}
+		_, err := checkBulkDocument("document", m.Document, limit)
+		return err
+
+	case *mongo.UpdateOneModel:
+		if m == nil {
+			return fmt.Errorf("%w: nil model", ErrInvalidBulkModel)
+		}
+		return validateBulkUpdate(m.Filter, m.Update, limit)
+
+	case *mongo.UpdateManyModel:
+		if m == nil {
+			return fmt.Errorf("%w: nil model", ErrInvalidBulkModel)
+		}
+		return validateBulkUpdate(m.Filter, m.Update, limit)
+
+	case *mongo.ReplaceOneModel:
+		if m == nil || m.Filter == nil {
+			return fmt.Errorf("%w: replace has no filter", ErrInvalidBulkModel)
+		}
+		if m.Replacement == nil {
+			return fmt.Errorf("%w: replace has no replacement document", ErrInvalidBulkModel)
+		}
+		doc, err := checkBulkDocument("replacement", m.Replacement, limit)
+		if err != nil {
+			return err
+		}
+		if key, ok := firstKey(doc); ok && strings.HasPrefix(key, "$") {
+			return fmt.Errorf("%w: replacement must not use update operators (found %s)", ErrInvalidBulkModel, key)
+		}
+		return nil
+
+	case *mongo.DeleteOneModel:
+		if m == nil || m.Filter == nil {
+			return fmt.Errorf("%w: delete has no filter", ErrInvalidBulkModel)
+		}
+		return nil
+
+	case *mongo.DeleteManyModel:
+		if m == nil || m.Filter == nil {
+			return fmt.Errorf("%w: delete has no filter", ErrInvalidBulkModel)
+		}
+		return nil
+
+	case nil:
+		return fmt.Errorf("%w: nil model", ErrInvalidBulkModel)
+	}
+
+	return fmt.Errorf("%w: unsupported model type %T", ErrInvalidBulkModel, model)
+}
+
+// validateBulkUpdate checks the filter and update of an update model. An update is either a
+// document of update operators or a non-empty aggregation pipeline.
+func validateBulkUpdate(filterDoc, updateDoc any, limit int) error {
+	if filterDoc == nil {
+		return fmt.Errorf("%w: update has no filter", ErrInvalidBulkModel)
+	}
+	if updateDoc == nil {
+		return fmt.Errorf("%w: update has no update document", ErrInvalidBulkModel)
+	}
+
+	t, data, err := bson.MarshalValue(updateDoc)
+	if err != nil {
+		return fmt.Errorf("%w: failed to marshal update: %w", ErrInvalidBulkModel, err)
+	}
+	if len(data) > limit {
+		return fmt.Errorf("%w: update is %d bytes, limit is %d bytes", ErrDocumentTooLarge, len(data), limit)
+	}
+
+	switch t {
+	case bson.TypeArray:
+		values, err := bson.RawArray(data).Values()
+		if err != nil || len(values) == 0 {
+			return fmt.Errorf("%w: update pipeline is empty", ErrInvalidBulkModel)
+		}
+		return nil
+	case bson.TypeEmbeddedDocument:
+		key, ok := firstKey(bson.Raw(data))
+		if !ok {
+			return fmt.Errorf("%w: update document is empty", ErrInvalidBulkModel)
+		}
+		if !strings.HasPrefix(key, "$") {
+			return fmt.Errorf("%w: update must use update operators such as $set (found %s); use a replace model to replace the document", ErrInvalidBulkModel, key)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%w: update must be a document or a pipeline, got %s", ErrInvalidBulkModel, t)
+}
+
+// checkBulkDocument marshals a document of a bulk model and checks its size
+func checkBulkDocument(description string, document any, limit int) (bson.Raw, error) {
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to marshal %s: %w", ErrInvalidBulkModel, description, err)
+	}
+	if len(data) > limit {
+		return nil, fmt.Errorf("%w: %s is %d bytes, limit is %d bytes", ErrDocumentTooLarge, description, len(data), limit)
+	}
+	return data, nil
+}
+
+// firstKey returns the first key of a document, or false if it is empty
+func firstKey(doc bson.Raw) (string, bool) {
+	elements, err := doc.Elements()
+	if err != nil || len(elements) == 0 {
+		return "", false
+	}
+	return elements[0].Key(), true
+}
@@ -0,0 +1,89 @@
+package mongodb
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestValidateBulk(t *testing.T) {
+	col := newTestCollection("orders")
+
+	valid := []mongo.WriteModel{
+		mongo.NewInsertOneModel().SetDocument(bson.M{"sku": "A1"}),
+		mongo.NewUpdateOneModel().SetFilter(bson.M{"sku": "A1"}).SetUpdate(bson.M{"$inc": bson.M{"qty": 1}}),
+		mongo.NewUpdateManyModel().SetFilter(bson.M{}).SetUpdate(bson.A{bson.M{"$set": bson.M{"total": "$qty"}}}),
+		mongo.NewReplaceOneModel().SetFilter(bson.M{"sku": "B2"}).SetReplacement(bson.M{"sku": "B2", "qty": 3}),
+		mongo.NewDeleteOneModel().SetFilter(bson.M{"sku": "C3"}),
+		mongo.NewDeleteManyModel().SetFilter(bson.D{}),
+	}
+	if err := col.ValidateBulk(valid); err != nil {
+		t.Fatalf("Expected valid models to pass, got %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		model   mongo.WriteModel
+		message string
+	}{
+		{"Nil model", nil, "nil model"},
+		{"Insert without document", mongo.NewInsertOneModel(), "no document"},
+		{"Update without filter", mongo.NewUpdateOneModel().SetUpdate(bson.M{"$set": bson.M{"a": 1}}), "no filter"},
+		{"Update without update", mongo.NewUpdateManyModel().SetFilter(bson.M{}), "no update document"},
+		{"Empty update", mongo.NewUpdateOneModel().SetFilter(bson.M{}).SetUpdate(bson.M{}), "update document is empty"},
+		{"Update without operators", mongo.NewUpdateOneModel().SetFilter(bson.M{}).SetUpdate(bson.M{"qty": 1}), "update operators"},
+		{"Empty pipeline", mongo.NewUpdateManyModel().SetFilter(bson.M{}).SetUpdate(bson.A{}), "pipeline is empty"},
+		{"Replace with operators", mongo.NewReplaceOneModel().SetFilter(bson.M{}).SetReplacement(bson.M{"$set": bson.M{"a": 1}}), "must not use update operators"},
+		{"Delete without filter", mongo.NewDeleteOneModel(), "no filter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Place the invalid model after valid ones to check the reported index
+			models := append(append([]mongo.WriteModel{}, valid...), tt.model, valid[0])
+
+			err := col.ValidateBulk(models)
+			var modelErr *BulkModelError
+			if !errors.As(err, &modelErr) {
+				t.Fatalf("Expected a BulkModelError, got %v", err)
+			}
+			if modelErr.Index != len(valid) {
+				t.Errorf("Expected index %d, got %d", len(valid), modelErr.Index)
+			}
+			if !errors.Is(err, ErrInvalidBulkModel) {
+				t.Errorf("Expected ErrInvalidBulkModel, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Expected error mentioning %q, got %v", tt.message, err)
+			}
+		})
+	}
+}
+
+func TestValidateBulkDocumentSize(t *testing.T) {
+	col := newTestCollection("orders", WithMaxDocumentSize(64))
+
+	big := strings.Repeat("x", 100)
+	models := []mongo.WriteModel{
+		mongo.NewInsertOneModel().SetDocument(bson.M{"note": "small"}),
+		mongo.NewUpdateOneModel().SetFilter(bson.M{}).SetUpdate(bson.M{"$set": bson.M{"note": big}}),
+	}
+
+	err := col.ValidateBulk(models)
+	var modelErr *BulkModelError
+	if !errors.As(err, &modelErr) || modelErr.Index != 1 {
+		t.Fatalf("Expected model 1 to be rejected, got %v", err)
+	}
+	if !errors.Is(err, ErrDocumentTooLarge) {
+		t.Errorf("Expected ErrDocumentTooLarge, got %v", err)
+	}
+
+	// Without a configured limit the 16MB server limit applies
+	unlimited := newTestCollection("orders")
+	if err := unlimited.ValidateBulk(models); err != nil {
+		t.Errorf("Expected models under 16MB to pass, got %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package mongodb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloudresty/go-mongodb/v2/internal/bsonutil"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ApplyTo returns the document after the event, computed from a cached copy of the document
+// before it. Update events apply updateDescription (updated fields, removed fields and
+// truncated arrays) to base, so a stream opened without fullDocument=updateLookup can keep a
+// cache current without the server sending or looking up the whole document for every
+// update. Insert and replace events return their full document, and delete events return nil.
+// base is never modified.
+//
+// When base is nil (the document is not cached) the event's post-image is used if present;
+// otherwise ErrNoDocumentImage is returned, and the document should be read once by its
+// DocumentKey to seed the cache.
+//
+// Example:
+//
+//	cache := map[string]bson.M{}
+//	for stream.Next(ctx) {
+//	    var event mongodb.ChangeEvent
+//	    if err := stream.Decode(&event); err != nil {
+//	        return err
+//	    }
+//	    id := event.DocumentKey.Lookup("_id").StringValue()
+//	    doc, err := event.ApplyTo(cache[id])
+//	    if errors.Is(err, mongodb.ErrNoDocumentImage) {
+//	        err = orders.FindByID(ctx, id).Decode(&doc)
+//	    }
+//	    if err != nil {
+//	        return err
+//	    }
+//	    cache[id] = doc
+//	}
+func (e *ChangeEvent) ApplyTo(base bson.M) (bson.M, error) {
+	switch e.OperationType {
+	case OperationDelete:
+		return nil, nil
+	case OperationInsert, OperationReplace:
+		return e.fullDocumentMap()
+	case OperationUpdate:
+	default:
+		return nil, fmt.Errorf("cannot apply %s change event to a document", e.OperationType)
+	}
+
+	if base == nil {
+		return e.fullDocumentMap()
+	}
+	if e.UpdateDescription == nil {
+		return nil, fmt.Errorf("update change event has no update description")
+	}
+
+	doc := bsonutil.DeepCopyM(base)
+	for _, truncated := range e.UpdateDescription.TruncatedArrays {
+		size := truncated.NewSize
+		modifyPath(doc, strings.Split(truncated.Field, "."), false, func(value any, exists bool) (any, bool) {
+			switch array := value.(type) {
+			case bson.A:
+				if size < len(array) {
+					return array[:size], true
+				}
+			case []any:
+				if size < len(array) {
+					return array[:size], true
+				}
+			}
+			return value, exists
+		})
+	}
+	for _, field := range e.UpdateDescription.RemovedFields {
+		modifyPath(doc, strings.Split(field, "."), false, func(any, bool) (any, bool) {
+			return nil, false
+		})
+	}
+
+	if len(e.UpdateDescription.UpdatedFields) > 0 {
+		var updated bson.M
+		if err := bson.Unmarshal(e.UpdateDescription.UpdatedFields, &updated); err != nil {
+			return nil, fmt.Errorf("failed to decode updated fields: %w", err)
+		}
+		for field, value := range updated {
+			modifyPath(doc, strings.Split(field, "."), true, func(any, bool) (any, bool) {
+				return value, true
+			})
+		}
+	}
+	return doc, nil
+}
+
+// fullDocumentMap decodes the post-image of the event
+func (e *ChangeEvent) fullDocumentMap() (bson.M, error) {
+	var doc bson.M
+	if err := e.DecodeFullDocument(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// modifyPath returns container with the value at path replaced by fn, which receives the
+// current value and whether it exists, and returns the new value and whether to keep it.
+// With create, missing embedded documents along the path are created and arrays are padded
+// with null like the server does; otherwise a missing path leaves container unchanged.
+func modifyPath(container any, path []string, create bool, fn func(value any, exists bool) (any, bool)) any {
+	key, rest := path[0], path[1:]
+	apply := func(value any, exists bool) (any, bool) {
+		if len(rest) == 0 {
+			return fn(value, exists)
+		}
+		if !exists && !create {
+			return nil, false
+		}
+		return modifyPath(value, rest, create, fn), true
+	}
+
+	switch c := container.(type) {
+	case bson.M:
+		value, exists := c[key]
+		if value, keep := apply(value, exists); keep {
+			c[key] = value
+		} else {
+			delete(c, key)
+		}
+		return c
+	case map[string]any:
+		value, exists := c[key]
+		if value, keep := apply(value, exists); keep {
+			c[key] = value
+		} else {
+			delete(c, key)
+		}
+		return c
+	case bson.D:
+		for i, elem := range c {
+			if elem.Key != key {
+				continue
+			}
+			value, keep := apply(elem.Value, true)
+			if !keep {
+				return append(c[:i], c[i+1:]...)
+			}
+			c[i].Value = value
+			return c
+		}
+		if value, keep := apply(nil, false); keep {
+			c = append(c, bson.E{Key: key, Value: value})
+		}
+		return c
+	case bson.A:
+		return bson.A(modifyIndex(c, key, create, apply))
+	case []any:
+		return modifyIndex(c, key, create, apply)
+	}
+
+	if !create {
+		return container
+	}
+	// Missing or scalar intermediate values become embedded documents
+	return modifyPath(bson.M{}, path, create, fn)
+}
+
+// modifyIndex applies modifyPath's apply function to the array element at index key
+func modifyIndex(c []any, key string, create bool, apply func(value any, exists bool) (any, bool)) []any {
+	index, err := strconv.Atoi(key)
+	if err != nil || index < 0 {
+		return c
+	}
+	exists := index < len(c)
+	if !exists && !create {
+		return c
+	}
+	for len(c) <= index {
+		c = append(c, nil)
+	}
+	value, keep := apply(c[index], exists)
+	if !keep {
+		// Removing an array element sets it to null, like $unset
+		value = nil
+	}
+	c[index] = value
+	return c
+}
@@ -0,0 +1,177 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Change event operation types, for use with WatchOperations and ChangeEvent.OperationType
+const (
+	OperationInsert  = "insert"
+	OperationUpdate  = "update"
+	OperationReplace = "replace"
+	OperationDelete  = "delete"
+)
+
+// ErrNoDocumentImage is returned when decoding a document image that is not present on a change event,
+// e.g. the pre-image of an insert or a pre-image on a collection without pre/post images enabled.
+var ErrNoDocumentImage = errors.New("change event does not contain the requested document image")
+
+// ChangeEvent is a typed change stream event. Decode events from a stream returned by Watch:
+//
+//	for stream.Next(ctx) {
+//	    var event mongodb.ChangeEvent
+//	    if err := stream.Decode(&event); err != nil {
+//	        return err
+//	    }
+//	}
+type ChangeEvent struct {
+	// ID is the resume token of the event
+	ID            bson.Raw        `bson:"_id"`
+	OperationType string          `bson:"operationType"`
+	Namespace     ChangeNamespace `bson:"ns"`
+	DocumentKey   bson.Raw        `bson:"documentKey,omitempty"`
+	ClusterTime   bson.Timestamp  `bson:"clusterTime"`
+
+	// FullDocument is the post-image; present for inserts and replaces, and for updates
+	// when the stream was opened with FullDocument set (see ChangeStreamPrePostImages)
+	FullDocument bson.Raw `bson:"fullDocument,omitempty"`
+	// FullDocumentBeforeChange is the pre-image; present when the stream was opened with
+	// FullDocumentBeforeChange set and the collection has pre/post images enabled
+	FullDocumentBeforeChange bson.Raw `bson:"fullDocumentBeforeChange,omitempty"`
+
+	UpdateDescription *UpdateDescription `bson:"updateDescription,omitempty"`
+}
+
+// ChangeNamespace identifies the database and collection of a change event
+type ChangeNamespace struct {
+	Database   string `bson:"db"`
+	Collection string `bson:"coll"`
+}
+
+// UpdateDescription describes the fields changed by an update event
+type UpdateDescription struct {
+	UpdatedFields bson.Raw `bson:"updatedFields,omitempty"`
+	RemovedFields []string `bson:"removedFields,omitempty"`
+	// TruncatedArrays lists arrays shortened by the update (e.g. by $pop or $pull)
+	TruncatedArrays []TruncatedArray `bson:"truncatedArrays,omitempty"`
+}
+
+// TruncatedArray is an array field shortened to NewSize elements by an update event
+type TruncatedArray struct {
+	Field   string `bson:"field"`
+	NewSize int    `bson:"newSize"`
+}
+
+// HasFullDocument reports whether the event carries a post-image
+func (e *ChangeEvent) HasFullDocument() bool {
+	return len(e.FullDocument) > 0
+}
+
+// HasFullDocumentBeforeChange reports whether the event carries a pre-image
+func (e *ChangeEvent) HasFullDocumentBeforeChange() bool {
+	return len(e.FullDocumentBeforeChange) > 0
+}
+
+// DecodeFullDocument decodes the post-image into v.
+// Returns ErrNoDocumentImage if the event has no post-image.
+func (e *ChangeEvent) DecodeFullDocument(v any) error {
+	if !e.HasFullDocument() {
+		return ErrNoDocumentImage
+	}
+	return bson.Unmarshal(e.FullDocument, v)
+}
+
+// DecodeFullDocumentBeforeChange decodes the pre-image into v.
+// Returns ErrNoDocumentImage if the event has no pre-image.
+func (e *ChangeEvent) DecodeFullDocumentBeforeChange(v any) error {
+	if !e.HasFullDocumentBeforeChange() {
+		return ErrNoDocumentImage
+	}
+	return bson.Unmarshal(e.FullDocumentBeforeChange, v)
+}
+
+// ChangeStreamPrePostImages returns change stream options requesting both document images:
+// the current document for updates (fullDocument=updateLookup) and the document before the
+// change (fullDocumentBeforeChange=whenAvailable).
+//
+// Pre-images require changeStreamPreAndPostImages to be enabled on the collection, see
+// CreateCollectionWithPrePostImages and EnableChangeStreamPreAndPostImages. Note that
+// updateLookup reads the current document at lookup time, which may include later changes.
+//
+// Example:
+//
+//	stream, err := col.Watch(ctx, bson.A{}, mongodb.ChangeStreamPrePostImages())
+func ChangeStreamPrePostImages() *options.ChangeStreamOptionsBuilder {
+	return options.ChangeStream().
+		SetFullDocument(options.UpdateLookup).
+		SetFullDocumentBeforeChange(options.WhenAvailable)
+}
+
+// CreateCollectionWithPrePostImages creates a collection with changeStreamPreAndPostImages enabled
+func (db *Database) CreateCollectionWithPrePostImages(ctx context.Context, name string) error {
+	return db.CreateCollection(ctx, name, options.CreateCollection().
+		SetChangeStreamPreAndPostImages(bson.M{"enabled": true}))
+}
+
+// EnableChangeStreamPreAndPostImages enables changeStreamPreAndPostImages on an existing collection
+// using collMod, so that change streams can return pre-images of changed documents.
+func (col *Collection) EnableChangeStreamPreAndPostImages(ctx context.Context) error {
+	if err := col.checkWritable("EnableChangeStreamPreAndPostImages"); err != nil {
+		return err
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	cmd := bson.D{
+		{Key: "collMod", Value: col.name},
+		{Key: "changeStreamPreAndPostImages", Value: bson.M{"enabled": true}},
+	}
+
+	if err := col.checkConnected(); err != nil {
+		return err
+	}
+
+	if err := col.collection.Database().RunCommand(ctx, cmd).Err(); err != nil {
+		col.logger(ctx).Error("Failed to enable change stream pre and post images",
+			"error", err.Error(),
+			"collection", col.name)
+		return fmt.Errorf("failed to enable change stream pre and post images: %w", err)
+	}
+
+	col.logger(ctx).Debug("Change stream pre and post images enabled",
+		"collection", col.name)
+
+	return nil
+}
+
+// WatchWithPipeline returns a change stream for the collection filtered by a pipeline builder.
+// A nil builder watches all events.
+func (col *Collection) WatchWithPipeline(ctx context.Context, pipelineBuilder *pipeline.Builder, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
+	stages := []bson.M{}
+	if pipelineBuilder != nil {
+		stages = pipelineBuilder.Build()
+	}
+	return col.Watch(ctx, stages, opts...)
+}
+
+// WatchOperations returns a change stream for the collection that only emits events of the
+// given operation types, filtered on the server. An empty list watches all events.
+//
+// Example:
+//
+//	stream, err := col.WatchOperations(ctx, []string{mongodb.OperationInsert, mongodb.OperationDelete})
+func (col *Collection) WatchOperations(ctx context.Context, operationTypes []string, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
+	return col.WatchWithPipeline(ctx, pipeline.ChangeStreamMatch(operationTypes...), opts...)
+}
@@ -0,0 +1,366 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/update"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type changeEventOrder struct {
+	ID     string `bson:"_id"`
+	Status string `bson:"status"`
+}
+
+func TestChangeEventDecodeImages(t *testing.T) {
+	raw, err := bson.Marshal(bson.M{
+		"_id":                      bson.M{"_data": "826A"},
+		"operationType":            "update",
+		"ns":                       bson.M{"db": "app", "coll": "orders"},
+		"documentKey":              bson.M{"_id": "o1"},
+		"clusterTime":              bson.Timestamp{T: 1700000000, I: 1},
+		"fullDocument":             bson.M{"_id": "o1", "status": "shipped"},
+		"fullDocumentBeforeChange": bson.M{"_id": "o1", "status": "pending"},
+		"updateDescription": bson.M{
+			"updatedFields": bson.M{"status": "shipped"},
+			"removedFields": bson.A{"note"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal event: %v", err)
+	}
+
+	var event ChangeEvent
+	if err := bson.Unmarshal(raw, &event); err != nil {
+		t.Fatalf("Failed to decode change event: %v", err)
+	}
+
+	if event.OperationType != "update" || event.Namespace.Collection != "orders" || event.Namespace.Database != "app" {
+		t.Errorf("Unexpected event metadata: %+v", event)
+	}
+	if event.UpdateDescription == nil || len(event.UpdateDescription.RemovedFields) != 1 {
+		t.Errorf("Expected update description with removed fields, got %+v", event.UpdateDescription)
+	}
+
+	var before, after changeEventOrder
+	if err := event.DecodeFullDocumentBeforeChange(&before); err != nil {
+		t.Fatalf("Failed to decode pre-image: %v", err)
+	}
+	if err := event.DecodeFullDocument(&after); err != nil {
+		t.Fatalf("Failed to decode post-image: %v", err)
+	}
+	if before.Status != "pending" || after.Status != "shipped" {
+		t.Errorf("Expected pending -> shipped, got %s -> %s", before.Status, after.Status)
+	}
+}
+
+func TestChangeEventMissingImages(t *testing.T) {
+	raw, err := bson.Marshal(bson.M{
+		"_id":           bson.M{"_data": "826B"},
+		"operationType": "delete",
+		"ns":            bson.M{"db": "app", "coll": "orders"},
+		"documentKey":   bson.M{"_id": "o1"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal event: %v", err)
+	}
+
+	var event ChangeEvent
+	if err := bson.Unmarshal(raw, &event); err != nil {
+		t.Fatalf("Failed to decode change event: %v", err)
+	}
+
+	var doc changeEventOrder
+	if err := event.DecodeFullDocument(&doc); !errors.Is(err, ErrNoDocumentImage) {
+		t.Errorf("Expected ErrNoDocumentImage for post-image, got %v", err)
+	}
+	if err := event.DecodeFullDocumentBeforeChange(&doc); !errors.Is(err, ErrNoDocumentImage) {
+		t.Errorf("Expected ErrNoDocumentImage for pre-image, got %v", err)
+	}
+}
+
+func TestChangeStreamPrePostImages(t *testing.T) {
+	resolved := &options.ChangeStreamOptions{}
+	for _, apply := range ChangeStreamPrePostImages().List() {
+		if err := apply(resolved); err != nil {
+			t.Fatalf("Failed to apply change stream option: %v", err)
+		}
+	}
+
+	if resolved.FullDocument == nil || *resolved.FullDocument != options.UpdateLookup {
+		t.Errorf("Expected fullDocument=updateLookup, got %v", resolved.FullDocument)
+	}
+	if resolved.FullDocumentBeforeChange == nil || *resolved.FullDocumentBeforeChange != options.WhenAvailable {
+		t.Errorf("Expected fullDocumentBeforeChange=whenAvailable, got %v", resolved.FullDocumentBeforeChange)
+	}
+}
+
+func TestChangeStreamPrePostImagesIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	db := client.Database(client.config.Database)
+	name := "test_change_stream_images"
+	_ = db.Collection(name).Raw().Drop(ctx)
+	defer func() {
+		_ = db.Collection(name).Raw().Drop(ctx)
+	}()
+
+	// Pre/post images require MongoDB 6.0+ running as a replica set
+	if err := db.CreateCollectionWithPrePostImages(ctx, name); err != nil {
+		t.Skipf("Pre/post images not supported by this deployment: %v", err)
+	}
+	col := db.Collection(name)
+
+	stream, err := col.Watch(ctx, bson.A{}, ChangeStreamPrePostImages())
+	if err != nil {
+		t.Skipf("Change streams not supported by this deployment: %v", err)
+	}
+	defer func() {
+		_ = stream.Close(ctx)
+	}()
+
+	if _, err := col.InsertOne(ctx, bson.M{"_id": "o1", "status": "pending"}); err != nil {
+		t.Fatalf("Failed to insert document: %v", err)
+	}
+	if _, err := col.UpdateOne(ctx, filter.Eq("_id", "o1"), update.Set("status", "shipped")); err != nil {
+		t.Fatalf("Failed to update document: %v", err)
+	}
+
+	for stream.Next(ctx) {
+		var event ChangeEvent
+		if err := stream.Decode(&event); err != nil {
+			t.Fatalf("Failed to decode change event: %v", err)
+		}
+		if event.OperationType != "update" {
+			continue
+		}
+
+		var before, after changeEventOrder
+		if err := event.DecodeFullDocumentBeforeChange(&before); err != nil {
+			t.Fatalf("Failed to decode pre-image: %v", err)
+		}
+		if err := event.DecodeFullDocument(&after); err != nil {
+			t.Fatalf("Failed to decode post-image: %v", err)
+		}
+		if before.Status != "pending" || after.Status != "shipped" {
+			t.Errorf("Expected pending -> shipped, got %s -> %s", before.Status, after.Status)
+		}
+		return
+	}
+	t.Fatalf("Did not receive update event: %v", stream.Err())
+}
+
+func TestWatchOperationsIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_watch_operations")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	stream, err := col.WatchOperations(ctx, []string{OperationDelete})
+	if err != nil {
+		t.Skipf("Change streams not supported by this deployment: %v", err)
+	}
+	defer func() {
+		_ = stream.Close(ctx)
+	}()
+
+	if _, err := col.InsertOne(ctx, bson.M{"_id": "w1"}); err != nil {
+		t.Fatalf("Failed to insert document: %v", err)
+	}
+	if _, err := col.DeleteOne(ctx, filter.Eq("_id", "w1")); err != nil {
+		t.Fatalf("Failed to delete document: %v", err)
+	}
+
+	// The insert is filtered out on the server, so the first event is the delete
+	if !stream.Next(ctx) {
+		t.Fatalf("Did not receive delete event: %v", stream.Err())
+	}
+	var event ChangeEvent
+	if err := stream.Decode(&event); err != nil {
+		t.Fatalf("Failed to decode change event: %v", err)
+	}
+	if event.OperationType != OperationDelete {
+		t.Errorf("Expected delete event, got %s", event.OperationType)
+	}
+}
+
+// updateEvent returns a decoded update event with the given update description
+func updateEvent(t *testing.T, description bson.M) *ChangeEvent {
+	t.Helper()
+	raw, err := bson.Marshal(bson.M{
+		"operationType":     OperationUpdate,
+		"documentKey":       bson.M{"_id": "o1"},
+		"updateDescription": description,
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal event: %v", err)
+	}
+	var event ChangeEvent
+	if err := bson.Unmarshal(raw, &event); err != nil {
+		t.Fatalf("Failed to decode change event: %v", err)
+	}
+	return &event
+}
+
+func TestChangeEventApplyTo(t *testing.T) {
+	base := bson.M{
+		"_id":      "o1",
+		"status":   "pending",
+		"note":     "call first",
+		"shipping": bson.M{"city": "Oslo", "zip": "0150"},
+		"items":    bson.A{"a", "b", "c", "d"},
+		"tags":     bson.A{"new"},
+	}
+
+	event := updateEvent(t, bson.M{
+		"updatedFields": bson.M{
+			"status":            "shipped",
+			"shipping.city":     "Bergen",
+			"tracking.carrier":  "posten",
+			"tags.2":            "priority",
+			"shipping.attempts": int32(1),
+		},
+		"removedFields":   bson.A{"note", "shipping.zip"},
+		"truncatedArrays": bson.A{bson.M{"field": "items", "newSize": int32(2)}},
+	})
+
+	doc, err := event.ApplyTo(base)
+	if err != nil {
+		t.Fatalf("ApplyTo failed: %v", err)
+	}
+
+	expected := bson.M{
+		"_id":      "o1",
+		"status":   "shipped",
+		"shipping": bson.M{"city": "Bergen", "attempts": int32(1)},
+		"tracking": bson.M{"carrier": "posten"},
+		"items":    bson.A{"a", "b"},
+		"tags":     bson.A{"new", nil, "priority"},
+	}
+	if !reflect.DeepEqual(doc, expected) {
+		t.Errorf("Expected %v, got %v", expected, doc)
+	}
+
+	// The cached document is left as is
+	if base["status"] != "pending" || base["note"] != "call first" || len(base["items"].(bson.A)) != 4 ||
+		base["shipping"].(bson.M)["city"] != "Oslo" {
+		t.Errorf("Expected the base document to be unchanged, got %v", base)
+	}
+}
+
+func TestChangeEventApplyToEmbeddedD(t *testing.T) {
+	base := bson.M{"_id": "o1", "shipping": bson.D{{Key: "city", Value: "Oslo"}, {Key: "zip", Value: "0150"}}}
+
+	event := updateEvent(t, bson.M{
+		"updatedFields": bson.M{"shipping.city": "Bergen", "shipping.country": "NO"},
+		"removedFields": bson.A{"shipping.zip", "missing.field"},
+	})
+	doc, err := event.ApplyTo(base)
+	if err != nil {
+		t.Fatalf("ApplyTo failed: %v", err)
+	}
+
+	shipping, ok := doc["shipping"].(bson.D)
+	if !ok {
+		t.Fatalf("Expected shipping to stay a bson.D, got %T", doc["shipping"])
+	}
+	got := bson.M{}
+	for _, elem := range shipping {
+		got[elem.Key] = elem.Value
+	}
+	if len(got) != 2 || got["city"] != "Bergen" || got["country"] != "NO" {
+		t.Errorf("Unexpected shipping document: %v", shipping)
+	}
+	if _, ok := doc["missing"]; ok {
+		t.Errorf("Expected removing a missing field not to create it, got %v", doc)
+	}
+}
+
+func TestChangeEventApplyToGoSlices(t *testing.T) {
+	base := bson.M{"_id": "o1", "tags": []any{"new", "sale"}, "items": []any{"a", "b", "c"}}
+
+	event := updateEvent(t, bson.M{
+		"updatedFields":   bson.M{"tags.1": "priority", "tags.3": "gift"},
+		"truncatedArrays": bson.A{bson.M{"field": "items", "newSize": int32(1)}},
+	})
+	doc, err := event.ApplyTo(base)
+	if err != nil {
+		t.Fatalf("ApplyTo failed: %v", err)
+	}
+
+	if tags, ok := doc["tags"].([]any); !ok || !reflect.DeepEqual(tags, []any{"new", "priority", nil, "gift"}) {
+		t.Errorf("Expected tags updated by index, got %#v", doc["tags"])
+	}
+	if items, ok := doc["items"].([]any); !ok || !reflect.DeepEqual(items, []any{"a"}) {
+		t.Errorf("Expected items truncated, got %#v", doc["items"])
+	}
+	if !reflect.DeepEqual(base["tags"], []any{"new", "sale"}) || len(base["items"].([]any)) != 3 {
+		t.Errorf("Expected the base document to be unchanged, got %v", base)
+	}
+}
+
+func TestChangeEventApplyToOperations(t *testing.T) {
+	decode := func(fields bson.M) *ChangeEvent {
+		raw, err := bson.Marshal(fields)
+		if err != nil {
+			t.Fatalf("Failed to marshal event: %v", err)
+		}
+		var event ChangeEvent
+		if err := bson.Unmarshal(raw, &event); err != nil {
+			t.Fatalf("Failed to decode change event: %v", err)
+		}
+		return &event
+	}
+	cached := bson.M{"_id": "o1", "status": "pending"}
+
+	insert := decode(bson.M{"operationType": OperationInsert, "fullDocument": bson.M{"_id": "o1", "status": "new"}})
+	if doc, err := insert.ApplyTo(nil); err != nil || doc["status"] != "new" {
+		t.Errorf("Expected the inserted document, got %v, %v", doc, err)
+	}
+
+	replace := decode(bson.M{"operationType": OperationReplace, "fullDocument": bson.M{"_id": "o1", "total": int32(5)}})
+	if doc, err := replace.ApplyTo(cached); err != nil || !reflect.DeepEqual(doc, bson.M{"_id": "o1", "total": int32(5)}) {
+		t.Errorf("Expected the replacement document, got %v, %v", doc, err)
+	}
+
+	deleted := decode(bson.M{"operationType": OperationDelete})
+	if doc, err := deleted.ApplyTo(cached); err != nil || doc != nil {
+		t.Errorf("Expected nil for a delete, got %v, %v", doc, err)
+	}
+
+	// An update of an uncached document needs the post-image
+	update := updateEvent(t, bson.M{"updatedFields": bson.M{"status": "shipped"}})
+	if _, err := update.ApplyTo(nil); !errors.Is(err, ErrNoDocumentImage) {
+		t.Errorf("Expected ErrNoDocumentImage, got %v", err)
+	}
+	update.FullDocument, _ = bson.Marshal(bson.M{"_id": "o1", "status": "shipped"})
+	if doc, err := update.ApplyTo(nil); err != nil || doc["status"] != "shipped" {
+		t.Errorf("Expected the post-image, got %v, %v", doc, err)
+	}
+
+	invalidate := decode(bson.M{"operationType": "invalidate"})
+	if _, err := invalidate.ApplyTo(cached); err == nil {
+		t.Error("Expected an error for an invalidate event")
+	}
+}
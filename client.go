@@ -3,9 +3,14 @@ package mongodb
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"maps"
+	"net/url"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cloudresty/ulid"
@@ -30,6 +35,10 @@ const (
 	IDModeCustom IDMode = "custom"
 )
 
+// ErrNotConnected is returned by client operations when there is no usable connection:
+// the client was closed, or the connection could not be established
+var ErrNotConnected = errors.New("client is not connected")
+
 // Client represents a MongoDB client with environment-first configuration.
 // The MongoDB driver handles reconnection automatically via SDAM (Server Discovery and Monitoring).
 type Client struct {
@@ -38,10 +47,23 @@ type Client struct {
 	database     *mongo.Database
 	mutex        sync.RWMutex
 	isConnected  bool
+	closed       bool
 	healthTicker *time.Ticker
 	shutdownChan chan struct{}
 	shutdownOnce sync.Once
 
+	// latency records command durations reported by the command monitor
+	latency latencyRecorder
+
+	// writeLimiter throttles bulk writes when WriteRateLimit is set
+	writeLimiter *rateLimiter
+
+	// databases caches the handles returned by Database, keyed by database name
+	databases struct {
+		sync.Mutex
+		handles map[string]*Database
+	}
+
 	// Connection pool monitoring
 	poolStats struct {
 		sync.RWMutex
@@ -54,6 +76,10 @@ type Client struct {
 		// Values are "idle" or "active". This prevents metrics drift
 		// when connections are closed while in different states.
 		connStates map[int64]string
+
+		// Pool saturation tracking for the health check alert
+		saturatedSince    time.Time
+		saturationAlerted bool
 	}
 }
 
@@ -67,37 +93,97 @@ type Config struct {
 	AuthDatabase string `env:"MONGODB_AUTH_DATABASE,default=admin"`
 	ReplicaSet   string `env:"MONGODB_REPLICA_SET"`
 
+	// SRV builds a mongodb+srv:// connection string. Hosts must then be a single DNS name
+	// without a port, whose SRV and TXT records provide the host list and default options.
+	SRV bool `env:"MONGODB_SRV,default=false"`
+
+	// LazyConnect returns the client even when the server is unreachable at creation time.
+	// The driver keeps reconnecting in the background; operations fail until the server is
+	// available and are not retried.
+	LazyConnect bool `env:"MONGODB_LAZY_CONNECT,default=false"`
+
+	// StrictDatabase makes client creation fail when no database name was explicitly configured,
+	// instead of silently falling back to the default "app" database.
+	StrictDatabase bool `env:"MONGODB_STRICT_DATABASE,default=false"`
+
 	// Connection pool settings
 	MaxPoolSize     uint64        `env:"MONGODB_MAX_POOL_SIZE,default=100"`
 	MinPoolSize     uint64        `env:"MONGODB_MIN_POOL_SIZE,default=5"`
 	MaxIdleTime     time.Duration `env:"MONGODB_MAX_IDLE_TIME,default=5m"`
 	MaxConnIdleTime time.Duration `env:"MONGODB_MAX_CONN_IDLE_TIME,default=10m"`
+	WarmPool        bool          `env:"MONGODB_WARM_POOL,default=false"` // Pre-establish MinPoolSize connections on connect
+
+	// WriteRateLimit caps bulk write operations (documents for InsertMany, models for BulkWrite,
+	// one per UpdateMany call) per second across the client; 0 disables rate limiting
+	WriteRateLimit int `env:"MONGODB_WRITE_RATE_LIMIT,default=0"`
+
+	// MaxDocumentSize rejects documents whose marshaled BSON exceeds this many bytes in
+	// InsertOne, InsertMany and ReplaceOne before they are sent; 0 disables the check
+	MaxDocumentSize int `env:"MONGODB_MAX_DOCUMENT_SIZE,default=0"`
+
+	// PreciseCount makes CountDocuments always count matching documents; by default a count
+	// with an empty filter uses the collection metadata (estimatedDocumentCount) instead
+	PreciseCount bool `env:"MONGODB_PRECISE_COUNT,default=false"`
 
 	// Timeout settings
 	ConnectTimeout      time.Duration `env:"MONGODB_CONNECT_TIMEOUT,default=10s"`
 	ServerSelectTimeout time.Duration `env:"MONGODB_SERVER_SELECT_TIMEOUT,default=5s"`
 	SocketTimeout       time.Duration `env:"MONGODB_SOCKET_TIMEOUT,default=10s"`
 
+	// HeartbeatInterval is how often the driver checks each server to monitor the topology;
+	// shorter intervals detect a new primary sooner after a failover. 0 uses the driver
+	// default (10s) and the driver rejects values below 500ms.
+	HeartbeatInterval time.Duration `env:"MONGODB_HEARTBEAT_INTERVAL,default=10s"`
+
+	// MaxTime is the server-side time limit applied to every find, aggregate, count, distinct
+	// and update operation, protecting the cluster from runaway queries; 0 disables it.
+	// WithOperationMaxTime overrides it for a single call.
+	MaxTime time.Duration `env:"MONGODB_MAX_TIME,default=0s"`
+
+	// OperationRetryAttempts is the total number of attempts for FindOne, CountDocuments,
+	// Distinct, DistinctCount and opening Find and Aggregate cursors when they fail with a
+	// transient error; 1 disables retries. Attempts share the caller's context deadline (see
+	// WithOperationRetry).
+	OperationRetryAttempts int           `env:"MONGODB_OPERATION_RETRY_ATTEMPTS,default=1"`
+	OperationRetryBackoff  time.Duration `env:"MONGODB_OPERATION_RETRY_BACKOFF,default=100ms"`
+
 	// Health check settings
 	HealthCheckEnabled  bool          `env:"MONGODB_HEALTH_CHECK_ENABLED,default=true"`
 	HealthCheckInterval time.Duration `env:"MONGODB_HEALTH_CHECK_INTERVAL,default=30s"`
 
+	// PoolSaturationThreshold is the share of MaxPoolSize in use (0-1) at which the health check
+	// considers the pool saturated; 0 disables the alert. The alert fires once the pool has been
+	// saturated for PoolSaturationDuration, calling OnPoolSaturation or logging a warning.
+	PoolSaturationThreshold float64              `env:"MONGODB_POOL_SATURATION_THRESHOLD,default=0"`
+	PoolSaturationDuration  time.Duration        `env:"MONGODB_POOL_SATURATION_DURATION,default=1m"`
+	OnPoolSaturation        func(PoolSaturation) // Optional alert handler, replaces the warning log
+
 	// Performance settings
 	CompressionEnabled   bool   `env:"MONGODB_COMPRESSION_ENABLED,default=true"`
-	CompressionAlgorithm string `env:"MONGODB_COMPRESSION_ALGORITHM,default=snappy"`
+	CompressionAlgorithm string `env:"MONGODB_COMPRESSION_ALGORITHM,default=snappy"` // Comma-separated in order of preference (e.g. "zstd,snappy")
 	ReadPreference       string `env:"MONGODB_READ_PREFERENCE,default=primary"`
 	WriteConcern         string `env:"MONGODB_WRITE_CONCERN,default=majority"`
 	ReadConcern          string `env:"MONGODB_READ_CONCERN,default=local"`
 	DirectConnection     bool   `env:"MONGODB_DIRECT_CONNECTION,default=false"`
 
+	// ServerAPIVersion declares the Stable API version (e.g. "1") with every command, so that
+	// server upgrades cannot change the behavior the application relies on; empty disables it.
+	// With ServerAPIStrict, commands outside the API version fail (see IsAPIStrictError).
+	ServerAPIVersion string `env:"MONGODB_SERVER_API_VERSION"`
+	ServerAPIStrict  bool   `env:"MONGODB_SERVER_API_STRICT,default=false"`
+
 	// Application settings
 	AppName        string `env:"MONGODB_APP_NAME,default=go-mongodb-app"`
+	AppNameSuffix  string `env:"MONGODB_APP_NAME_SUFFIX"` // Appended to AppName to identify the instance (e.g. pod name)
 	ConnectionName string `env:"MONGODB_CONNECTION_NAME"`
 
 	// TLS/SSL settings
 	TLSEnabled bool        `env:"MONGODB_TLS_ENABLED,default=false"`
 	TLSConfig  *tls.Config // Custom TLS configuration (takes precedence over TLSEnabled)
 
+	// AutoEncryption enables Client-Side Field Level Encryption when set
+	AutoEncryption *AutoEncryptionOptions
+
 	// ID Generation settings
 	IDMode IDMode `env:"MONGODB_ID_MODE,default=ulid"`
 
@@ -108,20 +194,48 @@ type Config struct {
 	LogLevel  string `env:"MONGODB_LOG_LEVEL,default=info"`
 	LogFormat string `env:"MONGODB_LOG_FORMAT,default=json"`
 	Logger    Logger // Pluggable logger interface, defaults to NopLogger if not provided
+
+	// LogQueriesOnError adds the filter, update or pipeline of a failed operation to its
+	// error log entry (see WithQueryLoggingOnError)
+	LogQueriesOnError bool `env:"MONGODB_LOG_QUERIES_ON_ERROR,default=false"`
+
+	// databaseSet records whether Database was set explicitly (WithDatabase or MONGODB_DATABASE)
+	// rather than taken from the defaults
+	databaseSet bool
+
+	// strictDatabaseOption records an explicit WithStrictDatabase, which FromEnv keeps
+	strictDatabaseOption *bool
+
+	// uriOptions holds the connection string options parsed by ParseURI that have no Config
+	// field; BuildConnectionURI passes them through to the driver
+	uriOptions url.Values
+}
+
+// checkDatabase returns ErrDatabaseNotSet if StrictDatabase is enabled and the database
+// name was not configured explicitly
+func (c *Config) checkDatabase() error {
+	if c.StrictDatabase && (!c.databaseSet || c.Database == "") {
+		return ErrDatabaseNotSet
+	}
+	return nil
 }
 
 // BuildConnectionURI constructs a MongoDB connection URI from configuration components
 func (c *Config) BuildConnectionURI() string {
 	// Build URI from components
 	uri := "mongodb://"
+	if c.SRV {
+		uri = "mongodb+srv://"
+	}
 
-	// Add credentials if provided
+	// Add credentials if provided, percent-encoding reserved characters
 	if c.Username != "" && c.Password != "" {
-		uri += fmt.Sprintf("%s:%s@", c.Username, c.Password)
+		uri += url.UserPassword(c.Username, c.Password).String() + "@"
 	}
 
-	// Add hosts (can be single host:port or comma-separated multiple hosts)
-	uri += c.Hosts
+	// Add hosts (can be single host:port or comma-separated multiple hosts). The whole seed
+	// list is kept so the driver can rediscover the topology when members change.
+	uri += strings.Join(c.hostList(), ",")
 
 	// Add database
 	if c.Database != "" {
@@ -142,8 +256,8 @@ func (c *Config) BuildConnectionURI() string {
 	}
 
 	// App name
-	if c.AppName != "" {
-		params = append(params, fmt.Sprintf("appName=%s", c.AppName))
+	if appName := c.effectiveAppName(); appName != "" {
+		params = append(params, fmt.Sprintf("appName=%s", appName))
 	}
 
 	// Add compression if enabled
@@ -156,11 +270,18 @@ func (c *Config) BuildConnectionURI() string {
 		params = append(params, fmt.Sprintf("readPreference=%s", c.ReadPreference))
 	}
 
-	// Add direct connection ONLY if enabled AND we have a single host
-	if c.DirectConnection && c.isSingleHost() {
+	// Add direct connection ONLY if enabled AND we have a single host (not allowed with SRV)
+	if c.DirectConnection && c.isSingleHost() && !c.SRV {
 		params = append(params, "directConnection=true")
 	}
 
+	// Driver options from ParseURI without a Config field, in a stable order
+	for _, key := range slices.Sorted(maps.Keys(c.uriOptions)) {
+		for _, value := range c.uriOptions[key] {
+			params = append(params, url.QueryEscape(key)+"="+url.QueryEscape(value))
+		}
+	}
+
 	// Add query string if we have parameters
 	if len(params) > 0 {
 		uri += "?" + joinParams(params)
@@ -179,7 +300,11 @@ type HealthStatus struct {
 
 // InsertOneResult represents the result of an insert operation
 type InsertOneResult struct {
-	InsertedID  any       `json:"inserted_id" bson:"_id"` // Can be ULID string, ObjectID, or custom type
+	InsertedID any `json:"inserted_id" bson:"_id"` // Can be ULID string, ObjectID, or custom type
+	// WasGenerated reports whether the _id was generated on insert (a ULID by this package, or an
+	// ObjectID by the driver) rather than provided by the caller in the document.
+	WasGenerated bool `json:"was_generated" bson:"was_generated"`
+	// GeneratedAt is the time the _id was generated; zero when the caller provided the _id.
 	GeneratedAt time.Time `json:"generated_at" bson:"generated_at"`
 }
 
@@ -196,11 +321,17 @@ type UpdateResult struct {
 	ModifiedCount int64 `json:"modified_count" bson:"modified_count"`
 	UpsertedID    any   `json:"upserted_id,omitempty" bson:"upserted_id,omitempty"` // Can be any ID type
 	UpsertedCount int64 `json:"upserted_count" bson:"upserted_count"`
+	// Duration is the time spent waiting for the server to execute the operation.
+	// It serializes to JSON as integer nanoseconds.
+	Duration time.Duration `json:"duration" bson:"duration"`
 }
 
 // DeleteResult represents the result of a delete operation
 type DeleteResult struct {
 	DeletedCount int64 `json:"deleted_count" bson:"deleted_count"`
+	// Duration is the time spent waiting for the server to execute the operation.
+	// It serializes to JSON as integer nanoseconds.
+	Duration time.Duration `json:"duration" bson:"duration"`
 }
 
 // BulkWriteResult represents the result of a BulkWrite operation
@@ -224,6 +355,24 @@ type BulkWriteResult struct {
 	Acknowledged bool `json:"acknowledged" bson:"acknowledged"`
 }
 
+// UpsertedIDStrings returns the IDs of the upserted documents keyed by the index of the model
+// that upserted them, formatted with ToString (ObjectIDs as hex). Models that matched an
+// existing document, and models of other kinds, have no entry, so the map tells a sync job
+// which of its inputs created new documents.
+//
+// Example:
+//
+//	for i, id := range result.UpsertedIDStrings() {
+//	    log.Printf("record %s created document %s", records[i].ExternalID, id)
+//	}
+func (r *BulkWriteResult) UpsertedIDStrings() map[int]string {
+	ids := make(map[int]string, len(r.UpsertedIDs))
+	for index, id := range r.UpsertedIDs {
+		ids[int(index)] = ToString(id)
+	}
+	return ids
+}
+
 // QueryOptions provides options for query operations
 type QueryOptions struct {
 	Sort       bson.D
@@ -231,6 +380,28 @@ type QueryOptions struct {
 	Skip       *int64
 	Projection bson.D
 	Timeout    time.Duration
+	// ReadPreference overrides the collection read preference for this call only,
+	// e.g. readpref.Secondary() to route a single analytics query to a secondary.
+	// The collection handle itself is left unchanged.
+	ReadPreference *readpref.ReadPref
+}
+
+// AggregateOptions provides options for aggregation operations
+type AggregateOptions struct {
+	// Let defines variables accessible in the pipeline as $$name (e.g. inside $expr)
+	Let          bson.M
+	AllowDiskUse bool
+	BatchSize    *int32
+}
+
+// WriteOptions overrides collection-level settings for a single bulk write
+type WriteOptions struct {
+	// WriteConcern replaces the client write concern for this call. Use
+	// writeconcern.Unacknowledged() (w:0) for fire-and-forget writes: the call returns as soon as
+	// the documents are sent, without waiting for the server to apply them. Failed writes,
+	// including duplicate keys and validation errors, are then silently lost, and nothing is
+	// durable until the server processes the batch. Only use it for data you can afford to lose.
+	WriteConcern *writeconcern.WriteConcern
 }
 
 // IndexModel represents a MongoDB index
@@ -259,7 +430,28 @@ type TransactionOptions struct {
 //	)
 func NewClient(opts ...Option) (*Client, error) {
 	// Start with default configuration
-	config := &Config{
+	config := newDefaultConfig()
+
+	// Apply all options
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if err := config.checkDatabase(); err != nil {
+		if config.Logger != nil {
+			config.Logger.Warn("No database configured in strict database mode",
+				"default_database", config.Database)
+		}
+		return nil, err
+	}
+
+	// Create client using the existing internal logic
+	return NewClientWithConfig(config)
+}
+
+// newDefaultConfig returns the configuration that NewClient starts from before applying options
+func newDefaultConfig() *Config {
+	return &Config{
 		Hosts:        "localhost:27017",
 		Database:     "app",
 		AuthDatabase: "admin",
@@ -269,14 +461,6 @@ func NewClient(opts ...Option) (*Client, error) {
 		IDMode:       IDModeULID,
 		Logger:       NopLogger{}, // Default to no-op logger
 	}
-
-	// Apply all options
-	for _, opt := range opts {
-		opt(config)
-	}
-
-	// Create client using the existing internal logic
-	return NewClientWithConfig(config)
 }
 
 // NewClientFromEnv creates a new MongoDB client using environment variables.
@@ -308,10 +492,19 @@ func NewClientWithConfig(config *Config) (*Client, error) {
 		config.Logger = NopLogger{}
 	}
 
+	if config.StrictDatabase && config.Database == "" {
+		return nil, ErrDatabaseNotSet
+	}
+	if config.AutoEncryption != nil {
+		if err := config.AutoEncryption.validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	config.Logger.Info("Creating new MongoDB client",
 		"hosts", config.Hosts,
 		"database", config.Database,
-		"app_name", config.AppName)
+		"app_name", config.effectiveAppName())
 
 	client := &Client{
 		config:       config,
@@ -320,8 +513,17 @@ func NewClientWithConfig(config *Config) (*Client, error) {
 	// Initialize connection state tracking map
 	client.poolStats.connStates = make(map[int64]string)
 
+	if config.WriteRateLimit > 0 {
+		client.writeLimiter = newRateLimiter(config.WriteRateLimit)
+	}
+
 	if err := client.connect(); err != nil {
-		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+		if !config.LazyConnect {
+			return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+		}
+		config.Logger.Warn("MongoDB is not reachable yet, connecting lazily",
+			"hosts", config.Hosts,
+			"error", err.Error())
 	}
 
 	if config.HealthCheckEnabled {
@@ -331,16 +533,59 @@ func NewClientWithConfig(config *Config) (*Client, error) {
 	config.Logger.Info("MongoDB client initialized successfully",
 		"hosts", config.Hosts,
 		"database", config.Database,
-		"app_name", config.AppName)
+		"app_name", config.effectiveAppName())
 
 	return client, nil
 }
 
-// connect establishes a connection to MongoDB
+// connection returns the driver client and database handles. With LazyConnect, a client
+// without handles makes one connection attempt first. It returns ErrNotConnected when the
+// client was closed or no connection could be established.
+func (c *Client) connection() (*mongo.Client, *mongo.Database, error) {
+	c.mutex.RLock()
+	client, database, closed := c.client, c.database, c.closed
+	c.mutex.RUnlock()
+
+	if closed {
+		return nil, nil, ErrNotConnected
+	}
+	if client != nil {
+		return client, database, nil
+	}
+	if !c.config.LazyConnect {
+		return nil, nil, ErrNotConnected
+	}
+
+	// Retry once; connect keeps the handles under LazyConnect even if the ping fails
+	connectErr := c.connect()
+
+	c.mutex.RLock()
+	client, database = c.client, c.database
+	c.mutex.RUnlock()
+
+	if client == nil {
+		if connectErr == nil {
+			return nil, nil, ErrNotConnected
+		}
+		return nil, nil, fmt.Errorf("%w: %w", ErrNotConnected, connectErr)
+	}
+	return client, database, nil
+}
+
+// connect establishes a connection to MongoDB.
+// With LazyConnect, the driver client is kept even when the ping fails, so operations can
+// proceed once the server becomes available; otherwise it is discarded.
 func (c *Client) connect() error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	if c.closed {
+		return ErrNotConnected
+	}
+	if c.client != nil {
+		return nil
+	}
+
 	clientOptions := c.buildClientOptions()
 
 	ctx, cancel := context.WithTimeout(context.Background(), c.config.ConnectTimeout)
@@ -353,9 +598,19 @@ func (c *Client) connect() error {
 
 	// Test the connection
 	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		if c.config.LazyConnect {
+			c.client = client
+			c.database = client.Database(c.config.Database)
+		} else {
+			_ = client.Disconnect(ctx)
+		}
 		return fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
+	if c.config.WarmPool && c.config.MinPoolSize > 0 {
+		c.warmPool(ctx, client)
+	}
+
 	c.client = client
 	c.database = client.Database(c.config.Database)
 	c.isConnected = true
@@ -363,6 +618,60 @@ func (c *Client) connect() error {
 	return nil
 }
 
+// setConnected records the outcome of the latest ping, so a client created with LazyConnect
+// reports itself connected once the server is reachable
+func (c *Client) setConnected(connected bool) {
+	c.mutex.Lock()
+	c.isConnected = connected
+	c.mutex.Unlock()
+}
+
+// warmPool pre-establishes MinPoolSize connections by issuing that many pings in parallel.
+// The driver creates connections lazily, so without this the first requests after startup
+// pay the connection handshake cost. Failures are logged and do not fail the connect.
+func (c *Client) warmPool(ctx context.Context, client *mongo.Client) {
+	size := c.config.MinPoolSize
+	if c.config.MaxPoolSize > 0 && size > c.config.MaxPoolSize {
+		size = c.config.MaxPoolSize
+	}
+
+	start := time.Now()
+	warmed := warmConnections(ctx, int(size), func(ctx context.Context) error {
+		return client.Ping(ctx, readpref.Primary())
+	})
+
+	if warmed < int(size) {
+		c.config.Logger.Warn("Connection pool warm-up incomplete",
+			"requested", int(size),
+			"warmed", warmed)
+		return
+	}
+
+	c.config.Logger.Debug("Connection pool warmed up",
+		"connections", warmed,
+		"duration", time.Since(start))
+}
+
+// warmConnections runs n pings concurrently, so that each one checks out its own
+// connection from the pool, and returns the number of pings that succeeded.
+func warmConnections(ctx context.Context, n int, ping func(context.Context) error) int {
+	var wg sync.WaitGroup
+	var succeeded atomic.Int64
+
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := ping(ctx); err == nil {
+				succeeded.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return int(succeeded.Load())
+}
+
 // buildClientOptions constructs MongoDB client options from configuration
 func (c *Client) buildClientOptions() *options.ClientOptions {
 	opts := options.Client()
@@ -381,8 +690,13 @@ func (c *Client) buildClientOptions() *options.ClientOptions {
 	opts.SetServerSelectionTimeout(c.config.ServerSelectTimeout)
 	opts.SetTimeout(c.config.SocketTimeout)
 
+	// Server monitoring
+	if c.config.HeartbeatInterval > 0 {
+		opts.SetHeartbeatInterval(c.config.HeartbeatInterval)
+	}
+
 	// Application settings
-	opts.SetAppName(c.config.AppName)
+	opts.SetAppName(c.config.effectiveAppName())
 
 	// Connection pool monitoring for real metrics
 	poolMonitor := &event.PoolMonitor{
@@ -401,53 +715,93 @@ func (c *Client) buildClientOptions() *options.ClientOptions {
 		opts.SetTLSConfig(&tls.Config{})
 	}
 
+	// Client-Side Field Level Encryption
+	if c.config.AutoEncryption != nil {
+		opts.SetAutoEncryptionOptions(c.config.AutoEncryption.toDriverOptions())
+	}
+
 	// Compression
 	if c.config.CompressionEnabled {
-		compressors := []string{c.config.CompressionAlgorithm}
-		opts.SetCompressors(compressors)
+		opts.SetCompressors(c.config.compressorList())
 	}
 
 	// Read preference
-	switch c.config.ReadPreference {
+	if pref := c.config.readPreference(); pref != nil {
+		opts.SetReadPreference(pref)
+	}
+
+	// Stable API
+	if c.config.ServerAPIVersion != "" {
+		serverAPI := options.ServerAPI(options.ServerAPIVersion(c.config.ServerAPIVersion)).
+			SetStrict(c.config.ServerAPIStrict)
+		opts.SetServerAPIOptions(serverAPI)
+	}
+
+	// Command monitoring records latencies for Stats and forwards events to the monitor for
+	// APM integration (Datadog, OpenTelemetry, etc.)
+	opts.SetMonitor(c.commandMonitor())
+
+	return opts
+}
+
+// readPreference returns the driver read preference for ReadPreference, or nil if it names
+// no known mode
+func (c *Config) readPreference() *readpref.ReadPref {
+	switch c.ReadPreference {
 	case "primary":
-		opts.SetReadPreference(readpref.Primary())
+		return readpref.Primary()
 	case "primaryPreferred":
-		opts.SetReadPreference(readpref.PrimaryPreferred())
+		return readpref.PrimaryPreferred()
 	case "secondary":
-		opts.SetReadPreference(readpref.Secondary())
+		return readpref.Secondary()
 	case "secondaryPreferred":
-		opts.SetReadPreference(readpref.SecondaryPreferred())
+		return readpref.SecondaryPreferred()
 	case "nearest":
-		opts.SetReadPreference(readpref.Nearest())
+		return readpref.Nearest()
 	}
+	return nil
+}
 
-	// Command monitoring for APM integration (Datadog, OpenTelemetry, etc.)
-	if c.config.CommandMonitor != nil {
-		opts.SetMonitor(c.config.CommandMonitor)
-	}
+// compressorList returns the compressors of CompressionAlgorithm in order of preference
+func (c *Config) compressorList() []string {
+	return strings.Split(c.CompressionAlgorithm, ",")
+}
 
-	return opts
+// maxAppNameLength is the maximum appName size accepted by the server in the client metadata
+const maxAppNameLength = 128
+
+// effectiveAppName returns the application name reported to the server, composed of
+// AppName and AppNameSuffix, truncated to the server limit.
+func (c *Config) effectiveAppName() string {
+	appName := c.AppName
+	if c.AppNameSuffix != "" {
+		if appName != "" {
+			appName += "-"
+		}
+		appName += c.AppNameSuffix
+	}
+	if len(appName) > maxAppNameLength {
+		appName = appName[:maxAppNameLength]
+	}
+	return appName
 }
 
 // isSingleHost checks if the configuration specifies only a single host
 // This is used to determine if directConnection=true should be applied
 func (c *Config) isSingleHost() bool {
-	if c.Hosts == "" {
-		return false
-	}
-
-	// Split by comma and check if we have exactly one host
-	hosts := strings.Split(strings.TrimSpace(c.Hosts), ",")
+	return len(c.hostList()) == 1
+}
 
-	// Count non-empty hosts after trimming whitespace
-	validHosts := 0
-	for _, host := range hosts {
-		if strings.TrimSpace(host) != "" {
-			validHosts++
+// hostList returns the configured hosts, with whitespace around each one and empty entries
+// removed
+func (c *Config) hostList() []string {
+	var hosts []string
+	for host := range strings.SplitSeq(c.Hosts, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
 		}
 	}
-
-	return validHosts == 1
+	return hosts
 }
 
 // joinParams joins URL parameters
@@ -478,7 +832,7 @@ func (c *Client) startHealthCheck() {
 	}()
 }
 
-// performHealthCheck checks the health of the MongoDB connection.
+// performHealthCheck checks the health of the MongoDB connection and the pool saturation.
 // Note: The MongoDB driver handles reconnection automatically via SDAM.
 // This health check only reports status; it does not attempt manual reconnection.
 func (c *Client) performHealthCheck() {
@@ -491,19 +845,17 @@ func (c *Client) performHealthCheck() {
 
 	if client == nil {
 		c.config.Logger.Warn("Health check: client is nil")
-		c.mutex.Lock()
-		c.isConnected = false
-		c.mutex.Unlock()
+		c.setConnected(false)
 		return
 	}
 
+	c.checkPoolSaturation(time.Now())
+
 	start := time.Now()
 	if err := client.Ping(ctx, readpref.Primary()); err != nil {
 		c.config.Logger.Warn("Health check failed",
 			"error", err.Error())
-		c.mutex.Lock()
-		c.isConnected = false
-		c.mutex.Unlock()
+		c.setConnected(false)
 		// Note: The MongoDB driver will automatically attempt to reconnect
 		// via its built-in SDAM (Server Discovery and Monitoring) mechanism.
 		return
@@ -511,9 +863,7 @@ func (c *Client) performHealthCheck() {
 
 	latency := time.Since(start)
 
-	c.mutex.Lock()
-	c.isConnected = true
-	c.mutex.Unlock()
+	c.setConnected(true)
 
 	c.config.Logger.Debug("Health check passed",
 		"latency", latency)
@@ -526,13 +876,10 @@ func (c *Client) HealthCheck() *HealthStatus {
 		CheckedAt: startTime,
 	}
 
-	c.mutex.RLock()
-	client := c.client
-	c.mutex.RUnlock()
-
-	if client == nil {
+	client, _, err := c.connection()
+	if err != nil {
 		status.IsHealthy = false
-		status.Error = "client is not connected"
+		status.Error = err.Error()
 		status.Latency = time.Since(startTime)
 		return status
 	}
@@ -546,6 +893,7 @@ func (c *Client) HealthCheck() *HealthStatus {
 	} else {
 		status.IsHealthy = true
 	}
+	c.setConnected(status.IsHealthy)
 
 	status.Latency = time.Since(startTime)
 	return status
@@ -560,6 +908,16 @@ func (c *Client) Name() string {
 	return c.config.ConnectionName
 }
 
+// Raw returns the underlying driver client as an escape hatch for features
+// the wrapper does not expose yet. Operations performed through the returned handle
+// bypass this package's instrumentation and enhancements.
+// Returns nil if the client is not connected.
+func (c *Client) Raw() *mongo.Client {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.client
+}
+
 // Close gracefully closes the MongoDB connection
 func (c *Client) Close() error {
 	var closeErr error
@@ -591,6 +949,7 @@ func (c *Client) Close() error {
 		}
 
 		c.isConnected = false
+		c.closed = true
 	})
 
 	return closeErr
@@ -625,6 +984,10 @@ type ClientStats struct {
 	ServerVersion  string `json:"server_version"`
 	ReplicaSetName string `json:"replica_set_name,omitempty"`
 	IsMaster       bool   `json:"is_master"`
+
+	// Latencies summarizes operation durations per server command (e.g. "find", "update"),
+	// with p50/p95/p99 estimates from bounded histograms
+	Latencies map[string]LatencyStats `json:"latencies,omitempty"`
 }
 
 // Stats returns current client statistics and metrics
@@ -648,6 +1011,7 @@ func (c *Client) Stats() *ClientStats {
 		ServerVersion:      serverVersion,
 		ReplicaSetName:     replicaSetName,
 		IsMaster:           isMaster,
+		Latencies:          c.latency.snapshot(),
 	}
 	c.poolStats.RUnlock()
 
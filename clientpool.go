@@ -0,0 +1,204 @@
+package mongodb
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultPoolRefreshInterval is how often a ClientPool re-checks the health of its clients
+// when ClientPoolOptions.RefreshInterval is not set
+const DefaultPoolRefreshInterval = 30 * time.Second
+
+// ErrEmptyClientPool is returned by NewClientPool when no clients are given
+var ErrEmptyClientPool = errors.New("client pool requires at least one client")
+
+// ErrNoHealthyClient is returned by ClientPool.Healthiest when the last health check found
+// no healthy client
+var ErrNoHealthyClient = errors.New("no healthy client in pool")
+
+// ClientPoolOptions configures a ClientPool
+type ClientPoolOptions struct {
+	// RefreshInterval is how often the health of every client is checked in the background.
+	// Zero uses DefaultPoolRefreshInterval; a negative value disables background checks, so
+	// health is only updated by Refresh.
+	RefreshInterval time.Duration
+	// Logger receives pool events; defaults to NopLogger
+	Logger Logger
+}
+
+// ClientHealth is the last known health of a client in a ClientPool
+type ClientHealth struct {
+	Client *Client
+	Status *HealthStatus
+}
+
+// ClientPool routes reads across several clients, for example read replicas of the same data
+// in different regions, to the healthiest one: the healthy client with the lowest latency
+// reported by HealthCheck. Health is checked when the pool is created and then periodically in
+// the background, so picking a client does not add a round trip to every operation.
+//
+// The pool does not own its clients: Close stops the background checks, and the clients must
+// still be closed by the caller. Writes should keep going to the client of the primary
+// cluster, since the pool may pick a different cluster on every call.
+type ClientPool struct {
+	clients []*Client
+	logger  Logger
+
+	mu     sync.RWMutex
+	health []*HealthStatus
+
+	// check probes a client; it is Client.HealthCheck outside of tests
+	check func(*Client) *HealthStatus
+
+	ticker    *time.Ticker
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewClientPool creates a pool over the given clients and checks their health once before
+// returning. opts may be nil.
+//
+// Example:
+//
+//	pool, err := mongodb.NewClientPool([]*mongodb.Client{euClient, usClient}, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	defer pool.Close()
+//
+//	cursor, err := pool.Collection("products").Find(ctx, filter.Eq("active", true))
+func NewClientPool(clients []*Client, opts *ClientPoolOptions) (*ClientPool, error) {
+	return newClientPool(clients, opts, (*Client).HealthCheck)
+}
+
+// newClientPool creates a pool using check to probe the health of each client
+func newClientPool(clients []*Client, opts *ClientPoolOptions, check func(*Client) *HealthStatus) (*ClientPool, error) {
+	if len(clients) == 0 {
+		return nil, ErrEmptyClientPool
+	}
+	if opts == nil {
+		opts = &ClientPoolOptions{}
+	}
+
+	p := &ClientPool{
+		clients: clients,
+		logger:  opts.Logger,
+		health:  make([]*HealthStatus, len(clients)),
+		check:   check,
+		done:    make(chan struct{}),
+	}
+	if p.logger == nil {
+		p.logger = NopLogger{}
+	}
+
+	p.Refresh()
+
+	interval := opts.RefreshInterval
+	if interval == 0 {
+		interval = DefaultPoolRefreshInterval
+	}
+	if interval > 0 {
+		p.ticker = time.NewTicker(interval)
+		go p.refreshLoop()
+	}
+
+	return p, nil
+}
+
+// refreshLoop re-checks the health of the clients until the pool is closed
+func (p *ClientPool) refreshLoop() {
+	for {
+		select {
+		case <-p.ticker.C:
+			p.Refresh()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// Refresh checks the health of every client concurrently and records the results used to
+// pick the healthiest client
+func (p *ClientPool) Refresh() {
+	results := make([]*HealthStatus, len(p.clients))
+
+	var wg sync.WaitGroup
+	for i, client := range p.clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = p.check(client)
+		}()
+	}
+	wg.Wait()
+
+	for i, status := range results {
+		if !status.IsHealthy {
+			p.logger.Warn("Client pool member is unhealthy",
+				"connection_name", p.clients[i].Name(),
+				"error", status.Error)
+		}
+	}
+
+	p.mu.Lock()
+	p.health = results
+	p.mu.Unlock()
+}
+
+// Healthiest returns the healthy client with the lowest latency according to the last health
+// check, or ErrNoHealthyClient if none was healthy
+func (p *ClientPool) Healthiest() (*Client, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	best := -1
+	for i, status := range p.health {
+		if status == nil || !status.IsHealthy {
+			continue
+		}
+		if best < 0 || status.Latency < p.health[best].Latency {
+			best = i
+		}
+	}
+	if best < 0 {
+		return nil, ErrNoHealthyClient
+	}
+	return p.clients[best], nil
+}
+
+// Collection returns a handle to the named collection on the healthiest client, picked anew
+// on every call. Keep the handle for a single operation or request rather than caching it,
+// so later calls follow changes in health. If no client is healthy, the first client is
+// used and the operation reports its own connection error.
+func (p *ClientPool) Collection(name string) *Collection {
+	client, err := p.Healthiest()
+	if err != nil {
+		p.logger.Warn("No healthy client in pool, using the first client",
+			"collection", name)
+		client = p.clients[0]
+	}
+	return client.Collection(name)
+}
+
+// Health returns the last known health of every client, in the order they were given
+func (p *ClientPool) Health() []ClientHealth {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	health := make([]ClientHealth, len(p.clients))
+	for i, client := range p.clients {
+		health[i] = ClientHealth{Client: client, Status: p.health[i]}
+	}
+	return health
+}
+
+// Close stops the background health checks. It does not close the clients.
+func (p *ClientPool) Close() {
+	p.closeOnce.Do(func() {
+		if p.ticker != nil {
+			p.ticker.Stop()
+		}
+		close(p.done)
+	})
+}
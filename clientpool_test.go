@@ -0,0 +1,143 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// fakeHealth returns a health probe reporting the configured status for each connection name
+type fakeHealth struct {
+	mu       sync.Mutex
+	statuses map[string]*HealthStatus
+}
+
+func (f *fakeHealth) set(name string, healthy bool, latency time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	status := &HealthStatus{IsHealthy: healthy, Latency: latency, CheckedAt: time.Now()}
+	if !healthy {
+		status.Error = "server selection timeout"
+	}
+	f.statuses[name] = status
+}
+
+func (f *fakeHealth) check(c *Client) *HealthStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.statuses[c.Name()]
+}
+
+func TestClientPoolPicksHealthiest(t *testing.T) {
+	eu := newTestClient(WithConnectionName("eu"))
+	us := newTestClient(WithConnectionName("us"))
+	ap := newTestClient(WithConnectionName("ap"))
+
+	health := &fakeHealth{statuses: map[string]*HealthStatus{}}
+	health.set("eu", true, 40*time.Millisecond)
+	health.set("us", true, 15*time.Millisecond)
+	health.set("ap", false, time.Millisecond)
+
+	pool, err := newClientPool([]*Client{eu, us, ap}, &ClientPoolOptions{RefreshInterval: -1}, health.check)
+	if err != nil {
+		t.Fatalf("newClientPool failed: %v", err)
+	}
+	defer pool.Close()
+
+	// The unhealthy client is skipped even though it reports the lowest latency
+	if client, err := pool.Healthiest(); err != nil || client != us {
+		t.Fatalf("Expected the us client, got %v (err %v)", client, err)
+	}
+
+	// Health changes are picked up on refresh
+	health.set("us", false, 0)
+	pool.Refresh()
+	if client, err := pool.Healthiest(); err != nil || client != eu {
+		t.Errorf("Expected the eu client after us became unhealthy, got %v (err %v)", client, err)
+	}
+
+	statuses := pool.Health()
+	if len(statuses) != 3 || statuses[1].Client != us || statuses[1].Status.IsHealthy {
+		t.Errorf("Expected health in client order with us unhealthy, got %+v", statuses)
+	}
+
+	health.set("eu", false, 0)
+	pool.Refresh()
+	if _, err := pool.Healthiest(); !errors.Is(err, ErrNoHealthyClient) {
+		t.Errorf("Expected ErrNoHealthyClient, got %v", err)
+	}
+}
+
+func TestClientPoolCollection(t *testing.T) {
+	driverClient, err := mongo.Connect(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatalf("Failed to create driver client: %v", err)
+	}
+	defer func() {
+		_ = driverClient.Disconnect(context.Background())
+	}()
+
+	eu := newTestClient(WithConnectionName("eu"))
+	eu.database = driverClient.Database("shop")
+	us := newTestClient(WithConnectionName("us"))
+	us.database = driverClient.Database("shop")
+
+	health := &fakeHealth{statuses: map[string]*HealthStatus{}}
+	health.set("eu", true, 30*time.Millisecond)
+	health.set("us", true, 10*time.Millisecond)
+
+	pool, err := newClientPool([]*Client{eu, us}, &ClientPoolOptions{RefreshInterval: -1}, health.check)
+	if err != nil {
+		t.Fatalf("newClientPool failed: %v", err)
+	}
+	defer pool.Close()
+
+	if col := pool.Collection("products"); col.client != us || col.Name() != "products" {
+		t.Errorf("Expected products on the us client, got %s on %s", col.Name(), col.client.Name())
+	}
+
+	// With no healthy client the first client is used
+	health.set("eu", false, 0)
+	health.set("us", false, 0)
+	pool.Refresh()
+	if col := pool.Collection("products"); col.client != eu {
+		t.Errorf("Expected fallback to the first client, got %s", col.client.Name())
+	}
+}
+
+func TestClientPoolBackgroundRefresh(t *testing.T) {
+	eu := newTestClient(WithConnectionName("eu"))
+	us := newTestClient(WithConnectionName("us"))
+
+	health := &fakeHealth{statuses: map[string]*HealthStatus{}}
+	health.set("eu", true, 10*time.Millisecond)
+	health.set("us", true, 20*time.Millisecond)
+
+	pool, err := newClientPool([]*Client{eu, us}, &ClientPoolOptions{RefreshInterval: 5 * time.Millisecond}, health.check)
+	if err != nil {
+		t.Fatalf("newClientPool failed: %v", err)
+	}
+	defer pool.Close()
+
+	health.set("eu", false, 0)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if client, err := pool.Healthiest(); err == nil && client == us {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("Expected the background refresh to switch to the us client")
+}
+
+func TestNewClientPoolRequiresClients(t *testing.T) {
+	if _, err := NewClientPool(nil, nil); !errors.Is(err, ErrEmptyClientPool) {
+		t.Errorf("Expected ErrEmptyClientPool, got %v", err)
+	}
+}
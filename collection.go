@@ -2,7 +2,9 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"reflect"
 	"sync"
 	"time"
@@ -14,6 +16,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 )
 
 // idFieldInfo holds cached information about a struct's ID field.
@@ -46,6 +49,19 @@ type Collection struct {
 	collection *mongo.Collection
 	client     *Client
 	name       string
+
+	// softDeleteField enables soft-delete mode when non-empty (see WithSoftDelete)
+	softDeleteField string
+
+	// shardKey enables shard key validation of filters when set (see WithShardKey)
+	shardKey       bson.D
+	strictShardKey bool
+
+	// readOnly rejects write operations with ErrReadOnly (see ReadOnly)
+	readOnly bool
+
+	// normalizers rewrite field values on inserts and updates (see NormalizeField)
+	normalizers []fieldNormalizer
 }
 
 // Result types for modern API
@@ -63,6 +79,11 @@ type FindResult struct {
 // AggregateResult wraps mongo.Cursor for aggregation operations
 type AggregateResult struct {
 	cursor *mongo.Cursor
+
+	// col, pipeline and opts are kept so that Stats can explain the aggregation
+	col      *Collection
+	pipeline bson.A
+	opts     []options.Lister[options.AggregateOptions]
 }
 
 // Methods for FindOneResult
@@ -99,6 +120,26 @@ func (r *FindResult) Err() error {
 	return r.cursor.Err()
 }
 
+// Current returns the raw BSON bytes of the document the cursor is positioned on.
+// Use it after Next to read individual fields via Lookup without decoding the whole
+// document. The returned bson.Raw is only valid until the next call to Next.
+func (r *FindResult) Current() bson.Raw {
+	return r.cursor.Current
+}
+
+// TryNext attempts to advance the cursor without blocking for new data. It returns false if
+// no document is immediately available; check Err to tell an error apart from an empty batch.
+// This is mainly useful for tailable cursors, where Next would block waiting for new documents.
+func (r *FindResult) TryNext(ctx context.Context) bool {
+	return r.cursor.TryNext(ctx)
+}
+
+// RemainingBatchLength returns the number of documents left in the current batch, which can
+// be iterated without another round trip to the server. Useful for progress reporting.
+func (r *FindResult) RemainingBatchLength() int {
+	return r.cursor.RemainingBatchLength()
+}
+
 // Methods for AggregateResult
 func (r *AggregateResult) Next(ctx context.Context) bool {
 	return r.cursor.Next(ctx)
@@ -120,11 +161,36 @@ func (r *AggregateResult) Err() error {
 	return r.cursor.Err()
 }
 
+// Current returns the raw BSON bytes of the document the cursor is positioned on.
+// Use it after Next to read individual fields via Lookup without decoding the whole
+// document. The returned bson.Raw is only valid until the next call to Next.
+func (r *AggregateResult) Current() bson.Raw {
+	return r.cursor.Current
+}
+
 // Name returns the collection name
 func (col *Collection) Name() string {
 	return col.name
 }
 
+// Raw returns the underlying driver collection as an escape hatch for features
+// the wrapper does not expose yet. Operations performed through the returned handle
+// bypass this package's instrumentation (logging, operation metrics) and enhancements
+// such as automatic ULID generation.
+func (col *Collection) Raw() *mongo.Collection {
+	return col.collection
+}
+
+// checkConnected returns ErrNotConnected when the handle was created by a lazy client that
+// had no driver client yet. Such a handle stays unbound; request a new one from the client
+// once it has connected.
+func (col *Collection) checkConnected() error {
+	if col.collection == nil {
+		return ErrNotConnected
+	}
+	return nil
+}
+
 // hasID checks if a document already has an _id field without full marshal/unmarshal.
 // Returns (hasID, existingID) where existingID is only valid if hasID is true.
 func hasID(document any) (bool, any) {
@@ -319,6 +385,27 @@ func trySetULIDOnStructWithInfo(document any, result *inspectResult) (any, strin
 	return document, id, true
 }
 
+// documentHasID reports whether the caller already set an _id on the document.
+// For structs, a zero-valued ID field counts as unset.
+func documentHasID(document any) bool {
+	switch doc := document.(type) {
+	case bson.M:
+		_, exists := doc["_id"]
+		return exists
+	case map[string]any:
+		_, exists := doc["_id"]
+		return exists
+	case bson.D:
+		for _, elem := range doc {
+			if elem.Key == "_id" {
+				return true
+			}
+		}
+		return false
+	}
+	return inspectStruct(document).hasID
+}
+
 // prepareDocumentForInsert prepares a document for insertion, adding ULID if needed.
 // Returns the document to insert and the generated ID (if any).
 //
@@ -336,16 +423,7 @@ func (col *Collection) prepareDocumentForInsert(document any) (any, error) {
 	// Handle known map types first (no reflection needed)
 	switch doc := document.(type) {
 	case bson.M:
-		if _, exists := doc["_id"]; exists {
-			return document, nil
-		}
-		// Add ULID to map
-		id, err := ulid.New()
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate ULID: %w", err)
-		}
-		doc["_id"] = id
-		return doc, nil
+		return mapWithULID(document, doc)
 	case bson.D:
 		for _, elem := range doc {
 			if elem.Key == "_id" {
@@ -364,16 +442,7 @@ func (col *Collection) prepareDocumentForInsert(document any) (any, error) {
 		docMap["_id"] = id
 		return docMap, nil
 	case map[string]any:
-		if _, exists := doc["_id"]; exists {
-			return document, nil
-		}
-		// Add ULID to map
-		id, err := ulid.New()
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate ULID: %w", err)
-		}
-		doc["_id"] = id
-		return doc, nil
+		return mapWithULID(document, doc)
 	}
 
 	// For structs, use single-pass inspection
@@ -419,6 +488,23 @@ func (col *Collection) prepareDocumentForInsert(document any) (any, error) {
 	return docMap, nil
 }
 
+// mapWithULID returns the map document doc with a generated ULID _id, or the original document
+// if it already has an _id. The caller's map is never modified: the ID is added to a shallow copy,
+// which the driver marshals once like the original map.
+func mapWithULID(document any, doc map[string]any) (any, error) {
+	if _, exists := doc["_id"]; exists {
+		return document, nil
+	}
+	id, err := ulid.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ULID: %w", err)
+	}
+	docMap := make(bson.M, len(doc)+1)
+	maps.Copy(docMap, doc)
+	docMap["_id"] = id
+	return docMap, nil
+}
+
 // InsertOne inserts a single document with automatic ULID generation when IDMode is IDModeULID.
 //
 // Performance: For struct pointers with string ID fields (e.g., *User with ID string `bson:"_id"`),
@@ -427,36 +513,60 @@ func (col *Collection) prepareDocumentForInsert(document any) (any, error) {
 //
 // For non-pointer structs or non-string ID fields, the document is converted to bson.M.
 func (col *Collection) InsertOne(ctx context.Context, document any, opts ...options.Lister[options.InsertOneOptions]) (*InsertOneResult, error) {
+	if err := col.checkWritable("InsertOne"); err != nil {
+		return nil, err
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 	}
 
-	// Prepare document (add ULID if needed)
+	// Check for a caller-provided ID before preparation adds a generated one
+	callerProvidedID := documentHasID(document)
+
+	// Prepare document (add ULID if needed) before normalization converts structs, so the
+	// ID field type checks see the caller's document
 	docToInsert, err := col.prepareDocumentForInsert(document)
 	if err != nil {
 		return nil, err
 	}
+	docToInsert, err = col.normalizeFields(docToInsert)
+	if err != nil {
+		return nil, err
+	}
+	if err := col.checkDocumentSize(ctx, docToInsert, "InsertOne document"); err != nil {
+		return nil, err
+	}
+
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
 
 	result, err := col.collection.InsertOne(ctx, docToInsert, opts...)
 	if err != nil {
 		col.client.incrementFailureCount()
-		col.client.config.Logger.Error("Failed to insert document",
+		col.logger(ctx).Error("Failed to insert document",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
 	}
 
 	col.client.incrementOperationCount()
-	col.client.config.Logger.Debug("Document inserted successfully",
+	col.logger(ctx).Debug("Document inserted successfully",
 		"collection", col.name,
 		"id", result.InsertedID)
 
-	return &InsertOneResult{
-		InsertedID:  result.InsertedID,
-		GeneratedAt: time.Now(),
-	}, nil
+	insertResult := &InsertOneResult{
+		InsertedID:   result.InsertedID,
+		WasGenerated: !callerProvidedID,
+	}
+	if insertResult.WasGenerated {
+		insertResult.GeneratedAt = time.Now()
+	}
+
+	return insertResult, nil
 }
 
 // InsertMany inserts multiple documents with automatic ULID generation when IDMode is IDModeULID.
@@ -468,6 +578,10 @@ func (col *Collection) InsertOne(ctx context.Context, document any, opts ...opti
 //   - Pre-set the ID field on your structs before insertion
 //   - Use IDModeObjectID or IDModeCustom to skip ULID generation
 func (col *Collection) InsertMany(ctx context.Context, documents []any, opts ...options.Lister[options.InsertManyOptions]) (*InsertManyResult, error) {
+	if err := col.checkWritable("InsertMany"); err != nil {
+		return nil, err
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
@@ -478,12 +592,19 @@ func (col *Collection) InsertMany(ctx context.Context, documents []any, opts ...
 	processedDocs := make([]any, 0, len(documents))
 	generatedIDs := make([]any, 0, len(documents))
 
-	for _, doc := range documents {
+	for i, doc := range documents {
 		// Use prepareDocumentForInsert for consistent handling (includes safety checks)
 		preparedDoc, err := col.prepareDocumentForInsert(doc)
 		if err != nil {
 			return nil, err
 		}
+		preparedDoc, err = col.normalizeFields(preparedDoc)
+		if err != nil {
+			return nil, err
+		}
+		if err := col.checkDocumentSize(ctx, preparedDoc, fmt.Sprintf("InsertMany document %d", i)); err != nil {
+			return nil, err
+		}
 
 		// Extract the ID from the prepared document
 		_, docID := hasID(preparedDoc)
@@ -491,10 +612,18 @@ func (col *Collection) InsertMany(ctx context.Context, documents []any, opts ...
 		generatedIDs = append(generatedIDs, docID)
 	}
 
+	if err := col.client.waitForWrites(ctx, len(processedDocs)); err != nil {
+		return nil, err
+	}
+
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
 	result, err := col.collection.InsertMany(ctx, processedDocs, opts...)
 	if err != nil {
 		col.client.incrementFailureCount()
-		col.client.config.Logger.Error("Failed to insert documents",
+		col.logger(ctx).Error("Failed to insert documents",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
@@ -508,7 +637,7 @@ func (col *Collection) InsertMany(ctx context.Context, documents []any, opts ...
 	}
 
 	col.client.incrementOperationCount()
-	col.client.config.Logger.Debug("Documents inserted successfully",
+	col.logger(ctx).Debug("Documents inserted successfully",
 		"collection", col.name,
 		"count", len(processedDocs))
 
@@ -519,6 +648,32 @@ func (col *Collection) InsertMany(ctx context.Context, documents []any, opts ...
 	}, nil
 }
 
+// InsertManyWithOptions inserts documents like InsertMany with per-call WriteOptions, e.g. an
+// unacknowledged write concern for non-critical analytics data. With w:0 the result reports
+// the documents sent rather than the documents the server inserted.
+//
+// Example:
+//
+//	_, err := events.InsertManyWithOptions(ctx, batch,
+//	    &mongodb.WriteOptions{WriteConcern: writeconcern.Unacknowledged()})
+func (col *Collection) InsertManyWithOptions(ctx context.Context, documents []any, writeOpts *WriteOptions, opts ...options.Lister[options.InsertManyOptions]) (*InsertManyResult, error) {
+	return col.withWriteOptions(writeOpts).InsertMany(ctx, documents, opts...)
+}
+
+// withWriteOptions returns a collection handle applying the write options, or col itself when
+// there is nothing to override
+func (col *Collection) withWriteOptions(writeOpts *WriteOptions) *Collection {
+	if writeOpts == nil || writeOpts.WriteConcern == nil {
+		return col
+	}
+
+	clone := *col
+	if col.collection != nil {
+		clone.collection = col.collection.Clone(options.Collection().SetWriteConcern(writeOpts.WriteConcern))
+	}
+	return &clone
+}
+
 // FindOne finds a single document using a filter builder
 func (col *Collection) FindOne(ctx context.Context, filterBuilder *filter.Builder, opts ...options.Lister[options.FindOneOptions]) *FindOneResult {
 	if ctx == nil {
@@ -527,15 +682,43 @@ func (col *Collection) FindOne(ctx context.Context, filterBuilder *filter.Builde
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter document
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "FindOne"); err != nil {
+		return errorFindOneResult(err)
+	}
+	filterDoc = col.excludeSoftDeleted(filterDoc)
 
-	col.client.config.Logger.Debug("Finding document",
+	col.logger(ctx).Debug("Finding document",
 		"collection", col.name)
 
+	if err := col.checkConnected(); err != nil {
+		return errorFindOneResult(err)
+	}
+
+	if col.client.config.OperationRetryAttempts > 1 {
+		// Retries need the outcome now, so the document is fetched eagerly
+		var raw bson.Raw
+		err := col.client.runWithRetry(ctx, "FindOne", func(ctx context.Context) error {
+			var err error
+			raw, err = col.collection.FindOne(ctx, filterDoc, opts...).Raw()
+			return err
+		})
+		if err != nil {
+			return errorFindOneResult(err)
+		}
+		col.client.incrementOperationCount()
+		return &FindOneResult{
+			result: mongo.NewSingleResultFromDocument(raw, nil, nil),
+		}
+	}
+
 	result := col.collection.FindOne(ctx, filterDoc, opts...)
 
 	// Track read operation (Note: MongoDB SingleResult doesn't expose error until Decode())
@@ -554,18 +737,31 @@ func (col *Collection) Find(ctx context.Context, filterBuilder *filter.Builder,
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter document
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "Find"); err != nil {
+		return nil, err
+	}
+	filterDoc = col.excludeSoftDeleted(filterDoc)
 
-	col.client.config.Logger.Debug("Finding documents",
+	col.logger(ctx).Debug("Finding documents",
 		"collection", col.name)
 
-	cursor, err := col.collection.Find(ctx, filterDoc, opts...)
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	cursor, err := col.openCursor(ctx, "Find", func(ctx context.Context) (*mongo.Cursor, error) {
+		return col.find(ctx, col.collection, nil, filterDoc, opts)
+	})
 	if err != nil {
-		col.client.config.Logger.Error("Failed to find documents",
+		col.errorLogger(ctx, "filter", filterDoc).Error("Failed to find documents",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
@@ -590,11 +786,18 @@ func (col *Collection) FindWithOptions(ctx context.Context, filterBuilder *filte
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter document
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "Find"); err != nil {
+		return nil, err
+	}
+	filterDoc = col.excludeSoftDeleted(filterDoc)
 
 	// Convert QueryOptions to MongoDB options
 	opts := []options.Lister[options.FindOptions]{}
@@ -620,15 +823,21 @@ func (col *Collection) FindWithOptions(ctx context.Context, filterBuilder *filte
 		opts = append(opts, findOpts)
 	}
 
-	col.client.config.Logger.Debug("Finding documents with options",
+	col.logger(ctx).Debug("Finding documents with options",
 		"collection", col.name,
 		"hasSort", queryOpts != nil && len(queryOpts.Sort) > 0,
 		"limit", queryOpts != nil && queryOpts.Limit != nil && *queryOpts.Limit > 0,
 		"skip", queryOpts != nil && queryOpts.Skip != nil && *queryOpts.Skip > 0)
 
-	cursor, err := col.collection.Find(ctx, filterDoc, opts...)
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	cursor, err := col.openCursor(ctx, "Find", func(ctx context.Context) (*mongo.Cursor, error) {
+		return col.find(ctx, col.collectionFor(queryOpts), queryOpts.readPreference(), filterDoc, opts)
+	})
 	if err != nil {
-		col.client.config.Logger.Error("Failed to find documents with options",
+		col.errorLogger(ctx, "filter", filterDoc).Error("Failed to find documents with options",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
@@ -647,11 +856,18 @@ func (col *Collection) FindOneWithOptions(ctx context.Context, filterBuilder *fi
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter document
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "FindOne"); err != nil {
+		return errorFindOneResult(err)
+	}
+	filterDoc = col.excludeSoftDeleted(filterDoc)
 
 	// Convert QueryOptions to MongoDB options
 	opts := []options.Lister[options.FindOneOptions]{}
@@ -673,11 +889,15 @@ func (col *Collection) FindOneWithOptions(ctx context.Context, filterBuilder *fi
 		opts = append(opts, findOneOpts)
 	}
 
-	col.client.config.Logger.Debug("Finding one document with options",
+	col.logger(ctx).Debug("Finding one document with options",
 		"collection", col.name,
 		"hasSort", queryOpts != nil && len(queryOpts.Sort) > 0)
 
-	result := col.collection.FindOne(ctx, filterDoc, opts...)
+	if err := col.checkConnected(); err != nil {
+		return errorFindOneResult(err)
+	}
+
+	result := col.collectionFor(queryOpts).FindOne(ctx, filterDoc, opts...)
 
 	// Track read operation
 	col.client.incrementOperationCount()
@@ -687,6 +907,33 @@ func (col *Collection) FindOneWithOptions(ctx context.Context, filterBuilder *fi
 	}
 }
 
+// collectionFor returns the driver collection to run a query with, applying any
+// per-call overrides from queryOpts to a cheap clone of the underlying collection
+func (col *Collection) collectionFor(queryOpts *QueryOptions) *mongo.Collection {
+	if collOpts := queryOpts.collectionOptions(); collOpts != nil {
+		return col.collection.Clone(collOpts)
+	}
+	return col.collection
+}
+
+// collectionOptions returns the collection-level overrides requested by the query options,
+// or nil if the collection defaults should be used
+func (o *QueryOptions) collectionOptions() *options.CollectionOptionsBuilder {
+	pref := o.readPreference()
+	if pref == nil {
+		return nil
+	}
+	return options.Collection().SetReadPreference(pref)
+}
+
+// readPreference returns the per-call read preference override, or nil if there is none
+func (o *QueryOptions) readPreference() *readpref.ReadPref {
+	if o == nil {
+		return nil
+	}
+	return o.ReadPreference
+}
+
 // Convenience methods for common sort operations
 
 // FindSorted finds documents with a sort order
@@ -738,27 +985,42 @@ func (col *Collection) FindWithProjection(ctx context.Context, filterBuilder *fi
 
 // UpdateOne updates a single document
 func (col *Collection) UpdateOne(ctx context.Context, filterBuilder *filter.Builder, updateBuilder *update.Builder, opts ...options.Lister[options.UpdateOneOptions]) (*UpdateResult, error) {
+	if err := col.checkWritable("UpdateOne"); err != nil {
+		return nil, err
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter and update documents
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "UpdateOne"); err != nil {
+		return nil, err
+	}
 
 	updateDoc := bson.M{}
 	if updateBuilder != nil {
-		updateDoc = updateBuilder.Build()
+		updateDoc = col.normalizeUpdate(updateBuilder.Build())
 	}
 
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
 	result, err := col.collection.UpdateOne(ctx, filterDoc, updateDoc, opts...)
 	if err != nil {
 		col.client.incrementFailureCount()
-		col.client.config.Logger.Error("Failed to update document",
+		col.errorLogger(ctx, "filter", filterDoc, "update", updateDoc).Error("Failed to update document",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
@@ -771,9 +1033,10 @@ func (col *Collection) UpdateOne(ctx context.Context, filterBuilder *filter.Buil
 		ModifiedCount: result.ModifiedCount,
 		UpsertedCount: result.UpsertedCount,
 		UpsertedID:    result.UpsertedID,
+		Duration:      time.Since(start),
 	}
 
-	col.client.config.Logger.Debug("Document updated successfully",
+	col.logger(ctx).Debug("Document updated successfully",
 		"collection", col.name,
 		"matched", int(updateResult.MatchedCount),
 		"modified", int(updateResult.ModifiedCount))
@@ -783,26 +1046,45 @@ func (col *Collection) UpdateOne(ctx context.Context, filterBuilder *filter.Buil
 
 // UpdateMany updates multiple documents
 func (col *Collection) UpdateMany(ctx context.Context, filterBuilder *filter.Builder, updateBuilder *update.Builder, opts ...options.Lister[options.UpdateManyOptions]) (*UpdateResult, error) {
+	if err := col.checkWritable("UpdateMany"); err != nil {
+		return nil, err
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter and update documents
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "UpdateMany"); err != nil {
+		return nil, err
+	}
 
 	updateDoc := bson.M{}
 	if updateBuilder != nil {
-		updateDoc = updateBuilder.Build()
+		updateDoc = col.normalizeUpdate(updateBuilder.Build())
+	}
+
+	if err := col.client.waitForWrites(ctx, 1); err != nil {
+		return nil, err
 	}
 
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
 	result, err := col.collection.UpdateMany(ctx, filterDoc, updateDoc, opts...)
 	if err != nil {
-		col.client.config.Logger.Error("Failed to update documents",
+		col.errorLogger(ctx, "filter", filterDoc, "update", updateDoc).Error("Failed to update documents",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
@@ -813,9 +1095,10 @@ func (col *Collection) UpdateMany(ctx context.Context, filterBuilder *filter.Bui
 		ModifiedCount: result.ModifiedCount,
 		UpsertedCount: result.UpsertedCount,
 		UpsertedID:    result.UpsertedID,
+		Duration:      time.Since(start),
 	}
 
-	col.client.config.Logger.Debug("Documents updated successfully",
+	col.logger(ctx).Debug("Documents updated successfully",
 		"collection", col.name,
 		"matched", int(updateResult.MatchedCount),
 		"modified", int(updateResult.ModifiedCount))
@@ -825,21 +1108,43 @@ func (col *Collection) UpdateMany(ctx context.Context, filterBuilder *filter.Bui
 
 // ReplaceOne replaces a single document
 func (col *Collection) ReplaceOne(ctx context.Context, filterBuilder *filter.Builder, replacement any, opts ...options.Lister[options.ReplaceOptions]) (*UpdateResult, error) {
+	if err := col.checkWritable("ReplaceOne"); err != nil {
+		return nil, err
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter document
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "ReplaceOne"); err != nil {
+		return nil, err
+	}
+	replacement, err := col.normalizeFields(replacement)
+	if err != nil {
+		return nil, err
+	}
+	if err := col.checkDocumentSize(ctx, replacement, "ReplaceOne replacement"); err != nil {
+		return nil, err
+	}
 
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
 	result, err := col.collection.ReplaceOne(ctx, filterDoc, replacement, opts...)
 	if err != nil {
-		col.client.config.Logger.Error("Failed to replace document",
+		col.errorLogger(ctx, "filter", filterDoc).Error("Failed to replace document",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
@@ -850,9 +1155,10 @@ func (col *Collection) ReplaceOne(ctx context.Context, filterBuilder *filter.Bui
 		ModifiedCount: result.ModifiedCount,
 		UpsertedCount: result.UpsertedCount,
 		UpsertedID:    result.UpsertedID,
+		Duration:      time.Since(start),
 	}
 
-	col.client.config.Logger.Debug("Document replaced successfully",
+	col.logger(ctx).Debug("Document replaced successfully",
 		"collection", col.name,
 		"matched", int(updateResult.MatchedCount),
 		"modified", int(updateResult.ModifiedCount))
@@ -862,69 +1168,136 @@ func (col *Collection) ReplaceOne(ctx context.Context, filterBuilder *filter.Bui
 
 // DeleteOne deletes a single document
 func (col *Collection) DeleteOne(ctx context.Context, filterBuilder *filter.Builder, opts ...options.Lister[options.DeleteOneOptions]) (*DeleteResult, error) {
+	if err := col.checkWritable("DeleteOne"); err != nil {
+		return nil, err
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter document
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "DeleteOne"); err != nil {
+		return nil, err
+	}
+
+	if col.softDeleteField != "" {
+		resolved, err := resolveOptions(opts)
+		if err != nil {
+			return nil, err
+		}
+		return col.softDelete(ctx, filterDoc, false, softDeleteOptions{
+			Collation: resolved.Collation,
+			Comment:   resolved.Comment,
+			Hint:      resolved.Hint,
+			Let:       resolved.Let,
+		})
+	}
 
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
 	result, err := col.collection.DeleteOne(ctx, filterDoc, opts...)
 	if err != nil {
 		col.client.incrementFailureCount()
-		col.client.config.Logger.Error("Failed to delete document",
+		col.errorLogger(ctx, "filter", filterDoc).Error("Failed to delete document",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
 	}
 
 	col.client.incrementOperationCount()
-	col.client.config.Logger.Debug("Document deleted successfully",
+	col.logger(ctx).Debug("Document deleted successfully",
 		"collection", col.name,
 		"deleted", int(result.DeletedCount))
 
 	return &DeleteResult{
 		DeletedCount: result.DeletedCount,
+		Duration:     time.Since(start),
 	}, nil
 }
 
 // DeleteMany deletes multiple documents
 func (col *Collection) DeleteMany(ctx context.Context, filterBuilder *filter.Builder, opts ...options.Lister[options.DeleteManyOptions]) (*DeleteResult, error) {
+	if err := col.checkWritable("DeleteMany"); err != nil {
+		return nil, err
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter document
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "DeleteMany"); err != nil {
+		return nil, err
+	}
+
+	if col.softDeleteField != "" {
+		resolved, err := resolveOptions(opts)
+		if err != nil {
+			return nil, err
+		}
+		return col.softDelete(ctx, filterDoc, true, softDeleteOptions{
+			Collation: resolved.Collation,
+			Comment:   resolved.Comment,
+			Hint:      resolved.Hint,
+			Let:       resolved.Let,
+		})
+	}
 
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
 	result, err := col.collection.DeleteMany(ctx, filterDoc, opts...)
 	if err != nil {
-		col.client.config.Logger.Error("Failed to delete documents",
+		col.client.incrementFailureCount()
+		col.errorLogger(ctx, "filter", filterDoc).Error("Failed to delete documents",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
 	}
 
-	col.client.config.Logger.Debug("Documents deleted successfully",
+	col.client.incrementOperationCount()
+
+	col.logger(ctx).Debug("Documents deleted successfully",
 		"collection", col.name,
 		"deleted", int(result.DeletedCount))
 
 	return &DeleteResult{
 		DeletedCount: result.DeletedCount,
+		Duration:     time.Since(start),
 	}, nil
 }
 
-// CountDocuments counts documents in the collection
+// CountDocuments counts documents in the collection.
+//
+// With an empty filter and no options, the total is read from the collection metadata
+// (estimatedDocumentCount) rather than counted, which is much faster on large collections but
+// can be stale after an unclean shutdown or include orphaned documents on sharded clusters.
+// Soft-delete handles, calls in a session, and clients configured with WithPreciseCount
+// always count exactly.
 func (col *Collection) CountDocuments(ctx context.Context, filterBuilder *filter.Builder, opts ...options.Lister[options.CountOptions]) (int64, error) {
 	if ctx == nil {
 		var cancel context.CancelFunc
@@ -932,27 +1305,59 @@ func (col *Collection) CountDocuments(ctx context.Context, filterBuilder *filter
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter document
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "CountDocuments"); err != nil {
+		return 0, err
+	}
+	filterDoc = col.excludeSoftDeleted(filterDoc)
+
+	estimate := col.useEstimatedCount(ctx, filterDoc, len(opts) > 0)
+
+	if err := col.checkConnected(); err != nil {
+		return 0, err
+	}
 
-	count, err := col.collection.CountDocuments(ctx, filterDoc, opts...)
+	var count int64
+	err := col.client.runWithRetry(ctx, "CountDocuments", func(ctx context.Context) error {
+		var err error
+		if estimate {
+			count, err = col.collection.EstimatedDocumentCount(ctx)
+		} else {
+			count, err = col.collection.CountDocuments(ctx, filterDoc, opts...)
+		}
+		return err
+	})
 	if err != nil {
-		col.client.config.Logger.Error("Failed to count documents",
+		col.errorLogger(ctx, "filter", filterDoc).Error("Failed to count documents",
 			"error", err.Error(),
 			"collection", col.name)
 		return 0, err
 	}
 
-	col.client.config.Logger.Debug("Documents counted successfully",
+	col.logger(ctx).Debug("Documents counted successfully",
 		"collection", col.name,
 		"count", int(count))
 
 	return count, nil
 }
 
+// useEstimatedCount reports whether CountDocuments can answer from collection metadata: the
+// filter is empty, no count options were given, PreciseCount is off, and the call is not part
+// of a session (estimatedDocumentCount cannot run in a transaction)
+func (col *Collection) useEstimatedCount(ctx context.Context, filterDoc bson.M, hasOpts bool) bool {
+	return len(filterDoc) == 0 &&
+		!hasOpts &&
+		!col.client.config.PreciseCount &&
+		mongo.SessionFromContext(ctx) == nil
+}
+
 // Distinct returns distinct values for a field
 func (col *Collection) Distinct(ctx context.Context, fieldName string, filterBuilder *filter.Builder, opts ...options.Lister[options.DistinctOptions]) ([]any, error) {
 	if ctx == nil {
@@ -961,27 +1366,40 @@ func (col *Collection) Distinct(ctx context.Context, fieldName string, filterBui
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter document
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "Distinct"); err != nil {
+		return nil, err
+	}
+	filterDoc = col.excludeSoftDeleted(filterDoc)
 
-	result := col.collection.Distinct(ctx, fieldName, filterDoc, opts...)
-	if result.Err() != nil {
-		col.client.config.Logger.Error("Failed to get distinct values",
-			"error", result.Err().Error(),
-			"collection", col.name,
-			"field", fieldName)
-		return nil, result.Err()
+	if err := col.checkConnected(); err != nil {
+		return nil, err
 	}
 
 	var values []any
-	if err := result.Decode(&values); err != nil {
+	err := col.client.runWithRetry(ctx, "Distinct", func(ctx context.Context) error {
+		result := col.collection.Distinct(ctx, fieldName, filterDoc, opts...)
+		if err := result.Err(); err != nil {
+			return err
+		}
+		return result.Decode(&values)
+	})
+	if err != nil {
+		col.errorLogger(ctx, "filter", filterDoc).Error("Failed to get distinct values",
+			"error", err.Error(),
+			"collection", col.name,
+			"field", fieldName)
 		return nil, err
 	}
 
-	col.client.config.Logger.Debug("Distinct values retrieved successfully",
+	col.logger(ctx).Debug("Distinct values retrieved successfully",
 		"collection", col.name,
 		"field", fieldName,
 		"count", len(values))
@@ -989,6 +1407,93 @@ func (col *Collection) Distinct(ctx context.Context, fieldName string, filterBui
 	return values, nil
 }
 
+// DistinctCount returns the number of distinct values of a field among the documents matching
+// the filter. It counts on the server with $group and $count instead of transferring every
+// distinct value like Distinct does. As with Distinct, array elements are counted individually.
+// Documents where the field is missing, null or an empty array are not counted.
+func (col *Collection) DistinctCount(ctx context.Context, fieldName string, filterBuilder *filter.Builder) (int64, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
+	// Build filter document
+	filterDoc := bson.M{}
+	if filterBuilder != nil {
+		filterDoc = filterBuilder.Build()
+	}
+	if err := col.checkShardKey(ctx, filterDoc, "DistinctCount"); err != nil {
+		return 0, err
+	}
+	filterDoc = col.excludeSoftDeleted(filterDoc)
+
+	var count int64
+	err := col.client.runWithRetry(ctx, "DistinctCount", func(ctx context.Context) error {
+		var err error
+		count, err = col.distinctCount(ctx, fieldName, filterDoc)
+		return err
+	})
+	if err != nil {
+		col.client.incrementFailureCount()
+		col.errorLogger(ctx, "filter", filterDoc).Error("Failed to count distinct values",
+			"error", err.Error(),
+			"collection", col.name,
+			"field", fieldName)
+		return 0, err
+	}
+
+	col.client.incrementOperationCount()
+	col.logger(ctx).Debug("Distinct values counted successfully",
+		"collection", col.name,
+		"field", fieldName,
+		"count", count)
+
+	return count, nil
+}
+
+// distinctCount runs the distinct count aggregation once
+func (col *Collection) distinctCount(ctx context.Context, fieldName string, filterDoc bson.M) (int64, error) {
+	if err := col.checkConnected(); err != nil {
+		return 0, err
+	}
+
+	cursor, err := col.collection.Aggregate(ctx, distinctCountPipeline(fieldName, filterDoc))
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	// $count emits no document when nothing matched
+	var result struct {
+		Count int64 `bson:"count"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return 0, fmt.Errorf("failed to decode distinct count: %w", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+	return result.Count, nil
+}
+
+// distinctCountPipeline builds the aggregation used by DistinctCount
+func distinctCountPipeline(fieldName string, filterDoc bson.M) bson.A {
+	return pipeline.New().
+		MatchRaw(filterDoc).
+		Unwind("$" + fieldName).
+		Group("$"+fieldName, nil).
+		Count("count").
+		ToBSONArray()
+}
+
 // Aggregate performs an aggregation operation
 func (col *Collection) Aggregate(ctx context.Context, pipeline any, opts ...options.Lister[options.AggregateOptions]) (*mongo.Cursor, error) {
 	if ctx == nil {
@@ -997,15 +1502,28 @@ func (col *Collection) Aggregate(ctx context.Context, pipeline any, opts ...opti
 		defer cancel()
 	}
 
-	cursor, err := col.collection.Aggregate(ctx, pipeline, opts...)
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
+	if err := col.checkAggregateWritable(pipeline); err != nil {
+		return nil, err
+	}
+
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	cursor, err := col.openCursor(ctx, "Aggregate", func(ctx context.Context) (*mongo.Cursor, error) {
+		return col.collection.Aggregate(ctx, pipeline, col.aggregateMaxTimeOptions(ctx, opts)...)
+	})
 	if err != nil {
-		col.client.config.Logger.Error("Failed to aggregate",
+		col.errorLogger(ctx, "pipeline", pipeline).Error("Failed to aggregate",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
 	}
 
-	col.client.config.Logger.Debug("Aggregation started successfully",
+	col.logger(ctx).Debug("Aggregation started successfully",
 		"collection", col.name)
 
 	return cursor, nil
@@ -1019,19 +1537,34 @@ func (col *Collection) AggregateWithPipeline(ctx context.Context, pipelineBuilde
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build pipeline
 	pipelineDoc := bson.A{}
 	if pipelineBuilder != nil {
+		if err := pipelineBuilder.Validate(); err != nil {
+			return nil, err
+		}
 		pipelineDoc = pipelineBuilder.ToBSONArray()
 	}
+	if err := col.checkAggregateWritable(pipelineDoc); err != nil {
+		return nil, err
+	}
 
-	col.client.config.Logger.Debug("Aggregating with pipeline builder",
+	col.logger(ctx).Debug("Aggregating with pipeline builder",
 		"collection", col.name,
 		"stages", len(pipelineDoc))
 
-	cursor, err := col.collection.Aggregate(ctx, pipelineDoc, opts...)
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	cursor, err := col.openCursor(ctx, "Aggregate", func(ctx context.Context) (*mongo.Cursor, error) {
+		return col.collection.Aggregate(ctx, pipelineDoc, col.aggregateMaxTimeOptions(ctx, opts)...)
+	})
 	if err != nil {
-		col.client.config.Logger.Error("Failed to aggregate with pipeline",
+		col.errorLogger(ctx, "pipeline", pipelineDoc).Error("Failed to aggregate with pipeline",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
@@ -1040,10 +1573,50 @@ func (col *Collection) AggregateWithPipeline(ctx context.Context, pipelineBuilde
 	col.client.incrementOperationCount()
 
 	return &AggregateResult{
-		cursor: cursor,
+		cursor:   cursor,
+		col:      col,
+		pipeline: pipelineDoc,
+		opts:     opts,
 	}, nil
 }
 
+// AggregateWithOptions performs an aggregation using a pipeline builder and AggregateOptions.
+// Pipeline variables defined in Let can be referenced as $$name, which allows reusing
+// a pipeline with different parameters:
+//
+//	p := pipeline.New().MatchRaw(bson.M{"$expr": bson.M{"$gte": bson.A{"$total", "$$minTotal"}}})
+//	result, err := col.AggregateWithOptions(ctx, p, &AggregateOptions{Let: bson.M{"minTotal": 100}})
+//
+// Additional driver options are applied after aggOpts and take precedence.
+func (col *Collection) AggregateWithOptions(ctx context.Context, pipelineBuilder *pipeline.Builder, aggOpts *AggregateOptions, opts ...options.Lister[options.AggregateOptions]) (*AggregateResult, error) {
+	allOpts := make([]options.Lister[options.AggregateOptions], 0, len(opts)+1)
+	if aggOpts != nil {
+		allOpts = append(allOpts, aggOpts.toDriverOptions())
+	}
+	allOpts = append(allOpts, opts...)
+
+	return col.AggregateWithPipeline(ctx, pipelineBuilder, allOpts...)
+}
+
+// toDriverOptions converts AggregateOptions to MongoDB driver options
+func (o *AggregateOptions) toDriverOptions() *options.AggregateOptionsBuilder {
+	aggOpts := options.Aggregate()
+
+	if len(o.Let) > 0 {
+		aggOpts.SetLet(o.Let)
+	}
+
+	if o.AllowDiskUse {
+		aggOpts.SetAllowDiskUse(true)
+	}
+
+	if o.BatchSize != nil && *o.BatchSize > 0 {
+		aggOpts.SetBatchSize(*o.BatchSize)
+	}
+
+	return aggOpts
+}
+
 // Indexes returns the index operations for this collection
 func (col *Collection) Indexes() mongo.IndexView {
 	return col.collection.Indexes()
@@ -1053,6 +1626,10 @@ func (col *Collection) Indexes() mongo.IndexView {
 // This eliminates the need to import mongo-driver directly for index operations.
 // Use helper functions like IndexAsc(), IndexDesc(), IndexUnique(), IndexText() to create IndexModel.
 func (col *Collection) CreateIndex(ctx context.Context, model IndexModel, opts ...options.Lister[options.CreateIndexesOptions]) (string, error) {
+	if err := col.checkWritable("CreateIndex"); err != nil {
+		return "", err
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
@@ -1065,15 +1642,19 @@ func (col *Collection) CreateIndex(ctx context.Context, model IndexModel, opts .
 		Options: model.Options,
 	}
 
+	if err := col.checkConnected(); err != nil {
+		return "", err
+	}
+
 	name, err := col.collection.Indexes().CreateOne(ctx, mongoModel, opts...)
 	if err != nil {
-		col.client.config.Logger.Error("Failed to create index",
+		col.logger(ctx).Error("Failed to create index",
 			"error", err.Error(),
 			"collection", col.name)
 		return "", err
 	}
 
-	col.client.config.Logger.Debug("Index created successfully",
+	col.logger(ctx).Debug("Index created successfully",
 		"collection", col.name,
 		"index", name)
 
@@ -1084,6 +1665,10 @@ func (col *Collection) CreateIndex(ctx context.Context, model IndexModel, opts .
 // This eliminates the need to import mongo-driver directly for index operations.
 // Use helper functions like IndexAsc(), IndexDesc(), IndexUnique(), IndexText() to create IndexModel.
 func (col *Collection) CreateIndexes(ctx context.Context, models []IndexModel, opts ...options.Lister[options.CreateIndexesOptions]) ([]string, error) {
+	if err := col.checkWritable("CreateIndexes"); err != nil {
+		return nil, err
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
@@ -1099,39 +1684,84 @@ func (col *Collection) CreateIndexes(ctx context.Context, models []IndexModel, o
 		}
 	}
 
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
 	names, err := col.collection.Indexes().CreateMany(ctx, mongoModels, opts...)
 	if err != nil {
-		col.client.config.Logger.Error("Failed to create indexes",
+		col.logger(ctx).Error("Failed to create indexes",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
 	}
 
-	col.client.config.Logger.Debug("Indexes created successfully",
+	col.logger(ctx).Debug("Indexes created successfully",
 		"collection", col.name,
 		"count", len(names))
 
 	return names, nil
 }
 
+// Drop drops the collection and its indexes.
+// Dropping a collection that does not exist succeeds, so the call is safe to retry.
+func (col *Collection) Drop(ctx context.Context, opts ...options.Lister[options.DropCollectionOptions]) error {
+	if err := col.checkWritable("Drop"); err != nil {
+		return err
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	if err := col.checkConnected(); err != nil {
+		return err
+	}
+
+	err := ignoreNamespaceNotFound(col.collection.Drop(ctx, opts...))
+	if err != nil {
+		col.client.incrementFailureCount()
+		col.logger(ctx).Error("Failed to drop collection",
+			"error", err.Error(),
+			"collection", col.name)
+		return err
+	}
+
+	col.client.incrementOperationCount()
+	col.logger(ctx).Debug("Collection dropped successfully",
+		"collection", col.name)
+
+	return nil
+}
+
 // DropIndex drops a single index
 func (col *Collection) DropIndex(ctx context.Context, name string, opts ...options.Lister[options.DropIndexesOptions]) error {
+	if err := col.checkWritable("DropIndex"); err != nil {
+		return err
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 	}
 
+	if err := col.checkConnected(); err != nil {
+		return err
+	}
+
 	err := col.collection.Indexes().DropOne(ctx, name, opts...)
 	if err != nil {
-		col.client.config.Logger.Error("Failed to drop index",
+		col.logger(ctx).Error("Failed to drop index",
 			"error", err.Error(),
 			"collection", col.name,
 			"index", name)
 		return err
 	}
 
-	col.client.config.Logger.Debug("Index dropped successfully",
+	col.logger(ctx).Debug("Index dropped successfully",
 		"collection", col.name,
 		"index", name)
 
@@ -1146,15 +1776,19 @@ func (col *Collection) ListIndexes(ctx context.Context, opts ...options.Lister[o
 		defer cancel()
 	}
 
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
 	cursor, err := col.collection.Indexes().List(ctx, opts...)
 	if err != nil {
-		col.client.config.Logger.Error("Failed to list indexes",
+		col.logger(ctx).Error("Failed to list indexes",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
 	}
 
-	col.client.config.Logger.Debug("Indexes listed successfully",
+	col.logger(ctx).Debug("Indexes listed successfully",
 		"collection", col.name)
 
 	return cursor, nil
@@ -1168,15 +1802,19 @@ func (col *Collection) Watch(ctx context.Context, pipeline any, opts ...options.
 		defer cancel()
 	}
 
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
 	stream, err := col.collection.Watch(ctx, pipeline, opts...)
 	if err != nil {
-		col.client.config.Logger.Error("Failed to create change stream",
+		col.logger(ctx).Error("Failed to create change stream",
 			"error", err.Error(),
 			"collection", col.name)
 		return nil, err
 	}
 
-	col.client.config.Logger.Debug("Change stream created successfully",
+	col.logger(ctx).Debug("Change stream created successfully",
 		"collection", col.name)
 
 	return stream, nil
@@ -1201,6 +1839,9 @@ func (col *Collection) UpsertByField(ctx context.Context, field string, value an
 	if err != nil {
 		return nil, fmt.Errorf("failed to create update builder: %w", err)
 	}
+	if len(updateBuilder.Build()) == 0 {
+		return nil, ErrEmptyUpsert
+	}
 
 	// Enable upsert
 	opts := options.UpdateOne().SetUpsert(true)
@@ -1228,11 +1869,20 @@ func (col *Collection) UpsertByFieldMap(ctx context.Context, field string, value
 	return col.UpdateOne(ctx, filterBuilder, updateBuilder, opts)
 }
 
+// ErrEmptyUpsert is returned by UpsertByFieldWithOptions with OmitZero when every field of the
+// document is a zero value, which would leave nothing to write
+var ErrEmptyUpsert = errors.New("upsert document has no non-zero fields")
+
 // UpsertOptions provides configuration for upsert operations
 type UpsertOptions struct {
 	// OnlyInsert when true, ensures existing documents are never modified
 	// This is the default behavior when using $setOnInsert
 	OnlyInsert bool
+
+	// OmitZero when true, leaves zero-value fields of the document out of the update
+	// (see update.SetOnInsertStructNonZero and update.SetStructNonZero). A document whose
+	// fields are all zero fails with ErrEmptyUpsert.
+	OmitZero bool
 }
 
 // UpsertByFieldWithOptions performs an atomic upsert with additional configuration options
@@ -1252,16 +1902,24 @@ func (col *Collection) UpsertByFieldWithOptions(ctx context.Context, field strin
 
 	var updateBuilder *update.Builder
 	var err error
-	if upsertOpts.OnlyInsert {
+	switch {
+	case upsertOpts.OnlyInsert && upsertOpts.OmitZero:
+		updateBuilder, err = update.New().SetOnInsertStructNonZero(document)
+	case upsertOpts.OnlyInsert:
 		// Use $setOnInsert to ensure existing documents are not modified
 		updateBuilder, err = update.New().SetOnInsertStruct(document)
-	} else {
+	case upsertOpts.OmitZero:
+		updateBuilder, err = update.New().SetStructNonZero(document)
+	default:
 		// Use $set to update existing documents as well
 		updateBuilder, err = update.New().SetStruct(document)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create update builder: %w", err)
 	}
+	if len(updateBuilder.Build()) == 0 {
+		return nil, ErrEmptyUpsert
+	}
 
 	// Enable upsert
 	opts := options.UpdateOne().SetUpsert(true)
@@ -1269,6 +1927,69 @@ func (col *Collection) UpsertByFieldWithOptions(ctx context.Context, field strin
 	return col.UpdateOne(ctx, filterBuilder, updateBuilder, opts)
 }
 
+// UpsertAction reports what an Upsert did to the collection
+type UpsertAction int
+
+const (
+	// UpsertUnchanged means a document matched but the update left it as it was
+	UpsertUnchanged UpsertAction = iota
+	// UpsertUpdated means an existing document matched and was modified
+	UpsertUpdated
+	// UpsertInserted means no document matched and a new one was inserted
+	UpsertInserted
+)
+
+// String returns the action name: "unchanged", "updated" or "inserted"
+func (a UpsertAction) String() string {
+	switch a {
+	case UpsertInserted:
+		return "inserted"
+	case UpsertUpdated:
+		return "updated"
+	default:
+		return "unchanged"
+	}
+}
+
+// UpsertOutcome describes the result of an Upsert
+type UpsertOutcome struct {
+	Action UpsertAction `json:"action"`
+	// UpsertedID is the _id of the inserted document; nil unless Action is UpsertInserted
+	UpsertedID any `json:"upserted_id,omitempty"`
+}
+
+// Upsert updates the document matching the filter, inserting one if none matches, and
+// reports whether the document was inserted, updated or left unchanged.
+//
+// Example:
+//
+//	outcome, err := col.Upsert(ctx, filter.Eq("external_id", event.ID), update.SetMap(fields))
+//	if err != nil {
+//	    return err
+//	}
+//	if outcome.Action == mongodb.UpsertInserted {
+//	    notifyCreated(outcome.UpsertedID)
+//	}
+func (col *Collection) Upsert(ctx context.Context, filterBuilder *filter.Builder, updateBuilder *update.Builder) (UpsertOutcome, error) {
+	result, err := col.UpdateOne(ctx, filterBuilder, updateBuilder, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return UpsertOutcome{}, err
+	}
+	return upsertOutcome(result), nil
+}
+
+// upsertOutcome classifies the result of an upserting UpdateOne
+func upsertOutcome(result *UpdateResult) UpsertOutcome {
+	switch {
+	case result.UpsertedCount > 0 || result.UpsertedID != nil:
+		return UpsertOutcome{Action: UpsertInserted, UpsertedID: result.UpsertedID}
+	case result.ModifiedCount > 0:
+		return UpsertOutcome{Action: UpsertUpdated}
+	default:
+		return UpsertOutcome{Action: UpsertUnchanged}
+	}
+}
+
 // ReturnDocument specifies when to capture the document for FindOneAnd* operations.
 type ReturnDocument int
 
@@ -1398,22 +2119,33 @@ func (o *FindOneAndDeleteOptions) SetProjection(projection bson.D) *FindOneAndDe
 // either the original or the modified document based on options.
 // This is essential for atomic operations like counters, reservations, and queue processing.
 func (col *Collection) FindOneAndUpdate(ctx context.Context, filterBuilder *filter.Builder, updateBuilder *update.Builder, opts ...*FindOneAndUpdateOptions) *FindOneResult {
+	if err := col.checkWritable("FindOneAndUpdate"); err != nil {
+		return errorFindOneResult(err)
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter document
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "FindOneAndUpdate"); err != nil {
+		return errorFindOneResult(err)
+	}
+	filterDoc = col.excludeSoftDeleted(filterDoc)
 
 	// Build update document
 	updateDoc := bson.M{}
 	if updateBuilder != nil {
-		updateDoc = updateBuilder.Build()
+		updateDoc = col.normalizeUpdate(updateBuilder.Build())
 	}
 
 	// Convert our options to mongo driver options
@@ -1441,9 +2173,13 @@ func (col *Collection) FindOneAndUpdate(ctx context.Context, filterBuilder *filt
 		}
 	}
 
-	col.client.config.Logger.Debug("FindOneAndUpdate",
+	col.logger(ctx).Debug("FindOneAndUpdate",
 		"collection", col.name)
 
+	if err := col.checkConnected(); err != nil {
+		return errorFindOneResult(err)
+	}
+
 	result := col.collection.FindOneAndUpdate(ctx, filterDoc, updateDoc, driverOpts)
 
 	col.client.incrementOperationCount()
@@ -1456,17 +2192,33 @@ func (col *Collection) FindOneAndUpdate(ctx context.Context, filterBuilder *filt
 // FindOneAndReplace atomically finds a document, replaces it, and returns
 // either the original or the replacement document based on options.
 func (col *Collection) FindOneAndReplace(ctx context.Context, filterBuilder *filter.Builder, replacement any, opts ...*FindOneAndReplaceOptions) *FindOneResult {
+	if err := col.checkWritable("FindOneAndReplace"); err != nil {
+		return errorFindOneResult(err)
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter document
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "FindOneAndReplace"); err != nil {
+		return errorFindOneResult(err)
+	}
+	filterDoc = col.excludeSoftDeleted(filterDoc)
+
+	replacement, err := col.normalizeFields(replacement)
+	if err != nil {
+		return errorFindOneResult(err)
+	}
 
 	// Convert our options to mongo driver options
 	driverOpts := options.FindOneAndReplace()
@@ -1493,9 +2245,13 @@ func (col *Collection) FindOneAndReplace(ctx context.Context, filterBuilder *fil
 		}
 	}
 
-	col.client.config.Logger.Debug("FindOneAndReplace",
+	col.logger(ctx).Debug("FindOneAndReplace",
 		"collection", col.name)
 
+	if err := col.checkConnected(); err != nil {
+		return errorFindOneResult(err)
+	}
+
 	result := col.collection.FindOneAndReplace(ctx, filterDoc, replacement, driverOpts)
 
 	col.client.incrementOperationCount()
@@ -1507,18 +2263,30 @@ func (col *Collection) FindOneAndReplace(ctx context.Context, filterBuilder *fil
 
 // FindOneAndDelete atomically finds a document and deletes it, returning the deleted document.
 // This is useful for queue-like operations where you need to atomically claim and remove an item.
+// In soft-delete mode the document is marked as deleted instead, and returned as it was before.
 func (col *Collection) FindOneAndDelete(ctx context.Context, filterBuilder *filter.Builder, opts ...*FindOneAndDeleteOptions) *FindOneResult {
+	if err := col.checkWritable("FindOneAndDelete"); err != nil {
+		return errorFindOneResult(err)
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 	}
 
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
 	// Build filter document
 	filterDoc := bson.M{}
 	if filterBuilder != nil {
 		filterDoc = filterBuilder.Build()
 	}
+	if err := col.checkShardKey(ctx, filterDoc, "FindOneAndDelete"); err != nil {
+		return errorFindOneResult(err)
+	}
+	filterDoc = col.excludeSoftDeleted(filterDoc)
 
 	// Convert our options to mongo driver options
 	driverOpts := options.FindOneAndDelete()
@@ -1535,9 +2303,17 @@ func (col *Collection) FindOneAndDelete(ctx context.Context, filterBuilder *filt
 		}
 	}
 
-	col.client.config.Logger.Debug("FindOneAndDelete",
+	col.logger(ctx).Debug("FindOneAndDelete",
 		"collection", col.name)
 
+	if col.softDeleteField != "" {
+		return col.softFindOneAndDelete(ctx, filterDoc, driverOpts)
+	}
+
+	if err := col.checkConnected(); err != nil {
+		return errorFindOneResult(err)
+	}
+
 	result := col.collection.FindOneAndDelete(ctx, filterDoc, driverOpts)
 
 	col.client.incrementOperationCount()
@@ -1608,12 +2384,118 @@ func (col *Collection) UpdateByID(ctx context.Context, id any, updateBuilder *up
 	return col.UpdateOne(ctx, filter.Eq("_id", id), updateBuilder)
 }
 
+// UpdateByIDReturning updates a single document by its _id field and returns the document as
+// it is after the update, in one atomic FindOneAndUpdate. When no document has the id, the
+// result's Err (and Decode) returns mongo.ErrNoDocuments, which IsNotFoundError recognizes.
+//
+// Example:
+//
+//	var user User
+//	err := users.UpdateByIDReturning(ctx, id, update.Set("name", req.Name)).Decode(&user)
+//	if mongodb.IsNotFoundError(err) {
+//	    return c.NoContent(http.StatusNotFound)
+//	}
+func (col *Collection) UpdateByIDReturning(ctx context.Context, id any, updateBuilder *update.Builder) *FindOneResult {
+	return col.FindOneAndUpdate(ctx, filter.Eq("_id", id), updateBuilder,
+		FindOneAndUpdateOpts().SetReturnDocument(ReturnAfter))
+}
+
 // DeleteByID deletes a single document by its _id field.
 // This is a convenience method that works with any ID type (ULID string, ObjectID, etc.).
 func (col *Collection) DeleteByID(ctx context.Context, id any) (*DeleteResult, error) {
 	return col.DeleteOne(ctx, filter.Eq("_id", id))
 }
 
+// ErrNotFound is returned by the *Required methods when no document matches the filter
+var ErrNotFound = errors.New("no document matched the filter")
+
+// UpdateOneRequired updates a single document like UpdateOne, but returns ErrNotFound when no
+// document matches the filter instead of a result with MatchedCount 0. A matched document that
+// is left unchanged by the update is not an error.
+func (col *Collection) UpdateOneRequired(ctx context.Context, filterBuilder *filter.Builder, updateBuilder *update.Builder, opts ...options.Lister[options.UpdateOneOptions]) error {
+	result, err := col.UpdateOne(ctx, filterBuilder, updateBuilder, opts...)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 && result.UpsertedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteOneRequired deletes a single document like DeleteOne, but returns ErrNotFound when no
+// document matches the filter instead of a result with DeletedCount 0.
+func (col *Collection) DeleteOneRequired(ctx context.Context, filterBuilder *filter.Builder, opts ...options.Lister[options.DeleteOneOptions]) error {
+	result, err := col.DeleteOne(ctx, filterBuilder, opts...)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// =============================================================================
+// Optimistic Concurrency
+// =============================================================================
+
+// VersionField is the document field used by UpdateWithVersion for optimistic concurrency control
+const VersionField = "version"
+
+// ErrVersionConflict is the sentinel matched (via errors.Is) by VersionConflictError
+var ErrVersionConflict = errors.New("version conflict")
+
+// VersionConflictError is returned by UpdateWithVersion when no document with the given ID
+// has the expected version, meaning it was modified concurrently (or does not exist).
+type VersionConflictError struct {
+	ID              string
+	ExpectedVersion int64
+}
+
+// Error implements the error interface
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict: document %q is not at version %d", e.ID, e.ExpectedVersion)
+}
+
+// Unwrap allows errors.Is(err, ErrVersionConflict)
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
+
+// UpdateWithVersion performs a compare-and-set update for safe read-modify-write cycles.
+// The update only applies if the document's version field equals expectedVersion, and it
+// atomically increments the version by 1. If no document matches, a *VersionConflictError
+// is returned so callers can reload the document and retry.
+//
+// Example:
+//
+//	_, err := col.UpdateWithVersion(ctx, doc.ID, doc.Version, update.Set("status", "shipped"))
+//	if errors.Is(err, mongodb.ErrVersionConflict) {
+//	    // reload and retry
+//	}
+func (col *Collection) UpdateWithVersion(ctx context.Context, id string, expectedVersion int64, updateBuilder *update.Builder) (*UpdateResult, error) {
+	filterBuilder := filter.Eq("_id", id).And(filter.Eq(VersionField, expectedVersion))
+
+	// Copy the caller's update so reused builders are not modified
+	versionedUpdate := update.New().And(updateBuilder).Inc(VersionField, 1)
+
+	result, err := col.UpdateOne(ctx, filterBuilder, versionedUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.MatchedCount == 0 {
+		col.logger(ctx).Debug("Version conflict on update",
+			"collection", col.name,
+			"id", id,
+			"expected_version", expectedVersion)
+		return nil, &VersionConflictError{ID: id, ExpectedVersion: expectedVersion}
+	}
+
+	return result, nil
+}
+
 // =============================================================================
 // BulkWrite
 // =============================================================================
@@ -1638,6 +2520,10 @@ func (col *Collection) DeleteByID(ctx context.Context, id any) (*DeleteResult, e
 //	    mongo.NewDeleteOneModel().SetFilter(filter.Eq("name", "Charlie").Build()),
 //	})
 func (col *Collection) BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...options.Lister[options.BulkWriteOptions]) (*BulkWriteResult, error) {
+	if err := col.checkWritable("BulkWrite"); err != nil {
+		return nil, err
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
@@ -1679,18 +2565,33 @@ func (col *Collection) BulkWrite(ctx context.Context, models []mongo.WriteModel,
 		}
 	}
 
+	if err := col.client.waitForWrites(ctx, len(models)); err != nil {
+		return nil, err
+	}
+
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
 	result, err := col.collection.BulkWrite(ctx, models, opts...)
 	if err != nil {
 		col.client.incrementFailureCount()
-		col.client.config.Logger.Error("BulkWrite failed",
+		col.logger(ctx).Error("BulkWrite failed",
 			"error", err.Error(),
 			"collection", col.name,
 			"models", len(models))
 		return nil, err
 	}
 
+	// The driver reports upserts keyed by model index; keep the map non-nil so callers can
+	// look up any index without a nil check
+	upsertedIDs := result.UpsertedIDs
+	if upsertedIDs == nil {
+		upsertedIDs = make(map[int64]any)
+	}
+
 	col.client.incrementOperationCount()
-	col.client.config.Logger.Debug("BulkWrite completed",
+	col.logger(ctx).Debug("BulkWrite completed",
 		"collection", col.name,
 		"inserted", result.InsertedCount,
 		"matched", result.MatchedCount,
@@ -1704,8 +2605,20 @@ func (col *Collection) BulkWrite(ctx context.Context, models []mongo.WriteModel,
 		ModifiedCount: result.ModifiedCount,
 		DeletedCount:  result.DeletedCount,
 		UpsertedCount: result.UpsertedCount,
-		UpsertedIDs:   result.UpsertedIDs,
+		UpsertedIDs:   upsertedIDs,
 		InsertedIDs:   insertedIDs,
 		Acknowledged:  result.Acknowledged,
 	}, nil
 }
+
+// BulkWriteWithOptions executes a batch of write operations like BulkWrite with per-call
+// WriteOptions. With an unacknowledged write concern the result has Acknowledged set to false
+// and all counts are zero, since the server does not report them.
+//
+// Example:
+//
+//	result, err := col.BulkWriteWithOptions(ctx, models,
+//	    &mongodb.WriteOptions{WriteConcern: writeconcern.Unacknowledged()})
+func (col *Collection) BulkWriteWithOptions(ctx context.Context, models []mongo.WriteModel, writeOpts *WriteOptions, opts ...options.Lister[options.BulkWriteOptions]) (*BulkWriteResult, error) {
+	return col.withWriteOptions(writeOpts).BulkWrite(ctx, models, opts...)
+}
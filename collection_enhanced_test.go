@@ -2,11 +2,13 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/cloudresty/go-mongodb/v2/filter"
 	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"github.com/cloudresty/go-mongodb/v2/update"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -23,6 +25,31 @@ func setupTestClientForEnhanced(t *testing.T) *Client {
 	return client
 }
 
+// newTestClient returns a client without a driver connection for unit tests, configured by
+// opts on top of a NopLogger; operations that reach the driver fail or panic
+func newTestClient(opts ...Option) *Client {
+	config := &Config{Logger: NopLogger{}}
+	for _, opt := range opts {
+		opt(config)
+	}
+	client := &Client{config: config}
+	client.poolStats.connStates = make(map[int64]string)
+	return client
+}
+
+// newTestCollection returns a collection of a newTestClient without a driver collection, so
+// unit tests can exercise the wrapper logic in front of the driver
+func newTestCollection(name string, opts ...Option) *Collection {
+	return &Collection{name: name, client: newTestClient(opts...)}
+}
+
+// withIDMode sets the ID generation mode of a test client
+func withIDMode(mode IDMode) Option {
+	return func(c *Config) {
+		c.IDMode = mode
+	}
+}
+
 func TestFindWithOptions(t *testing.T) {
 	client := setupTestClientForEnhanced(t)
 	defer func() {
@@ -585,3 +612,366 @@ func TestUpsertByFieldMapTimestampConflict(t *testing.T) {
 		t.Errorf("Expected title='Test Map Event', got '%v'", foundDoc["title"])
 	}
 }
+
+func TestUpdateWithVersion(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := client.Collection("test_update_with_version")
+	_, _ = collection.DeleteMany(ctx, nil)
+	defer func() {
+		_, _ = collection.DeleteMany(ctx, nil)
+	}()
+
+	_, err := collection.InsertOne(ctx, bson.M{"_id": "doc1", "status": "new", VersionField: int64(1)})
+	if err != nil {
+		t.Fatalf("Failed to insert document: %v", err)
+	}
+
+	base := update.Set("status", "processing")
+
+	// First writer holds the current version and succeeds
+	result, err := collection.UpdateWithVersion(ctx, "doc1", 1, base)
+	if err != nil {
+		t.Fatalf("UpdateWithVersion failed: %v", err)
+	}
+	if result.ModifiedCount != 1 {
+		t.Errorf("Expected 1 modified document, got %d", result.ModifiedCount)
+	}
+
+	// Second writer read the same (now stale) version and must fail
+	_, err = collection.UpdateWithVersion(ctx, "doc1", 1, update.Set("status", "cancelled"))
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("Expected version conflict, got %v", err)
+	}
+	var conflict *VersionConflictError
+	if !errors.As(err, &conflict) || conflict.ExpectedVersion != 1 || conflict.ID != "doc1" {
+		t.Errorf("Expected VersionConflictError for doc1@1, got %v", err)
+	}
+
+	var doc bson.M
+	if err := collection.FindByID(ctx, "doc1").Decode(&doc); err != nil {
+		t.Fatalf("Failed to read document: %v", err)
+	}
+	if doc["status"] != "processing" {
+		t.Errorf("Expected stale update to be rejected, status is %v", doc["status"])
+	}
+	if doc[VersionField] != int64(2) {
+		t.Errorf("Expected version 2, got %v", doc[VersionField])
+	}
+
+	// The caller's update builder must not be modified
+	if _, ok := base.Build()["$inc"]; ok {
+		t.Error("UpdateWithVersion should not mutate the caller's update builder")
+	}
+}
+
+func TestAggregateWithLetVariables(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := client.Collection("test_aggregate_let")
+	_, _ = collection.DeleteMany(ctx, nil)
+	defer func() {
+		_, _ = collection.DeleteMany(ctx, nil)
+	}()
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.M{"_id": "o1", "total": 50},
+		bson.M{"_id": "o2", "total": 150},
+		bson.M{"_id": "o3", "total": 250},
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert documents: %v", err)
+	}
+
+	// The same pipeline is reused with different parameters
+	p := pipeline.New().MatchRaw(bson.M{"$expr": bson.M{"$gte": bson.A{"$total", "$$minTotal"}}})
+
+	for minTotal, expected := range map[int]int{100: 2, 200: 1} {
+		result, err := collection.AggregateWithOptions(ctx, p, &AggregateOptions{Let: bson.M{"minTotal": minTotal}})
+		if err != nil {
+			t.Fatalf("AggregateWithOptions failed: %v", err)
+		}
+
+		var docs []bson.M
+		if err := result.All(ctx, &docs); err != nil {
+			t.Fatalf("Failed to decode results: %v", err)
+		}
+		if len(docs) != expected {
+			t.Errorf("minTotal=%d: expected %d documents, got %d", minTotal, expected, len(docs))
+		}
+	}
+}
+
+func TestUpsertByFieldWithOptionsOmitZero(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_upsert_omit_zero")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	type Account struct {
+		Email   string `bson:"email"`
+		Plan    string `bson:"plan"`
+		Credits int    `bson:"credits"`
+	}
+
+	_, err := col.UpsertByFieldWithOptions(ctx, "email", "a@example.com",
+		Account{Email: "a@example.com", Plan: "free"},
+		&UpsertOptions{OnlyInsert: true, OmitZero: true})
+	if err != nil {
+		t.Fatalf("UpsertByFieldWithOptions failed: %v", err)
+	}
+
+	var stored bson.M
+	if err := col.FindOne(ctx, filter.Eq("email", "a@example.com")).Decode(&stored); err != nil {
+		t.Fatalf("Failed to read upserted document: %v", err)
+	}
+	if stored["plan"] != "free" {
+		t.Errorf("Expected plan to be seeded, got %v", stored["plan"])
+	}
+	if _, ok := stored["credits"]; ok {
+		t.Errorf("Expected zero credits not to be seeded, got %v", stored)
+	}
+}
+
+func TestUpsertByFieldWithOptionsOmitZeroEmpty(t *testing.T) {
+	// Without a driver collection, reaching the server would panic
+	col := newTestCollection("accounts")
+
+	type Account struct {
+		Plan    string `bson:"plan"`
+		Credits int    `bson:"credits"`
+	}
+
+	for _, onlyInsert := range []bool{true, false} {
+		_, err := col.UpsertByFieldWithOptions(context.Background(), "email", "a@example.com",
+			Account{}, &UpsertOptions{OnlyInsert: onlyInsert, OmitZero: true})
+		if !errors.Is(err, ErrEmptyUpsert) {
+			t.Errorf("OnlyInsert=%v: expected ErrEmptyUpsert, got %v", onlyInsert, err)
+		}
+	}
+}
+
+func TestRequiredWriteOperations(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_required_writes")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	if _, err := col.InsertOne(ctx, bson.M{"_id": "r1", "status": "new"}); err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+
+	// Matched
+	if err := col.UpdateOneRequired(ctx, filter.Eq("_id", "r1"), update.Set("status", "done")); err != nil {
+		t.Errorf("Expected update of existing document to succeed, got %v", err)
+	}
+	// Matched but unchanged is still success
+	if err := col.UpdateOneRequired(ctx, filter.Eq("_id", "r1"), update.Set("status", "done")); err != nil {
+		t.Errorf("Expected no-op update of existing document to succeed, got %v", err)
+	}
+
+	// Unmatched
+	err := col.UpdateOneRequired(ctx, filter.Eq("_id", "missing"), update.Set("status", "done"))
+	if !errors.Is(err, ErrNotFound) || !IsNotFoundError(err) {
+		t.Errorf("Expected ErrNotFound for missing update target, got %v", err)
+	}
+	if err := col.DeleteOneRequired(ctx, filter.Eq("_id", "missing")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for missing delete target, got %v", err)
+	}
+
+	if err := col.DeleteOneRequired(ctx, filter.Eq("_id", "r1")); err != nil {
+		t.Errorf("Expected delete of existing document to succeed, got %v", err)
+	}
+	if err := col.DeleteOneRequired(ctx, filter.Eq("_id", "r1")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for already deleted document, got %v", err)
+	}
+}
+
+func TestDistinctCount(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_distinct_count")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	_, err := col.InsertMany(ctx, []any{
+		bson.M{"department": "engineering", "tags": bson.A{"go", "mongodb"}, "active": true},
+		bson.M{"department": "engineering", "tags": bson.A{"go"}, "active": true},
+		bson.M{"department": "sales", "tags": bson.A{"crm"}, "active": false},
+		bson.M{"department": "sales", "tags": bson.A{}, "active": true},
+		bson.M{"department": "support", "active": true},
+		bson.M{"tags": "mongodb", "active": true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to seed collection: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		field    string
+		filter   *filter.Builder
+		expected int64
+	}{
+		{"duplicate values", "department", nil, 3},
+		{"with filter", "department", filter.Eq("active", true), 3},
+		{"filtered to one value", "department", filter.Eq("department", "sales"), 1},
+		{"array elements counted individually", "tags", nil, 3},
+		{"no matches", "department", filter.Eq("active", "unknown"), 0},
+		{"missing field", "region", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := col.DistinctCount(ctx, tt.field, tt.filter)
+			if err != nil {
+				t.Fatalf("DistinctCount failed: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("Expected %d distinct values, got %d", tt.expected, count)
+			}
+
+			// Consistent with the number of values Distinct returns
+			values, err := col.Distinct(ctx, tt.field, tt.filter)
+			if err != nil {
+				t.Fatalf("Distinct failed: %v", err)
+			}
+			if int64(len(values)) != count {
+				t.Errorf("Expected DistinctCount to match len(Distinct) = %d, got %d", len(values), count)
+			}
+		})
+	}
+}
+
+func TestSortByCountOrdering(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_sort_by_count")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	_, err := col.InsertMany(ctx, []any{
+		bson.M{"category": "books"},
+		bson.M{"category": "games"},
+		bson.M{"category": "games"},
+		bson.M{"category": "music"},
+		bson.M{"category": "games"},
+		bson.M{"category": "books"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to seed collection: %v", err)
+	}
+
+	result, err := col.AggregateWithPipeline(ctx, pipeline.SortByCount("$category"))
+	if err != nil {
+		t.Fatalf("AggregateWithPipeline failed: %v", err)
+	}
+
+	var groups []struct {
+		ID    string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err := result.All(ctx, &groups); err != nil {
+		t.Fatalf("Failed to decode results: %v", err)
+	}
+
+	expected := []struct {
+		id    string
+		count int64
+	}{{"games", 3}, {"books", 2}, {"music", 1}}
+	if len(groups) != len(expected) {
+		t.Fatalf("Expected %d groups, got %d", len(expected), len(groups))
+	}
+	for i, want := range expected {
+		if groups[i].ID != want.id || groups[i].Count != want.count {
+			t.Errorf("Group %d: expected %s=%d, got %s=%d", i, want.id, want.count, groups[i].ID, groups[i].Count)
+		}
+	}
+}
+
+func TestUpsertReportsOutcome(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_upsert_outcome")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	byExternalID := filter.Eq("external_id", "evt-1")
+
+	outcome, err := col.Upsert(ctx, byExternalID, update.Set("status", "created"))
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	if outcome.Action != UpsertInserted || outcome.UpsertedID == nil {
+		t.Errorf("Expected inserted outcome with an id, got %+v", outcome)
+	}
+
+	outcome, err = col.Upsert(ctx, byExternalID, update.Set("status", "paid"))
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	if outcome.Action != UpsertUpdated || outcome.UpsertedID != nil {
+		t.Errorf("Expected updated outcome, got %+v", outcome)
+	}
+
+	outcome, err = col.Upsert(ctx, byExternalID, update.Set("status", "paid"))
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	if outcome.Action != UpsertUnchanged {
+		t.Errorf("Expected unchanged outcome, got %+v", outcome)
+	}
+}
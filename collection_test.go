@@ -1,7 +1,23 @@
 package mongodb
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"github.com/cloudresty/go-mongodb/v2/update"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
 )
 
 // Unit tests for collection.go functions
@@ -14,3 +30,708 @@ func TestCollectionBasics(t *testing.T) {
 	// after removing automatic timestamp functionality.
 	t.Log("Automatic timestamp management has been removed from the library")
 }
+
+func TestRawAccessors(t *testing.T) {
+	driverClient, err := mongo.Connect(options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		t.Fatalf("Failed to create driver client: %v", err)
+	}
+	defer func() {
+		_ = driverClient.Disconnect(context.Background())
+	}()
+
+	driverDB := driverClient.Database("raw_test")
+	driverCol := driverDB.Collection("items")
+
+	client := newTestClient()
+	client.client, client.database = driverClient, driverDB
+	if client.Raw() != driverClient {
+		t.Error("Client.Raw() should return the embedded driver client")
+	}
+
+	db := &Database{database: driverDB, client: client, name: "raw_test"}
+	if db.Raw() != driverDB {
+		t.Error("Database.Raw() should return the embedded driver database")
+	}
+
+	col := &Collection{collection: driverCol, client: client, name: "items"}
+	if col.Raw() != driverCol {
+		t.Error("Collection.Raw() should return the embedded driver collection")
+	}
+}
+
+func TestResultCurrentRaw(t *testing.T) {
+	docs := []any{
+		bson.M{"_id": "a", "total": int32(10)},
+		bson.M{"_id": "b", "total": int32(32)},
+	}
+
+	cursor, err := mongo.NewCursorFromDocuments(docs, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create cursor: %v", err)
+	}
+	result := &AggregateResult{cursor: cursor}
+
+	ctx := context.Background()
+	var sum int32
+	for result.Next(ctx) {
+		total, ok := result.Current().Lookup("total").Int32OK()
+		if !ok {
+			t.Fatalf("Expected int32 total field in %v", result.Current())
+		}
+		sum += total
+	}
+	if err := result.Err(); err != nil {
+		t.Fatalf("Cursor error: %v", err)
+	}
+	if sum != 42 {
+		t.Errorf("Expected sum of 42, got %d", sum)
+	}
+
+	cursor, err = mongo.NewCursorFromDocuments(docs[:1], nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create cursor: %v", err)
+	}
+	findResult := &FindResult{cursor: cursor}
+	if !findResult.Next(ctx) {
+		t.Fatal("Expected a document from FindResult")
+	}
+	if id := findResult.Current().Lookup("_id").StringValue(); id != "a" {
+		t.Errorf("Expected _id 'a', got %q", id)
+	}
+}
+
+func TestAggregateOptionsToDriverOptions(t *testing.T) {
+	batch := int32(50)
+	aggOpts := &AggregateOptions{
+		Let:          bson.M{"minTotal": 100},
+		AllowDiskUse: true,
+		BatchSize:    &batch,
+	}
+
+	resolved := &options.AggregateOptions{}
+	for _, apply := range aggOpts.toDriverOptions().List() {
+		if err := apply(resolved); err != nil {
+			t.Fatalf("Failed to apply aggregate option: %v", err)
+		}
+	}
+
+	let, ok := resolved.Let.(bson.M)
+	if !ok || let["minTotal"] != 100 {
+		t.Errorf("Expected let variables to be set, got %v", resolved.Let)
+	}
+	if resolved.AllowDiskUse == nil || !*resolved.AllowDiskUse {
+		t.Error("Expected AllowDiskUse to be set")
+	}
+	if resolved.BatchSize == nil || *resolved.BatchSize != 50 {
+		t.Errorf("Expected batch size 50, got %v", resolved.BatchSize)
+	}
+
+	// Empty options leave the driver defaults untouched
+	resolved = &options.AggregateOptions{}
+	for _, apply := range (&AggregateOptions{}).toDriverOptions().List() {
+		_ = apply(resolved)
+	}
+	if resolved.Let != nil || resolved.AllowDiskUse != nil || resolved.BatchSize != nil {
+		t.Errorf("Expected no driver options to be set, got %+v", resolved)
+	}
+}
+
+func TestQueryOptionsReadPreference(t *testing.T) {
+	queryOpts := &QueryOptions{ReadPreference: readpref.Secondary()}
+
+	collOpts := queryOpts.collectionOptions()
+	if collOpts == nil {
+		t.Fatal("Expected collection options when a read preference is set")
+	}
+	resolved := &options.CollectionOptions{}
+	for _, apply := range collOpts.List() {
+		if err := apply(resolved); err != nil {
+			t.Fatalf("Failed to apply collection option: %v", err)
+		}
+	}
+	if resolved.ReadPreference == nil || resolved.ReadPreference.Mode() != readpref.SecondaryMode {
+		t.Errorf("Expected secondary read preference, got %v", resolved.ReadPreference)
+	}
+
+	// Without an override the collection defaults are used as-is
+	driverClient, err := mongo.Connect(options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		t.Fatalf("Failed to create driver client: %v", err)
+	}
+	defer func() {
+		_ = driverClient.Disconnect(context.Background())
+	}()
+
+	driverCol := driverClient.Database("readpref_test").Collection("items")
+	col := &Collection{collection: driverCol, name: "items"}
+
+	if col.collectionFor(nil) != driverCol || col.collectionFor(&QueryOptions{}) != driverCol {
+		t.Error("Expected the collection handle to be reused when no override is set")
+	}
+	if col.collectionFor(queryOpts) == driverCol {
+		t.Error("Expected a per-call clone when a read preference is set")
+	}
+}
+
+func TestFindResultRemainingBatchLength(t *testing.T) {
+	docs := []any{bson.M{"n": 1}, bson.M{"n": 2}, bson.M{"n": 3}}
+
+	cursor, err := mongo.NewCursorFromDocuments(docs, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create cursor: %v", err)
+	}
+	result := &FindResult{cursor: cursor}
+	defer func() {
+		_ = result.Close(context.Background())
+	}()
+
+	if remaining := result.RemainingBatchLength(); remaining != 3 {
+		t.Fatalf("Expected 3 documents in the initial batch, got %d", remaining)
+	}
+
+	for expected := 2; expected >= 0; expected-- {
+		if !result.TryNext(context.Background()) {
+			t.Fatalf("Expected TryNext to return a document, err=%v", result.Err())
+		}
+		if remaining := result.RemainingBatchLength(); remaining != expected {
+			t.Errorf("Expected %d remaining documents, got %d", expected, remaining)
+		}
+	}
+
+	if result.TryNext(context.Background()) {
+		t.Error("Expected TryNext to return false on an exhausted cursor")
+	}
+	if err := result.Err(); err != nil {
+		t.Errorf("Unexpected cursor error: %v", err)
+	}
+}
+
+func TestOperationsHonorCallerCancellation(t *testing.T) {
+	driverClient, err := mongo.Connect(options.Client().
+		ApplyURI("mongodb://localhost:27017").
+		SetServerSelectionTimeout(30 * time.Second))
+	if err != nil {
+		t.Fatalf("Failed to create driver client: %v", err)
+	}
+	defer func() {
+		_ = driverClient.Disconnect(context.Background())
+	}()
+
+	client := newTestClient(withIDMode(IDModeULID))
+	client.client = driverClient
+	col := &Collection{
+		collection: driverClient.Database("context_test").Collection("items"),
+		client:     client,
+		name:       "items",
+	}
+
+	// An already-cancelled context must abort each operation instead of being
+	// replaced by a default timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	operations := map[string]func() error{
+		"InsertOne": func() error {
+			_, err := col.InsertOne(ctx, bson.M{"name": "test"})
+			return err
+		},
+		"FindOne": func() error {
+			return col.FindOne(ctx, filter.Eq("name", "test")).Err()
+		},
+		"Find": func() error {
+			_, err := col.Find(ctx, nil)
+			return err
+		},
+		"CountDocuments": func() error {
+			_, err := col.CountDocuments(ctx, nil)
+			return err
+		},
+		"UpdateOne": func() error {
+			_, err := col.UpdateOne(ctx, filter.Eq("name", "test"), update.Set("name", "x"))
+			return err
+		},
+		"DeleteMany": func() error {
+			_, err := col.DeleteMany(ctx, nil)
+			return err
+		},
+		"AggregateWithPipeline": func() error {
+			_, err := col.AggregateWithPipeline(ctx, nil)
+			return err
+		},
+	}
+
+	for name, op := range operations {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			err := op()
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected context.Canceled, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Cancelled operation took %v; caller context was not honored", elapsed)
+			}
+		})
+	}
+}
+
+func TestResultTypesMarshalJSON(t *testing.T) {
+	oid := bson.NewObjectID()
+	results := map[string]any{
+		"InsertOneResult":  &InsertOneResult{InsertedID: oid, WasGenerated: true, GeneratedAt: time.Now()},
+		"InsertManyResult": &InsertManyResult{InsertedIDs: []any{"01HZX", oid}, InsertedCount: 2, GeneratedAt: time.Now()},
+		"UpdateResult":     &UpdateResult{MatchedCount: 1, ModifiedCount: 1, UpsertedID: oid, Duration: 1500 * time.Microsecond},
+		"DeleteResult":     &DeleteResult{DeletedCount: 3, Duration: 2 * time.Millisecond},
+		"BulkWriteResult":  &BulkWriteResult{InsertedCount: 1, UpsertedIDs: map[int64]any{2: oid}},
+	}
+
+	for name, result := range results {
+		data, err := json.Marshal(result)
+		if err != nil {
+			t.Errorf("%s: failed to marshal JSON: %v", name, err)
+			continue
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Errorf("%s: produced invalid JSON %s: %v", name, data, err)
+		}
+	}
+
+	data, err := json.Marshal(&UpdateResult{UpsertedID: oid, Duration: 1500 * time.Microsecond})
+	if err != nil {
+		t.Fatalf("Failed to marshal UpdateResult: %v", err)
+	}
+	var decoded struct {
+		UpsertedID string `json:"upserted_id"`
+		Duration   int64  `json:"duration"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal UpdateResult JSON: %v", err)
+	}
+	if decoded.UpsertedID != oid.Hex() {
+		t.Errorf("Expected ObjectID to serialize as hex %s, got %s", oid.Hex(), decoded.UpsertedID)
+	}
+	if time.Duration(decoded.Duration) != 1500*time.Microsecond {
+		t.Errorf("Expected duration in nanoseconds, got %d", decoded.Duration)
+	}
+}
+
+func TestWriteResultDurationIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_result_duration")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	if _, err := col.InsertOne(ctx, bson.M{"_id": "d1", "status": "new"}); err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+
+	updateResult, err := col.UpdateOne(ctx, filter.Eq("_id", "d1"), update.Set("status", "done"))
+	if err != nil {
+		t.Fatalf("UpdateOne failed: %v", err)
+	}
+	if updateResult.Duration <= 0 {
+		t.Errorf("Expected positive update duration, got %v", updateResult.Duration)
+	}
+
+	deleteResult, err := col.DeleteOne(ctx, filter.Eq("_id", "d1"))
+	if err != nil {
+		t.Fatalf("DeleteOne failed: %v", err)
+	}
+	if deleteResult.Duration <= 0 {
+		t.Errorf("Expected positive delete duration, got %v", deleteResult.Duration)
+	}
+
+	if _, err := json.Marshal(deleteResult); err != nil {
+		t.Errorf("Failed to marshal DeleteResult: %v", err)
+	}
+}
+
+func TestDistinctCountPipeline(t *testing.T) {
+	got := distinctCountPipeline("department", bson.M{"active": true})
+	expected := bson.A{
+		bson.M{"$match": bson.M{"active": true}},
+		bson.M{"$unwind": "$department"},
+		bson.M{"$group": bson.M{"_id": "$department"}},
+		bson.M{"$count": "count"},
+	}
+
+	gotBytes, _ := bson.Marshal(bson.M{"p": got})
+	expectedBytes, _ := bson.Marshal(bson.M{"p": expected})
+	if string(gotBytes) != string(expectedBytes) {
+		t.Errorf("Expected pipeline %v, got %v", expected, got)
+	}
+}
+
+func TestWithWriteOptions(t *testing.T) {
+	// Connect does not perform I/O, so a driver collection can be created without a server
+	driverClient, err := mongo.Connect(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatalf("Failed to create driver client: %v", err)
+	}
+	defer func() {
+		_ = driverClient.Disconnect(context.Background())
+	}()
+
+	col := newTestCollection("events", withIDMode(IDModeULID))
+	col.collection = driverClient.Database("app").Collection("events")
+
+	if got := col.withWriteOptions(nil); got != col {
+		t.Error("Expected nil write options to reuse the collection handle")
+	}
+	if got := col.withWriteOptions(&WriteOptions{}); got != col {
+		t.Error("Expected empty write options to reuse the collection handle")
+	}
+
+	unack := col.withWriteOptions(&WriteOptions{WriteConcern: writeconcern.Unacknowledged()})
+	if unack == col || unack.collection == col.collection {
+		t.Error("Expected write options to apply to a cloned collection handle")
+	}
+	if unack.name != col.name || unack.client != col.client {
+		t.Error("Expected the clone to keep the collection name and client")
+	}
+}
+
+func TestUnacknowledgedBulkWritesIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// Record the write concern sent with each insert command
+	var mu sync.Mutex
+	var sent []bson.Raw
+	client, err := NewClient(FromEnv(), WithMonitor(&event.CommandMonitor{
+		Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+			if evt.CommandName != "insert" {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			sent = append(sent, evt.Command.Lookup("writeConcern").Document())
+		},
+	}))
+	if err != nil {
+		t.Skipf("Could not connect to MongoDB: %v", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_unacknowledged_writes")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	unack := &WriteOptions{WriteConcern: writeconcern.Unacknowledged()}
+
+	docs := make([]any, 50)
+	for i := range docs {
+		docs[i] = bson.M{"seq": i, "kind": "insert"}
+	}
+	if _, err := col.InsertManyWithOptions(ctx, docs, unack); err != nil {
+		t.Fatalf("InsertManyWithOptions failed: %v", err)
+	}
+
+	bulk, err := col.BulkWriteWithOptions(ctx, []mongo.WriteModel{
+		mongo.NewInsertOneModel().SetDocument(bson.M{"kind": "bulk"}),
+		mongo.NewInsertOneModel().SetDocument(bson.M{"kind": "bulk"}),
+	}, unack)
+	if err != nil {
+		t.Fatalf("BulkWriteWithOptions failed: %v", err)
+	}
+	if bulk.Acknowledged {
+		t.Error("Expected unacknowledged bulk write result")
+	}
+
+	mu.Lock()
+	for _, wc := range sent {
+		if w, ok := wc.Lookup("w").AsInt64OK(); !ok || w != 0 {
+			t.Errorf("Expected insert sent with w:0, got writeConcern %v", wc)
+		}
+	}
+	if len(sent) != 2 {
+		t.Errorf("Expected 2 insert commands, got %d", len(sent))
+	}
+	mu.Unlock()
+
+	// The writes are applied asynchronously; wait for them to become visible
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		count, err := col.CountDocuments(ctx, nil)
+		if err != nil {
+			t.Fatalf("CountDocuments failed: %v", err)
+		}
+		if count == 52 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected 52 documents to be written, found %d", count)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestAggregateRejectsMisplacedFirstStage(t *testing.T) {
+	// No driver collection: the pipeline must be rejected before reaching the driver
+	col := newTestCollection("places")
+	p := pipeline.MatchRaw(bson.M{"open": true}).GeoNear(pipeline.GeoNearOptions{Near: pipeline.Point(0, 0)})
+
+	if _, err := col.AggregateWithPipeline(context.Background(), p); !errors.Is(err, pipeline.ErrStageNotFirst) {
+		t.Errorf("Expected ErrStageNotFirst from AggregateWithPipeline, got %v", err)
+	}
+	if _, err := col.AggregatePaginated(context.Background(), p, 1, 10); !errors.Is(err, pipeline.ErrStageNotFirst) {
+		t.Errorf("Expected ErrStageNotFirst from AggregatePaginated, got %v", err)
+	}
+}
+
+func TestUpsertOutcome(t *testing.T) {
+	oid := bson.NewObjectID()
+	tests := []struct {
+		name     string
+		result   *UpdateResult
+		expected UpsertOutcome
+	}{
+		{"inserted", &UpdateResult{UpsertedCount: 1, UpsertedID: oid}, UpsertOutcome{Action: UpsertInserted, UpsertedID: oid}},
+		{"updated", &UpdateResult{MatchedCount: 1, ModifiedCount: 1}, UpsertOutcome{Action: UpsertUpdated}},
+		{"unchanged", &UpdateResult{MatchedCount: 1}, UpsertOutcome{Action: UpsertUnchanged}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if outcome := upsertOutcome(tt.result); outcome != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, outcome)
+			}
+			if tt.expected.Action.String() != tt.name {
+				t.Errorf("Expected action name %q, got %q", tt.name, tt.expected.Action.String())
+			}
+		})
+	}
+}
+
+func TestDatabaseHandlesAreCached(t *testing.T) {
+	driverClient, err := mongo.Connect(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatalf("Failed to create driver client: %v", err)
+	}
+	defer func() {
+		_ = driverClient.Disconnect(context.Background())
+	}()
+
+	client := newTestClient()
+	client.client = driverClient
+
+	billing := client.Database("billing")
+	if client.Database("billing") != billing {
+		t.Error("Expected the same handle for repeated Database calls")
+	}
+
+	orders := client.Coll("billing", "orders")
+	if orders.Name() != "orders" || orders.Raw().Database().Name() != "billing" {
+		t.Errorf("Expected billing.orders, got %s.%s", orders.Raw().Database().Name(), orders.Name())
+	}
+	users := client.Coll("accounts", "users")
+	if users.Raw().Database().Name() != "accounts" {
+		t.Errorf("Expected accounts database, got %s", users.Raw().Database().Name())
+	}
+
+	dbs := client.Databases()
+	if len(dbs) != 2 || dbs[0].Name() != "accounts" || dbs[1] != billing {
+		t.Errorf("Expected cached accounts and billing handles, got %v", dbs)
+	}
+}
+
+func TestCountDocumentsChoosesEstimate(t *testing.T) {
+	col := newTestCollection("orders")
+	ctx := context.Background()
+
+	if !col.useEstimatedCount(ctx, bson.M{}, false) {
+		t.Error("Expected an empty filter to use the metadata estimate")
+	}
+	if col.useEstimatedCount(ctx, filter.Eq("status", "paid").Build(), false) {
+		t.Error("Expected a non-empty filter to count exactly")
+	}
+	if col.useEstimatedCount(ctx, bson.M{}, true) {
+		t.Error("Expected count options (limit, skip, hint) to count exactly")
+	}
+
+	// Soft-delete handles add a filter, so they never estimate
+	softDeleted := col.WithSoftDelete("deleted_at")
+	if softDeleted.useEstimatedCount(ctx, softDeleted.excludeSoftDeleted(bson.M{}), false) {
+		t.Error("Expected soft-delete handles to count exactly")
+	}
+
+	precise := newTestCollection("orders")
+	WithPreciseCount(true)(precise.client.config)
+	if precise.useEstimatedCount(ctx, bson.M{}, false) {
+		t.Error("Expected WithPreciseCount to disable the estimate")
+	}
+
+	// estimatedDocumentCount cannot run in a transaction
+	driverClient, err := mongo.Connect(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatalf("Failed to create driver client: %v", err)
+	}
+	defer func() {
+		_ = driverClient.Disconnect(context.Background())
+	}()
+	session, err := driverClient.StartSession()
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	defer session.EndSession(context.Background())
+	if col.useEstimatedCount(mongo.NewSessionContext(ctx, session), bson.M{}, false) {
+		t.Error("Expected calls in a session to count exactly")
+	}
+}
+
+func TestCountDocumentsEstimateIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// Record the commands used for counting: estimatedDocumentCount sends "count",
+	// CountDocuments sends an "aggregate" with $group
+	var mu sync.Mutex
+	var commands []string
+	client, err := NewClient(FromEnv(), WithMonitor(&event.CommandMonitor{
+		Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+			if evt.CommandName != "count" && evt.CommandName != "aggregate" {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			commands = append(commands, evt.CommandName)
+		},
+	}))
+	if err != nil {
+		t.Skipf("Could not connect to MongoDB: %v", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_count_estimate")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	if _, err := col.InsertMany(ctx, []any{bson.M{"status": "paid"}, bson.M{"status": "new"}}); err != nil {
+		t.Fatalf("Failed to seed collection: %v", err)
+	}
+
+	total, err := col.CountDocuments(ctx, filter.New())
+	if err != nil || total != 2 {
+		t.Fatalf("Expected 2 documents, got %d, %v", total, err)
+	}
+	paid, err := col.CountDocuments(ctx, filter.Eq("status", "paid"))
+	if err != nil || paid != 1 {
+		t.Fatalf("Expected 1 paid document, got %d, %v", paid, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(commands) != 2 || commands[0] != "count" || commands[1] != "aggregate" {
+		t.Errorf("Expected count then aggregate commands, got %v", commands)
+	}
+}
+
+func TestBulkWriteResultUpsertedIDStrings(t *testing.T) {
+	oid := bson.NewObjectID()
+	result := &BulkWriteResult{UpsertedIDs: map[int64]any{1: oid, 3: "sku-42"}}
+
+	ids := result.UpsertedIDStrings()
+	expected := map[int]string{1: oid.Hex(), 3: "sku-42"}
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, ids)
+	}
+	for index, id := range expected {
+		if ids[index] != id {
+			t.Errorf("Expected model %d to map to %q, got %q", index, id, ids[index])
+		}
+	}
+
+	if ids := (&BulkWriteResult{}).UpsertedIDStrings(); ids == nil || len(ids) != 0 {
+		t.Errorf("Expected an empty map without upserts, got %v", ids)
+	}
+}
+
+func TestBulkWriteUpsertedIDsIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, err := NewClient(FromEnv())
+	if err != nil {
+		t.Skipf("Could not connect to MongoDB: %v", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_bulk_upserted_ids")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	if _, err := col.InsertMany(ctx, []any{bson.M{"sku": "A"}, bson.M{"sku": "C"}}); err != nil {
+		t.Fatalf("InsertMany failed: %v", err)
+	}
+
+	upsert := func(sku string) mongo.WriteModel {
+		return mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"sku": sku}).
+			SetUpdate(bson.M{"$set": bson.M{"synced": true}}).
+			SetUpsert(true)
+	}
+
+	// Models 0 and 2 match existing documents, 1 and 3 upsert
+	result, err := col.BulkWrite(ctx, []mongo.WriteModel{
+		upsert("A"),
+		upsert("B"),
+		upsert("C"),
+		mongo.NewReplaceOneModel().SetFilter(bson.M{"sku": "D"}).SetReplacement(bson.M{"sku": "D"}).SetUpsert(true),
+	})
+	if err != nil {
+		t.Fatalf("BulkWrite failed: %v", err)
+	}
+
+	if result.MatchedCount != 2 || result.UpsertedCount != 2 {
+		t.Errorf("Expected 2 matched and 2 upserted, got %d and %d", result.MatchedCount, result.UpsertedCount)
+	}
+
+	ids := result.UpsertedIDStrings()
+	if len(ids) != 2 || ids[1] == "" || ids[3] == "" {
+		t.Fatalf("Expected upserted IDs for models 1 and 3, got %v", ids)
+	}
+
+	for index, sku := range map[int]string{1: "B", 3: "D"} {
+		var doc bson.M
+		if err := col.FindOne(ctx, filter.Eq("_id", result.UpsertedIDs[int64(index)])).Decode(&doc); err != nil {
+			t.Fatalf("Failed to find upserted document of model %d: %v", index, err)
+		}
+		if doc["sku"] != sku {
+			t.Errorf("Expected model %d to upsert sku %s, got %v", index, sku, doc["sku"])
+		}
+	}
+}
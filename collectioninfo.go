@@ -0,0 +1,125 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Collection types reported in CollectionInfo.Type
+const (
+	CollectionTypeCollection = "collection"
+	CollectionTypeView       = "view"
+	CollectionTypeTimeSeries = "timeseries"
+)
+
+// CollectionInfo describes a collection as returned by the listCollections command
+type CollectionInfo struct {
+	Name string `json:"name" bson:"name"`
+	// Type is CollectionTypeCollection, CollectionTypeView or CollectionTypeTimeSeries
+	Type    string                `json:"type" bson:"type"`
+	Options CollectionInfoOptions `json:"options" bson:"options"`
+	// ReadOnly is set for views and for collections of a read-only database
+	ReadOnly bool `json:"read_only" bson:"-"`
+	// UUID identifies the collection; empty for views
+	UUID bson.Binary `json:"uuid,omitzero" bson:"-"`
+}
+
+// CollectionInfoOptions are the creation options of a collection. Fields that do not apply
+// to the collection type are left empty.
+type CollectionInfoOptions struct {
+	Capped bool `json:"capped,omitempty" bson:"capped,omitempty"`
+	// Size is the maximum size in bytes of a capped collection
+	Size int64 `json:"size,omitempty" bson:"size,omitempty"`
+	// Max is the maximum number of documents of a capped collection
+	Max int64 `json:"max,omitempty" bson:"max,omitempty"`
+
+	Validator        bson.Raw `json:"validator,omitempty" bson:"validator,omitempty"`
+	ValidationLevel  string   `json:"validation_level,omitempty" bson:"validationLevel,omitempty"`
+	ValidationAction string   `json:"validation_action,omitempty" bson:"validationAction,omitempty"`
+
+	// ViewOn and Pipeline define a view
+	ViewOn   string `json:"view_on,omitempty" bson:"viewOn,omitempty"`
+	Pipeline bson.A `json:"pipeline,omitempty" bson:"pipeline,omitempty"`
+
+	TimeSeries *TimeSeriesInfo `json:"timeseries,omitempty" bson:"timeseries,omitempty"`
+	// ExpireAfterSeconds is the TTL of a time series collection
+	ExpireAfterSeconds int64 `json:"expire_after_seconds,omitempty" bson:"expireAfterSeconds,omitempty"`
+}
+
+// TimeSeriesInfo describes the time series options of a collection
+type TimeSeriesInfo struct {
+	TimeField   string `json:"time_field" bson:"timeField"`
+	MetaField   string `json:"meta_field,omitempty" bson:"metaField,omitempty"`
+	Granularity string `json:"granularity,omitempty" bson:"granularity,omitempty"`
+}
+
+// collectionInfoDocument is the listCollections output document of a collection
+type collectionInfoDocument struct {
+	CollectionInfo `bson:",inline"`
+	Info           struct {
+		ReadOnly bool        `bson:"readOnly"`
+		UUID     bson.Binary `bson:"uuid,omitempty"`
+	} `bson:"info"`
+}
+
+// ListCollectionsInfo returns the collections, views and time series collections of the
+// database matching the filter, with their type and creation options. The filter applies to
+// the listCollections output, e.g. filter.Eq("type", mongodb.CollectionTypeView) or
+// filter.Eq("options.capped", true); a nil filter lists everything.
+//
+// Example:
+//
+//	infos, err := db.ListCollectionsInfo(ctx, filter.Eq("type", mongodb.CollectionTypeView))
+//	for _, info := range infos {
+//	    fmt.Println(info.Name, "is a view on", info.Options.ViewOn)
+//	}
+func (db *Database) ListCollectionsInfo(ctx context.Context, filterBuilder *filter.Builder) ([]CollectionInfo, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	filterDoc := bson.M{}
+	if filterBuilder != nil {
+		filterDoc = filterBuilder.Build()
+	}
+
+	if err := db.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	cursor, err := db.database.ListCollections(ctx, filterDoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	return decodeCollectionInfos(ctx, cursor)
+}
+
+// decodeCollectionInfos reads a listCollections cursor
+func decodeCollectionInfos(ctx context.Context, cursor *mongo.Cursor) ([]CollectionInfo, error) {
+	infos := []CollectionInfo{}
+	for cursor.Next(ctx) {
+		var doc collectionInfoDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode collection info: %w", err)
+		}
+		info := doc.CollectionInfo
+		info.ReadOnly = doc.Info.ReadOnly
+		info.UUID = doc.Info.UUID
+		infos = append(infos, info)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
@@ -0,0 +1,111 @@
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestDecodeCollectionInfos(t *testing.T) {
+	uuid := bson.Binary{Subtype: 4, Data: []byte("0123456789abcdef")}
+
+	// Documents as returned by listCollections
+	docs := []any{
+		bson.M{
+			"name": "events",
+			"type": "collection",
+			"options": bson.M{
+				"capped": true, "size": int32(1048576), "max": int64(5000),
+				"validator":       bson.M{"$jsonSchema": bson.M{"required": bson.A{"kind"}}},
+				"validationLevel": "moderate", "validationAction": "warn",
+			},
+			"info":    bson.M{"readOnly": false, "uuid": uuid},
+			"idIndex": bson.M{"v": int32(2), "key": bson.M{"_id": int32(1)}, "name": "_id_"},
+		},
+		bson.M{
+			"name":    "active_users",
+			"type":    "view",
+			"options": bson.M{"viewOn": "users", "pipeline": bson.A{bson.M{"$match": bson.M{"active": true}}}},
+			"info":    bson.M{"readOnly": true},
+		},
+		bson.M{
+			"name": "metrics",
+			"type": "timeseries",
+			"options": bson.M{
+				"timeseries":         bson.M{"timeField": "ts", "metaField": "sensor", "granularity": "minutes", "bucketMaxSpanSeconds": int32(86400)},
+				"expireAfterSeconds": int64(3600),
+			},
+			"info": bson.M{"readOnly": false},
+		},
+	}
+	cursor, err := mongo.NewCursorFromDocuments(docs, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create cursor: %v", err)
+	}
+
+	infos, err := decodeCollectionInfos(context.Background(), cursor)
+	if err != nil {
+		t.Fatalf("decodeCollectionInfos failed: %v", err)
+	}
+	if len(infos) != 3 {
+		t.Fatalf("Expected 3 collections, got %d", len(infos))
+	}
+
+	events := infos[0]
+	if events.Name != "events" || events.Type != CollectionTypeCollection || events.ReadOnly || !reflect.DeepEqual(events.UUID, uuid) {
+		t.Errorf("Unexpected collection info: %+v", events)
+	}
+	if !events.Options.Capped || events.Options.Size != 1048576 || events.Options.Max != 5000 {
+		t.Errorf("Expected capped options, got %+v", events.Options)
+	}
+	if events.Options.ValidationLevel != "moderate" || events.Options.ValidationAction != "warn" ||
+		events.Options.Validator.Lookup("$jsonSchema", "required", "0").StringValue() != "kind" {
+		t.Errorf("Expected validation options, got %+v", events.Options)
+	}
+
+	view := infos[1]
+	if view.Type != CollectionTypeView || !view.ReadOnly || view.Options.ViewOn != "users" || len(view.Options.Pipeline) != 1 {
+		t.Errorf("Unexpected view info: %+v", view)
+	}
+
+	metrics := infos[2]
+	expected := &TimeSeriesInfo{TimeField: "ts", MetaField: "sensor", Granularity: "minutes"}
+	if metrics.Type != CollectionTypeTimeSeries || !reflect.DeepEqual(metrics.Options.TimeSeries, expected) || metrics.Options.ExpireAfterSeconds != 3600 {
+		t.Errorf("Unexpected time series info: %+v", metrics)
+	}
+}
+
+func TestListCollectionsInfoIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	db := client.Database(client.config.Database)
+	_ = db.DropCollection(ctx, "test_list_info_capped")
+	defer func() {
+		_ = db.DropCollection(ctx, "test_list_info_capped")
+	}()
+
+	opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(4096)
+	if err := db.CreateCollection(ctx, "test_list_info_capped", opts); err != nil {
+		t.Fatalf("CreateCollection failed: %v", err)
+	}
+
+	infos, err := db.ListCollectionsInfo(ctx, filter.Eq("name", "test_list_info_capped"))
+	if err != nil {
+		t.Fatalf("ListCollectionsInfo failed: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Type != CollectionTypeCollection || !infos[0].Options.Capped {
+		t.Errorf("Expected one capped collection, got %+v", infos)
+	}
+}
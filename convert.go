@@ -0,0 +1,141 @@
+package mongodb
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ToTime converts the BSON date-like values found when decoding into bson.M to a time.Time
+// in UTC: bson.DateTime, bson.Timestamp (seconds precision), bson.ObjectID (its creation
+// time) and time.Time. The second return value is false for any other type.
+func ToTime(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC(), true
+	case bson.DateTime:
+		return v.Time().UTC(), true
+	case bson.Timestamp:
+		return time.Unix(int64(v.T), 0).UTC(), true
+	case bson.ObjectID:
+		return v.Timestamp().UTC(), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// ToString formats a decoded BSON value for display: ObjectIDs as hex, Decimal128 as its exact
+// decimal representation, dates as RFC 3339 in UTC, timestamps as "T:I", UUID binaries in
+// their canonical form and other binaries as base64. nil and BSON null become an empty
+// string; other values are formatted with fmt.Sprint.
+func ToString(value any) string {
+	switch v := value.(type) {
+	case nil, bson.Null, bson.Undefined:
+		return ""
+	case string:
+		return v
+	case bson.ObjectID:
+		return v.Hex()
+	case bson.Decimal128:
+		return v.String()
+	case bson.DateTime:
+		return v.Time().UTC().Format(time.RFC3339Nano)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case bson.Timestamp:
+		return fmt.Sprintf("%d:%d", v.T, v.I)
+	case bson.Binary:
+		if uuid, ok := binaryUUID(v); ok {
+			return uuid
+		}
+		return base64.StdEncoding.EncodeToString(v.Data)
+	case bson.Regex:
+		return "/" + v.Pattern + "/" + v.Options
+	case bson.Symbol:
+		return string(v)
+	case bson.JavaScript:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// DecodeFlexible decodes a document into a map of Go-native values, for heterogeneous
+// documents that are printed, logged or re-encoded as JSON rather than decoded into structs.
+// Extended BSON types are normalized recursively:
+//   - bson.DateTime and bson.Timestamp become time.Time in UTC
+//   - bson.ObjectID becomes its hex string
+//   - bson.Decimal128 becomes its exact decimal string (to avoid float rounding)
+//   - UUID binaries become the canonical UUID string, other binaries []byte
+//   - bson.Regex, bson.Symbol and bson.JavaScript become strings
+//   - BSON null and undefined become nil
+//   - embedded documents become map[string]any and arrays []any
+//
+// Example:
+//
+//	for results.Next(ctx) {
+//		doc, err := mongodb.DecodeFlexible(results.Current())
+//		...
+//	}
+func DecodeFlexible(raw bson.Raw) (map[string]any, error) {
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	return normalizeDocument(doc), nil
+}
+
+// normalizeDocument converts the values of a decoded document to Go-native values
+func normalizeDocument(doc bson.M) map[string]any {
+	normalized := make(map[string]any, len(doc))
+	for key, value := range doc {
+		normalized[key] = normalizeValue(value)
+	}
+	return normalized
+}
+
+// normalizeValue converts a single decoded BSON value to a Go-native value
+func normalizeValue(value any) any {
+	switch v := value.(type) {
+	case bson.M:
+		return normalizeDocument(v)
+	case bson.D:
+		normalized := make(map[string]any, len(v))
+		for _, elem := range v {
+			normalized[elem.Key] = normalizeValue(elem.Value)
+		}
+		return normalized
+	case bson.A:
+		normalized := make([]any, len(v))
+		for i, elem := range v {
+			normalized[i] = normalizeValue(elem)
+		}
+		return normalized
+	case bson.DateTime, bson.Timestamp:
+		t, _ := ToTime(v)
+		return t
+	case bson.Null, bson.Undefined:
+		return nil
+	case bson.Binary:
+		if uuid, ok := binaryUUID(v); ok {
+			return uuid
+		}
+		return v.Data
+	case bson.ObjectID, bson.Decimal128, bson.Regex, bson.Symbol, bson.JavaScript:
+		return ToString(v)
+	default:
+		return v
+	}
+}
+
+// binaryUUID formats a UUID binary (subtype 4) as a canonical UUID string
+func binaryUUID(b bson.Binary) (string, bool) {
+	if b.Subtype != bson.TypeBinaryUUID || len(b.Data) != 16 {
+		return "", false
+	}
+	h := hex.EncodeToString(b.Data)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32], true
+}
@@ -0,0 +1,124 @@
+package mongodb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestToTime(t *testing.T) {
+	when := time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC)
+	oid := bson.NewObjectIDFromTimestamp(when)
+
+	tests := []struct {
+		name     string
+		value    any
+		expected time.Time
+		ok       bool
+	}{
+		{"time.Time", when.In(time.FixedZone("X", 3600)), when, true},
+		{"bson.DateTime", bson.NewDateTimeFromTime(when), when, true},
+		{"bson.Timestamp", bson.Timestamp{T: uint32(when.Unix()), I: 7}, when, true},
+		{"bson.ObjectID", oid, when, true},
+		{"string", "2026-03-14", time.Time{}, false},
+		{"nil", nil, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ToTime(tt.value)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok=%v, got %v", tt.ok, ok)
+			}
+			if !got.Equal(tt.expected) || (ok && got.Location() != time.UTC) {
+				t.Errorf("Expected %v in UTC, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestToString(t *testing.T) {
+	oid, _ := bson.ObjectIDFromHex("65f3a1b2c3d4e5f601234567")
+	dec, _ := bson.ParseDecimal128("1234.5600")
+	uuid := bson.Binary{Subtype: bson.TypeBinaryUUID, Data: []byte{
+		0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00,
+	}}
+
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{"nil", nil, ""},
+		{"null", bson.Null{}, ""},
+		{"string", "plain", "plain"},
+		{"ObjectID", oid, "65f3a1b2c3d4e5f601234567"},
+		{"Decimal128", dec, "1234.5600"},
+		{"DateTime", bson.NewDateTimeFromTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)), "2026-01-02T03:04:05Z"},
+		{"Timestamp", bson.Timestamp{T: 1700000000, I: 3}, "1700000000:3"},
+		{"UUID binary", uuid, "123e4567-e89b-12d3-a456-426614174000"},
+		{"generic binary", bson.Binary{Subtype: bson.TypeBinaryGeneric, Data: []byte("hi")}, "aGk="},
+		{"Regex", bson.Regex{Pattern: "^a", Options: "i"}, "/^a/i"},
+		{"int32", int32(42), "42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToString(tt.value); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecodeFlexible(t *testing.T) {
+	when := time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC)
+	oid, _ := bson.ObjectIDFromHex("65f3a1b2c3d4e5f601234567")
+	dec, _ := bson.ParseDecimal128("19.99")
+
+	raw, err := bson.Marshal(bson.D{
+		{Key: "_id", Value: oid},
+		{Key: "price", Value: dec},
+		{Key: "created", Value: bson.NewDateTimeFromTime(when)},
+		{Key: "op_time", Value: bson.Timestamp{T: uint32(when.Unix()), I: 1}},
+		{Key: "payload", Value: bson.Binary{Subtype: bson.TypeBinaryGeneric, Data: []byte{1, 2}}},
+		{Key: "deleted", Value: nil},
+		{Key: "count", Value: int32(3)},
+		{Key: "meta", Value: bson.D{{Key: "ref", Value: oid}}},
+		{Key: "history", Value: bson.A{bson.NewDateTimeFromTime(when), "x"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal document: %v", err)
+	}
+
+	doc, err := DecodeFlexible(raw)
+	if err != nil {
+		t.Fatalf("DecodeFlexible failed: %v", err)
+	}
+
+	expected := map[string]any{
+		"_id":     "65f3a1b2c3d4e5f601234567",
+		"price":   "19.99",
+		"created": when,
+		"op_time": when,
+		"deleted": nil,
+		"count":   int32(3),
+		"meta":    map[string]any{"ref": "65f3a1b2c3d4e5f601234567"},
+		"history": []any{when, "x"},
+	}
+	payload, _ := doc["payload"].([]byte)
+	if !bytes.Equal(payload, []byte{1, 2}) {
+		t.Errorf("Expected binary payload as []byte, got %#v", doc["payload"])
+	}
+	delete(doc, "payload")
+	if !reflect.DeepEqual(doc, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, doc)
+	}
+
+	if _, err := DecodeFlexible(bson.Raw{0x01}); err == nil {
+		t.Error("Expected error for invalid document")
+	}
+}
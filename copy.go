@@ -0,0 +1,114 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// defaultCopyBatchSize is used by CopyTo when batchSize is not positive
+const defaultCopyBatchSize = 1000
+
+// CopyTo copies documents matching the filter into the target collection, which may belong
+// to another database. Documents are streamed from the source and inserted in batches of
+// batchSize as raw BSON, so _ids and field types are preserved exactly and no IDs are generated.
+//
+// The copy is not atomic: on error, the returned count is the number of documents already
+// inserted into the target. Inserting a document whose _id already exists in the target fails
+// with a duplicate key error. A nil ctx defaults to a 10 minute timeout for the whole copy.
+//
+// Example:
+//
+//	archive := client.Database("archive").Collection("orders")
+//	copied, err := client.Collection("orders").CopyTo(ctx, archive, filter.Eq("status", "closed"), 500)
+func (col *Collection) CopyTo(ctx context.Context, target *Collection, filterBuilder *filter.Builder, batchSize int) (int64, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+	}
+
+	if target == nil {
+		return 0, errors.New("copy target collection is nil")
+	}
+	if err := target.checkWritable("CopyTo"); err != nil {
+		return 0, err
+	}
+	if err := target.checkConnected(); err != nil {
+		return 0, err
+	}
+	if batchSize <= 0 {
+		batchSize = defaultCopyBatchSize
+	}
+
+	cursor, err := col.Find(ctx, filterBuilder, options.Find().SetBatchSize(int32(batchSize)))
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var copied int64
+	batch := make([]any, 0, batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := target.client.waitForWrites(ctx, len(batch)); err != nil {
+			return err
+		}
+
+		_, err := target.collection.InsertMany(ctx, batch)
+		if err != nil {
+			// Inserts are ordered, so documents before the first failed write were inserted
+			var bulkErr mongo.BulkWriteException
+			if errors.As(err, &bulkErr) && len(bulkErr.WriteErrors) > 0 {
+				copied += int64(bulkErr.WriteErrors[0].Index)
+			}
+			target.client.incrementFailureCount()
+			target.logger(ctx).Error("Failed to copy documents",
+				"error", err.Error(),
+				"collection", col.name,
+				"target", target.name,
+				"copied", copied)
+			return err
+		}
+		target.client.incrementOperationCount()
+		copied += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		// Current is only valid until the next call to Next
+		batch = append(batch, bson.Raw(append([]byte(nil), cursor.Current()...)))
+		if len(batch) >= batchSize {
+			if err := flush(); err != nil {
+				return copied, err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		col.logger(ctx).Error("Failed to read documents to copy",
+			"error", err.Error(),
+			"collection", col.name)
+		return copied, err
+	}
+	if err := flush(); err != nil {
+		return copied, err
+	}
+
+	col.logger(ctx).Debug("Documents copied successfully",
+		"collection", col.name,
+		"target", target.name,
+		"count", copied)
+
+	return copied, nil
+}
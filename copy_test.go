@@ -0,0 +1,67 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestCopyToIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	source := client.Collection("test_copy_source")
+	target := client.Database(client.config.Database + "_copy").Collection("test_copy_target")
+	_ = source.Raw().Drop(ctx)
+	_ = target.Raw().Drop(ctx)
+	defer func() {
+		_ = source.Raw().Drop(ctx)
+		_ = target.Raw().Drop(ctx)
+	}()
+
+	docs := make([]any, 25)
+	for i := range docs {
+		status := "open"
+		if i%5 == 0 {
+			status = "closed"
+		}
+		docs[i] = bson.M{"seq": i, "status": status}
+	}
+	seeded, err := source.InsertMany(ctx, docs)
+	if err != nil {
+		t.Fatalf("Failed to seed source collection: %v", err)
+	}
+
+	// A batch size smaller than the data exercises multiple flushes
+	copied, err := source.CopyTo(ctx, target, nil, 10)
+	if err != nil {
+		t.Fatalf("CopyTo failed: %v", err)
+	}
+	if copied != 25 {
+		t.Errorf("Expected 25 copied documents, got %d", copied)
+	}
+
+	for _, id := range seeded.InsertedIDs {
+		var doc bson.M
+		if err := target.FindOne(ctx, filter.Eq("_id", id)).Decode(&doc); err != nil {
+			t.Errorf("Expected document %v in target with preserved _id: %v", id, err)
+		}
+	}
+
+	// Copying again fails on the first duplicate _id without reporting phantom inserts
+	copied, err = source.CopyTo(ctx, target, filter.Eq("status", "closed"), 0)
+	if !IsDuplicateKeyError(err) {
+		t.Errorf("Expected duplicate key error, got %v", err)
+	}
+	if copied != 0 {
+		t.Errorf("Expected 0 copied documents on duplicate, got %d", copied)
+	}
+}
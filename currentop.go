@@ -0,0 +1,117 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// CurrentOp returns the operations currently in progress on the server, as reported by the
+// admin currentOp command. The filter is matched against the operation documents, e.g.
+// filter.Eq("ns", "app.orders") or filter.Gte("secs_running", 30); a nil filter returns all
+// active operations. Each returned document includes the "opid" to pass to KillOp.
+//
+// Example:
+//
+//	ops, err := client.CurrentOp(ctx, filter.Eq("ns", "app.orders").And(filter.Gte("secs_running", 60)))
+func (c *Client) CurrentOp(ctx context.Context, filterBuilder *filter.Builder) ([]bson.M, error) {
+	client, _, err := c.connection()
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+	}
+
+	// Build filter document
+	filterDoc := bson.M{}
+	if filterBuilder != nil {
+		filterDoc = filterBuilder.Build()
+	}
+
+	raw, err := client.Database("admin").RunCommand(ctx, currentOpCommand(filterDoc)).Raw()
+	if err != nil {
+		c.config.Logger.Error("Failed to run currentOp",
+			"error", err.Error())
+		return nil, fmt.Errorf("failed to run currentOp: %w", err)
+	}
+
+	return decodeCurrentOp(raw)
+}
+
+// CurrentOpByComment returns the operations in progress whose command carries the given
+// comment, as set with the driver's SetComment options. Tagging queries with a comment makes
+// it possible to find and kill a runaway query issued by this application.
+//
+// Example:
+//
+//	cursor, err := col.Find(ctx, f, options.Find().SetComment("nightly-report"))
+//	...
+//	ops, err := client.CurrentOpByComment(ctx, "nightly-report")
+//	for _, op := range ops {
+//		_ = client.KillOp(ctx, op["opid"])
+//	}
+func (c *Client) CurrentOpByComment(ctx context.Context, comment string) ([]bson.M, error) {
+	return c.CurrentOp(ctx, filter.Eq("command.comment", comment))
+}
+
+// KillOp terminates an operation by its opid as returned by CurrentOp. The opid is an integer
+// on replica sets and a "shard:opid" string on sharded clusters, so the value from the
+// currentOp document can be passed through unchanged.
+func (c *Client) KillOp(ctx context.Context, opid any) error {
+	client, _, err := c.connection()
+	if err != nil {
+		return err
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+	}
+
+	err = client.Database("admin").RunCommand(ctx, bson.D{
+		{Key: "killOp", Value: 1},
+		{Key: "op", Value: opid},
+	}).Err()
+	if err != nil {
+		c.config.Logger.Error("Failed to kill operation",
+			"opid", opid,
+			"error", err.Error())
+		return fmt.Errorf("failed to kill operation %v: %w", opid, err)
+	}
+
+	c.config.Logger.Info("Operation killed",
+		"opid", opid)
+
+	return nil
+}
+
+// currentOpCommand builds the currentOp command; filter fields are top-level command fields
+func currentOpCommand(filterDoc bson.M) bson.D {
+	cmd := bson.D{{Key: "currentOp", Value: 1}}
+	for key, value := range filterDoc {
+		cmd = append(cmd, bson.E{Key: key, Value: value})
+	}
+	return cmd
+}
+
+// decodeCurrentOp extracts the in-progress operations from a currentOp response
+func decodeCurrentOp(raw bson.Raw) ([]bson.M, error) {
+	var response struct {
+		InProg []bson.M `bson:"inprog"`
+	}
+	if err := bson.Unmarshal(raw, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode currentOp response: %w", err)
+	}
+	if response.InProg == nil {
+		return []bson.M{}, nil
+	}
+	return response.InProg, nil
+}
@@ -0,0 +1,74 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestDecodeCurrentOp(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{
+		{Key: "inprog", Value: bson.A{
+			bson.D{
+				{Key: "opid", Value: int32(4711)},
+				{Key: "op", Value: "query"},
+				{Key: "ns", Value: "app.orders"},
+				{Key: "secs_running", Value: int64(95)},
+				{Key: "command", Value: bson.D{
+					{Key: "find", Value: "orders"},
+					{Key: "comment", Value: "nightly-report"},
+				}},
+			},
+			bson.D{
+				{Key: "opid", Value: "shard01:1234"},
+				{Key: "op", Value: "update"},
+			},
+		}},
+		{Key: "ok", Value: 1.0},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal response: %v", err)
+	}
+
+	ops, err := decodeCurrentOp(raw)
+	if err != nil {
+		t.Fatalf("decodeCurrentOp failed: %v", err)
+	}
+	if len(ops) != 2 {
+		t.Fatalf("Expected 2 operations, got %d", len(ops))
+	}
+
+	if ops[0]["opid"] != int32(4711) || ops[0]["ns"] != "app.orders" {
+		t.Errorf("Unexpected first operation %v", ops[0])
+	}
+	command, err := bson.Marshal(ops[0]["command"])
+	if err != nil || bson.Raw(command).Lookup("comment").StringValue() != "nightly-report" {
+		t.Errorf("Expected command comment, got %v", ops[0]["command"])
+	}
+	if ops[1]["opid"] != "shard01:1234" {
+		t.Errorf("Expected sharded opid string, got %v", ops[1]["opid"])
+	}
+
+	// A response without in-progress operations decodes to an empty slice
+	raw, _ = bson.Marshal(bson.D{{Key: "inprog", Value: bson.A{}}, {Key: "ok", Value: 1.0}})
+	if ops, err := decodeCurrentOp(raw); err != nil || ops == nil || len(ops) != 0 {
+		t.Errorf("Expected empty operations, got %v (err=%v)", ops, err)
+	}
+}
+
+func TestCurrentOpCommand(t *testing.T) {
+	cmd := currentOpCommand(filter.Eq("command.comment", "nightly-report").Build())
+
+	expected := bson.D{
+		{Key: "currentOp", Value: 1},
+		{Key: "command.comment", Value: "nightly-report"},
+	}
+	if len(cmd) != len(expected) || cmd[0] != expected[0] || cmd[1] != expected[1] {
+		t.Errorf("Expected %v, got %v", expected, cmd)
+	}
+
+	if cmd := currentOpCommand(bson.M{}); len(cmd) != 1 || cmd[0].Key != "currentOp" {
+		t.Errorf("Expected bare currentOp command, got %v", cmd)
+	}
+}
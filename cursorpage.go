@@ -0,0 +1,222 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// defaultCursorPageSize is the page size used by Paginate when Limit is not set
+const defaultCursorPageSize = 20
+
+// ErrInvalidCursor is returned by Paginate for a cursor token it did not produce
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// CursorPageOptions configures Paginate
+type CursorPageOptions struct {
+	// Limit is the maximum number of documents per page (default 20)
+	Limit int
+	// SortField is the field pages are ordered by (default "_id"); ties are broken by _id. It
+	// should be present on every document and ideally indexed together with _id.
+	SortField string
+	// Descending orders pages from the highest SortField value to the lowest
+	Descending bool
+	// After is the NextCursor of the previous page, to fetch the page that follows it
+	After string
+	// Before is the PrevCursor of the previous page, to fetch the page that precedes it;
+	// After takes precedence when both are set
+	Before string
+	// IncludeTotal counts every document matching the filter, which costs an extra
+	// CountDocuments per page
+	IncludeTotal bool
+}
+
+// CursorPage is one page of a cursor-based (keyset) pagination
+type CursorPage struct {
+	Documents []bson.M `json:"documents" bson:"documents"`
+	// PageSize is the number of documents on this page
+	PageSize int `json:"page_size" bson:"page_size"`
+	// NextCursor fetches the following page (CursorPageOptions.After); empty on the last page
+	NextCursor string `json:"next_cursor,omitempty" bson:"next_cursor,omitempty"`
+	// PrevCursor fetches the preceding page (CursorPageOptions.Before); empty on the first page
+	PrevCursor string `json:"prev_cursor,omitempty" bson:"prev_cursor,omitempty"`
+	HasNext    bool   `json:"has_next" bson:"has_next"`
+	HasPrev    bool   `json:"has_prev" bson:"has_prev"`
+	// TotalCount is the number of documents matching the filter; only set with IncludeTotal
+	TotalCount int64 `json:"total_count,omitempty" bson:"total_count,omitempty"`
+}
+
+// Paginate returns one page of the documents matching the filter using keyset pagination:
+// instead of skipping documents, each page continues from the sort key of the last document of
+// the previous one, so deep pages stay as fast as the first and no document is skipped or
+// repeated when documents are inserted between requests.
+//
+// Cursor tokens are opaque URL-safe strings; pass NextCursor as After for the next page and
+// PrevCursor as Before for the previous one.
+//
+// Example:
+//
+//	page, err := col.Paginate(ctx, filter.Eq("status", "active"), mongodb.CursorPageOptions{
+//	    Limit:      50,
+//	    SortField:  "created_at",
+//	    Descending: true,
+//	    After:      r.URL.Query().Get("cursor"),
+//	})
+func (col *Collection) Paginate(ctx context.Context, filterBuilder *filter.Builder, opts CursorPageOptions) (*CursorPage, error) {
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("page size must be at least 1, got %d", opts.Limit)
+	}
+	if opts.Limit == 0 {
+		opts.Limit = defaultCursorPageSize
+	}
+	if opts.SortField == "" {
+		opts.SortField = "_id"
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	// Pages before a cursor are read in reverse order, then flipped
+	token, backward := opts.After, false
+	if token == "" && opts.Before != "" {
+		token, backward = opts.Before, true
+	}
+
+	query := filterBuilder
+	if token != "" {
+		key, err := decodeCursor(token)
+		if err != nil {
+			return nil, err
+		}
+		// Forward in descending order, or backward in ascending order, continues with lower keys
+		query = filter.And(filterBuilder, keysetFilter(opts.SortField, key, opts.Descending != backward))
+	}
+
+	direction := 1
+	if opts.Descending != backward {
+		direction = -1
+	}
+	sort := bson.D{{Key: opts.SortField, Value: direction}}
+	if opts.SortField != "_id" {
+		sort = append(sort, bson.E{Key: "_id", Value: direction})
+	}
+
+	// One extra document tells whether there is a page beyond this one
+	findOpts := options.Find().SetSort(sort).SetLimit(int64(opts.Limit) + 1)
+	results, err := col.Find(ctx, query, findOpts)
+	if err != nil {
+		return nil, err
+	}
+	var documents []bson.M
+	if err := results.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("failed to decode page: %w", err)
+	}
+
+	page, err := newCursorPage(documents, opts, token != "", backward)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.IncludeTotal {
+		if page.TotalCount, err = col.CountDocuments(ctx, filterBuilder); err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// newCursorPage computes the metadata of a page from the documents fetched with one extra
+// document. fromCursor reports whether the page was requested with a cursor and backward
+// whether it was read in reverse order (Before).
+func newCursorPage(documents []bson.M, opts CursorPageOptions, fromCursor, backward bool) (*CursorPage, error) {
+	more := len(documents) > opts.Limit
+	if more {
+		documents = documents[:opts.Limit]
+	}
+	if backward {
+		slices.Reverse(documents)
+	}
+	if documents == nil {
+		documents = []bson.M{}
+	}
+
+	page := &CursorPage{Documents: documents, PageSize: len(documents)}
+	if backward {
+		page.HasPrev = more
+		page.HasNext = fromCursor
+	} else {
+		page.HasNext = more
+		page.HasPrev = fromCursor
+	}
+	if len(documents) == 0 {
+		return page, nil
+	}
+
+	var err error
+	if page.HasNext {
+		if page.NextCursor, err = encodeCursor(documents[len(documents)-1], opts.SortField); err != nil {
+			return nil, err
+		}
+	}
+	if page.HasPrev {
+		if page.PrevCursor, err = encodeCursor(documents[0], opts.SortField); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// cursorKey is the position encoded in a cursor token: the sort key and _id of a document
+type cursorKey struct {
+	Value any `bson:"v"`
+	ID    any `bson:"id"`
+}
+
+// encodeCursor returns the cursor token of the position of doc
+func encodeCursor(doc bson.M, sortField string) (string, error) {
+	value, _ := lookupPath(doc, sortField)
+	data, err := bson.Marshal(cursorKey{Value: value, ID: doc["_id"]})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode pagination cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor parses a cursor token produced by encodeCursor
+func decodeCursor(token string) (cursorKey, error) {
+	var key cursorKey
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return key, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if err := bson.Unmarshal(data, &key); err != nil {
+		return key, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return key, nil
+}
+
+// keysetFilter matches the documents after key in sort order; lower selects keys below it
+func keysetFilter(sortField string, key cursorKey, lower bool) *filter.Builder {
+	compare := filter.Gt
+	if lower {
+		compare = filter.Lt
+	}
+	if sortField == "_id" {
+		return compare("_id", key.ID)
+	}
+	return filter.Or(
+		compare(sortField, key.Value),
+		filter.Eq(sortField, key.Value).And(compare("_id", key.ID)),
+	)
+}
@@ -0,0 +1,188 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// pageDocs returns documents with _id "d<from>" .. "d<to>" and a rank equal to their number
+func pageDocs(from, to int) []bson.M {
+	var docs []bson.M
+	for i := from; i <= to; i++ {
+		docs = append(docs, bson.M{"_id": fmt.Sprintf("d%d", i), "rank": int32(i)})
+	}
+	return docs
+}
+
+// pageIDs returns the _id values of a page
+func pageIDs(page *CursorPage) []any {
+	ids := []any{}
+	for _, doc := range page.Documents {
+		ids = append(ids, doc["_id"])
+	}
+	return ids
+}
+
+func TestNewCursorPage(t *testing.T) {
+	opts := CursorPageOptions{Limit: 2, SortField: "rank"}
+
+	tests := []struct {
+		name       string
+		documents  []bson.M
+		fromCursor bool
+		backward   bool
+		ids        []any
+		hasNext    bool
+		hasPrev    bool
+	}{
+		{"first page", pageDocs(1, 3), false, false, []any{"d1", "d2"}, true, false},
+		{"middle page", pageDocs(3, 5), true, false, []any{"d3", "d4"}, true, true},
+		{"last page", pageDocs(5, 5), true, false, []any{"d5"}, false, true},
+		{"single page", pageDocs(1, 2), false, false, []any{"d1", "d2"}, false, false},
+		{"empty", nil, false, false, []any{}, false, false},
+		// Pages before a cursor are fetched in reverse order
+		{"previous page", []bson.M{pageDocs(4, 4)[0], pageDocs(3, 3)[0], pageDocs(2, 2)[0]}, true, true, []any{"d3", "d4"}, true, true},
+		{"first page reached backward", []bson.M{pageDocs(2, 2)[0], pageDocs(1, 1)[0]}, true, true, []any{"d1", "d2"}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := newCursorPage(tt.documents, opts, tt.fromCursor, tt.backward)
+			if err != nil {
+				t.Fatalf("newCursorPage failed: %v", err)
+			}
+			if ids := pageIDs(page); !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("Expected documents %v, got %v", tt.ids, ids)
+			}
+			if page.PageSize != len(tt.ids) {
+				t.Errorf("Expected page size %d, got %d", len(tt.ids), page.PageSize)
+			}
+			if page.HasNext != tt.hasNext || page.HasPrev != tt.hasPrev {
+				t.Errorf("Expected HasNext %v and HasPrev %v, got %v and %v", tt.hasNext, tt.hasPrev, page.HasNext, page.HasPrev)
+			}
+			if (page.NextCursor != "") != tt.hasNext || (page.PrevCursor != "") != tt.hasPrev {
+				t.Errorf("Expected cursors only for existing pages, got next %q and prev %q", page.NextCursor, page.PrevCursor)
+			}
+		})
+	}
+}
+
+func TestCursorTokenRoundTrip(t *testing.T) {
+	created := bson.NewDateTimeFromTime(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	oid := bson.NewObjectID()
+
+	token, err := encodeCursor(bson.M{"_id": oid, "meta": bson.M{"created": created}}, "meta.created")
+	if err != nil {
+		t.Fatalf("encodeCursor failed: %v", err)
+	}
+	key, err := decodeCursor(token)
+	if err != nil {
+		t.Fatalf("decodeCursor failed: %v", err)
+	}
+	if key.Value != created || key.ID != oid {
+		t.Errorf("Expected %v and %v, got %+v", created, oid, key)
+	}
+
+	if _, err := decodeCursor("not a cursor!"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestKeysetFilter(t *testing.T) {
+	key := cursorKey{Value: int32(7), ID: "d7"}
+
+	if got := keysetFilter("_id", key, false).Build(); !reflect.DeepEqual(got, bson.M{"_id": bson.M{"$gt": "d7"}}) {
+		t.Errorf("Unexpected _id keyset filter: %v", got)
+	}
+
+	expected := bson.M{"$or": []bson.M{
+		{"rank": bson.M{"$lt": int32(7)}},
+		{"$and": []bson.M{{"rank": int32(7)}, {"_id": bson.M{"$lt": "d7"}}}},
+	}}
+	if got := keysetFilter("rank", key, true).Build(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestPaginateRejectsInvalidOptions(t *testing.T) {
+	col := newTestCollection("items")
+
+	if _, err := col.Paginate(context.Background(), nil, CursorPageOptions{Limit: -1}); err == nil {
+		t.Error("Expected an error for a negative page size")
+	}
+	if _, err := col.Paginate(context.Background(), nil, CursorPageOptions{After: "%%%"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestPaginateIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_cursor_paginate")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	docs := []any{}
+	for _, doc := range pageDocs(1, 5) {
+		doc["group"] = "a"
+		docs = append(docs, doc)
+	}
+	docs = append(docs, bson.M{"_id": "other", "rank": int32(0), "group": "b"})
+	if _, err := col.InsertMany(ctx, docs); err != nil {
+		t.Fatalf("InsertMany failed: %v", err)
+	}
+
+	opts := CursorPageOptions{Limit: 2, SortField: "rank", Descending: true, IncludeTotal: true}
+	inGroup := filter.Eq("group", "a")
+
+	first, err := col.Paginate(ctx, inGroup, opts)
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if ids := pageIDs(first); !reflect.DeepEqual(ids, []any{"d5", "d4"}) || !first.HasNext || first.HasPrev || first.TotalCount != 5 {
+		t.Errorf("Unexpected first page: %v %+v", ids, first)
+	}
+
+	opts.After = first.NextCursor
+	middle, err := col.Paginate(ctx, inGroup, opts)
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if ids := pageIDs(middle); !reflect.DeepEqual(ids, []any{"d3", "d2"}) || !middle.HasNext || !middle.HasPrev {
+		t.Errorf("Unexpected middle page: %v %+v", ids, middle)
+	}
+
+	opts.After = middle.NextCursor
+	last, err := col.Paginate(ctx, inGroup, opts)
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if ids := pageIDs(last); !reflect.DeepEqual(ids, []any{"d1"}) || last.HasNext || !last.HasPrev {
+		t.Errorf("Unexpected last page: %v %+v", ids, last)
+	}
+
+	// Walking back from the last page returns the middle page again
+	opts.After, opts.Before = "", last.PrevCursor
+	back, err := col.Paginate(ctx, inGroup, opts)
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if ids := pageIDs(back); !reflect.DeepEqual(ids, []any{"d3", "d2"}) || !back.HasNext || !back.HasPrev {
+		t.Errorf("Unexpected previous page: %v %+v", ids, back)
+	}
+}
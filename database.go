@@ -2,7 +2,9 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -13,32 +15,96 @@ import (
 // Collection returns a MongoDB collection instance
 func (c *Client) Collection(name string) *Collection {
 	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+	database := c.database
+	c.mutex.RUnlock()
+
+	if database == nil && c.config.LazyConnect {
+		_, database, _ = c.connection()
+	}
 
-	return &Collection{
-		collection: c.database.Collection(name),
-		client:     c,
-		name:       name,
+	col := &Collection{
+		client: c,
+		name:   name,
+	}
+	// Without a driver client (a lazy client whose connect failed) the handle stays unbound
+	// and its operations return ErrNotConnected
+	if database != nil {
+		col.collection = database.Collection(name)
 	}
+	return col
 }
 
-// Database returns a database handle for the specified name using the modern API
+// Database returns a database handle for the specified name using the modern API.
+// Handles are cached, so repeated calls with the same name return the same *Database.
 func (c *Client) Database(name string) *Database {
-	return &Database{
-		database: c.client.Database(name),
-		client:   c,
-		name:     name,
+	c.databases.Lock()
+	defer c.databases.Unlock()
+
+	if db, ok := c.databases.handles[name]; ok {
+		return db
 	}
-}
 
-// Ping tests the connection to MongoDB
-func (c *Client) Ping(ctx context.Context) error {
 	c.mutex.RLock()
 	client := c.client
 	c.mutex.RUnlock()
 
-	if client == nil {
-		return fmt.Errorf("client is not connected")
+	if client == nil && c.config.LazyConnect {
+		client, _, _ = c.connection()
+	}
+
+	db := &Database{
+		client: c,
+		name:   name,
+	}
+
+	// Only cache handles bound to a driver client; a lazy client that has not connected yet
+	// returns an unbound handle whose operations fail with ErrNotConnected, and gets a fresh
+	// handle on the next call
+	if client != nil {
+		db.database = client.Database(name)
+		if c.databases.handles == nil {
+			c.databases.handles = make(map[string]*Database)
+		}
+		c.databases.handles[name] = db
+	}
+	return db
+}
+
+// Databases returns the database handles obtained through Database or Coll so far,
+// sorted by name
+func (c *Client) Databases() []*Database {
+	c.databases.Lock()
+	defer c.databases.Unlock()
+
+	names := make([]string, 0, len(c.databases.handles))
+	for name := range c.databases.handles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	dbs := make([]*Database, 0, len(names))
+	for _, name := range names {
+		dbs = append(dbs, c.databases.handles[name])
+	}
+	return dbs
+}
+
+// Coll returns a handle for a collection in any database of the client, a shortcut for
+// client.Database(db).Collection(coll) that reuses the cached database handle.
+//
+// Example:
+//
+//	orders := client.Coll("billing", "orders")
+//	users := client.Coll("accounts", "users")
+func (c *Client) Coll(db, coll string) *Collection {
+	return c.Database(db).Collection(coll)
+}
+
+// Ping tests the connection to MongoDB
+func (c *Client) Ping(ctx context.Context) error {
+	client, _, err := c.connection()
+	if err != nil {
+		return err
 	}
 
 	if ctx == nil {
@@ -47,24 +113,66 @@ func (c *Client) Ping(ctx context.Context) error {
 		defer cancel()
 	}
 
-	return client.Ping(ctx, nil)
+	err = client.Ping(ctx, nil)
+	c.setConnected(err == nil)
+	return err
 }
 
 // StartSession starts a new session for transactions
 func (c *Client) StartSession(opts ...options.Lister[options.SessionOptions]) (*mongo.Session, error) {
-	c.mutex.RLock()
-	client := c.client
-	c.mutex.RUnlock()
-
-	if client == nil {
-		return nil, fmt.Errorf("client is not connected")
+	client, _, err := c.connection()
+	if err != nil {
+		return nil, err
 	}
 
 	return client.StartSession(opts...)
 }
 
-// WithTransaction executes a function within a transaction
+// CausalSession starts a causally consistent session and returns a context carrying it,
+// together with a function that ends the session. Operations on any collection of this client
+// that use the returned context run in the session, so a read issued after a write observes
+// that write even when it is routed to a secondary, without the cost of a transaction.
+//
+// The guarantee holds across elections only with majority read and write concerns. A session
+// must not be used by concurrent goroutines; always call end when done.
+//
+// Example:
+//
+//	sessCtx, end, err := client.CausalSession(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	defer end()
+//
+//	res, err := client.Collection("orders").InsertOne(sessCtx, order)
+//	...
+//	err = client.Collection("orders").FindOne(sessCtx, filter.Eq("_id", res.InsertedID)).Decode(&saved)
+func (c *Client) CausalSession(ctx context.Context) (context.Context, func(), error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	session, err := c.StartSession(options.Session().SetCausalConsistency(true))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start session: %w", err)
+	}
+
+	end := func() {
+		session.EndSession(context.Background())
+	}
+
+	return mongo.NewSessionContext(ctx, session), end, nil
+}
+
+// WithTransaction executes a function within a transaction.
+// On a standalone server it returns an error wrapping ErrTransactionsUnsupported.
 func (c *Client) WithTransaction(ctx context.Context, fn func(context.Context) (any, error), opts ...options.Lister[options.TransactionOptions]) (any, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+	}
+
 	session, err := c.StartSession()
 	if err != nil {
 		return nil, fmt.Errorf("failed to start session: %w", err)
@@ -79,6 +187,9 @@ func (c *Client) WithTransaction(ctx context.Context, fn func(context.Context) (
 	if err != nil {
 		c.config.Logger.Error("Transaction failed",
 			"error", err.Error())
+		if isTransactionsUnsupportedError(err) {
+			return nil, fmt.Errorf("transaction failed: %w: %w", ErrTransactionsUnsupported, err)
+		}
 		return nil, fmt.Errorf("transaction failed: %w", err)
 	}
 
@@ -86,14 +197,43 @@ func (c *Client) WithTransaction(ctx context.Context, fn func(context.Context) (
 	return result, nil
 }
 
+// WithTransactionTyped executes a function within a transaction and returns its typed result.
+// It behaves like Client.WithTransaction but avoids type assertions on the callback result.
+// Go does not allow type parameters on methods, so the client is passed explicitly.
+//
+// Example:
+//
+//	order, err := mongodb.WithTransactionTyped(ctx, client, func(ctx context.Context) (Order, error) {
+//	    // ... transactional operations
+//	    return order, nil
+//	})
+func WithTransactionTyped[T any](ctx context.Context, c *Client, fn func(context.Context) (T, error), opts ...options.Lister[options.TransactionOptions]) (T, error) {
+	var zero T
+
+	result, err := c.WithTransaction(ctx, func(sessCtx context.Context) (any, error) {
+		return fn(sessCtx)
+	}, opts...)
+	if err != nil {
+		return zero, err
+	}
+
+	// A nil interface result cannot be asserted back to T
+	if result == nil {
+		return zero, nil
+	}
+
+	typed, ok := result.(T)
+	if !ok {
+		return zero, fmt.Errorf("transaction returned unexpected result type %T", result)
+	}
+	return typed, nil
+}
+
 // ListDatabases lists all databases
 func (c *Client) ListDatabases(ctx context.Context, filter any, opts ...options.Lister[options.ListDatabasesOptions]) (mongo.ListDatabasesResult, error) {
-	c.mutex.RLock()
-	client := c.client
-	c.mutex.RUnlock()
-
-	if client == nil {
-		return mongo.ListDatabasesResult{}, fmt.Errorf("client is not connected")
+	client, _, err := c.connection()
+	if err != nil {
+		return mongo.ListDatabasesResult{}, err
 	}
 
 	if ctx == nil {
@@ -107,12 +247,9 @@ func (c *Client) ListDatabases(ctx context.Context, filter any, opts ...options.
 
 // ListCollections lists all collections in the current database
 func (c *Client) ListCollections(ctx context.Context, filter any, opts ...options.Lister[options.ListCollectionsOptions]) (*mongo.Cursor, error) {
-	c.mutex.RLock()
-	database := c.database
-	c.mutex.RUnlock()
-
-	if database == nil {
-		return nil, fmt.Errorf("database is not available")
+	_, database, err := c.connection()
+	if err != nil {
+		return nil, err
 	}
 
 	if ctx == nil {
@@ -124,14 +261,12 @@ func (c *Client) ListCollections(ctx context.Context, filter any, opts ...option
 	return database.ListCollections(ctx, filter, opts...)
 }
 
-// DropDatabase drops the current database
+// DropDatabase drops the current database.
+// Dropping a database that does not exist succeeds, so the call is safe to retry.
 func (c *Client) DropDatabase(ctx context.Context) error {
-	c.mutex.RLock()
-	database := c.database
-	c.mutex.RUnlock()
-
-	if database == nil {
-		return fmt.Errorf("database is not available")
+	_, database, err := c.connection()
+	if err != nil {
+		return err
 	}
 
 	if ctx == nil {
@@ -140,7 +275,7 @@ func (c *Client) DropDatabase(ctx context.Context) error {
 		defer cancel()
 	}
 
-	err := database.Drop(ctx)
+	err = ignoreNamespaceNotFound(database.Drop(ctx))
 	if err != nil {
 		c.config.Logger.Error("Failed to drop database",
 			"database", c.config.Database,
@@ -156,12 +291,9 @@ func (c *Client) DropDatabase(ctx context.Context) error {
 
 // GetStats returns database statistics
 func (c *Client) GetStats(ctx context.Context) (bson.M, error) {
-	c.mutex.RLock()
-	database := c.database
-	c.mutex.RUnlock()
-
-	if database == nil {
-		return nil, fmt.Errorf("database is not available")
+	_, database, err := c.connection()
+	if err != nil {
+		return nil, err
 	}
 
 	if ctx == nil {
@@ -171,7 +303,7 @@ func (c *Client) GetStats(ctx context.Context) (bson.M, error) {
 	}
 
 	var result bson.M
-	err := database.RunCommand(ctx, bson.D{bson.E{Key: "dbStats", Value: 1}}).Decode(&result)
+	err = database.RunCommand(ctx, bson.D{bson.E{Key: "dbStats", Value: 1}}).Decode(&result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database stats: %w", err)
 	}
@@ -215,24 +347,64 @@ func (db *Database) Name() string {
 	return db.name
 }
 
-// Drop removes the entire database
+// Raw returns the underlying driver database as an escape hatch for features
+// the wrapper does not expose yet. Operations performed through the returned handle
+// bypass this package's instrumentation and enhancements.
+func (db *Database) Raw() *mongo.Database {
+	return db.database
+}
+
+// Drop removes the entire database.
+// Dropping a database that does not exist succeeds, so the call is safe to retry.
 func (db *Database) Drop(ctx context.Context) error {
-	return db.database.Drop(ctx)
+	if err := db.checkConnected(); err != nil {
+		return err
+	}
+
+	return ignoreNamespaceNotFound(db.database.Drop(ctx))
+}
+
+// DropCollection drops the named collection, succeeding if it does not exist
+func (db *Database) DropCollection(ctx context.Context, name string) error {
+	return db.Collection(name).Drop(ctx)
+}
+
+// ignoreNamespaceNotFound returns nil for NamespaceNotFound (code 26) errors, which some
+// server versions return when dropping a database or collection that does not exist
+func ignoreNamespaceNotFound(err error) error {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == 26 {
+		return nil
+	}
+	return err
 }
 
 // RunCommand executes a database command
 func (db *Database) RunCommand(ctx context.Context, runCommand any) *mongo.SingleResult {
+	if err := db.checkConnected(); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+
 	return db.database.RunCommand(ctx, runCommand)
 }
 
 // ListCollectionNames returns the names of all collections in the database
 func (db *Database) ListCollectionNames(ctx context.Context) ([]string, error) {
+	if err := db.checkConnected(); err != nil {
+		return nil, err
+	}
+
 	return db.database.ListCollectionNames(ctx, struct{}{})
 }
 
-// CreateCollection creates a new collection with the specified name
-func (db *Database) CreateCollection(ctx context.Context, name string) error {
-	return db.database.CreateCollection(ctx, name)
+// CreateCollection creates a new collection with the specified name.
+// Optional driver options can configure capped collections, validators, pre/post images, etc.
+func (db *Database) CreateCollection(ctx context.Context, name string, opts ...options.Lister[options.CreateCollectionOptions]) error {
+	if err := db.checkConnected(); err != nil {
+		return err
+	}
+
+	return db.database.CreateCollection(ctx, name, opts...)
 }
 
 // Client returns the client that this database belongs to
@@ -242,9 +414,21 @@ func (db *Database) Client() *Client {
 
 // Collection returns a collection handle for the specified name
 func (db *Database) Collection(name string) *Collection {
-	return &Collection{
-		collection: db.database.Collection(name),
-		client:     db.client,
-		name:       name,
+	col := &Collection{
+		client: db.client,
+		name:   name,
+	}
+	if db.database != nil {
+		col.collection = db.database.Collection(name)
+	}
+	return col
+}
+
+// checkConnected returns ErrNotConnected when the handle was created by a lazy client that
+// had no driver client yet
+func (db *Database) checkConnected() error {
+	if db.database == nil {
+		return ErrNotConnected
 	}
+	return nil
 }
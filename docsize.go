@@ -0,0 +1,37 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ErrDocumentTooLarge is returned when a document exceeds the size configured with
+// WithMaxDocumentSize. The document is not sent to the server.
+var ErrDocumentTooLarge = errors.New("document exceeds maximum size")
+
+// checkDocumentSize returns ErrDocumentTooLarge when the marshaled document is larger than the
+// configured MaxDocumentSize. It is a no-op when no limit is configured. The description names
+// the document in errors, e.g. "InsertMany document 3".
+func (col *Collection) checkDocumentSize(ctx context.Context, document any, description string) error {
+	limit := col.client.config.MaxDocumentSize
+	if limit <= 0 {
+		return nil
+	}
+
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", description, err)
+	}
+	if len(data) > limit {
+		col.logger(ctx).Error("Document exceeds maximum size",
+			"document", description,
+			"collection", col.name,
+			"size", len(data),
+			"limit", limit)
+		return fmt.Errorf("%w: %s is %d bytes, limit is %d bytes", ErrDocumentTooLarge, description, len(data), limit)
+	}
+	return nil
+}
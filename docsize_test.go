@@ -0,0 +1,67 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMaxDocumentSizeRejectsOversizedDocuments(t *testing.T) {
+	col := newTestCollection("events", withIDMode(IDModeULID))
+	col.client.config.MaxDocumentSize = 1024
+	ctx := context.Background()
+
+	oversized := bson.M{"payload": strings.Repeat("x", 2048)}
+
+	_, err := col.InsertOne(ctx, oversized)
+	if !errors.Is(err, ErrDocumentTooLarge) {
+		t.Fatalf("InsertOne: expected ErrDocumentTooLarge, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "limit is 1024 bytes") {
+		t.Errorf("Expected error to report the limit, got %v", err)
+	}
+
+	_, err = col.InsertMany(ctx, []any{bson.M{"payload": "small"}, oversized})
+	if !errors.Is(err, ErrDocumentTooLarge) || !strings.Contains(err.Error(), "InsertMany document 1") {
+		t.Errorf("InsertMany: expected ErrDocumentTooLarge naming document 1, got %v", err)
+	}
+
+	_, err = col.ReplaceOne(ctx, filter.Eq("_id", "e1"), oversized)
+	if !errors.Is(err, ErrDocumentTooLarge) {
+		t.Errorf("ReplaceOne: expected ErrDocumentTooLarge, got %v", err)
+	}
+}
+
+func TestCheckDocumentSize(t *testing.T) {
+	col := newTestCollection("events", withIDMode(IDModeULID))
+	doc := bson.M{"payload": strings.Repeat("x", 100)}
+
+	// No limit configured
+	if err := col.checkDocumentSize(context.Background(), doc, "test document"); err != nil {
+		t.Errorf("Expected no check without a limit, got %v", err)
+	}
+
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to marshal document: %v", err)
+	}
+
+	// The limit is inclusive
+	col.client.config.MaxDocumentSize = len(data)
+	if err := col.checkDocumentSize(context.Background(), doc, "test document"); err != nil {
+		t.Errorf("Expected document at the limit to pass, got %v", err)
+	}
+
+	col.client.config.MaxDocumentSize = len(data) - 1
+	if err := col.checkDocumentSize(context.Background(), doc, "test document"); !errors.Is(err, ErrDocumentTooLarge) {
+		t.Errorf("Expected ErrDocumentTooLarge one byte over the limit, got %v", err)
+	}
+
+	if err := col.checkDocumentSize(context.Background(), make(chan int), "test document"); err == nil || errors.Is(err, ErrDocumentTooLarge) {
+		t.Errorf("Expected marshal error, got %v", err)
+	}
+}
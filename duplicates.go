@@ -0,0 +1,98 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// FindDuplicates returns the groups of documents sharing the same values for fields, the key
+// a unique index on those fields would reject, so duplicates can be cleaned up before the
+// index build fails part way through. Each group has the form
+//
+//	{_id: {<field>: <value>, ...}, count: <n>, ids: [<_id>, ...]}
+//
+// and groups are sorted by count, largest first, with _id keyed by the field paths as given
+// (address.city for an embedded field). As in a unique index, a missing field counts as
+// null. Soft-deleted documents are included since the index covers them too. An empty result
+// means the index can be created.
+//
+// Example:
+//
+//	groups, err := users.FindDuplicates(ctx, []string{"tenant_id", "email"})
+//	if err != nil {
+//	    return err
+//	}
+//	if len(groups) == 0 {
+//	    _, err = users.CreateIndex(ctx, mongodb.IndexUnique("tenant_id", "email"))
+//	}
+func (col *Collection) FindDuplicates(ctx context.Context, fields []string) ([]bson.M, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("FindDuplicates requires at least one field")
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	cursor, err := col.Aggregate(ctx, duplicatesPipeline(fields))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	groups := []bson.M{}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode duplicate groups: %w", err)
+	}
+	for _, group := range groups {
+		if key, ok := group["_id"].(bson.M); ok {
+			group["_id"] = duplicateFieldValues(key, fields)
+		}
+	}
+	return groups, nil
+}
+
+// duplicatesPipeline groups documents by the values of fields and keeps the groups with more
+// than one document
+func duplicatesPipeline(fields []string) bson.A {
+	key := make(bson.D, 0, len(fields))
+	for i, field := range fields {
+		// A unique index stores missing fields as null, so group them together
+		key = append(key, bson.E{Key: duplicateGroupKey(i), Value: bson.M{"$ifNull": bson.A{"$" + field, nil}}})
+	}
+
+	return bson.A{
+		bson.M{"$group": bson.M{
+			"_id":   key,
+			"count": bson.M{"$sum": 1},
+			"ids":   bson.M{"$push": "$_id"},
+		}},
+		bson.M{"$match": bson.M{"count": bson.M{"$gt": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}}},
+	}
+}
+
+// duplicateGroupKey returns the key of the i-th field in the _id of a duplicate group. The
+// server does not accept dots in _id keys, so fields are keyed by position rather than by
+// path, which also keeps address.city and address_city apart.
+func duplicateGroupKey(i int) string {
+	return "f" + strconv.Itoa(i)
+}
+
+// duplicateFieldValues rekeys the positional _id of a duplicate group by field path
+func duplicateFieldValues(key bson.M, fields []string) bson.M {
+	values := make(bson.M, len(fields))
+	for i, field := range fields {
+		values[field] = key[duplicateGroupKey(i)]
+	}
+	return values
+}
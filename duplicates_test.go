@@ -0,0 +1,143 @@
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestDuplicatesPipeline(t *testing.T) {
+	stages := duplicatesPipeline([]string{"tenant_id", "email"})
+
+	expected := bson.A{
+		bson.M{"$group": bson.M{
+			"_id": bson.D{
+				{Key: "f0", Value: bson.M{"$ifNull": bson.A{"$tenant_id", nil}}},
+				{Key: "f1", Value: bson.M{"$ifNull": bson.A{"$email", nil}}},
+			},
+			"count": bson.M{"$sum": 1},
+			"ids":   bson.M{"$push": "$_id"},
+		}},
+		bson.M{"$match": bson.M{"count": bson.M{"$gt": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}}},
+	}
+	if !reflect.DeepEqual(stages, expected) {
+		t.Errorf("Expected %v, got %v", expected, stages)
+	}
+}
+
+func TestDuplicatesPipelineEmbeddedField(t *testing.T) {
+	// address.city and address_city are different fields and must not share a key
+	stages := duplicatesPipeline([]string{"address.city", "address_city"})
+
+	key := stages[0].(bson.M)["$group"].(bson.M)["_id"]
+	expected := bson.D{
+		{Key: "f0", Value: bson.M{"$ifNull": bson.A{"$address.city", nil}}},
+		{Key: "f1", Value: bson.M{"$ifNull": bson.A{"$address_city", nil}}},
+	}
+	if !reflect.DeepEqual(key, expected) {
+		t.Errorf("Expected %v, got %v", expected, key)
+	}
+}
+
+func TestDuplicateFieldValues(t *testing.T) {
+	values := duplicateFieldValues(bson.M{"f0": "Oslo", "f1": nil}, []string{"address.city", "address_city"})
+
+	expected := bson.M{"address.city": "Oslo", "address_city": nil}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+}
+
+func TestFindDuplicatesRequiresFields(t *testing.T) {
+	col := newTestCollection("users")
+	if _, err := col.FindDuplicates(context.Background(), nil); err == nil {
+		t.Error("Expected an error without fields")
+	}
+}
+
+func TestFindDuplicatesIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_find_duplicates")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	_, err := col.InsertMany(ctx, []any{
+		bson.M{"_id": "u1", "tenant": "a", "email": "x@example.com"},
+		bson.M{"_id": "u2", "tenant": "a", "email": "x@example.com"},
+		bson.M{"_id": "u3", "tenant": "a", "email": "x@example.com"},
+		bson.M{"_id": "u4", "tenant": "b", "email": "x@example.com"},
+		bson.M{"_id": "u5", "tenant": "b", "email": "y@example.com"},
+		// A missing email and a null email collide in a unique index
+		bson.M{"_id": "u6", "tenant": "c"},
+		bson.M{"_id": "u7", "tenant": "c", "email": nil},
+	})
+	if err != nil {
+		t.Fatalf("InsertMany failed: %v", err)
+	}
+
+	groups, err := col.FindDuplicates(ctx, []string{"tenant", "email"})
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 duplicate groups, got %v", groups)
+	}
+
+	largest := groups[0]
+	if largest["count"] != int32(3) {
+		t.Errorf("Expected the largest group first with 3 documents, got %v", largest)
+	}
+	ids, _ := largest["ids"].(bson.A)
+	if !slices.Equal([]any(ids), []any{"u1", "u2", "u3"}) {
+		t.Errorf("Expected ids u1, u2 and u3, got %v", ids)
+	}
+
+	// The unique index cannot be built until the duplicates are removed
+	if _, err := col.CreateIndex(ctx, IndexUnique("tenant", "email")); err == nil {
+		t.Error("Expected the unique index build to fail on duplicates")
+	}
+
+	// Embedded fields, as in a compound unique index on address.city and address.zip
+	_, err = col.InsertMany(ctx, []any{
+		bson.M{"_id": "a1", "address": bson.M{"city": "Oslo", "zip": "0150"}},
+		bson.M{"_id": "a2", "address": bson.M{"city": "Oslo", "zip": "0150"}},
+		bson.M{"_id": "a3", "address": bson.M{"city": "Oslo", "zip": "0151"}},
+	})
+	if err != nil {
+		t.Fatalf("InsertMany failed: %v", err)
+	}
+	embedded, err := col.FindDuplicates(ctx, []string{"address.city", "address.zip"})
+	if err != nil {
+		t.Fatalf("FindDuplicates on embedded fields failed: %v", err)
+	}
+	var addressGroup bson.M
+	for _, group := range embedded {
+		if key, _ := group["_id"].(bson.M); key["address.city"] == "Oslo" {
+			addressGroup = group
+		}
+	}
+	if addressGroup == nil || addressGroup["count"] != int32(2) || addressGroup["_id"].(bson.M)["address.zip"] != "0150" {
+		t.Errorf("Expected a1 and a2 as an address.city/address.zip group, got %v", embedded)
+	}
+
+	if _, err := col.DeleteMany(ctx, nil); err != nil {
+		t.Fatalf("DeleteMany failed: %v", err)
+	}
+	if groups, err := col.FindDuplicates(ctx, []string{"tenant", "email"}); err != nil || len(groups) != 0 {
+		t.Errorf("Expected no duplicates in an empty collection, got %v, %v", groups, err)
+	}
+}
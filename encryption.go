@@ -0,0 +1,138 @@
+package mongodb
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrInvalidAutoEncryption is returned by client creation when the auto encryption settings
+// are incomplete
+var ErrInvalidAutoEncryption = errors.New("invalid auto encryption options")
+
+// localMasterKeySize is the size in bytes of a local KMS provider master key
+const localMasterKeySize = 96
+
+// kmsRequiredFields lists the credentials each KMS provider type needs. Cloud providers may
+// instead be configured with an empty map, in which case the driver fetches credentials
+// from the environment on demand.
+var kmsRequiredFields = map[string][]string{
+	"aws":   {"accessKeyId", "secretAccessKey"},
+	"azure": {"tenantId", "clientId", "clientSecret"},
+	"gcp":   {"email", "privateKey"},
+	"kmip":  {"endpoint"},
+	"local": {"key"},
+}
+
+// onDemandKMSProviders are the provider types that accept an empty map for on-demand credentials
+var onDemandKMSProviders = []string{"aws", "azure", "gcp"}
+
+// AutoEncryptionOptions configures Client-Side Field Level Encryption. Fields named in the
+// schema map are encrypted on writes and decrypted on reads transparently.
+//
+// Automatic encryption requires building with the "cse" build tag and libmongocrypt, plus
+// the crypt_shared library or mongocryptd at runtime; client creation fails otherwise.
+type AutoEncryptionOptions struct {
+	// KeyVaultNamespace is the "database.collection" holding the data encryption keys
+	KeyVaultNamespace string
+
+	// KMSProviders maps a provider name ("local", "aws", "azure", "gcp", "kmip", or a named
+	// provider such as "aws:eu") to its credentials, e.g. {"local": {"key": masterKey}}
+	KMSProviders map[string]map[string]any
+
+	// SchemaMap maps "database.collection" namespaces to their $jsonSchema with encrypt
+	// annotations. Namespaces not listed use the server-side schema, if any.
+	SchemaMap map[string]any
+
+	// BypassAutoEncryption disables encryption of writes while still decrypting reads
+	BypassAutoEncryption bool
+
+	// ExtraOptions configures the query analysis component, e.g. cryptSharedLibPath
+	ExtraOptions map[string]any
+
+	// TLSConfig maps KMS provider names to the TLS configuration used to reach them
+	TLSConfig map[string]*tls.Config
+}
+
+// validate checks that the key vault namespace is well formed and that every KMS provider
+// has the credentials it needs
+func (o *AutoEncryptionOptions) validate() error {
+	db, coll, found := strings.Cut(o.KeyVaultNamespace, ".")
+	if !found || db == "" || coll == "" {
+		return fmt.Errorf("%w: key vault namespace %q must have the form database.collection",
+			ErrInvalidAutoEncryption, o.KeyVaultNamespace)
+	}
+
+	if len(o.KMSProviders) == 0 {
+		return fmt.Errorf("%w: at least one KMS provider is required", ErrInvalidAutoEncryption)
+	}
+
+	// Sort names so the reported error is deterministic
+	names := make([]string, 0, len(o.KMSProviders))
+	for name := range o.KMSProviders {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if err := validateKMSProvider(name, o.KMSProviders[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateKMSProvider checks a single KMS provider entry
+func validateKMSProvider(name string, credentials map[string]any) error {
+	// Named providers have the form "type:name"
+	providerType, _, _ := strings.Cut(name, ":")
+
+	required, known := kmsRequiredFields[providerType]
+	if !known {
+		return fmt.Errorf("%w: unknown KMS provider %q", ErrInvalidAutoEncryption, name)
+	}
+
+	if len(credentials) == 0 && slices.Contains(onDemandKMSProviders, providerType) {
+		return nil
+	}
+
+	for _, field := range required {
+		value, ok := credentials[field]
+		if !ok || value == nil || value == "" {
+			return fmt.Errorf("%w: KMS provider %q requires %q", ErrInvalidAutoEncryption, name, field)
+		}
+	}
+
+	if providerType == "local" {
+		key, ok := credentials["key"].([]byte)
+		if !ok || len(key) != localMasterKeySize {
+			return fmt.Errorf("%w: KMS provider %q key must be %d bytes", ErrInvalidAutoEncryption, name, localMasterKeySize)
+		}
+	}
+	return nil
+}
+
+// toDriverOptions converts the options to the driver's auto encryption options
+func (o *AutoEncryptionOptions) toDriverOptions() *options.AutoEncryptionOptions {
+	opts := options.AutoEncryption().
+		SetKeyVaultNamespace(o.KeyVaultNamespace).
+		SetKmsProviders(o.KMSProviders)
+
+	if o.SchemaMap != nil {
+		opts.SetSchemaMap(o.SchemaMap)
+	}
+	if o.BypassAutoEncryption {
+		opts.SetBypassAutoEncryption(true)
+	}
+	if o.ExtraOptions != nil {
+		opts.SetExtraOptions(o.ExtraOptions)
+	}
+	if o.TLSConfig != nil {
+		opts.SetTLSConfig(o.TLSConfig)
+	}
+	return opts
+}
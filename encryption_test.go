@@ -0,0 +1,166 @@
+package mongodb
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAutoEncryptionOptionsValidate(t *testing.T) {
+	localKey := bytes.Repeat([]byte{1}, localMasterKeySize)
+
+	tests := []struct {
+		name    string
+		opts    AutoEncryptionOptions
+		wantErr string
+	}{
+		{
+			name: "local provider",
+			opts: AutoEncryptionOptions{
+				KeyVaultNamespace: "encryption.__keyVault",
+				KMSProviders:      map[string]map[string]any{"local": {"key": localKey}},
+			},
+		},
+		{
+			name: "aws with credentials and named local provider",
+			opts: AutoEncryptionOptions{
+				KeyVaultNamespace: "encryption.__keyVault",
+				KMSProviders: map[string]map[string]any{
+					"aws":          {"accessKeyId": "AKIA", "secretAccessKey": "secret"},
+					"local:backup": {"key": localKey},
+				},
+			},
+		},
+		{
+			name: "cloud provider with on-demand credentials",
+			opts: AutoEncryptionOptions{
+				KeyVaultNamespace: "encryption.__keyVault",
+				KMSProviders:      map[string]map[string]any{"gcp": {}},
+			},
+		},
+		{
+			name: "missing namespace collection",
+			opts: AutoEncryptionOptions{
+				KeyVaultNamespace: "encryption",
+				KMSProviders:      map[string]map[string]any{"local": {"key": localKey}},
+			},
+			wantErr: "database.collection",
+		},
+		{
+			name:    "no providers",
+			opts:    AutoEncryptionOptions{KeyVaultNamespace: "encryption.__keyVault"},
+			wantErr: "at least one KMS provider",
+		},
+		{
+			name: "unknown provider",
+			opts: AutoEncryptionOptions{
+				KeyVaultNamespace: "encryption.__keyVault",
+				KMSProviders:      map[string]map[string]any{"vault": {"token": "t"}},
+			},
+			wantErr: `unknown KMS provider "vault"`,
+		},
+		{
+			name: "aws missing secret",
+			opts: AutoEncryptionOptions{
+				KeyVaultNamespace: "encryption.__keyVault",
+				KMSProviders:      map[string]map[string]any{"aws": {"accessKeyId": "AKIA"}},
+			},
+			wantErr: `requires "secretAccessKey"`,
+		},
+		{
+			name: "local without key",
+			opts: AutoEncryptionOptions{
+				KeyVaultNamespace: "encryption.__keyVault",
+				KMSProviders:      map[string]map[string]any{"local": {}},
+			},
+			wantErr: `requires "key"`,
+		},
+		{
+			name: "local key of wrong size",
+			opts: AutoEncryptionOptions{
+				KeyVaultNamespace: "encryption.__keyVault",
+				KMSProviders:      map[string]map[string]any{"local": {"key": []byte("short")}},
+			},
+			wantErr: "must be 96 bytes",
+		},
+		{
+			name: "kmip without endpoint",
+			opts: AutoEncryptionOptions{
+				KeyVaultNamespace: "encryption.__keyVault",
+				KMSProviders:      map[string]map[string]any{"kmip": {}},
+			},
+			wantErr: `requires "endpoint"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected valid options, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidAutoEncryption) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected ErrInvalidAutoEncryption mentioning %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAutoEncryptionDriverOptions(t *testing.T) {
+	localKey := bytes.Repeat([]byte{1}, localMasterKeySize)
+	schema := map[string]any{"bsonType": "object"}
+	kmsTLS := map[string]*tls.Config{"kmip": {ServerName: "kms.example.com"}}
+
+	config := newDefaultConfig()
+	WithAutoEncryption(AutoEncryptionOptions{
+		KeyVaultNamespace:    "encryption.__keyVault",
+		KMSProviders:         map[string]map[string]any{"local": {"key": localKey}},
+		SchemaMap:            map[string]any{"app.patients": schema},
+		BypassAutoEncryption: true,
+		ExtraOptions:         map[string]any{"cryptSharedLibPath": "/opt/mongo_crypt_v1.so"},
+		TLSConfig:            kmsTLS,
+	})(config)
+
+	client := &Client{config: config}
+	opts := client.buildClientOptions().AutoEncryptionOptions
+	if opts == nil {
+		t.Fatal("Expected auto encryption options on the client options")
+	}
+
+	if opts.KeyVaultNamespace != "encryption.__keyVault" {
+		t.Errorf("Unexpected key vault namespace %q", opts.KeyVaultNamespace)
+	}
+	if key, _ := opts.KmsProviders["local"]["key"].([]byte); !bytes.Equal(key, localKey) {
+		t.Errorf("Expected local master key to be passed through, got %v", opts.KmsProviders)
+	}
+	if opts.SchemaMap["app.patients"] == nil {
+		t.Errorf("Expected schema map entry, got %v", opts.SchemaMap)
+	}
+	if opts.BypassAutoEncryption == nil || !*opts.BypassAutoEncryption {
+		t.Error("Expected BypassAutoEncryption to be set")
+	}
+	if opts.ExtraOptions["cryptSharedLibPath"] != "/opt/mongo_crypt_v1.so" {
+		t.Errorf("Unexpected extra options %v", opts.ExtraOptions)
+	}
+	if opts.TLSConfig["kmip"] != kmsTLS["kmip"] {
+		t.Errorf("Expected KMS TLS config to be passed through")
+	}
+
+	// Without WithAutoEncryption no encryption options are set
+	plain := &Client{config: newDefaultConfig()}
+	if plain.buildClientOptions().AutoEncryptionOptions != nil {
+		t.Error("Expected no auto encryption options by default")
+	}
+}
+
+func TestNewClientRejectsInvalidAutoEncryption(t *testing.T) {
+	_, err := NewClient(WithAutoEncryption(AutoEncryptionOptions{KeyVaultNamespace: "encryption.__keyVault"}))
+	if !errors.Is(err, ErrInvalidAutoEncryption) {
+		t.Errorf("Expected ErrInvalidAutoEncryption before connecting, got %v", err)
+	}
+}
@@ -3,11 +3,16 @@ package mongodb
 import (
 	"errors"
 	"fmt"
+	"os"
 	"slices"
+	"strings"
 
 	"github.com/cloudresty/go-env"
 )
 
+// ErrDatabaseNotSet is returned when StrictDatabase is enabled and no database name was configured
+var ErrDatabaseNotSet = errors.New("database name is not configured")
+
 // loadConfigFromEnv loads MongoDB configuration from environment variables (internal function)
 func loadConfigFromEnv(prefix string) (*Config, error) {
 	// Create empty config struct - go-env v1.0.1 will apply defaults from struct tags
@@ -28,6 +33,10 @@ func loadConfigFromEnv(prefix string) (*Config, error) {
 		config.Logger = NopLogger{}
 	}
 
+	// Distinguish an explicit database name from the struct tag default,
+	// so that NewClient can enforce StrictDatabase
+	config.databaseSet = os.Getenv(prefix+EnvMongoDBDatabase) != ""
+
 	// Validate the final configuration
 	if err := validateConfig(config); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -73,10 +82,16 @@ func isValidReadPreference(pref string) bool {
 	return slices.Contains(validPrefs, pref)
 }
 
-// isValidCompressionAlgorithm checks if the compression algorithm is valid
+// isValidCompressionAlgorithm checks if the compression algorithm, or each algorithm of a
+// comma-separated list, is valid
 func isValidCompressionAlgorithm(algo string) bool {
 	validAlgos := []string{"snappy", "zlib", "zstd"}
-	return slices.Contains(validAlgos, algo)
+	for name := range strings.SplitSeq(algo, ",") {
+		if !slices.Contains(validAlgos, name) {
+			return false
+		}
+	}
+	return true
 }
 
 // isValidLogLevel checks if the log level is valid
@@ -99,30 +114,47 @@ func isValidIDMode(mode string) bool {
 
 // Environment variable names for reference
 const (
-	EnvMongoDBHosts                = "MONGODB_HOSTS"
-	EnvMongoDBUsername             = "MONGODB_USERNAME"
-	EnvMongoDBPassword             = "MONGODB_PASSWORD"
-	EnvMongoDBDatabase             = "MONGODB_DATABASE"
-	EnvMongoDBAuthDatabase         = "MONGODB_AUTH_DATABASE"
-	EnvMongoDBReplicaSet           = "MONGODB_REPLICA_SET"
-	EnvMongoDBMaxPoolSize          = "MONGODB_MAX_POOL_SIZE"
-	EnvMongoDBMinPoolSize          = "MONGODB_MIN_POOL_SIZE"
-	EnvMongoDBMaxIdleTime          = "MONGODB_MAX_IDLE_TIME"
-	EnvMongoDBMaxConnIdleTime      = "MONGODB_MAX_CONN_IDLE_TIME"
-	EnvMongoDBConnectTimeout       = "MONGODB_CONNECT_TIMEOUT"
-	EnvMongoDBServerSelectTimeout  = "MONGODB_SERVER_SELECT_TIMEOUT"
-	EnvMongoDBSocketTimeout        = "MONGODB_SOCKET_TIMEOUT"
-	EnvMongoDBHealthCheckEnabled   = "MONGODB_HEALTH_CHECK_ENABLED"
-	EnvMongoDBHealthCheckInterval  = "MONGODB_HEALTH_CHECK_INTERVAL"
-	EnvMongoDBCompressionEnabled   = "MONGODB_COMPRESSION_ENABLED"
-	EnvMongoDBCompressionAlgorithm = "MONGODB_COMPRESSION_ALGORITHM"
-	EnvMongoDBReadPreference       = "MONGODB_READ_PREFERENCE"
-	EnvMongoDBWriteConcern         = "MONGODB_WRITE_CONCERN"
-	EnvMongoDBReadConcern          = "MONGODB_READ_CONCERN"
-	EnvMongoDBDirectConnection     = "MONGODB_DIRECT_CONNECTION"
-	EnvMongoDBAppName              = "MONGODB_APP_NAME"
-	EnvMongoDBConnectionName       = "MONGODB_CONNECTION_NAME"
-	EnvMongoDBIDMode               = "MONGODB_ID_MODE"
-	EnvMongoDBLogLevel             = "MONGODB_LOG_LEVEL"
-	EnvMongoDBLogFormat            = "MONGODB_LOG_FORMAT"
+	EnvMongoDBHosts                   = "MONGODB_HOSTS"
+	EnvMongoDBUsername                = "MONGODB_USERNAME"
+	EnvMongoDBPassword                = "MONGODB_PASSWORD"
+	EnvMongoDBDatabase                = "MONGODB_DATABASE"
+	EnvMongoDBStrictDatabase          = "MONGODB_STRICT_DATABASE"
+	EnvMongoDBLazyConnect             = "MONGODB_LAZY_CONNECT"
+	EnvMongoDBAuthDatabase            = "MONGODB_AUTH_DATABASE"
+	EnvMongoDBReplicaSet              = "MONGODB_REPLICA_SET"
+	EnvMongoDBSRV                     = "MONGODB_SRV"
+	EnvMongoDBMaxPoolSize             = "MONGODB_MAX_POOL_SIZE"
+	EnvMongoDBMinPoolSize             = "MONGODB_MIN_POOL_SIZE"
+	EnvMongoDBWarmPool                = "MONGODB_WARM_POOL"
+	EnvMongoDBWriteRateLimit          = "MONGODB_WRITE_RATE_LIMIT"
+	EnvMongoDBMaxDocumentSize         = "MONGODB_MAX_DOCUMENT_SIZE"
+	EnvMongoDBPreciseCount            = "MONGODB_PRECISE_COUNT"
+	EnvMongoDBMaxIdleTime             = "MONGODB_MAX_IDLE_TIME"
+	EnvMongoDBMaxConnIdleTime         = "MONGODB_MAX_CONN_IDLE_TIME"
+	EnvMongoDBConnectTimeout          = "MONGODB_CONNECT_TIMEOUT"
+	EnvMongoDBServerSelectTimeout     = "MONGODB_SERVER_SELECT_TIMEOUT"
+	EnvMongoDBSocketTimeout           = "MONGODB_SOCKET_TIMEOUT"
+	EnvMongoDBHeartbeatInterval       = "MONGODB_HEARTBEAT_INTERVAL"
+	EnvMongoDBMaxTime                 = "MONGODB_MAX_TIME"
+	EnvMongoDBOperationRetryAttempts  = "MONGODB_OPERATION_RETRY_ATTEMPTS"
+	EnvMongoDBOperationRetryBackoff   = "MONGODB_OPERATION_RETRY_BACKOFF"
+	EnvMongoDBHealthCheckEnabled      = "MONGODB_HEALTH_CHECK_ENABLED"
+	EnvMongoDBHealthCheckInterval     = "MONGODB_HEALTH_CHECK_INTERVAL"
+	EnvMongoDBPoolSaturationThreshold = "MONGODB_POOL_SATURATION_THRESHOLD"
+	EnvMongoDBPoolSaturationDuration  = "MONGODB_POOL_SATURATION_DURATION"
+	EnvMongoDBCompressionEnabled      = "MONGODB_COMPRESSION_ENABLED"
+	EnvMongoDBCompressionAlgorithm    = "MONGODB_COMPRESSION_ALGORITHM"
+	EnvMongoDBReadPreference          = "MONGODB_READ_PREFERENCE"
+	EnvMongoDBWriteConcern            = "MONGODB_WRITE_CONCERN"
+	EnvMongoDBReadConcern             = "MONGODB_READ_CONCERN"
+	EnvMongoDBDirectConnection        = "MONGODB_DIRECT_CONNECTION"
+	EnvMongoDBServerAPIVersion        = "MONGODB_SERVER_API_VERSION"
+	EnvMongoDBServerAPIStrict         = "MONGODB_SERVER_API_STRICT"
+	EnvMongoDBAppName                 = "MONGODB_APP_NAME"
+	EnvMongoDBAppNameSuffix           = "MONGODB_APP_NAME_SUFFIX"
+	EnvMongoDBConnectionName          = "MONGODB_CONNECTION_NAME"
+	EnvMongoDBIDMode                  = "MONGODB_ID_MODE"
+	EnvMongoDBLogLevel                = "MONGODB_LOG_LEVEL"
+	EnvMongoDBLogFormat               = "MONGODB_LOG_FORMAT"
+	EnvMongoDBLogQueriesOnError       = "MONGODB_LOG_QUERIES_ON_ERROR"
 )
@@ -1,7 +1,9 @@
 package mongodb
 
 import (
+	"errors"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -304,3 +306,104 @@ func TestEnvDefaults(t *testing.T) {
 		t.Errorf("Expected default app name 'go-mongodb-app', got '%s'", config.AppName)
 	}
 }
+
+func TestStrictDatabase(t *testing.T) {
+	// Strict mode fails fast without a database, before any connection attempt
+	_, err := NewClient(WithStrictDatabase(true))
+	if !errors.Is(err, ErrDatabaseNotSet) {
+		t.Errorf("Expected ErrDatabaseNotSet, got %v", err)
+	}
+
+	config := &Config{}
+	WithStrictDatabase(true)(config)
+	WithDatabase("orders")(config)
+	if err := config.checkDatabase(); err != nil {
+		t.Errorf("Expected explicit database to pass strict check, got %v", err)
+	}
+
+	WithDatabase("")(config)
+	if err := config.checkDatabase(); !errors.Is(err, ErrDatabaseNotSet) {
+		t.Errorf("Expected ErrDatabaseNotSet for empty database, got %v", err)
+	}
+
+	// Lenient mode keeps the default database
+	lenient := &Config{Database: "app"}
+	if err := lenient.checkDatabase(); err != nil {
+		t.Errorf("Expected lenient mode to accept default database, got %v", err)
+	}
+}
+
+func TestStrictDatabaseFromEnv(t *testing.T) {
+	t.Setenv("MONGODB_STRICT_DATABASE", "true")
+	// t.Setenv restores the original value, Unsetenv leaves the variable unset for the test
+	t.Setenv("MONGODB_DATABASE", "")
+	_ = os.Unsetenv("MONGODB_DATABASE")
+
+	config, err := loadConfigFromEnv("")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if !config.StrictDatabase {
+		t.Error("Expected StrictDatabase to be loaded from environment")
+	}
+	if config.Database != "app" {
+		t.Errorf("Expected default database 'app', got '%s'", config.Database)
+	}
+	if err := config.checkDatabase(); !errors.Is(err, ErrDatabaseNotSet) {
+		t.Errorf("Expected ErrDatabaseNotSet when MONGODB_DATABASE is unset, got %v", err)
+	}
+
+	t.Setenv("MONGODB_DATABASE", "orders")
+	config, err = loadConfigFromEnv("")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if err := config.checkDatabase(); err != nil {
+		t.Errorf("Expected MONGODB_DATABASE to satisfy strict mode, got %v", err)
+	}
+}
+
+func TestStrictDatabaseOptionSurvivesFromEnv(t *testing.T) {
+	t.Setenv("MONGODB_STRICT_DATABASE", "false")
+	t.Setenv("MONGODB_DATABASE", "")
+	_ = os.Unsetenv("MONGODB_DATABASE")
+
+	logger := &warnRecorder{}
+	_, err := NewClient(WithStrictDatabase(true), FromEnv(), WithLogger(logger))
+	if !errors.Is(err, ErrDatabaseNotSet) {
+		t.Errorf("Expected ErrDatabaseNotSet with strict option before FromEnv, got %v", err)
+	}
+	if len(logger.warnings) != 1 {
+		t.Errorf("Expected one warning in strict mode, got %v", logger.warnings)
+	}
+
+	config := &Config{}
+	WithStrictDatabase(true)(config)
+	FromEnvWithPrefix("")(config)
+	if !config.StrictDatabase {
+		t.Error("Expected FromEnvWithPrefix to keep WithStrictDatabase(true)")
+	}
+
+	t.Setenv("MONGODB_STRICT_DATABASE", "true")
+	config = &Config{}
+	WithStrictDatabase(false)(config)
+	FromEnv()(config)
+	if config.StrictDatabase {
+		t.Error("Expected WithStrictDatabase(false) to override MONGODB_STRICT_DATABASE")
+	}
+}
+
+func TestLenientDatabaseDoesNotWarn(t *testing.T) {
+	logger := &warnRecorder{}
+	client, err := NewClient(WithLazyConnect(true), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("Failed to create lazy client: %v", err)
+	}
+	defer client.Close()
+
+	for _, msg := range logger.warnings {
+		if strings.Contains(msg, "database") {
+			t.Errorf("Expected no database warning in lenient mode, got %q", msg)
+		}
+	}
+}
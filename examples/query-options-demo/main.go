@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/cloudresty/go-mongodb/v2"
+	"github.com/cloudresty/go-mongodb/v2/expr"
 	"github.com/cloudresty/go-mongodb/v2/filter"
 	"github.com/cloudresty/go-mongodb/v2/pipeline"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -122,15 +123,10 @@ func main() {
 	analyticsPipeline := pipeline.New().
 		Match(filter.Eq("status", "active")).
 		AddFields(bson.M{
-			"ageGroup": bson.M{
-				"$switch": bson.M{
-					"branches": []bson.M{
-						{"case": bson.M{"$lt": []any{"$age", 25}}, "then": "young"},
-						{"case": bson.M{"$lt": []any{"$age", 40}}, "then": "adult"},
-					},
-					"default": "senior",
-				},
-			},
+			"ageGroup": expr.Switch(
+				expr.Case(expr.Lt("$age", 25), "young"),
+				expr.Case(expr.Lt("$age", 40), "adult"),
+			).Default("senior"),
 		}).
 		Group("$ageGroup", bson.M{
 			"count":  bson.M{"$sum": 1},
@@ -238,9 +234,7 @@ func main() {
 				"avgAge": bson.M{"$avg": "$age"},
 				"count":  bson.M{"$sum": 1},
 			}).Build(),
-			"topCategories": pipeline.Group("$category", bson.M{
-				"count": bson.M{"$sum": 1},
-			}).Sort(bson.D{{Key: "count", Value: -1}}).Limit(5).Build(),
+			"topCategories": pipeline.SortByCount("$category").Limit(5).Build(),
 		})
 
 	facetResult, err := collection.AggregateWithPipeline(ctx, complexPipeline)
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/cloudresty/go-mongodb/v2"
+	"github.com/cloudresty/go-mongodb/v2/expr"
 	"github.com/cloudresty/go-mongodb/v2/filter"
 	"github.com/cloudresty/go-mongodb/v2/pipeline"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -333,15 +334,10 @@ func demonstrateAdvancedPipelines(collection *mongodb.Collection, ctx context.Co
 	analysisPipeline := pipeline.New().
 		Match(filter.Eq("status", "active")).
 		AddFields(bson.M{
-			"experienceLevel": bson.M{
-				"$switch": bson.M{
-					"branches": []bson.M{
-						{"case": bson.M{"$gte": []any{"$age", 35}}, "then": "Senior"},
-						{"case": bson.M{"$gte": []any{"$age", 30}}, "then": "Mid-level"},
-					},
-					"default": "Junior",
-				},
-			},
+			"experienceLevel": expr.Switch(
+				expr.Case(expr.Gte("$age", 35), "Senior"),
+				expr.Case(expr.Gte("$age", 30), "Mid-level"),
+			).Default("Junior"),
 		}).
 		Group("$experienceLevel", bson.M{
 			"count":     bson.M{"$sum": 1},
@@ -42,6 +42,17 @@ func main() {
 	}
 	defer func() { _ = client.Close() }()
 
+	// Check the topology up front instead of matching the server error later
+	supported, err := client.SupportsTransactions(context.Background())
+	if err != nil {
+		log.Printf("Failed to check transaction support: %v", err)
+		os.Exit(1)
+	}
+	if !supported {
+		log.Println("Transactions are not supported on a standalone server; run against a replica set")
+		return
+	}
+
 	database := client.Database("transactions_example")
 	ordersCollection := database.Collection("orders")
 	inventoryCollection := database.Collection("inventory")
@@ -0,0 +1,305 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// explainFind runs the explain command for a find with the given filter and sort
+// using the "queryPlanner" verbosity and returns the raw explain output.
+func (col *Collection) explainFind(ctx context.Context, filterDoc bson.M, sort bson.D) (bson.Raw, error) {
+	findCmd := bson.D{
+		{Key: "find", Value: col.name},
+		{Key: "filter", Value: filterDoc},
+	}
+	if len(sort) > 0 {
+		findCmd = append(findCmd, bson.E{Key: "sort", Value: sort})
+	}
+
+	cmd := bson.D{
+		{Key: "explain", Value: findCmd},
+		{Key: "verbosity", Value: "queryPlanner"},
+	}
+
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	raw, err := col.collection.Database().RunCommand(ctx, cmd).Raw()
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	return raw, nil
+}
+
+// SuggestIndexes runs explain for the given filter and sort and, when the winning plan
+// performs a collection scan or an in-memory sort, proposes an index covering the query.
+// The suggested key order follows the equality-sort-range guideline.
+//
+// This is a heuristic developer aid, not a guarantee: review suggestions before creating them.
+// Returns an empty slice when the current plan already uses an index for both predicate and sort.
+//
+// Example:
+//
+//	suggestions, err := col.SuggestIndexes(ctx, filter.Eq("status", "active"), SortDesc("created_at"))
+//	for _, model := range suggestions {
+//	    fmt.Println(model.Keys)
+//	}
+func (col *Collection) SuggestIndexes(ctx context.Context, filterBuilder *filter.Builder, sort SortSpec) ([]IndexModel, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	// Build filter document
+	filterDoc := bson.M{}
+	if filterBuilder != nil {
+		filterDoc = filterBuilder.Build()
+	}
+	sortDoc := convertSortSpec(sort)
+
+	explain, err := col.explainFind(ctx, filterDoc, sortDoc)
+	if err != nil {
+		col.logger(ctx).Error("Failed to explain query for index suggestion",
+			"error", err.Error(),
+			"collection", col.name)
+		return nil, err
+	}
+
+	suggestions := suggestIndexesFromExplain(explain, filterDoc, sortDoc)
+
+	col.logger(ctx).Debug("Index suggestions computed",
+		"collection", col.name,
+		"count", len(suggestions))
+
+	return suggestions, nil
+}
+
+// WouldScanCollection runs explain in queryPlanner mode for the given filter and sort and
+// reports whether the winning plan performs a full collection scan (COLLSCAN). The query is
+// planned but not executed, so it is cheap enough for startup checks or tests asserting that
+// hot-path queries are backed by an index. On sharded clusters it reports true if any shard
+// would scan.
+//
+// Example:
+//
+//	scan, err := col.WouldScanCollection(ctx, filter.Eq("customer_id", id), SortDesc("created_at"))
+//	if err != nil {
+//	    return err
+//	}
+//	if scan {
+//	    return fmt.Errorf("orders by customer_id is not indexed")
+//	}
+func (col *Collection) WouldScanCollection(ctx context.Context, filterBuilder *filter.Builder, sort SortSpec) (bool, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	// Build filter document
+	filterDoc := bson.M{}
+	if filterBuilder != nil {
+		filterDoc = filterBuilder.Build()
+	}
+
+	explain, err := col.explainFind(ctx, filterDoc, convertSortSpec(sort))
+	if err != nil {
+		col.logger(ctx).Error("Failed to explain query for collection scan check",
+			"error", err.Error(),
+			"collection", col.name)
+		return false, err
+	}
+
+	return planScansCollection(explain), nil
+}
+
+// planScansCollection reports whether the winning plan of an explain document has a COLLSCAN stage
+func planScansCollection(explain bson.Raw) bool {
+	return slices.Contains(winningPlanStages(explain), "COLLSCAN")
+}
+
+// suggestIndexesFromExplain inspects an explain document and returns index suggestions
+// for the given filter and sort when the winning plan shows a COLLSCAN or blocking SORT stage.
+func suggestIndexesFromExplain(explain bson.Raw, filterDoc bson.M, sort bson.D) []IndexModel {
+	stages := winningPlanStages(explain)
+	if !slices.Contains(stages, "COLLSCAN") && !slices.Contains(stages, "SORT") {
+		return []IndexModel{}
+	}
+
+	equality, ranges := classifyFilterFields(filterDoc)
+
+	keys := bson.D{}
+	seen := make(map[string]bool)
+	addKey := func(field string, direction any) {
+		if seen[field] {
+			return
+		}
+		seen[field] = true
+		keys = append(keys, bson.E{Key: field, Value: direction})
+	}
+
+	for _, field := range equality {
+		addKey(field, 1)
+	}
+	for _, elem := range sort {
+		addKey(elem.Key, elem.Value)
+	}
+	for _, field := range ranges {
+		addKey(field, 1)
+	}
+
+	if len(keys) == 0 {
+		return []IndexModel{}
+	}
+	return []IndexModel{{Keys: keys}}
+}
+
+// winningPlanStages returns the names of all stages in the winning plan of an explain document.
+// Both the classic layout (queryPlanner.winningPlan) and the SBE layout
+// (queryPlanner.winningPlan.queryPlan) are supported, as are the per-shard winning plans of
+// a sharded cluster.
+func winningPlanStages(explain bson.Raw) []string {
+	plan, ok := explain.Lookup("queryPlanner", "winningPlan").DocumentOK()
+	if !ok {
+		return nil
+	}
+
+	var stages []string
+	collectPlanStages(unwrapQueryPlan(plan), &stages)
+	return stages
+}
+
+// unwrapQueryPlan returns the queryPlan of an SBE winning plan, or the plan itself
+func unwrapQueryPlan(plan bson.Raw) bson.Raw {
+	if queryPlan, ok := plan.Lookup("queryPlan").DocumentOK(); ok {
+		return queryPlan
+	}
+	return plan
+}
+
+// collectPlanStages walks a plan stage and its inputStage/inputStages recursively, including
+// the winningPlan of each shard under a SHARD_MERGE or SINGLE_SHARD stage
+func collectPlanStages(stage bson.Raw, stages *[]string) {
+	if name, ok := stage.Lookup("stage").StringValueOK(); ok {
+		*stages = append(*stages, name)
+	}
+	if input, ok := stage.Lookup("inputStage").DocumentOK(); ok {
+		collectPlanStages(input, stages)
+	}
+	for _, input := range planDocuments(stage.Lookup("inputStages")) {
+		collectPlanStages(input, stages)
+	}
+	for _, shard := range planDocuments(stage.Lookup("shards")) {
+		if plan, ok := shard.Lookup("winningPlan").DocumentOK(); ok {
+			collectPlanStages(unwrapQueryPlan(plan), stages)
+		}
+	}
+}
+
+// planDocuments returns the documents of an array value, or nil if it is not an array. It is
+// shared by explain plan and validation error parsing.
+func planDocuments(value bson.RawValue) []bson.Raw {
+	array, ok := value.ArrayOK()
+	if !ok {
+		return nil
+	}
+	values, err := array.Values()
+	if err != nil {
+		return nil
+	}
+
+	docs := make([]bson.Raw, 0, len(values))
+	for _, value := range values {
+		if doc, ok := value.DocumentOK(); ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs
+}
+
+// classifyFilterFields splits the fields referenced by a filter into equality and range
+// predicates. Fields are returned in sorted order for deterministic suggestions.
+// Conditions nested in $and are included; $or/$nor branches are ignored.
+func classifyFilterFields(filterDoc bson.M) (equality []string, ranges []string) {
+	eqSet := make(map[string]bool)
+	rangeSet := make(map[string]bool)
+
+	var walk func(doc bson.M)
+	walk = func(doc bson.M) {
+		for field, value := range doc {
+			if field == "$and" {
+				for _, cond := range andConditions(value) {
+					walk(cond)
+				}
+				continue
+			}
+			if strings.HasPrefix(field, "$") {
+				continue
+			}
+			if isRangePredicate(value) {
+				rangeSet[field] = true
+			} else {
+				eqSet[field] = true
+			}
+		}
+	}
+	walk(filterDoc)
+
+	for field := range eqSet {
+		equality = append(equality, field)
+	}
+	for field := range rangeSet {
+		if !eqSet[field] {
+			ranges = append(ranges, field)
+		}
+	}
+	slices.Sort(equality)
+	slices.Sort(ranges)
+	return equality, ranges
+}
+
+// andConditions extracts the conditions of an $and operand
+func andConditions(value any) []bson.M {
+	switch conds := value.(type) {
+	case []bson.M:
+		return conds
+	case bson.A:
+		result := make([]bson.M, 0, len(conds))
+		for _, cond := range conds {
+			if m, ok := cond.(bson.M); ok {
+				result = append(result, m)
+			}
+		}
+		return result
+	case []any:
+		return andConditions(bson.A(conds))
+	}
+	return nil
+}
+
+// isRangePredicate reports whether a field condition is a non-equality predicate
+func isRangePredicate(value any) bool {
+	cond, ok := value.(bson.M)
+	if !ok {
+		return false
+	}
+	for op := range cond {
+		switch op {
+		case "$eq", "$in":
+			continue
+		default:
+			if strings.HasPrefix(op, "$") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// explainFixture builds a raw explain document with the given winning plan
+func explainFixture(t *testing.T, winningPlan bson.M) bson.Raw {
+	t.Helper()
+
+	raw, err := bson.Marshal(bson.M{
+		"queryPlanner": bson.M{
+			"namespace":   "app.orders",
+			"winningPlan": winningPlan,
+		},
+		"ok": 1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal explain fixture: %v", err)
+	}
+	return raw
+}
+
+func TestSuggestIndexesFromExplain(t *testing.T) {
+	tests := []struct {
+		name     string
+		plan     bson.M
+		filter   bson.M
+		sort     bson.D
+		expected []bson.D
+	}{
+		{
+			name:   "Collection scan with equality, sort and range",
+			plan:   bson.M{"stage": "SORT", "inputStage": bson.M{"stage": "COLLSCAN"}},
+			filter: filter.Eq("status", "active").And(filter.Gte("amount", 100)).Build(),
+			sort:   bson.D{{Key: "created_at", Value: -1}},
+			expected: []bson.D{{
+				{Key: "status", Value: 1},
+				{Key: "created_at", Value: -1},
+				{Key: "amount", Value: 1},
+			}},
+		},
+		{
+			name:     "Collection scan without sort",
+			plan:     bson.M{"stage": "COLLSCAN"},
+			filter:   bson.M{"customer_id": "c1"},
+			expected: []bson.D{{{Key: "customer_id", Value: 1}}},
+		},
+		{
+			name: "In-memory sort on top of an index scan",
+			plan: bson.M{
+				"stage": "FETCH",
+				"inputStage": bson.M{
+					"stage":      "SORT",
+					"inputStage": bson.M{"stage": "IXSCAN", "indexName": "status_1"},
+				},
+			},
+			filter: bson.M{"status": bson.M{"$in": bson.A{"new", "paid"}}},
+			sort:   bson.D{{Key: "total", Value: 1}},
+			expected: []bson.D{{
+				{Key: "status", Value: 1},
+				{Key: "total", Value: 1},
+			}},
+		},
+		{
+			name: "SBE layout with nested queryPlan",
+			plan: bson.M{
+				"queryPlan": bson.M{"stage": "COLLSCAN"},
+			},
+			filter:   bson.M{"email": "a@example.com"},
+			expected: []bson.D{{{Key: "email", Value: 1}}},
+		},
+		{
+			name:     "Index scan needs no suggestion",
+			plan:     bson.M{"stage": "FETCH", "inputStage": bson.M{"stage": "IXSCAN", "indexName": "status_1"}},
+			filter:   bson.M{"status": "active"},
+			expected: []bson.D{},
+		},
+		{
+			name:     "Empty query produces no suggestion",
+			plan:     bson.M{"stage": "COLLSCAN"},
+			filter:   bson.M{},
+			expected: []bson.D{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions := suggestIndexesFromExplain(explainFixture(t, tt.plan), tt.filter, tt.sort)
+
+			if len(suggestions) != len(tt.expected) {
+				t.Fatalf("Expected %d suggestions, got %d: %v", len(tt.expected), len(suggestions), suggestions)
+			}
+			for i, model := range suggestions {
+				if !equalBSOND(model.Keys, tt.expected[i]) {
+					t.Errorf("Suggestion %d: expected keys %v, got %v", i, tt.expected[i], model.Keys)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanScansCollection(t *testing.T) {
+	tests := []struct {
+		name     string
+		plan     bson.M
+		expected bool
+	}{
+		{
+			name:     "Collection scan",
+			plan:     bson.M{"stage": "COLLSCAN", "direction": "forward"},
+			expected: true,
+		},
+		{
+			name:     "Collection scan below an in-memory sort",
+			plan:     bson.M{"stage": "SORT", "inputStage": bson.M{"stage": "COLLSCAN"}},
+			expected: true,
+		},
+		{
+			name:     "Index scan",
+			plan:     bson.M{"stage": "FETCH", "inputStage": bson.M{"stage": "IXSCAN", "indexName": "customer_id_1"}},
+			expected: false,
+		},
+		{
+			name:     "SBE layout with nested queryPlan",
+			plan:     bson.M{"queryPlan": bson.M{"stage": "COLLSCAN"}, "slotBasedPlan": bson.M{}},
+			expected: true,
+		},
+		{
+			name: "Index union for $or",
+			plan: bson.M{
+				"stage": "FETCH",
+				"inputStage": bson.M{
+					"stage": "OR",
+					"inputStages": bson.A{
+						bson.M{"stage": "IXSCAN", "indexName": "email_1"},
+						bson.M{"stage": "IXSCAN", "indexName": "phone_1"},
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "Sharded with one shard scanning",
+			plan: bson.M{
+				"stage": "SHARD_MERGE",
+				"shards": bson.A{
+					bson.M{"shardName": "rs0", "winningPlan": bson.M{"stage": "FETCH", "inputStage": bson.M{"stage": "IXSCAN"}}},
+					bson.M{"shardName": "rs1", "winningPlan": bson.M{"queryPlan": bson.M{"stage": "COLLSCAN"}}},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "Sharded with every shard using an index",
+			plan: bson.M{
+				"stage": "SINGLE_SHARD",
+				"shards": bson.A{
+					bson.M{"shardName": "rs0", "winningPlan": bson.M{"stage": "IXSCAN"}},
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if scan := planScansCollection(explainFixture(t, tt.plan)); scan != tt.expected {
+				t.Errorf("Expected collection scan %v, got %v", tt.expected, scan)
+			}
+		})
+	}
+
+	// A response without a query planner section is not reported as a scan
+	raw, _ := bson.Marshal(bson.M{"ok": 1})
+	if planScansCollection(raw) {
+		t.Error("Expected no collection scan without a winning plan")
+	}
+}
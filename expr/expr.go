@@ -0,0 +1,130 @@
+// Package expr builds aggregation expressions, the operator documents used to compute values
+// inside stages such as $addFields, $project, $set or $group, so computed fields read as
+// function calls instead of nested bson.M and bson.A literals.
+//
+// Arguments are field paths such as "$price", literals, or other expressions. Each helper
+// returns the operator document; none of them add stages.
+//
+// Example:
+//
+//	pipeline.New().AddFields(bson.M{
+//		"fullName": expr.Concat("$firstName", " ", "$lastName"),
+//		"total":    expr.Add("$subtotal", "$tax", "$shipping"),
+//		"ageGroup": expr.Switch(
+//			expr.Case(expr.Lt("$age", 25), "young"),
+//			expr.Case(expr.Lt("$age", 40), "adult"),
+//		).Default("senior"),
+//	})
+package expr
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+
+// Concat returns a $concat expression joining strings, e.g. Concat("$first", " ", "$last").
+// The result is null if any argument is null or missing.
+func Concat(parts ...any) bson.M {
+	return operator("$concat", parts)
+}
+
+// Add returns an $add expression summing numbers, or adding milliseconds to a date
+func Add(values ...any) bson.M {
+	return operator("$add", values)
+}
+
+// Subtract returns a $subtract expression computing a - b
+func Subtract(a, b any) bson.M {
+	return bson.M{"$subtract": bson.A{a, b}}
+}
+
+// Multiply returns a $multiply expression computing the product of the values
+func Multiply(values ...any) bson.M {
+	return operator("$multiply", values)
+}
+
+// Divide returns a $divide expression computing a / b. The server fails the operation when
+// b is zero; wrap it in Cond to guard against that.
+func Divide(a, b any) bson.M {
+	return bson.M{"$divide": bson.A{a, b}}
+}
+
+// Cond returns a $cond expression evaluating to then when condition is true and to
+// otherwise when it is false
+func Cond(condition, then, otherwise any) bson.M {
+	return bson.M{"$cond": bson.M{"if": condition, "then": then, "else": otherwise}}
+}
+
+// IfNull returns an $ifNull expression evaluating to value, or to replacement when value is
+// null or missing
+func IfNull(value, replacement any) bson.M {
+	return bson.M{"$ifNull": bson.A{value, replacement}}
+}
+
+// Eq returns an $eq comparison expression (a == b), e.g. as the condition of Cond or Case
+func Eq(a, b any) bson.M {
+	return bson.M{"$eq": bson.A{a, b}}
+}
+
+// Ne returns a $ne comparison expression (a != b)
+func Ne(a, b any) bson.M {
+	return bson.M{"$ne": bson.A{a, b}}
+}
+
+// Gt returns a $gt comparison expression (a > b)
+func Gt(a, b any) bson.M {
+	return bson.M{"$gt": bson.A{a, b}}
+}
+
+// Gte returns a $gte comparison expression (a >= b)
+func Gte(a, b any) bson.M {
+	return bson.M{"$gte": bson.A{a, b}}
+}
+
+// Lt returns a $lt comparison expression (a < b)
+func Lt(a, b any) bson.M {
+	return bson.M{"$lt": bson.A{a, b}}
+}
+
+// Lte returns a $lte comparison expression (a <= b)
+func Lte(a, b any) bson.M {
+	return bson.M{"$lte": bson.A{a, b}}
+}
+
+// Branch is one case of a Switch expression
+type Branch struct {
+	condition any
+	then      any
+}
+
+// Case returns a Switch branch evaluating to then when condition is true
+func Case(condition, then any) Branch {
+	return Branch{condition: condition, then: then}
+}
+
+// SwitchExpr is a $switch expression. It is a document and can be used directly as a value
+// in a stage; call Default to set the value used when no branch matches.
+type SwitchExpr bson.M
+
+// Switch returns a $switch expression evaluating the branches in order and using the value
+// of the first one whose condition is true. Without Default, the server fails the operation
+// when no branch matches.
+func Switch(branches ...Branch) SwitchExpr {
+	docs := bson.A{}
+	for _, branch := range branches {
+		docs = append(docs, bson.M{"case": branch.condition, "then": branch.then})
+	}
+	return SwitchExpr{"$switch": bson.M{"branches": docs}}
+}
+
+// Default sets the value of the expression when no branch matches
+func (s SwitchExpr) Default(value any) SwitchExpr {
+	s["$switch"].(bson.M)["default"] = value
+	return s
+}
+
+// operator returns an expression applying op to a list of arguments
+func operator(op string, args []any) bson.M {
+	list := bson.A{}
+	for _, arg := range args {
+		list = append(list, arg)
+	}
+	return bson.M{op: list}
+}
@@ -0,0 +1,111 @@
+package expr
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestExpressionBuilders(t *testing.T) {
+	tests := []struct {
+		name     string
+		actual   bson.M
+		expected bson.M
+	}{
+		{
+			name:     "Concat",
+			actual:   Concat("$firstName", " ", "$lastName"),
+			expected: bson.M{"$concat": bson.A{"$firstName", " ", "$lastName"}},
+		},
+		{
+			name:     "Add",
+			actual:   Add("$subtotal", "$tax", 5),
+			expected: bson.M{"$add": bson.A{"$subtotal", "$tax", 5}},
+		},
+		{
+			name:     "Subtract",
+			actual:   Subtract("$price", "$discount"),
+			expected: bson.M{"$subtract": bson.A{"$price", "$discount"}},
+		},
+		{
+			name:     "Multiply",
+			actual:   Multiply("$price", "$qty"),
+			expected: bson.M{"$multiply": bson.A{"$price", "$qty"}},
+		},
+		{
+			name:   "Cond with nested expressions",
+			actual: Cond(Eq("$qty", 0), 0, Divide("$total", "$qty")),
+			expected: bson.M{"$cond": bson.M{
+				"if":   bson.M{"$eq": bson.A{"$qty", 0}},
+				"then": 0,
+				"else": bson.M{"$divide": bson.A{"$total", "$qty"}},
+			}},
+		},
+		{
+			name:     "IfNull",
+			actual:   IfNull("$nickname", "$name"),
+			expected: bson.M{"$ifNull": bson.A{"$nickname", "$name"}},
+		},
+		{
+			name:     "Empty Concat",
+			actual:   Concat(),
+			expected: bson.M{"$concat": bson.A{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.actual, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, tt.actual)
+			}
+		})
+	}
+}
+
+func TestComparisons(t *testing.T) {
+	comparisons := map[string]bson.M{
+		"$eq":  Eq("$a", 1),
+		"$ne":  Ne("$a", 1),
+		"$gt":  Gt("$a", 1),
+		"$gte": Gte("$a", 1),
+		"$lt":  Lt("$a", 1),
+		"$lte": Lte("$a", 1),
+	}
+	for op, actual := range comparisons {
+		expected := bson.M{op: bson.A{"$a", 1}}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %v, got %v", expected, actual)
+		}
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	s := Switch(
+		Case(Lt("$age", 25), "young"),
+		Case(Lt("$age", 40), "adult"),
+	)
+
+	expected := bson.M{"$switch": bson.M{"branches": bson.A{
+		bson.M{"case": bson.M{"$lt": bson.A{"$age", 25}}, "then": "young"},
+		bson.M{"case": bson.M{"$lt": bson.A{"$age", 40}}, "then": "adult"},
+	}}}
+	if !reflect.DeepEqual(bson.M(s), expected) {
+		t.Errorf("Expected %v, got %v", expected, s)
+	}
+
+	s = s.Default("senior")
+	if got := s["$switch"].(bson.M)["default"]; got != "senior" {
+		t.Errorf("Expected default senior, got %v", got)
+	}
+
+	// The expression marshals as a plain document inside a stage
+	raw, err := bson.Marshal(bson.M{"$addFields": bson.M{"ageGroup": s}})
+	if err != nil {
+		t.Fatalf("Failed to marshal switch expression: %v", err)
+	}
+	value := bson.Raw(raw).Lookup("$addFields", "ageGroup", "$switch", "default")
+	if str, ok := value.StringValueOK(); !ok || str != "senior" {
+		t.Errorf("Expected marshaled default senior, got %v", value)
+	}
+}
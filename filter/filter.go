@@ -1,10 +1,20 @@
 package filter
 
 import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/internal/bsonutil"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-// Builder represents a fluent filter builder for MongoDB queries
+// Builder represents a fluent filter builder for MongoDB queries.
+//
+// Combinators (And, Or, Not) never modify their receiver or arguments; they return a new
+// Builder holding copies of the combined conditions, so a base filter can safely be reused.
+// Build returns the builder's internal document: do not modify it directly, and use Clone
+// when you need an independent copy to customize.
 type Builder struct {
 	filter bson.M
 }
@@ -24,11 +34,68 @@ func (b *Builder) Build() bson.M {
 	return b.filter
 }
 
+// ToMatchStage returns the filter as an aggregation $match stage, the inverse of
+// pipeline.Match. An empty or nil builder returns {$match: {}}, which matches every document.
+func (b *Builder) ToMatchStage() bson.M {
+	if b == nil {
+		return bson.M{"$match": bson.M{}}
+	}
+	return bson.M{"$match": b.Build()}
+}
+
 // ToBSONM converts the filter to a bson.M for compatibility
 func (b *Builder) ToBSONM() bson.M {
 	return b.Build()
 }
 
+// Clone returns a deep copy of the builder that shares no state with the original
+func (b *Builder) Clone() *Builder {
+	if b == nil {
+		return New()
+	}
+	filter := bsonutil.DeepCopyM(b.filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+	return &Builder{filter: filter}
+}
+
+// Operators returns the sorted, de-duplicated operators present at the top of the filter:
+// logical operators such as $and, $or and $expr, and the operators of each field condition
+// such as $gt in {"age": {"$gt": 18}}. Operators nested inside $and/$or conditions are not
+// included. It is meant for debugging and validation layers, e.g. rejecting $where.
+func (b *Builder) Operators() []string {
+	if b == nil {
+		return []string{}
+	}
+
+	operators := []string{}
+	for key, value := range b.filter {
+		if strings.HasPrefix(key, "$") {
+			operators = append(operators, key)
+			continue
+		}
+
+		switch condition := value.(type) {
+		case bson.M:
+			for op := range condition {
+				if strings.HasPrefix(op, "$") {
+					operators = append(operators, op)
+				}
+			}
+		case bson.D:
+			for _, elem := range condition {
+				if strings.HasPrefix(elem.Key, "$") {
+					operators = append(operators, elem.Key)
+				}
+			}
+		}
+	}
+
+	slices.Sort(operators)
+	return slices.Compact(operators)
+}
+
 // Comparison Operators
 
 // Eq creates an equality filter
@@ -73,6 +140,37 @@ func Lte(field string, value any) *Builder {
 	}
 }
 
+// Between creates an inclusive range filter matching min <= field <= max
+func Between(field string, min, max any) *Builder {
+	return &Builder{
+		filter: bson.M{field: bson.M{"$gte": min, "$lte": max}},
+	}
+}
+
+// DateRange creates an inclusive range filter on a date field, comparing BSON dates rather
+// than strings so ordering is chronological regardless of formatting or time zone. Bounds
+// are truncated to milliseconds, the precision of BSON dates. A zero start or end leaves that
+// side of the range open; if both are zero the filter matches any document.
+//
+// Example:
+//
+//	lastWeek := filter.DateRange("created_at", time.Now().AddDate(0, 0, -7), time.Now())
+func DateRange(field string, start, end time.Time) *Builder {
+	cond := bson.M{}
+	if !start.IsZero() {
+		cond["$gte"] = bson.NewDateTimeFromTime(start)
+	}
+	if !end.IsZero() {
+		cond["$lte"] = bson.NewDateTimeFromTime(end)
+	}
+	if len(cond) == 0 {
+		return New()
+	}
+	return &Builder{
+		filter: bson.M{field: cond},
+	}
+}
+
 // In creates an "in" filter for array membership
 func In(field string, values ...any) *Builder {
 	return &Builder{
@@ -80,6 +178,20 @@ func In(field string, values ...any) *Builder {
 	}
 }
 
+// InSlice creates an "in" filter from a typed slice, avoiding the conversion to []any that
+// spreading into In requires:
+//
+//	filter.InSlice("status", []string{"new", "paid"})
+//
+// An empty or nil slice matches no documents.
+func InSlice[T any](field string, values []T) *Builder {
+	items := make([]any, len(values))
+	for i, value := range values {
+		items[i] = value
+	}
+	return In(field, items...)
+}
+
 // Nin creates a "not in" filter for array non-membership
 func Nin(field string, values ...any) *Builder {
 	return &Builder{
@@ -92,20 +204,20 @@ func Nin(field string, values ...any) *Builder {
 // And combines multiple filters with logical AND
 func (b *Builder) And(filters ...*Builder) *Builder {
 	if len(filters) == 0 {
-		return b
+		return b.Clone()
 	}
 
 	conditions := make([]bson.M, 0, len(filters)+1)
 
-	// Add current filter if it exists
+	// Add a copy of the current filter if it exists
 	if len(b.filter) > 0 {
-		conditions = append(conditions, b.filter)
+		conditions = append(conditions, bsonutil.DeepCopyM(b.filter))
 	}
 
-	// Add all provided filters
+	// Add copies of all provided filters
 	for _, filter := range filters {
 		if filter != nil && len(filter.filter) > 0 {
-			conditions = append(conditions, filter.filter)
+			conditions = append(conditions, bsonutil.DeepCopyM(filter.filter))
 		}
 	}
 
@@ -125,20 +237,20 @@ func (b *Builder) And(filters ...*Builder) *Builder {
 // Or combines multiple filters with logical OR
 func (b *Builder) Or(filters ...*Builder) *Builder {
 	if len(filters) == 0 {
-		return b
+		return b.Clone()
 	}
 
 	conditions := make([]bson.M, 0, len(filters)+1)
 
-	// Add current filter if it exists
+	// Add a copy of the current filter if it exists
 	if len(b.filter) > 0 {
-		conditions = append(conditions, b.filter)
+		conditions = append(conditions, bsonutil.DeepCopyM(b.filter))
 	}
 
-	// Add all provided filters
+	// Add copies of all provided filters
 	for _, filter := range filters {
 		if filter != nil && len(filter.filter) > 0 {
-			conditions = append(conditions, filter.filter)
+			conditions = append(conditions, bsonutil.DeepCopyM(filter.filter))
 		}
 	}
 
@@ -158,11 +270,39 @@ func (b *Builder) Or(filters ...*Builder) *Builder {
 // Not negates the current filter
 func (b *Builder) Not() *Builder {
 	if len(b.filter) == 0 {
-		return b
+		return b.Clone()
+	}
+
+	return &Builder{
+		filter: bson.M{"$not": bsonutil.DeepCopyM(b.filter)},
+	}
+}
+
+// Negate returns a filter matching exactly the documents the current filter does not match,
+// by wrapping it in {"$nor": [current]}. It works for any filter, including compound ones.
+//
+// Negate differs from the field-level $not operator: $nor also matches documents where the
+// referenced fields are missing, so Eq("segment", "beta").Negate() matches documents without
+// a segment field as well. Negating an empty filter (which matches everything) matches no
+// documents, and negating a negated filter returns the original conditions.
+//
+// Example:
+//
+//	audience := filter.Eq("country", "DE").And(filter.Gte("age", 18))
+//	everyoneElse := audience.Negate()
+func (b *Builder) Negate() *Builder {
+	if len(b.filter) == 1 {
+		if conditions, ok := b.filter["$nor"].([]bson.M); ok && len(conditions) == 1 {
+			return &Builder{filter: bsonutil.DeepCopyM(conditions[0])}
+		}
 	}
 
+	current := bsonutil.DeepCopyM(b.filter)
+	if current == nil {
+		current = bson.M{}
+	}
 	return &Builder{
-		filter: bson.M{"$not": b.filter},
+		filter: bson.M{"$nor": []bson.M{current}},
 	}
 }
 
@@ -185,10 +325,35 @@ func ElemMatch(field string, filter *Builder) *Builder {
 	}
 
 	return &Builder{
-		filter: bson.M{field: bson.M{"$elemMatch": filter.filter}},
+		filter: bson.M{field: bson.M{"$elemMatch": bsonutil.DeepCopyM(filter.filter)}},
 	}
 }
 
+// AnyElem matches documents where at least one element of an array of subdocuments
+// satisfies every condition in cond. It is an alias for ElemMatch that reads better
+// for arrays of subdocuments:
+//
+//	// Some single order is both completed and over 1000
+//	filter.AnyElem("orders", filter.Eq("status", "completed").And(filter.Gt("amount", 1000)))
+//
+// Compare with dotted Field conditions, where each condition may be satisfied by a
+// different element of the array.
+func AnyElem(arrayField string, cond *Builder) *Builder {
+	return ElemMatch(arrayField, cond)
+}
+
+// Field creates an equality filter on a field path, typically a dotted path into an array
+// of subdocuments such as "orders.status". On arrays this matches documents where any
+// element has the value, without $elemMatch:
+//
+//	// Some order is completed and some (possibly different) order is over 1000
+//	filter.Field("orders.status", "completed").And(filter.Gt("orders.amount", 1000))
+//
+// Use AnyElem when a single element must satisfy all conditions. Field is equivalent to Eq.
+func Field(path string, value any) *Builder {
+	return Eq(path, value)
+}
+
 // Size creates a filter for array size
 func Size(field string, size int) *Builder {
 	return &Builder{
@@ -234,6 +399,26 @@ func Exists(field string, exists bool) *Builder {
 	}
 }
 
+// IsNull matches documents where the field exists and holds null. Unlike Eq(field, nil),
+// documents without the field do not match.
+func IsNull(field string) *Builder {
+	return Type(field, BSONTypeNull)
+}
+
+// IsMissing matches documents that do not have the field at all. Documents where the field
+// holds null do not match.
+func IsMissing(field string) *Builder {
+	return Exists(field, false)
+}
+
+// IsNullOrMissing matches documents where the field holds null or does not exist. It is
+// the same query as Eq(field, nil), spelled out for readability.
+func IsNullOrMissing(field string) *Builder {
+	return &Builder{
+		filter: bson.M{field: nil},
+	}
+}
+
 // BSONType represents BSON type constants for type checking
 type BSONType int
 
@@ -3,6 +3,7 @@ package filter
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -53,6 +54,88 @@ func TestComparisonFilters(t *testing.T) {
 	}
 }
 
+func TestBetween(t *testing.T) {
+	f := Between("age", 18, 65)
+	expected := bson.M{"age": bson.M{"$gte": 18, "$lte": 65}}
+
+	if !equalBSON(f.Build(), expected) {
+		t.Errorf("Expected %v, got %v", expected, f.Build())
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 31, 23, 59, 59, 999_999_999, time.UTC)
+
+	f := DateRange("created_at", start, end)
+	expected := bson.M{"created_at": bson.M{
+		"$gte": bson.NewDateTimeFromTime(start),
+		"$lte": bson.NewDateTimeFromTime(end),
+	}}
+	if !equalBSON(f.Build(), expected) {
+		t.Errorf("Expected %v, got %v", expected, f.Build())
+	}
+
+	// Bounds are BSON dates, not strings, and truncated to millisecond precision
+	cond := f.Build()["created_at"].(bson.M)
+	upper, ok := cond["$lte"].(bson.DateTime)
+	if !ok {
+		t.Fatalf("Expected bson.DateTime upper bound, got %T", cond["$lte"])
+	}
+	if !upper.Time().Equal(end.Truncate(time.Millisecond)) {
+		t.Errorf("Expected upper bound %v, got %v", end.Truncate(time.Millisecond), upper.Time())
+	}
+
+	// Equivalent instants in other time zones produce the same bounds
+	berlin := time.FixedZone("CET", 3600)
+	if !equalBSON(DateRange("created_at", start.In(berlin), end.In(berlin)).Build(), expected) {
+		t.Error("Expected time zone to not affect the bounds")
+	}
+
+	// Zero bounds leave the range open
+	openStart := DateRange("created_at", time.Time{}, end).Build()
+	if !equalBSON(openStart, bson.M{"created_at": bson.M{"$lte": bson.NewDateTimeFromTime(end)}}) {
+		t.Errorf("Expected open start, got %v", openStart)
+	}
+	openEnd := DateRange("created_at", start, time.Time{}).Build()
+	if !equalBSON(openEnd, bson.M{"created_at": bson.M{"$gte": bson.NewDateTimeFromTime(start)}}) {
+		t.Errorf("Expected open end, got %v", openEnd)
+	}
+	if unbounded := DateRange("created_at", time.Time{}, time.Time{}).Build(); len(unbounded) != 0 {
+		t.Errorf("Expected empty filter, got %v", unbounded)
+	}
+}
+
+func TestDateRangeInclusiveBounds(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	cond := DateRange("created_at", start, end).Build()["created_at"].(bson.M)
+	lower := cond["$gte"].(bson.DateTime)
+	upper := cond["$lte"].(bson.DateTime)
+
+	// Emulates the server comparison for documents on and around the bounds
+	matches := func(t time.Time) bool {
+		v := bson.NewDateTimeFromTime(t)
+		return v >= lower && v <= upper
+	}
+
+	cases := []struct {
+		at       time.Time
+		expected bool
+	}{
+		{start, true},
+		{end, true},
+		{start.Add(-time.Millisecond), false},
+		{end.Add(time.Millisecond), false},
+		{start.Add(24 * time.Hour), true},
+	}
+	for _, c := range cases {
+		if matches(c.at) != c.expected {
+			t.Errorf("%v: expected match=%v", c.at, c.expected)
+		}
+	}
+}
+
 func TestLogicalOperators(t *testing.T) {
 	// Test AND operation
 	f := And(
@@ -149,6 +232,43 @@ func TestArrayOperators(t *testing.T) {
 	}
 }
 
+func TestAnyElemVersusDottedField(t *testing.T) {
+	// AnyElem requires one element to satisfy every condition
+	anyElem := AnyElem("orders", Eq("status", "completed").And(Gt("amount", 1000)))
+	expected := bson.M{
+		"orders": bson.M{
+			"$elemMatch": bson.M{
+				"$and": []bson.M{
+					{"status": "completed"},
+					{"amount": bson.M{"$gt": 1000}},
+				},
+			},
+		},
+	}
+	if !equalBSON(anyElem.Build(), expected) {
+		t.Errorf("AnyElem filter: Expected %v, got %v", expected, anyElem.Build())
+	}
+	if !equalBSON(anyElem.Build(), ElemMatch("orders", Eq("status", "completed").And(Gt("amount", 1000))).Build()) {
+		t.Error("AnyElem should build the same filter as ElemMatch")
+	}
+
+	// Dotted fields match each condition independently against any element
+	dotted := Field("orders.status", "completed").And(Gt("orders.amount", 1000))
+	expectedDotted := bson.M{
+		"$and": []bson.M{
+			{"orders.status": "completed"},
+			{"orders.amount": bson.M{"$gt": 1000}},
+		},
+	}
+	if !equalBSON(dotted.Build(), expectedDotted) {
+		t.Errorf("Dotted field filter: Expected %v, got %v", expectedDotted, dotted.Build())
+	}
+
+	if !equalBSON(Field("orders.status", "completed").Build(), Eq("orders.status", "completed").Build()) {
+		t.Error("Field should build the same filter as Eq")
+	}
+}
+
 func TestStringOperators(t *testing.T) {
 	// Test Regex
 	f := Regex("name", "^John", "i")
@@ -194,8 +314,221 @@ func TestExistenceOperators(t *testing.T) {
 	}
 }
 
+func TestNullAndMissingOperators(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   *Builder
+		expected bson.M
+	}{
+		{"IsNull", IsNull("deleted_at"), bson.M{"deleted_at": bson.M{"$type": 10}}},
+		{"IsMissing", IsMissing("deleted_at"), bson.M{"deleted_at": bson.M{"$exists": false}}},
+		{"IsNullOrMissing", IsNullOrMissing("deleted_at"), bson.M{"deleted_at": nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !equalBSON(tt.filter.Build(), tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, tt.filter.Build())
+			}
+		})
+	}
+
+	// The three queries are distinct
+	if equalBSON(IsNull("a").Build(), IsNullOrMissing("a").Build()) || equalBSON(IsMissing("a").Build(), IsNullOrMissing("a").Build()) {
+		t.Error("Expected IsNull, IsMissing and IsNullOrMissing to build different queries")
+	}
+}
+
 // Helper function to compare BSON documents
 func equalBSON(a, b bson.M) bool {
 	// Use deep equality check for robust comparison
 	return reflect.DeepEqual(a, b)
 }
+
+func TestClone(t *testing.T) {
+	base := Eq("tenant", "acme").And(Gte("age", 18))
+	before := base.Clone().Build()
+
+	clone := base.Clone()
+	if !equalBSON(clone.Build(), base.Build()) {
+		t.Fatalf("Expected clone %v to equal original %v", clone.Build(), base.Build())
+	}
+
+	// Mutating the clone's document must not leak into the original
+	clone.Build()["extra"] = true
+	clone.Build()["$and"].([]bson.M)[1]["age"].(bson.M)["$gte"] = 65
+
+	if !equalBSON(base.Build(), before) {
+		t.Errorf("Original changed after mutating clone: %v", base.Build())
+	}
+
+	// A nil builder clones to an empty filter
+	var nilBuilder *Builder
+	if len(nilBuilder.Clone().Build()) != 0 {
+		t.Error("Expected empty filter when cloning nil builder")
+	}
+}
+
+func TestCombinatorsDoNotShareState(t *testing.T) {
+	base := Eq("tenant", "acme")
+
+	active := base.And(Eq("status", "active"))
+	archived := base.And(Eq("status", "archived"))
+
+	// Mutating one derived filter must not affect the base or its siblings
+	active.Build()["$and"].([]bson.M)[0]["tenant"] = "other"
+
+	if !equalBSON(base.Build(), bson.M{"tenant": "acme"}) {
+		t.Errorf("Base filter was mutated: %v", base.Build())
+	}
+	expected := bson.M{"$and": []bson.M{{"tenant": "acme"}, {"status": "archived"}}}
+	if !equalBSON(archived.Build(), expected) {
+		t.Errorf("Sibling filter was mutated: %v", archived.Build())
+	}
+
+	negated := base.Not()
+	negated.Build()["$not"].(bson.M)["tenant"] = "other"
+	if !equalBSON(base.Build(), bson.M{"tenant": "acme"}) {
+		t.Errorf("Base filter was mutated through Not: %v", base.Build())
+	}
+
+	// Combinators without arguments, or on an empty filter, still return a copy
+	for name, derived := range map[string]*Builder{"And": base.And(), "Or": base.Or()} {
+		if derived == base {
+			t.Errorf("Expected %s to return a new Builder", name)
+		}
+		derived.Build()["tenant"] = "other"
+	}
+	if !equalBSON(base.Build(), bson.M{"tenant": "acme"}) {
+		t.Errorf("Base filter was mutated through a copy: %v", base.Build())
+	}
+	empty := New()
+	if empty.Not() == empty {
+		t.Error("Expected Not on an empty filter to return a new Builder")
+	}
+}
+
+func TestOperators(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   *Builder
+		expected []string
+	}{
+		{"nil builder", nil, []string{}},
+		{"empty filter", New(), []string{}},
+		{"equality has no operators", Eq("status", "active"), []string{}},
+		{"field condition", Gte("age", 18), []string{"$gte"}},
+		{"range on one field", Between("age", 18, 65), []string{"$gte", "$lte"}},
+		{"logical operator only", Eq("a", 1).Or(Gt("b", 2)), []string{"$or"}},
+		{"nested conditions not included", Gt("a", 1).And(Lt("b", 2)), []string{"$and"}},
+		{"not", Eq("a", 1).Not(), []string{"$not"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Operators(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	// Field conditions built as bson.D are inspected too, and repeated operators listed once
+	raw := &Builder{filter: bson.M{
+		"score": bson.D{{Key: "$gt", Value: 1}, {Key: "$lt", Value: 5}},
+		"age":   bson.M{"$gt": 18},
+		"name":  bson.M{"$regex": "^a"},
+	}}
+	if got, expected := raw.Operators(), []string{"$gt", "$lt", "$regex"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestNegate(t *testing.T) {
+	// Simple filter
+	simple := Eq("segment", "beta")
+	negated := simple.Negate()
+	expected := bson.M{"$nor": []bson.M{{"segment": "beta"}}}
+	if !reflect.DeepEqual(negated.Build(), expected) {
+		t.Errorf("Expected %v, got %v", expected, negated.Build())
+	}
+
+	// Compound filter
+	audience := Eq("country", "DE").And(Gte("age", 18))
+	expected = bson.M{"$nor": []bson.M{{"$and": []bson.M{{"country": "DE"}, {"age": bson.M{"$gte": 18}}}}}}
+	if !reflect.DeepEqual(audience.Negate().Build(), expected) {
+		t.Errorf("Expected %v, got %v", expected, audience.Negate().Build())
+	}
+
+	// Negation composes with other conditions
+	composed := Eq("active", true).And(simple.Negate())
+	expected = bson.M{"$and": []bson.M{{"active": true}, {"$nor": []bson.M{{"segment": "beta"}}}}}
+	if !reflect.DeepEqual(composed.Build(), expected) {
+		t.Errorf("Expected %v, got %v", expected, composed.Build())
+	}
+
+	// Double negation returns the original conditions
+	if !reflect.DeepEqual(audience.Negate().Negate().Build(), audience.Build()) {
+		t.Errorf("Expected double negation to restore %v, got %v", audience.Build(), audience.Negate().Negate().Build())
+	}
+
+	// Negating the empty filter matches nothing
+	expected = bson.M{"$nor": []bson.M{{}}}
+	if !reflect.DeepEqual(New().Negate().Build(), expected) {
+		t.Errorf("Expected %v, got %v", expected, New().Negate().Build())
+	}
+
+	// The receiver is not modified and shares no state with the result
+	negated.Build()["$nor"].([]bson.M)[0]["segment"] = "changed"
+	if simple.Build()["segment"] != "beta" {
+		t.Error("Expected Negate to leave the original filter unchanged")
+	}
+}
+
+func TestInSlice(t *testing.T) {
+	statuses := []string{"new", "paid"}
+	result := InSlice("status", statuses).Build()
+	expected := bson.M{"status": bson.M{"$in": []any{"new", "paid"}}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	result = InSlice("priority", []int{1, 2, 3}).Build()
+	expected = bson.M{"priority": bson.M{"$in": []any{1, 2, 3}}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	// Same filter as spreading the values into In
+	if !reflect.DeepEqual(InSlice("status", statuses).Build(), In("status", "new", "paid").Build()) {
+		t.Error("Expected InSlice to match In with the same values")
+	}
+
+	// A nil slice becomes an empty $in array rather than null
+	result = InSlice[string]("status", nil).Build()
+	values := result["status"].(bson.M)["$in"].([]any)
+	if values == nil || len(values) != 0 {
+		t.Errorf("Expected an empty $in array, got %#v", values)
+	}
+}
+
+func TestToMatchStage(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *Builder
+		expected bson.M
+	}{
+		{"populated", Eq("status", "active").And(Gte("age", 18)), bson.M{"$match": bson.M{"$and": []bson.M{{"status": "active"}, {"age": bson.M{"$gte": 18}}}}}},
+		{"single condition", Eq("status", "active"), bson.M{"$match": bson.M{"status": "active"}}},
+		{"empty", New(), bson.M{"$match": bson.M{}}},
+		{"zero value", &Builder{}, bson.M{"$match": bson.M{}}},
+		{"nil", nil, bson.M{"$match": bson.M{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.builder.ToMatchStage(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ErrDuplicateMapKey is returned by FindAsMap with ErrorOnDuplicate when two documents have
+// the same key
+var ErrDuplicateMapKey = errors.New("duplicate map key")
+
+// FindAsMapOptions configures FindAsMap
+type FindAsMapOptions struct {
+	// ErrorOnDuplicate fails with ErrDuplicateMapKey when two documents have the same key,
+	// instead of keeping the last document read
+	ErrorOnDuplicate bool
+}
+
+// FindAsMap finds the documents matching the filter and returns them indexed by the value of
+// keyField (a dotted path for embedded fields), which is convenient for joining results in
+// application code. Go does not allow type parameters on methods, so the collection is passed
+// explicitly.
+//
+// Documents are decoded into V and the key value into K. Every matching document must have
+// keyField. When several documents have the same key the last one read wins, unless
+// ErrorOnDuplicate is set.
+//
+// Example:
+//
+//	users, err := mongodb.FindAsMap[string, User](ctx, client.Collection("users"),
+//	    filter.InSlice("_id", userIDs), "_id")
+//	for _, order := range orders {
+//	    fmt.Println(order.ID, users[order.UserID].Name)
+//	}
+func FindAsMap[K comparable, V any](ctx context.Context, col *Collection, filterBuilder *filter.Builder, keyField string, opts ...*FindAsMapOptions) (map[K]V, error) {
+	if keyField == "" {
+		return nil, errors.New("FindAsMap requires a key field")
+	}
+
+	var mapOpts FindAsMapOptions
+	if len(opts) > 0 && opts[0] != nil {
+		mapOpts = *opts[0]
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	results, err := col.Find(ctx, filterBuilder)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = results.Close(ctx)
+	}()
+
+	return decodeAsMap[K, V](ctx, results, keyField, mapOpts)
+}
+
+// decodeAsMap reads every document of results into a map keyed by keyField
+func decodeAsMap[K comparable, V any](ctx context.Context, results *FindResult, keyField string, opts FindAsMapOptions) (map[K]V, error) {
+	path := strings.Split(keyField, ".")
+	documents := make(map[K]V)
+
+	for results.Next(ctx) {
+		raw := results.Current()
+		keyValue, err := raw.LookupErr(path...)
+		if err != nil {
+			return nil, fmt.Errorf("document %v has no key field %s", raw.Lookup("_id"), keyField)
+		}
+
+		var key K
+		if err := keyValue.Unmarshal(&key); err != nil {
+			return nil, fmt.Errorf("failed to decode key field %s: %w", keyField, err)
+		}
+		if _, exists := documents[key]; exists && opts.ErrorOnDuplicate {
+			return nil, fmt.Errorf("%w: %v", ErrDuplicateMapKey, key)
+		}
+
+		var value V
+		if err := bson.Unmarshal(raw, &value); err != nil {
+			return nil, fmt.Errorf("failed to decode document: %w", err)
+		}
+		documents[key] = value
+	}
+	if err := results.Err(); err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
@@ -0,0 +1,142 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type mapTestUser struct {
+	ID   string `bson:"_id"`
+	Name string `bson:"name"`
+	Team string `bson:"team"`
+}
+
+// findResultFromDocuments returns a FindResult reading docs without a server
+func findResultFromDocuments(t *testing.T, docs ...any) *FindResult {
+	t.Helper()
+	cursor, err := mongo.NewCursorFromDocuments(docs, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create cursor: %v", err)
+	}
+	return &FindResult{cursor: cursor}
+}
+
+func TestDecodeAsMapUniqueKeys(t *testing.T) {
+	ctx := context.Background()
+	results := findResultFromDocuments(t,
+		bson.M{"_id": "u1", "name": "Ada", "team": "core"},
+		bson.M{"_id": "u2", "name": "Grace", "team": "infra"},
+	)
+
+	users, err := decodeAsMap[string, mapTestUser](ctx, results, "_id", FindAsMapOptions{ErrorOnDuplicate: true})
+	if err != nil {
+		t.Fatalf("decodeAsMap failed: %v", err)
+	}
+	expected := map[string]mapTestUser{
+		"u1": {ID: "u1", Name: "Ada", Team: "core"},
+		"u2": {ID: "u2", Name: "Grace", Team: "infra"},
+	}
+	if !reflect.DeepEqual(users, expected) {
+		t.Errorf("Expected %v, got %v", expected, users)
+	}
+
+	// Embedded key fields and non-string keys
+	results = findResultFromDocuments(t,
+		bson.M{"_id": "o1", "customer": bson.M{"number": int32(7)}},
+		bson.M{"_id": "o2", "customer": bson.M{"number": int32(9)}},
+	)
+	orders, err := decodeAsMap[int, bson.M](ctx, results, "customer.number", FindAsMapOptions{})
+	if err != nil {
+		t.Fatalf("decodeAsMap failed: %v", err)
+	}
+	if len(orders) != 2 || orders[7]["_id"] != "o1" || orders[9]["_id"] != "o2" {
+		t.Errorf("Unexpected orders by customer number: %v", orders)
+	}
+}
+
+func TestDecodeAsMapDuplicateKeys(t *testing.T) {
+	ctx := context.Background()
+	docs := []any{
+		bson.M{"_id": "u1", "name": "Ada", "team": "core"},
+		bson.M{"_id": "u2", "name": "Grace", "team": "infra"},
+		bson.M{"_id": "u3", "name": "Linus", "team": "core"},
+	}
+
+	// The last document read wins by default
+	byTeam, err := decodeAsMap[string, mapTestUser](ctx, findResultFromDocuments(t, docs...), "team", FindAsMapOptions{})
+	if err != nil {
+		t.Fatalf("decodeAsMap failed: %v", err)
+	}
+	if len(byTeam) != 2 || byTeam["core"].ID != "u3" {
+		t.Errorf("Expected the last core user to win, got %v", byTeam)
+	}
+
+	_, err = decodeAsMap[string, mapTestUser](ctx, findResultFromDocuments(t, docs...), "team", FindAsMapOptions{ErrorOnDuplicate: true})
+	if !errors.Is(err, ErrDuplicateMapKey) {
+		t.Errorf("Expected ErrDuplicateMapKey, got %v", err)
+	}
+}
+
+func TestDecodeAsMapErrors(t *testing.T) {
+	ctx := context.Background()
+
+	results := findResultFromDocuments(t, bson.M{"_id": "u1", "name": "Ada"})
+	if _, err := decodeAsMap[string, bson.M](ctx, results, "team", FindAsMapOptions{}); err == nil {
+		t.Error("Expected an error for a document without the key field")
+	}
+
+	results = findResultFromDocuments(t, bson.M{"_id": "u1", "team": bson.A{"core"}})
+	if _, err := decodeAsMap[string, bson.M](ctx, results, "team", FindAsMapOptions{}); err == nil {
+		t.Error("Expected an error for a key that does not decode into K")
+	}
+
+	col := newTestCollection("users")
+	if _, err := FindAsMap[string, bson.M](ctx, col, nil, ""); err == nil {
+		t.Error("Expected an error without a key field")
+	}
+}
+
+func TestFindAsMapIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_find_as_map")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	_, err := col.InsertMany(ctx, []any{
+		mapTestUser{ID: "u1", Name: "Ada", Team: "core"},
+		mapTestUser{ID: "u2", Name: "Grace", Team: "infra"},
+		mapTestUser{ID: "u3", Name: "Linus", Team: "core"},
+	})
+	if err != nil {
+		t.Fatalf("InsertMany failed: %v", err)
+	}
+
+	users, err := FindAsMap[string, mapTestUser](ctx, col, filter.InSlice("_id", []string{"u1", "u2"}), "_id")
+	if err != nil {
+		t.Fatalf("FindAsMap failed: %v", err)
+	}
+	if len(users) != 2 || users["u1"].Name != "Ada" || users["u2"].Name != "Grace" {
+		t.Errorf("Unexpected users: %v", users)
+	}
+
+	_, err = FindAsMap[string, mapTestUser](ctx, col, nil, "team", &FindAsMapOptions{ErrorOnDuplicate: true})
+	if !errors.Is(err, ErrDuplicateMapKey) {
+		t.Errorf("Expected ErrDuplicateMapKey, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package mongodb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// GroupResult decodes a $group output document of the shape {_id: K, ...V}. The group key in
+// _id is decoded into Key and the remaining fields are decoded into Value, so the accumulator
+// struct does not need an _id field of its own.
+//
+// Example:
+//
+//	type DepartmentStats struct {
+//		Count     int     `bson:"count"`
+//		AvgSalary float64 `bson:"avg_salary"`
+//	}
+//
+//	p := pipeline.New().Group("$department", bson.M{
+//		"count":      bson.M{"$sum": 1},
+//		"avg_salary": bson.M{"$avg": "$salary"},
+//	})
+//	result, err := col.AggregateWithPipeline(ctx, p)
+//	...
+//	var groups []mongodb.GroupResult[string, DepartmentStats]
+//	err = result.All(ctx, &groups)
+//	for _, g := range groups {
+//		fmt.Println(g.Key, g.Value.Count)
+//	}
+type GroupResult[K, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// UnmarshalBSON implements bson.Unmarshaler
+func (g *GroupResult[K, V]) UnmarshalBSON(data []byte) error {
+	raw := bson.Raw(data)
+	elements, err := raw.Elements()
+	if err != nil {
+		return fmt.Errorf("failed to decode group result: %w", err)
+	}
+
+	// Rebuild the document without _id so map and bson.D values only hold the accumulators
+	rest := make([]byte, 4, len(data))
+	for _, element := range elements {
+		if element.Key() == "_id" {
+			if err := element.Value().Unmarshal(&g.Key); err != nil {
+				return fmt.Errorf("failed to decode group key: %w", err)
+			}
+			continue
+		}
+		rest = append(rest, element...)
+	}
+	rest = append(rest, 0)
+	binary.LittleEndian.PutUint32(rest, uint32(len(rest)))
+
+	if err := bson.Unmarshal(rest, &g.Value); err != nil {
+		return fmt.Errorf("failed to decode group value: %w", err)
+	}
+	return nil
+}
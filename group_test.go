@@ -0,0 +1,78 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestGroupResultDecodesDepartmentCounts(t *testing.T) {
+	type departmentStats struct {
+		Count     int     `bson:"count"`
+		AvgSalary float64 `bson:"avg_salary"`
+	}
+
+	cursor, err := mongo.NewCursorFromDocuments([]any{
+		bson.D{{Key: "_id", Value: "engineering"}, {Key: "count", Value: 12}, {Key: "avg_salary", Value: 95000.0}},
+		bson.D{{Key: "count", Value: 3}, {Key: "_id", Value: "sales"}, {Key: "avg_salary", Value: 60000.0}},
+		bson.D{{Key: "_id", Value: nil}, {Key: "count", Value: 1}},
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create cursor: %v", err)
+	}
+	result := &AggregateResult{cursor: cursor}
+
+	var groups []GroupResult[string, departmentStats]
+	if err := result.All(context.Background(), &groups); err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+
+	expected := []GroupResult[string, departmentStats]{
+		{Key: "engineering", Value: departmentStats{Count: 12, AvgSalary: 95000}},
+		{Key: "sales", Value: departmentStats{Count: 3, AvgSalary: 60000}},
+		{Key: "", Value: departmentStats{Count: 1}},
+	}
+	if len(groups) != len(expected) {
+		t.Fatalf("Expected %d groups, got %d", len(expected), len(groups))
+	}
+	for i := range expected {
+		if groups[i] != expected[i] {
+			t.Errorf("Group %d: expected %+v, got %+v", i, expected[i], groups[i])
+		}
+	}
+}
+
+func TestGroupResultCompoundKeyAndMapValue(t *testing.T) {
+	type departmentKey struct {
+		Department string `bson:"department"`
+		Level      string `bson:"level"`
+	}
+
+	data, err := bson.Marshal(bson.D{
+		{Key: "_id", Value: bson.D{{Key: "department", Value: "engineering"}, {Key: "level", Value: "senior"}}},
+		{Key: "count", Value: int32(4)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal group: %v", err)
+	}
+
+	var group GroupResult[departmentKey, bson.M]
+	if err := bson.Unmarshal(data, &group); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if group.Key != (departmentKey{Department: "engineering", Level: "senior"}) {
+		t.Errorf("Unexpected key %+v", group.Key)
+	}
+	if _, exists := group.Value["_id"]; exists || group.Value["count"] != int32(4) || len(group.Value) != 1 {
+		t.Errorf("Expected value with only the accumulators, got %v", group.Value)
+	}
+
+	// A key of the wrong type is reported rather than silently dropped
+	var mismatched GroupResult[int, bson.M]
+	if err := bson.Unmarshal(data, &mismatched); err == nil {
+		t.Error("Expected error decoding a document key into an int")
+	}
+}
@@ -0,0 +1,68 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// InsertOrIgnore inserts a document like InsertOne, treating a duplicate key error as success.
+// The boolean reports whether the document was a duplicate, in which case nothing was written
+// and the result is nil. This suits idempotent ingestion with client-generated _id values, where
+// replaying a message must not fail.
+//
+// Note that a duplicate on any unique index is ignored, not only on _id.
+//
+// Example:
+//
+//	result, duplicate, err := col.InsertOrIgnore(ctx, bson.M{"_id": event.ID, "payload": event.Payload})
+//	if err != nil {
+//	    return err
+//	}
+//	if duplicate {
+//	    log.Printf("event %s already ingested", event.ID)
+//	}
+func (col *Collection) InsertOrIgnore(ctx context.Context, document any) (*InsertOneResult, bool, error) {
+	result, err := col.InsertOne(ctx, document)
+	if err != nil {
+		if IsDuplicateKeyError(err) {
+			col.logger(ctx).Debug("Ignored duplicate document",
+				"collection", col.name)
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+	return result, false, nil
+}
+
+// InsertOrReplace inserts a document, replacing the existing document with the same _id if there
+// is one. It runs as a single upserting ReplaceOne on _id, so it is atomic. When IDMode is
+// IDModeULID and the document has no _id, a ULID is generated and the document is inserted; in
+// other ID modes the document must carry its _id.
+//
+// UpsertedCount is 1 when the document was inserted and MatchedCount is 1 when it replaced an
+// existing document.
+//
+// Example:
+//
+//	result, err := col.InsertOrReplace(ctx, Product{ID: sku, Name: name, Price: price})
+func (col *Collection) InsertOrReplace(ctx context.Context, document any) (*UpdateResult, error) {
+	if err := col.checkWritable("InsertOrReplace"); err != nil {
+		return nil, err
+	}
+
+	// Prepare document (add ULID if needed)
+	docToWrite, err := col.prepareDocumentForInsert(document)
+	if err != nil {
+		return nil, err
+	}
+
+	found, id := hasID(docToWrite)
+	if !found || id == nil {
+		return nil, errors.New("InsertOrReplace: document has no _id to match on")
+	}
+
+	return col.ReplaceOne(ctx, filter.Eq("_id", id), docToWrite, options.Replace().SetUpsert(true))
+}
@@ -0,0 +1,134 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestInsertOrIgnoreReportsOtherErrors(t *testing.T) {
+	col := newTestCollection("events").ReadOnly()
+
+	result, duplicate, err := col.InsertOrIgnore(context.Background(), bson.M{"_id": "e1"})
+	if !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, got %v", err)
+	}
+	if duplicate || result != nil {
+		t.Errorf("Expected no result and no duplicate flag, got %+v (duplicate %v)", result, duplicate)
+	}
+}
+
+func TestInsertOrReplaceRequiresID(t *testing.T) {
+	col := newTestCollection("events", withIDMode(IDModeObjectID))
+
+	if _, err := col.InsertOrReplace(context.Background(), bson.M{"payload": "x"}); err == nil {
+		t.Error("Expected an error for a document without _id outside ULID mode")
+	}
+
+	readOnly := col.ReadOnly()
+	if _, err := readOnly.InsertOrReplace(context.Background(), bson.M{"_id": "e1"}); !errors.Is(err, ErrReadOnly) {
+		t.Error("Expected a read-only collection to reject InsertOrReplace")
+	}
+}
+
+func TestInsertOrIgnoreIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_insert_or_ignore")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	result, duplicate, err := col.InsertOrIgnore(ctx, bson.M{"_id": "evt-1", "attempt": 1})
+	if err != nil {
+		t.Fatalf("InsertOrIgnore failed: %v", err)
+	}
+	if duplicate || result == nil || result.InsertedID != "evt-1" {
+		t.Fatalf("Expected evt-1 to be inserted, got %+v (duplicate %v)", result, duplicate)
+	}
+
+	// Replaying the same _id is not an error and leaves the stored document alone
+	result, duplicate, err = col.InsertOrIgnore(ctx, bson.M{"_id": "evt-1", "attempt": 2})
+	if err != nil {
+		t.Fatalf("InsertOrIgnore of a duplicate failed: %v", err)
+	}
+	if !duplicate || result != nil {
+		t.Errorf("Expected a duplicate with no result, got %+v (duplicate %v)", result, duplicate)
+	}
+
+	var doc bson.M
+	if err := col.FindOne(ctx, filter.Eq("_id", "evt-1")).Decode(&doc); err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if doc["attempt"] != int32(1) {
+		t.Errorf("Expected the first document to be kept, got attempt %v", doc["attempt"])
+	}
+}
+
+func TestInsertOrReplaceIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_insert_or_replace")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	result, err := col.InsertOrReplace(ctx, bson.M{"_id": "sku-1", "price": 10})
+	if err != nil {
+		t.Fatalf("InsertOrReplace failed: %v", err)
+	}
+	if result.UpsertedCount != 1 || result.MatchedCount != 0 {
+		t.Errorf("Expected an insert, got %+v", result)
+	}
+
+	result, err = col.InsertOrReplace(ctx, bson.M{"_id": "sku-1", "price": 12})
+	if err != nil {
+		t.Fatalf("InsertOrReplace of an existing _id failed: %v", err)
+	}
+	if result.UpsertedCount != 0 || result.MatchedCount != 1 {
+		t.Errorf("Expected a replacement, got %+v", result)
+	}
+
+	var doc bson.M
+	if err := col.FindOne(ctx, filter.Eq("_id", "sku-1")).Decode(&doc); err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if doc["price"] != int32(12) {
+		t.Errorf("Expected the document to be replaced, got price %v", doc["price"])
+	}
+
+	// Without an _id a ULID is generated and the document inserted
+	result, err = col.InsertOrReplace(ctx, bson.M{"price": 5})
+	if err != nil {
+		t.Fatalf("InsertOrReplace without _id failed: %v", err)
+	}
+	if result.UpsertedCount != 1 {
+		t.Errorf("Expected a generated document to be inserted, got %+v", result)
+	}
+
+	count, err := col.CountDocuments(ctx, nil)
+	if err != nil {
+		t.Fatalf("CountDocuments failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 documents, got %d", count)
+	}
+}
@@ -0,0 +1,54 @@
+// Package bsonutil provides internal helpers shared by the builder packages.
+package bsonutil
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+
+// DeepCopyM returns a deep copy of a BSON document. Nested documents and arrays
+// (bson.M, bson.D, bson.A, []bson.M, []any and map[string]any) are copied recursively;
+// all other values are copied as-is.
+func DeepCopyM(doc bson.M) bson.M {
+	if doc == nil {
+		return nil
+	}
+	result := make(bson.M, len(doc))
+	for k, v := range doc {
+		result[k] = DeepCopyValue(v)
+	}
+	return result
+}
+
+// DeepCopyValue returns a deep copy of a BSON value
+func DeepCopyValue(value any) any {
+	switch v := value.(type) {
+	case bson.M:
+		return DeepCopyM(v)
+	case map[string]any:
+		return map[string]any(DeepCopyM(v))
+	case bson.D:
+		result := make(bson.D, len(v))
+		for i, elem := range v {
+			result[i] = bson.E{Key: elem.Key, Value: DeepCopyValue(elem.Value)}
+		}
+		return result
+	case bson.A:
+		result := make(bson.A, len(v))
+		for i, elem := range v {
+			result[i] = DeepCopyValue(elem)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, elem := range v {
+			result[i] = DeepCopyValue(elem)
+		}
+		return result
+	case []bson.M:
+		result := make([]bson.M, len(v))
+		for i, elem := range v {
+			result[i] = DeepCopyM(elem)
+		}
+		return result
+	default:
+		return value
+	}
+}
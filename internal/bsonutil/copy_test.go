@@ -0,0 +1,46 @@
+package bsonutil
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestDeepCopyM(t *testing.T) {
+	original := bson.M{
+		"status": "active",
+		"age":    bson.M{"$gte": 18},
+		"$or":    []bson.M{{"a": 1}, {"b": bson.A{1, 2}}},
+		"sort":   bson.D{{Key: "x", Value: bson.M{"y": 1}}},
+		"tags":   []any{"go", bson.M{"z": 1}},
+	}
+
+	copied := DeepCopyM(original)
+	if !reflect.DeepEqual(original, copied) {
+		t.Fatalf("Expected copy to equal original, got %v", copied)
+	}
+
+	// Mutating the copy at every nesting level must not affect the original
+	copied["status"] = "inactive"
+	copied["age"].(bson.M)["$gte"] = 21
+	copied["$or"].([]bson.M)[0]["a"] = 2
+	copied["$or"].([]bson.M)[1]["b"].(bson.A)[0] = 9
+	copied["sort"].(bson.D)[0].Value.(bson.M)["y"] = -1
+	copied["tags"].([]any)[1].(bson.M)["z"] = 2
+
+	expected := bson.M{
+		"status": "active",
+		"age":    bson.M{"$gte": 18},
+		"$or":    []bson.M{{"a": 1}, {"b": bson.A{1, 2}}},
+		"sort":   bson.D{{Key: "x", Value: bson.M{"y": 1}}},
+		"tags":   []any{"go", bson.M{"z": 1}},
+	}
+	if !reflect.DeepEqual(original, expected) {
+		t.Errorf("Original was mutated through the copy: %v", original)
+	}
+
+	if DeepCopyM(nil) != nil {
+		t.Error("Expected nil copy of nil document")
+	}
+}
@@ -0,0 +1,156 @@
+package mongodb
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+// latencyBucketBounds are the upper bounds of the latency histogram buckets. Durations above
+// the last bound are counted in an overflow bucket.
+var latencyBucketBounds = [...]time.Duration{
+	100 * time.Microsecond, 250 * time.Microsecond, 500 * time.Microsecond,
+	time.Millisecond, 2500 * time.Microsecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second,
+	10 * time.Second, 30 * time.Second, time.Minute,
+}
+
+// LatencyStats summarizes the durations of one kind of operation (server command, e.g.
+// "find", "insert", "aggregate" or "getMore") since the client was created.
+//
+// Percentiles come from a fixed-bucket histogram and are reported as the upper bound of the
+// bucket holding the percentile (capped at Max), so they are an upper estimate: a p95 of 25ms
+// means 95% of the operations completed in at most 25ms. Buckets range from 100µs to 1 minute.
+type LatencyStats struct {
+	Count  int64         `json:"count"`
+	Failed int64         `json:"failed"`
+	P50    time.Duration `json:"p50"`
+	P95    time.Duration `json:"p95"`
+	P99    time.Duration `json:"p99"`
+	Max    time.Duration `json:"max"`
+}
+
+// latencyHistogram counts operation durations in fixed buckets, so memory stays bounded
+// however many operations are recorded
+type latencyHistogram struct {
+	buckets [len(latencyBucketBounds) + 1]int64
+	count   int64
+	failed  int64
+	max     time.Duration
+}
+
+// record adds a duration to the histogram
+func (h *latencyHistogram) record(d time.Duration, failed bool) {
+	i := 0
+	for i < len(latencyBucketBounds) && d > latencyBucketBounds[i] {
+		i++
+	}
+	h.buckets[i]++
+	h.count++
+	if failed {
+		h.failed++
+	}
+	h.max = max(h.max, d)
+}
+
+// percentile returns the upper bound of the bucket holding the p-th percentile (0 < p <= 1),
+// capped at the largest recorded duration
+func (h *latencyHistogram) percentile(p float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+
+	// Nearest-rank method: the smallest duration with at least p of the operations at or below it
+	rank := int64(math.Ceil(p * float64(h.count)))
+	rank = min(max(rank, 1), h.count)
+
+	var seen int64
+	for i, n := range h.buckets {
+		seen += n
+		if seen >= rank {
+			if i == len(latencyBucketBounds) {
+				return h.max
+			}
+			return min(latencyBucketBounds[i], h.max)
+		}
+	}
+	return h.max
+}
+
+// stats summarizes the histogram
+func (h *latencyHistogram) stats() LatencyStats {
+	return LatencyStats{
+		Count:  h.count,
+		Failed: h.failed,
+		P50:    h.percentile(0.50),
+		P95:    h.percentile(0.95),
+		P99:    h.percentile(0.99),
+		Max:    h.max,
+	}
+}
+
+// latencyRecorder keeps one histogram per operation name
+type latencyRecorder struct {
+	mu          sync.Mutex
+	byOperation map[string]*latencyHistogram
+}
+
+// record adds the duration of an operation
+func (r *latencyRecorder) record(operation string, d time.Duration, failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.byOperation == nil {
+		r.byOperation = map[string]*latencyHistogram{}
+	}
+	h, ok := r.byOperation[operation]
+	if !ok {
+		h = &latencyHistogram{}
+		r.byOperation[operation] = h
+	}
+	h.record(d, failed)
+}
+
+// snapshot returns the latency summary of every operation recorded so far
+func (r *latencyRecorder) snapshot() map[string]LatencyStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make(map[string]LatencyStats, len(r.byOperation))
+	for operation, h := range r.byOperation {
+		stats[operation] = h.stats()
+	}
+	return stats
+}
+
+// commandMonitor returns the driver command monitor that records command durations for
+// Stats and reports Stable API strict-mode rejections, forwarding every event to the monitor
+// configured with WithMonitor, if any
+func (c *Client) commandMonitor() *event.CommandMonitor {
+	user := c.config.CommandMonitor
+	if user == nil {
+		user = &event.CommandMonitor{}
+	}
+
+	return &event.CommandMonitor{
+		Started: user.Started,
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			c.latency.record(evt.CommandName, evt.Duration, false)
+			if user.Succeeded != nil {
+				user.Succeeded(ctx, evt)
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			c.latency.record(evt.CommandName, evt.Duration, true)
+			c.reportAPIStrictFailure(evt)
+			if user.Failed != nil {
+				user.Failed(ctx, evt)
+			}
+		},
+	}
+}
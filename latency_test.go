@@ -0,0 +1,92 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+func TestLatencyHistogramPercentiles(t *testing.T) {
+	h := &latencyHistogram{}
+
+	// 90 fast finds, 8 moderately slow, 2 very slow
+	for range 90 {
+		h.record(800*time.Microsecond, false)
+	}
+	for range 8 {
+		h.record(40*time.Millisecond, false)
+	}
+	h.record(3*time.Second, false)
+	h.record(90*time.Second, true)
+
+	stats := h.stats()
+	expected := LatencyStats{
+		Count:  100,
+		Failed: 1,
+		P50:    time.Millisecond,      // bucket (500µs, 1ms]
+		P95:    50 * time.Millisecond, // bucket (25ms, 50ms]
+		P99:    5 * time.Second,       // bucket (2.5s, 5s]
+		Max:    90 * time.Second,
+	}
+	if stats != expected {
+		t.Errorf("Unexpected latency stats:\n%+v\nexpected:\n%+v", stats, expected)
+	}
+
+	// The overflow bucket reports the largest duration
+	if p := h.percentile(1); p != 90*time.Second {
+		t.Errorf("Expected p100 to be the max, got %s", p)
+	}
+}
+
+func TestLatencyHistogramCappedAtMax(t *testing.T) {
+	h := &latencyHistogram{}
+	h.record(3*time.Millisecond, false)
+	h.record(4*time.Millisecond, false)
+
+	// Both durations fall in the (2.5ms, 5ms] bucket; the estimate never exceeds the max
+	if p := h.percentile(0.99); p != 4*time.Millisecond {
+		t.Errorf("Expected p99 capped at 4ms, got %s", p)
+	}
+	if stats := (&latencyHistogram{}).stats(); stats != (LatencyStats{}) {
+		t.Errorf("Expected zero stats for an empty histogram, got %+v", stats)
+	}
+}
+
+func TestCommandMonitorRecordsLatencies(t *testing.T) {
+	var forwarded int
+	client := newTestClient(WithMonitor(&event.CommandMonitor{
+		Succeeded: func(context.Context, *event.CommandSucceededEvent) { forwarded++ },
+		Failed:    func(context.Context, *event.CommandFailedEvent) { forwarded++ },
+	}))
+
+	monitor := client.commandMonitor()
+	ctx := context.Background()
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", Duration: 2 * time.Millisecond}})
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", Duration: 20 * time.Millisecond}})
+	monitor.Failed(ctx, &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "insert", Duration: 7 * time.Millisecond},
+		Failure:              errors.New("E11000 duplicate key"),
+	})
+
+	if forwarded != 3 {
+		t.Errorf("Expected 3 events forwarded to the configured monitor, got %d", forwarded)
+	}
+
+	latencies := client.latency.snapshot()
+	if find := latencies["find"]; find.Count != 2 || find.P50 != 2500*time.Microsecond || find.Max != 20*time.Millisecond {
+		t.Errorf("Unexpected find latencies: %+v", find)
+	}
+	if insert := latencies["insert"]; insert.Count != 1 || insert.Failed != 1 {
+		t.Errorf("Unexpected insert latencies: %+v", insert)
+	}
+
+	// Without a configured monitor, latencies are still recorded
+	plain := newTestClient()
+	plain.commandMonitor().Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "ping"}})
+	if plain.latency.snapshot()["ping"].Count != 1 {
+		t.Error("Expected the ping to be recorded")
+	}
+}
@@ -0,0 +1,173 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+// maxTimeKey is the context key of a per-call MaxTime override
+type maxTimeKey struct{}
+
+// WithOperationMaxTime returns a copy of ctx that overrides the client MaxTime (see
+// WithMaxTimeMS) for the operations it is passed to, e.g. to give a known heavy report more
+// time than the default. A zero or negative limit disables the limit for those operations.
+//
+// Example:
+//
+//	ctx := mongodb.WithOperationMaxTime(ctx, 2*time.Minute)
+//	result, err := orders.AggregateWithPipeline(ctx, yearlyReport)
+func WithOperationMaxTime(ctx context.Context, limit time.Duration) context.Context {
+	return context.WithValue(ctx, maxTimeKey{}, limit)
+}
+
+// maxTime returns the time limit for an operation: the override carried by ctx if any,
+// otherwise the client MaxTime
+func (col *Collection) maxTime(ctx context.Context) time.Duration {
+	if limit, ok := ctx.Value(maxTimeKey{}).(time.Duration); ok {
+		return limit
+	}
+	return col.client.config.MaxTime
+}
+
+// applyMaxTime bounds ctx by the operation time limit. The driver derives the maxTimeMS it
+// sends to the server from the context deadline, so the limit is enforced server-side; an
+// earlier deadline already set on ctx is kept.
+func (col *Collection) applyMaxTime(ctx context.Context) (context.Context, context.CancelFunc) {
+	limit := col.maxTime(ctx)
+	if limit <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, limit)
+}
+
+// aggregateMaxTimeOptions returns the aggregate options carrying the operation time limit.
+// The driver does not derive maxTimeMS from the context for commands returning a cursor, so
+// for aggregations it is sent explicitly; it is placed before the caller's options, so a
+// caller setting its own Custom fields replaces it.
+func (col *Collection) aggregateMaxTimeOptions(ctx context.Context, opts []options.Lister[options.AggregateOptions]) []options.Lister[options.AggregateOptions] {
+	limit := col.maxTime(ctx)
+	if limit <= 0 {
+		return opts
+	}
+
+	withMaxTime := make([]options.Lister[options.AggregateOptions], 0, len(opts)+1)
+	withMaxTime = append(withMaxTime, options.Aggregate().SetCustom(bson.M{"maxTimeMS": limit.Milliseconds()}))
+	return append(withMaxTime, opts...)
+}
+
+// find runs a find on coll, reading with readPref if set and otherwise the client read
+// preference. The driver never sends maxTimeMS for finds since they return a cursor, so when
+// a time limit applies the find command is built here and run with RunCommandCursor, the
+// same way aggregateMaxTimeOptions sends the limit for aggregations.
+func (col *Collection) find(ctx context.Context, coll *mongo.Collection, readPref *readpref.ReadPref, filterDoc bson.M, opts []options.Lister[options.FindOptions]) (*mongo.Cursor, error) {
+	limit := col.maxTime(ctx)
+	if limit <= 0 {
+		return coll.Find(ctx, filterDoc, opts...)
+	}
+
+	resolved, err := resolveOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Transactions always read from the primary, and the driver rejects any other read
+	// preference for commands run inside one
+	runOpts := options.RunCmd()
+	if readPref == nil {
+		readPref = col.client.config.readPreference()
+	}
+	if sess := mongo.SessionFromContext(ctx); readPref != nil && (sess == nil || !sess.TransactionRunning()) {
+		runOpts.SetReadPreference(readPref)
+	}
+
+	cursor, err := coll.Database().RunCommandCursor(ctx, findCommand(coll.Name(), filterDoc, resolved, limit), runOpts)
+	if err != nil {
+		return nil, err
+	}
+	if resolved.BatchSize != nil {
+		cursor.SetBatchSize(*resolved.BatchSize)
+	}
+	if resolved.MaxAwaitTime != nil {
+		cursor.SetMaxAwaitTime(*resolved.MaxAwaitTime)
+	}
+	if resolved.Comment != nil {
+		cursor.SetComment(resolved.Comment)
+	}
+	return cursor, nil
+}
+
+// findCommand builds the find command for filterDoc and the resolved find options, carrying
+// maxTime as maxTimeMS. A negative limit returns a single batch, as it does for the driver.
+func findCommand(collection string, filterDoc bson.M, opts *options.FindOptions, maxTime time.Duration) bson.D {
+	cmd := bson.D{
+		{Key: "find", Value: collection},
+		{Key: "filter", Value: filterDoc},
+	}
+	if opts.Sort != nil {
+		cmd = append(cmd, bson.E{Key: "sort", Value: opts.Sort})
+	}
+	if opts.Projection != nil {
+		cmd = append(cmd, bson.E{Key: "projection", Value: opts.Projection})
+	}
+	if opts.Hint != nil {
+		cmd = append(cmd, bson.E{Key: "hint", Value: opts.Hint})
+	}
+	if opts.Skip != nil {
+		cmd = append(cmd, bson.E{Key: "skip", Value: *opts.Skip})
+	}
+	if opts.Limit != nil {
+		limit := *opts.Limit
+		if limit < 0 {
+			limit = -limit
+			cmd = append(cmd, bson.E{Key: "singleBatch", Value: true})
+		}
+		cmd = append(cmd, bson.E{Key: "limit", Value: limit})
+	}
+	if opts.BatchSize != nil {
+		cmd = append(cmd, bson.E{Key: "batchSize", Value: *opts.BatchSize})
+	}
+	if opts.Comment != nil {
+		cmd = append(cmd, bson.E{Key: "comment", Value: opts.Comment})
+	}
+	if opts.Collation != nil {
+		cmd = append(cmd, bson.E{Key: "collation", Value: collationDocument(opts.Collation)})
+	}
+	if opts.Let != nil {
+		cmd = append(cmd, bson.E{Key: "let", Value: opts.Let})
+	}
+	if opts.Min != nil {
+		cmd = append(cmd, bson.E{Key: "min", Value: opts.Min})
+	}
+	if opts.Max != nil {
+		cmd = append(cmd, bson.E{Key: "max", Value: opts.Max})
+	}
+	if opts.AllowDiskUse != nil {
+		cmd = append(cmd, bson.E{Key: "allowDiskUse", Value: *opts.AllowDiskUse})
+	}
+	if opts.AllowPartialResults != nil {
+		cmd = append(cmd, bson.E{Key: "allowPartialResults", Value: *opts.AllowPartialResults})
+	}
+	if opts.NoCursorTimeout != nil {
+		cmd = append(cmd, bson.E{Key: "noCursorTimeout", Value: *opts.NoCursorTimeout})
+	}
+	if opts.ReturnKey != nil {
+		cmd = append(cmd, bson.E{Key: "returnKey", Value: *opts.ReturnKey})
+	}
+	if opts.ShowRecordID != nil {
+		cmd = append(cmd, bson.E{Key: "showRecordId", Value: *opts.ShowRecordID})
+	}
+	if opts.CursorType != nil {
+		switch *opts.CursorType {
+		case options.Tailable:
+			cmd = append(cmd, bson.E{Key: "tailable", Value: true})
+		case options.TailableAwait:
+			cmd = append(cmd, bson.E{Key: "tailable", Value: true}, bson.E{Key: "awaitData", Value: true})
+		}
+	}
+	return append(cmd, bson.E{Key: "maxTimeMS", Value: maxTime.Milliseconds()})
+}
@@ -0,0 +1,202 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/update"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestApplyMaxTime(t *testing.T) {
+	col := newTestCollection("orders", WithMaxTimeMS(2*time.Second))
+
+	// The client default bounds a context without deadline
+	ctx, cancel := col.applyMaxTime(context.Background())
+	deadline, ok := ctx.Deadline()
+	cancel()
+	if !ok || time.Until(deadline) > 2*time.Second || time.Until(deadline) < time.Second {
+		t.Errorf("Expected a deadline about 2s away, got %v (set: %v)", time.Until(deadline), ok)
+	}
+
+	// It also caps a caller deadline that is further away
+	parent, parentCancel := context.WithTimeout(context.Background(), time.Hour)
+	defer parentCancel()
+	ctx, cancel = col.applyMaxTime(parent)
+	deadline, _ = ctx.Deadline()
+	cancel()
+	if time.Until(deadline) > 2*time.Second {
+		t.Errorf("Expected the client limit to cap a 1h deadline, got %v", time.Until(deadline))
+	}
+
+	// A per-call override replaces the default
+	ctx, cancel = col.applyMaxTime(WithOperationMaxTime(context.Background(), time.Minute))
+	deadline, _ = ctx.Deadline()
+	cancel()
+	if remaining := time.Until(deadline); remaining < 50*time.Second || remaining > time.Minute {
+		t.Errorf("Expected the override to allow about 1m, got %v", remaining)
+	}
+
+	// A zero override disables the limit for the call
+	ctx, cancel = col.applyMaxTime(WithOperationMaxTime(context.Background(), 0))
+	cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Expected no deadline with a zero override")
+	}
+
+	// Without WithMaxTimeMS nothing is applied
+	plain := newTestCollection("orders")
+	ctx, cancel = plain.applyMaxTime(context.Background())
+	cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Expected no deadline by default")
+	}
+}
+
+func TestAggregateMaxTimeOptions(t *testing.T) {
+	col := newTestCollection("orders", WithMaxTimeMS(1500*time.Millisecond))
+
+	userOpts := []options.Lister[options.AggregateOptions]{options.Aggregate().SetAllowDiskUse(true)}
+	opts := col.aggregateMaxTimeOptions(context.Background(), userOpts)
+	if len(opts) != 2 {
+		t.Fatalf("Expected the max time option before the caller options, got %d options", len(opts))
+	}
+
+	resolved := &options.AggregateOptions{}
+	for _, opt := range opts {
+		for _, apply := range opt.List() {
+			if err := apply(resolved); err != nil {
+				t.Fatalf("Failed to apply options: %v", err)
+			}
+		}
+	}
+	if resolved.Custom["maxTimeMS"] != int64(1500) {
+		t.Errorf("Expected maxTimeMS 1500, got %v", resolved.Custom["maxTimeMS"])
+	}
+	if resolved.AllowDiskUse == nil || !*resolved.AllowDiskUse {
+		t.Error("Expected the caller options to be kept")
+	}
+
+	ctx := WithOperationMaxTime(context.Background(), 0)
+	if opts := col.aggregateMaxTimeOptions(ctx, userOpts); len(opts) != 1 {
+		t.Errorf("Expected no max time option when disabled, got %d options", len(opts))
+	}
+}
+
+func TestMaxTimeMSIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// Record the maxTimeMS sent with each command
+	var mu sync.Mutex
+	maxTimes := map[string]int64{}
+	client, err := NewClient(FromEnv(), WithMaxTimeMS(3*time.Second), WithMonitor(&event.CommandMonitor{
+		Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+			switch evt.CommandName {
+			case "aggregate", "update", "find", "delete":
+			default:
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			maxTimes[evt.CommandName] = rawInt(evt.Command.Lookup("maxTimeMS"))
+		},
+	}))
+	if err != nil {
+		t.Skipf("Could not connect to MongoDB: %v", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx := context.Background()
+	col := client.Collection("test_max_time")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	if _, err := col.InsertOne(ctx, bson.M{"status": "new"}); err != nil {
+		t.Fatalf("Failed to seed collection: %v", err)
+	}
+
+	if _, err := col.UpdateOne(ctx, filter.Eq("status", "new"), update.Set("status", "paid")); err != nil {
+		t.Fatalf("UpdateOne failed: %v", err)
+	}
+	mu.Lock()
+	if maxTime := maxTimes["update"]; maxTime <= 0 || maxTime > 3000 {
+		t.Errorf("Expected update maxTimeMS up to 3000, got %d", maxTime)
+	}
+	mu.Unlock()
+
+	// A per-call override replaces the default
+	if _, err := col.CountDocuments(WithOperationMaxTime(ctx, 20*time.Second), filter.Eq("status", "paid")); err != nil {
+		t.Fatalf("CountDocuments failed: %v", err)
+	}
+	mu.Lock()
+	if maxTime := maxTimes["aggregate"]; maxTime <= 3000 || maxTime > 20000 {
+		t.Errorf("Expected overridden count maxTimeMS above 3000, got %d", maxTime)
+	}
+	mu.Unlock()
+
+	// Find sends maxTimeMS although the driver omits it for cursors
+	cursor, err := col.Find(ctx, filter.Eq("status", "paid"))
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	var found []bson.M
+	if err := cursor.All(ctx, &found); err != nil || len(found) != 1 {
+		t.Fatalf("Expected 1 document from Find, got %d (err: %v)", len(found), err)
+	}
+	if _, err := col.DeleteOne(ctx, filter.Eq("status", "paid")); err != nil {
+		t.Fatalf("DeleteOne failed: %v", err)
+	}
+	mu.Lock()
+	for _, name := range []string{"find", "delete"} {
+		if maxTime := maxTimes[name]; maxTime <= 0 || maxTime > 3000 {
+			t.Errorf("Expected %s maxTimeMS up to 3000, got %d", name, maxTime)
+		}
+	}
+	mu.Unlock()
+}
+
+func TestFindCommand(t *testing.T) {
+	opts, err := resolveOptions([]options.Lister[options.FindOptions]{
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetSkip(10).SetLimit(-5),
+		options.Find().SetProjection(bson.M{"name": 1}).SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to resolve options: %v", err)
+	}
+
+	cmd := findCommand("orders", bson.M{"status": "paid"}, opts, 1500*time.Millisecond)
+	fields := map[string]any{}
+	for _, elem := range cmd {
+		fields[elem.Key] = elem.Value
+	}
+
+	if cmd[0].Key != "find" || cmd[0].Value != "orders" {
+		t.Errorf("Expected the command to start with find: orders, got %v", cmd[0])
+	}
+	if fields["maxTimeMS"] != int64(1500) {
+		t.Errorf("Expected maxTimeMS 1500, got %v", fields["maxTimeMS"])
+	}
+	if fields["skip"] != int64(10) || fields["limit"] != int64(5) || fields["singleBatch"] != true {
+		t.Errorf("Expected skip 10 and a single batch of 5, got skip %v limit %v singleBatch %v",
+			fields["skip"], fields["limit"], fields["singleBatch"])
+	}
+	for _, key := range []string{"filter", "sort", "projection", "collation"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("Expected %s in the find command", key)
+		}
+	}
+	if _, ok := fields["batchSize"]; ok {
+		t.Error("Expected options left unset to be omitted")
+	}
+}
@@ -36,6 +36,12 @@
 //   - MONGODB_APP_NAME: Application name for connection metadata
 //   - MONGODB_LOG_LEVEL: Logging level (default: info)
 //
+// Contexts:
+//
+// Operations always run with the context passed by the caller, so its deadline and
+// cancellation are honored as-is and never replaced by an internal timeout. Only a nil
+// context is substituted with a default (30s for collection operations).
+//
 // Basic Usage:
 //
 //	package main
@@ -119,8 +125,10 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
+	"slices"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -158,6 +166,11 @@ func Quick(database ...string) (*Client, error) {
 
 	if len(database) > 0 {
 		config.Database = database[0]
+		config.databaseSet = config.Database != ""
+	}
+
+	if err := config.checkDatabase(); err != nil {
+		return nil, err
 	}
 
 	// Disable advanced features for quick connections
@@ -406,6 +419,37 @@ func IndexText(fields ...string) IndexModel {
 	return IndexModel{Keys: keys}
 }
 
+// IndexTextWeighted creates a text index model with per-field weights for relevance ranking.
+// Fields are indexed in sorted order so the generated key document is deterministic.
+// defaultLanguage and languageOverride are optional; pass "" to use the server defaults.
+// Example: IndexTextWeighted(map[string]int{"title": 10, "body": 1}, "english", "lang")
+func IndexTextWeighted(fields map[string]int, defaultLanguage string, languageOverride string) IndexModel {
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	slices.Sort(names)
+
+	keys := bson.D{}
+	weights := bson.D{}
+	for _, field := range names {
+		keys = append(keys, bson.E{Key: field, Value: "text"})
+		weights = append(weights, bson.E{Key: field, Value: fields[field]})
+	}
+
+	opts := options.Index().SetWeights(weights)
+	if defaultLanguage != "" {
+		opts = opts.SetDefaultLanguage(defaultLanguage)
+	}
+	if languageOverride != "" {
+		opts = opts.SetLanguageOverride(languageOverride)
+	}
+	return IndexModel{
+		Keys:    keys,
+		Options: opts,
+	}
+}
+
 // IndexUnique creates a unique index model
 func IndexUnique(fields ...string) IndexModel {
 	keys := bson.D{}
@@ -483,6 +527,25 @@ func IndexWithName(name string, model IndexModel) IndexModel {
 	return model
 }
 
+// IndexWithCollation adds a collation to any IndexModel, so that string comparisons on the
+// index follow the locale's rules. Strength 1 or 2 ignores case, e.g. a case-insensitive
+// unique email index:
+//
+//	IndexWithCollation(IndexUnique("email"), "en", 2)
+//
+// A strength of 0 leaves the server default (3, case-sensitive). Queries only use the index
+// when they specify the same collation.
+func IndexWithCollation(model IndexModel, locale string, strength int) IndexModel {
+	if model.Options == nil {
+		model.Options = options.Index()
+	}
+	model.Options = model.Options.SetCollation(&options.Collation{
+		Locale:   locale,
+		Strength: strength,
+	})
+	return model
+}
+
 // IndexPartial creates a partial index with a filter expression.
 // Only documents matching the filter are included in the index.
 func IndexPartial(filter bson.D, fields ...string) IndexModel {
@@ -581,6 +644,11 @@ func IsNotFoundError(err error) bool {
 		return true
 	}
 
+	// Check for the error returned by the *Required methods
+	if errors.Is(err, ErrNotFound) {
+		return true
+	}
+
 	// Check for MongoDB command errors related to not found
 	if cmdErr, ok := err.(mongo.CommandError); ok {
 		// Common MongoDB error codes for "not found" scenarios:
@@ -2,8 +2,13 @@ package mongodb
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,6 +16,7 @@ import (
 	"github.com/cloudresty/go-mongodb/v2/update"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 func TestClientCreation(t *testing.T) {
@@ -203,6 +209,159 @@ func TestHealthCheck(t *testing.T) {
 	}
 }
 
+// unreachableConfig returns a configuration pointing at a closed port with short timeouts
+func unreachableConfig() *Config {
+	config := newDefaultConfig()
+	config.Hosts = "127.0.0.1:1"
+	config.ConnectTimeout = 200 * time.Millisecond
+	config.ServerSelectTimeout = 200 * time.Millisecond
+	return config
+}
+
+func TestErrNotConnected(t *testing.T) {
+	client := newTestClient()
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Ping: expected ErrNotConnected, got %v", err)
+	}
+	if _, err := client.StartSession(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("StartSession: expected ErrNotConnected, got %v", err)
+	}
+	if _, err := client.ListDatabases(ctx, bson.M{}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("ListDatabases: expected ErrNotConnected, got %v", err)
+	}
+	if _, err := client.ListCollections(ctx, bson.M{}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("ListCollections: expected ErrNotConnected, got %v", err)
+	}
+	if err := client.DropDatabase(ctx); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("DropDatabase: expected ErrNotConnected, got %v", err)
+	}
+	if _, err := client.GetStats(ctx); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("GetStats: expected ErrNotConnected, got %v", err)
+	}
+	if _, err := client.CurrentOp(ctx, nil); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("CurrentOp: expected ErrNotConnected, got %v", err)
+	}
+	if _, err := client.SupportsTransactions(ctx); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("SupportsTransactions: expected ErrNotConnected, got %v", err)
+	}
+	if _, _, err := client.CausalSession(ctx); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("CausalSession: expected ErrNotConnected, got %v", err)
+	}
+	if health := client.HealthCheck(); health.IsHealthy || health.Error != ErrNotConnected.Error() {
+		t.Errorf("HealthCheck: expected unhealthy with ErrNotConnected, got %+v", health)
+	}
+}
+
+func TestLazyConnect(t *testing.T) {
+	// Without lazy connect an unreachable server fails client creation
+	if _, err := NewClientWithConfig(unreachableConfig()); err == nil {
+		t.Fatal("Expected client creation to fail for an unreachable server")
+	}
+
+	config := unreachableConfig()
+	config.LazyConnect = true
+	client, err := NewClientWithConfig(config)
+	if err != nil {
+		t.Fatalf("Expected lazy client creation to succeed, got %v", err)
+	}
+
+	if client.Raw() == nil {
+		t.Fatal("Expected driver client to be kept for lazy connection")
+	}
+	if col := client.Collection("orders"); col.Raw() == nil {
+		t.Error("Expected usable collection handle before the server is reachable")
+	}
+
+	// Operations fail with the driver error while the server is down, not ErrNotConnected,
+	// and the ping outcome is recorded
+	client.setConnected(true)
+	if err := client.Ping(context.Background()); err == nil || errors.Is(err, ErrNotConnected) {
+		t.Errorf("Expected ping to fail with a server selection error, got %v", err)
+	}
+	if client.isConnected {
+		t.Error("Expected a failed ping to mark the client as disconnected")
+	}
+
+	// Once closed, the client reports ErrNotConnected instead of reconnecting
+	_ = client.Close()
+	if err := client.Ping(context.Background()); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Expected ErrNotConnected after Close, got %v", err)
+	}
+}
+
+func TestLazyConnectCreatesClientBeforeFailing(t *testing.T) {
+	ctx := context.Background()
+
+	// A client without driver handles attempts to connect once when lazy connect is enabled
+	config := unreachableConfig()
+	config.LazyConnect = true
+	lazy := &Client{config: config, shutdownChan: make(chan struct{})}
+	lazy.poolStats.connStates = make(map[int64]string)
+	defer func() {
+		_ = lazy.Close()
+	}()
+
+	err := lazy.Ping(ctx)
+	if err == nil || errors.Is(err, ErrNotConnected) {
+		t.Errorf("Expected ping to reach the driver after reconnecting, got %v", err)
+	}
+	if lazy.Raw() == nil {
+		t.Error("Expected the reconnect attempt to create driver handles")
+	}
+
+	// Without lazy connect the same client fails immediately
+	strict := &Client{config: unreachableConfig()}
+	if err := strict.Ping(ctx); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Expected ErrNotConnected without lazy connect, got %v", err)
+	}
+	if strict.Raw() != nil {
+		t.Error("Expected no connection attempt without lazy connect")
+	}
+}
+
+func TestLazyConnectWithoutDriverClient(t *testing.T) {
+	// A failed SRV lookup leaves a lazy client without driver handles
+	config := unreachableConfig()
+	config.Hosts = "nonexistent.invalid"
+	config.SRV = true
+	config.LazyConnect = true
+	client, err := NewClientWithConfig(config)
+	if err != nil {
+		t.Fatalf("Expected lazy client creation to succeed, got %v", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+	if client.Raw() != nil {
+		t.Skip("SRV lookup unexpectedly succeeded")
+	}
+
+	ctx := context.Background()
+	col := client.Collection("orders")
+	if _, err := col.Find(ctx, nil); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Find: expected ErrNotConnected, got %v", err)
+	}
+	if err := col.FindOne(ctx, nil).Decode(&bson.M{}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("FindOne: expected ErrNotConnected, got %v", err)
+	}
+	if _, err := col.InsertOne(ctx, bson.M{"sku": "a"}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("InsertOne: expected ErrNotConnected, got %v", err)
+	}
+
+	db := client.Database("shop")
+	if _, err := db.ListCollectionNames(ctx); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("ListCollectionNames: expected ErrNotConnected, got %v", err)
+	}
+	if err := db.RunCommand(ctx, bson.D{{Key: "ping", Value: 1}}).Err(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("RunCommand: expected ErrNotConnected, got %v", err)
+	}
+	if _, err := client.Coll("shop", "orders").CountDocuments(ctx, nil); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("CountDocuments: expected ErrNotConnected, got %v", err)
+	}
+}
+
 func TestTransactionOperations(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
@@ -236,13 +395,114 @@ func TestTransactionOperations(t *testing.T) {
 
 	if err != nil {
 		// Skip if transactions are not supported (e.g., standalone MongoDB)
-		if strings.Contains(err.Error(), "Transaction numbers are only allowed on a replica set member or mongos") {
+		if errors.Is(err, ErrTransactionsUnsupported) {
 			t.Skip("Skipping transaction test: MongoDB is not running as a replica set")
 		}
 		t.Fatalf("Transaction failed: %v", err)
 	}
 }
 
+func TestWithTransactionTyped(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, err := NewClient(FromEnv())
+	if err != nil {
+		t.Skipf("Could not create client: %v", err)
+	}
+	defer func() {
+		_ = client.Close() // Ignore error during cleanup
+	}()
+
+	type order struct {
+		ID     string  `bson:"_id"`
+		Amount float64 `bson:"amount"`
+	}
+
+	ctx := context.Background()
+	collection := client.Collection("test_transaction_typed")
+	_, _ = collection.DeleteMany(ctx, nil)
+
+	created, err := WithTransactionTyped(ctx, client, func(ctx context.Context) (order, error) {
+		o := order{Amount: 99.5}
+		if _, err := collection.InsertOne(ctx, &o); err != nil {
+			return order{}, err
+		}
+		return o, nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrTransactionsUnsupported) {
+			t.Skip("Skipping transaction test: MongoDB is not running as a replica set")
+		}
+		t.Fatalf("Typed transaction failed: %v", err)
+	}
+
+	if created.ID == "" {
+		t.Error("Expected the returned order to carry the generated ID")
+	}
+	if created.Amount != 99.5 {
+		t.Errorf("Expected amount 99.5, got %v", created.Amount)
+	}
+
+	// Cleanup
+	_, _ = collection.DeleteMany(ctx, nil)
+}
+
+func TestCausalSessionReadsOwnWrites(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, err := NewClient(FromEnv(), WithReadPreference(SecondaryPreferred))
+	if err != nil {
+		t.Skipf("Could not create client: %v", err)
+	}
+	defer func() {
+		_ = client.Close() // Ignore error during cleanup
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	orders := client.Collection("test_causal_orders")
+	audit := client.Collection("test_causal_audit")
+	defer func() {
+		_ = orders.Drop(ctx)
+		_ = audit.Drop(ctx)
+	}()
+
+	sessCtx, end, err := client.CausalSession(ctx)
+	if err != nil {
+		t.Fatalf("CausalSession failed: %v", err)
+	}
+	defer end()
+
+	if mongo.SessionFromContext(sessCtx) == nil {
+		t.Fatal("Expected the returned context to carry a session")
+	}
+
+	for i := range 5 {
+		inserted, err := orders.InsertOne(sessCtx, bson.M{"seq": i})
+		if err != nil {
+			t.Fatalf("InsertOne failed: %v", err)
+		}
+		if _, err := audit.InsertOne(sessCtx, bson.M{"order_id": inserted.InsertedID}); err != nil {
+			t.Fatalf("Audit InsertOne failed: %v", err)
+		}
+
+		// Reads immediately after the writes, possibly on a secondary, must observe them
+		var order bson.M
+		if err := orders.FindOne(sessCtx, filter.Eq("_id", inserted.InsertedID)).Decode(&order); err != nil {
+			t.Fatalf("Expected to read own write of order %d: %v", i, err)
+		}
+		count, err := audit.CountDocuments(sessCtx, filter.Eq("order_id", inserted.InsertedID))
+		if err != nil || count != 1 {
+			t.Fatalf("Expected to read own audit write %d, count=%d err=%v", i, count, err)
+		}
+	}
+}
+
 func TestBulkOperations(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
@@ -385,6 +645,64 @@ func TestDirectConnectionConfiguration(t *testing.T) {
 	}
 }
 
+func TestReconnectKeepsTopologyDiscovery(t *testing.T) {
+	tests := []struct {
+		name       string
+		hosts      string
+		replicaSet string
+		hostList   []string
+		direct     bool
+	}{
+		{
+			name:       "Replica set seed list",
+			hosts:      "mongo-1:27017,mongo-2:27017,mongo-3:27017",
+			replicaSet: "rs0",
+			hostList:   []string{"mongo-1:27017", "mongo-2:27017", "mongo-3:27017"},
+		},
+		{
+			name:     "Seed list with spaces",
+			hosts:    "mongo-1:27017, mongo-2:27017",
+			hostList: []string{"mongo-1:27017", "mongo-2:27017"},
+		},
+		{
+			name:     "Single host",
+			hosts:    "localhost:27017",
+			hostList: []string{"localhost:27017"},
+			direct:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newDefaultConfig()
+			WithHosts(tt.hosts)(config)
+			WithReplicaSet(tt.replicaSet)(config)
+			WithDirectConnection(true)(config)
+			client := &Client{config: config}
+
+			// Every connect, including the retries of a lazily connected client, builds its
+			// options from the configuration, so a reconnect sees the full seed list
+			for attempt := range 2 {
+				opts := client.buildClientOptions()
+				if err := opts.Validate(); err != nil {
+					t.Fatalf("Attempt %d: invalid client options: %v", attempt, err)
+				}
+				if !slices.Equal(opts.Hosts, tt.hostList) {
+					t.Errorf("Attempt %d: expected hosts %v, got %v", attempt, tt.hostList, opts.Hosts)
+				}
+
+				direct := opts.Direct != nil && *opts.Direct
+				if direct != tt.direct {
+					t.Errorf("Attempt %d: expected direct connection %v, got %v", attempt, tt.direct, direct)
+				}
+				if tt.replicaSet != "" && (opts.ReplicaSet == nil || *opts.ReplicaSet != tt.replicaSet) {
+					t.Errorf("Attempt %d: expected replica set %q, got %v", attempt, tt.replicaSet, opts.ReplicaSet)
+				}
+			}
+		})
+	}
+}
+
 func TestWithDirectConnectionOption(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -416,6 +734,148 @@ func TestWithDirectConnectionOption(t *testing.T) {
 	}
 }
 
+func TestWithAppNameSuffixOption(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []Option
+		expected string
+	}{
+		{
+			name:     "App name without suffix",
+			options:  []Option{WithAppName("orders")},
+			expected: "orders",
+		},
+		{
+			name:     "App name with suffix",
+			options:  []Option{WithAppName("orders"), WithAppNameSuffix("pod-7")},
+			expected: "orders-pod-7",
+		},
+		{
+			name:     "Suffix applied before app name",
+			options:  []Option{WithAppNameSuffix("pod-7"), WithAppName("orders")},
+			expected: "orders-pod-7",
+		},
+		{
+			name:     "Suffix without app name",
+			options:  []Option{WithAppName(""), WithAppNameSuffix("pod-7")},
+			expected: "pod-7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Hosts: "localhost:27017"}
+			for _, option := range tt.options {
+				option(config)
+			}
+
+			uri := config.BuildConnectionURI()
+			if !strings.Contains(uri, "appName="+tt.expected) {
+				t.Errorf("Expected URI to contain appName=%s, got %s", tt.expected, uri)
+			}
+
+			client := &Client{config: config}
+			clientOpts := client.buildClientOptions()
+			if clientOpts.AppName == nil || *clientOpts.AppName != tt.expected {
+				t.Errorf("Expected client options app name %s, got %v", tt.expected, clientOpts.AppName)
+			}
+		})
+	}
+
+	// Instance suffixes identify the process and long names are truncated to the server limit
+	config := &Config{AppName: "orders"}
+	WithInstanceAppNameSuffix()(config)
+	if !strings.HasSuffix(config.effectiveAppName(), "-"+strconv.Itoa(os.Getpid())) {
+		t.Errorf("Expected instance app name to end with the pid, got %s", config.effectiveAppName())
+	}
+
+	config = &Config{AppName: strings.Repeat("a", 120), AppNameSuffix: strings.Repeat("b", 20)}
+	if len(config.effectiveAppName()) != maxAppNameLength {
+		t.Errorf("Expected app name truncated to %d bytes, got %d", maxAppNameLength, len(config.effectiveAppName()))
+	}
+}
+
+func TestWithHeartbeatIntervalOption(t *testing.T) {
+	config := &Config{Hosts: "localhost:27017"}
+	WithHeartbeatInterval(2 * time.Second)(config)
+
+	client := &Client{config: config}
+	clientOpts := client.buildClientOptions()
+	if clientOpts.HeartbeatInterval == nil || *clientOpts.HeartbeatInterval != 2*time.Second {
+		t.Errorf("Expected heartbeat interval 2s in client options, got %v", clientOpts.HeartbeatInterval)
+	}
+	if err := clientOpts.Validate(); err != nil {
+		t.Errorf("Expected valid client options, got %v", err)
+	}
+
+	// Without the option the driver default applies
+	client = &Client{config: &Config{Hosts: "localhost:27017"}}
+	if interval := client.buildClientOptions().HeartbeatInterval; interval != nil {
+		t.Errorf("Expected no heartbeat interval by default, got %v", *interval)
+	}
+}
+
+func TestWarmConnectionsRunsInParallel(t *testing.T) {
+	const n = 5
+
+	// Every ping blocks until all n are in flight, so a sequential implementation would time out
+	var arrived sync.WaitGroup
+	arrived.Add(n)
+	ping := func(ctx context.Context) error {
+		arrived.Done()
+		done := make(chan struct{})
+		go func() {
+			arrived.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if warmed := warmConnections(ctx, n, ping); warmed != n {
+		t.Errorf("Expected %d warmed connections, got %d", n, warmed)
+	}
+
+	failing := func(context.Context) error { return errors.New("unreachable") }
+	if warmed := warmConnections(ctx, 3, failing); warmed != 0 {
+		t.Errorf("Expected 0 warmed connections on failure, got %d", warmed)
+	}
+}
+
+func TestWarmPoolIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, err := NewClient(FromEnv(), WithMinPoolSize(8), WithWarmPool(true))
+	if err != nil {
+		t.Skipf("MongoDB not available: %v", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	// Pool events are delivered asynchronously, so allow a short settling period
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		total := client.Stats().TotalConnections
+		if total >= 8 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected at least 8 pooled connections after warm-up, got %d", total)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
 func TestDirectConnectionEnvironmentVariable(t *testing.T) {
 	// Save original environment
 	originalValue := os.Getenv("MONGODB_DIRECT_CONNECTION")
@@ -545,6 +1005,11 @@ func TestIsNotFoundError(t *testing.T) {
 			err:      &customError{msg: "namespace not found"},
 			expected: true,
 		},
+		{
+			name:     "ErrNotFound from Required methods",
+			err:      fmt.Errorf("update order: %w", ErrNotFound),
+			expected: true,
+		},
 		{
 			name:     "Unrelated error",
 			err:      &customError{msg: "connection timeout"},
@@ -595,6 +1060,68 @@ func TestIsNotFoundErrorIntegration(t *testing.T) {
 	}
 }
 
+func TestIgnoreNamespaceNotFound(t *testing.T) {
+	namespaceNotFound := mongo.CommandError{Code: 26, Message: "ns not found"}
+	if !IsNotFoundError(namespaceNotFound) {
+		t.Fatalf("Expected IsNotFoundError to classify %v as not found", namespaceNotFound)
+	}
+	if err := ignoreNamespaceNotFound(namespaceNotFound); err != nil {
+		t.Errorf("Expected NamespaceNotFound to be tolerated, got %v", err)
+	}
+	if err := ignoreNamespaceNotFound(fmt.Errorf("drop failed: %w", namespaceNotFound)); err != nil {
+		t.Errorf("Expected wrapped NamespaceNotFound to be tolerated, got %v", err)
+	}
+
+	// Other not-found errors are not drop-related and must be reported
+	for _, err := range []error{
+		mongo.CommandError{Code: 73, Message: "invalid namespace"},
+		mongo.CommandError{Code: 13, Message: "unauthorized"},
+		mongo.ErrNoDocuments,
+	} {
+		if got := ignoreNamespaceNotFound(err); got == nil {
+			t.Errorf("Expected %v to be returned, got nil", err)
+		}
+	}
+	if err := ignoreNamespaceNotFound(nil); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+}
+
+func TestDropIsIdempotentIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, err := NewClient(FromEnv())
+	if err != nil {
+		t.Skipf("Could not create client: %v", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	db := client.Database("test_drop_idempotent")
+	if _, err := db.Collection("items").InsertOne(ctx, bson.M{"name": "a"}); err != nil {
+		t.Fatalf("Failed to seed collection: %v", err)
+	}
+
+	// Repeated drops of collections and databases that no longer exist succeed
+	for i := range 2 {
+		if err := db.DropCollection(ctx, "items"); err != nil {
+			t.Errorf("DropCollection attempt %d failed: %v", i+1, err)
+		}
+		if err := db.Collection("never_created").Drop(ctx); err != nil {
+			t.Errorf("Drop of missing collection attempt %d failed: %v", i+1, err)
+		}
+		if err := db.Drop(ctx); err != nil {
+			t.Errorf("Database drop attempt %d failed: %v", i+1, err)
+		}
+	}
+}
+
 // Helper type for testing custom error messages
 type customError struct {
 	msg string
@@ -1101,3 +1628,127 @@ func TestFindOneAndUpdateWithSort(t *testing.T) {
 	// Cleanup
 	_, _ = collection.DeleteMany(ctx, nil)
 }
+
+func TestUpdateByIDReturning(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, err := NewClient(FromEnv())
+	if err != nil {
+		t.Skipf("Could not create client: %v", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	collection := client.Collection("test_update_by_id_returning")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, _ = collection.DeleteMany(ctx, nil)
+
+	if _, err := collection.InsertOne(ctx, bson.M{"_id": "user_001", "name": "Alice", "visits": 1}); err != nil {
+		t.Fatalf("Failed to insert test document: %v", err)
+	}
+
+	// Existing id: the updated document is returned
+	var result bson.M
+	err = collection.UpdateByIDReturning(ctx, "user_001", update.Set("name", "Alicia").Inc("visits", 1)).Decode(&result)
+	if err != nil {
+		t.Fatalf("UpdateByIDReturning failed: %v", err)
+	}
+	if result["name"] != "Alicia" || result["visits"] != int32(2) {
+		t.Errorf("Expected the document after the update, got %v", result)
+	}
+
+	// Missing id: not found, and nothing is created
+	err = collection.UpdateByIDReturning(ctx, "user_404", update.Set("name", "Ghost")).Decode(&result)
+	if !IsNotFoundError(err) {
+		t.Errorf("Expected a not found error for a missing id, got %v", err)
+	}
+	count, err := collection.CountDocuments(ctx, filter.Eq("_id", "user_404"))
+	if err != nil {
+		t.Fatalf("CountDocuments failed: %v", err)
+	}
+	if count != 0 {
+		t.Error("Expected no document to be created for a missing id")
+	}
+
+	// Cleanup
+	_, _ = collection.DeleteMany(ctx, nil)
+}
+
+func TestIndexTextWeighted(t *testing.T) {
+	model := IndexTextWeighted(map[string]int{"title": 10, "body": 2}, "english", "lang")
+
+	expectedKeys := bson.D{{Key: "body", Value: "text"}, {Key: "title", Value: "text"}}
+	if !equalBSOND(model.Keys, expectedKeys) {
+		t.Errorf("Expected keys %v, got %v", expectedKeys, model.Keys)
+	}
+
+	opts := resolveIndexOptions(t, model)
+
+	expectedWeights := bson.D{{Key: "body", Value: 2}, {Key: "title", Value: 10}}
+	weights, ok := opts.Weights.(bson.D)
+	if !ok || !equalBSOND(weights, expectedWeights) {
+		t.Errorf("Expected weights %v, got %v", expectedWeights, opts.Weights)
+	}
+	if opts.DefaultLanguage == nil || *opts.DefaultLanguage != "english" {
+		t.Errorf("Expected default language 'english', got %v", opts.DefaultLanguage)
+	}
+	if opts.LanguageOverride == nil || *opts.LanguageOverride != "lang" {
+		t.Errorf("Expected language override 'lang', got %v", opts.LanguageOverride)
+	}
+
+	// Empty language options should be left to the server defaults
+	model = IndexTextWeighted(map[string]int{"title": 5}, "", "")
+	opts = resolveIndexOptions(t, model)
+	if opts.DefaultLanguage != nil {
+		t.Errorf("Expected no default language, got %v", *opts.DefaultLanguage)
+	}
+	if opts.LanguageOverride != nil {
+		t.Errorf("Expected no language override, got %v", *opts.LanguageOverride)
+	}
+}
+
+// resolveIndexOptions applies the option setters of an IndexModel for inspection
+func resolveIndexOptions(t *testing.T, model IndexModel) *options.IndexOptions {
+	t.Helper()
+
+	opts := &options.IndexOptions{}
+	if model.Options == nil {
+		return opts
+	}
+	for _, apply := range model.Options.List() {
+		if err := apply(opts); err != nil {
+			t.Fatalf("Failed to apply index option: %v", err)
+		}
+	}
+	return opts
+}
+
+func TestIndexWithCollation(t *testing.T) {
+	model := IndexWithCollation(IndexUnique("email"), "en", 2)
+	opts := resolveIndexOptions(t, model)
+
+	if opts.Unique == nil || !*opts.Unique {
+		t.Error("Expected the unique option to be kept")
+	}
+	if opts.Collation == nil {
+		t.Fatal("Expected a collation on the index")
+	}
+	if opts.Collation.Locale != "en" || opts.Collation.Strength != 2 {
+		t.Errorf("Expected collation en/2, got %s/%d", opts.Collation.Locale, opts.Collation.Strength)
+	}
+
+	// Models without options get them created
+	model = IndexWithCollation(IndexAsc("name"), "fr", 1)
+	opts = resolveIndexOptions(t, model)
+	if opts.Collation == nil || opts.Collation.Locale != "fr" || opts.Collation.Strength != 1 {
+		t.Errorf("Expected collation fr/1, got %+v", opts.Collation)
+	}
+	if len(model.Keys) != 1 || model.Keys[0].Key != "name" {
+		t.Errorf("Expected index keys to be kept, got %v", model.Keys)
+	}
+}
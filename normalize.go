@@ -0,0 +1,215 @@
+package mongodb
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// fieldNormalizer rewrites the string values of a field before they are written
+type fieldNormalizer struct {
+	path []string
+	fn   func(string) string
+}
+
+// NormalizeField returns a collection handle that applies fn to the string values of field
+// (a dotted path for embedded fields) whenever documents are written. Combined with a unique
+// index it enforces case-insensitive uniqueness without a collation, since every stored value
+// is already lowercased. Calls can be chained to normalize several fields. The original
+// handle is left unchanged.
+//
+// Values are normalized in documents passed to InsertOne, InsertMany, ReplaceOne,
+// FindOneAndReplace and the helpers built on them, and in the $set, $setOnInsert, $push and
+// $addToSet operators of UpdateOne, UpdateMany, FindOneAndUpdate and the helpers built on
+// them. Strings inside an array at the path are normalized one by one. Filters are not
+// rewritten, so query with an already normalized value. Documents are copied to be
+// normalized (structs are converted to BSON) after the ID is generated, so the ULID checks on
+// struct ID fields still apply and the caller's document keeps its original values.
+// BulkWrite, pipeline updates, PreparedInsert and operations performed via Raw() are not
+// normalized.
+//
+// Example:
+//
+//	users := client.Collection("users").NormalizeField("email", strings.ToLower)
+//	_, err := users.CreateIndex(ctx, mongodb.IndexUnique("email"))
+//	_, err = users.InsertOne(ctx, bson.M{"email": "Ada@Example.com"}) // stored as ada@example.com
+func (col *Collection) NormalizeField(field string, fn func(string) string) *Collection {
+	clone := *col
+	clone.normalizers = append(slices.Clip(col.normalizers), fieldNormalizer{
+		path: strings.Split(field, "."),
+		fn:   fn,
+	})
+	return &clone
+}
+
+// normalizeFields returns a copy of a document to insert or replace with the normalized
+// fields rewritten. The caller's document is never modified.
+func (col *Collection) normalizeFields(document any) (any, error) {
+	if len(col.normalizers) == 0 || document == nil {
+		return document, nil
+	}
+
+	var doc any
+	switch d := document.(type) {
+	case bson.M, bson.D, map[string]any:
+		doc = d
+	default:
+		data, err := bson.Marshal(document)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal document for normalization: %w", err)
+		}
+		var converted bson.D
+		if err := bson.Unmarshal(data, &converted); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal document for normalization: %w", err)
+		}
+		doc = converted
+	}
+
+	for _, n := range col.normalizers {
+		doc = normalizeFieldValue(doc, n.path, n.fn)
+	}
+	return doc, nil
+}
+
+// normalizeUpdate returns a copy of an update document with the values written to the
+// normalized fields rewritten. The update builder's document is never modified.
+func (col *Collection) normalizeUpdate(updateDoc bson.M) bson.M {
+	if len(col.normalizers) == 0 {
+		return updateDoc
+	}
+
+	result := make(bson.M, len(updateDoc))
+	for operator, value := range updateDoc {
+		fields, ok := value.(bson.M)
+		switch operator {
+		case "$set", "$setOnInsert", "$push", "$addToSet":
+		default:
+			ok = false
+		}
+		if !ok {
+			result[operator] = value
+			continue
+		}
+
+		normalized := make(bson.M, len(fields))
+		for key, fieldValue := range fields {
+			for _, n := range col.normalizers {
+				if rest, ok := updatedPath(key, n.path); ok {
+					fieldValue = normalizeOperand(fieldValue, rest, n.fn)
+				}
+			}
+			normalized[key] = fieldValue
+		}
+		result[operator] = normalized
+	}
+	return result
+}
+
+// updatedPath reports whether an update operator key writes to the normalized path, and
+// returns the part of the path inside the written value. Array indexes and positional
+// operators after the path ("emails.0", "emails.$") write elements of the field.
+func updatedPath(key string, path []string) ([]string, bool) {
+	keyPath := strings.Split(key, ".")
+	n := min(len(keyPath), len(path))
+	if !slices.Equal(keyPath[:n], path[:n]) {
+		return nil, false
+	}
+	for _, segment := range keyPath[n:] {
+		if !isArrayElementSegment(segment) {
+			return nil, false
+		}
+	}
+	return path[n:], true
+}
+
+// isArrayElementSegment reports whether a path segment addresses an array element
+func isArrayElementSegment(segment string) bool {
+	if strings.HasPrefix(segment, "$") {
+		return true
+	}
+	return segment != "" && strings.Trim(segment, "0123456789") == ""
+}
+
+// normalizeOperand normalizes the value of an update operator, including the $each list of
+// $push and $addToSet
+func normalizeOperand(value any, path []string, fn func(string) string) any {
+	if modifiers, ok := value.(bson.M); ok && len(path) == 0 {
+		if each, ok := modifiers["$each"]; ok {
+			result := make(bson.M, len(modifiers))
+			for key, v := range modifiers {
+				result[key] = v
+			}
+			result["$each"] = normalizeFieldValue(each, nil, fn)
+			return result
+		}
+	}
+	return normalizeFieldValue(value, path, fn)
+}
+
+// normalizeFieldValue returns a copy of value with fn applied to the strings at path. Arrays
+// are traversed like in MongoDB dotted paths. Containers along the path are copied, so value
+// itself is never modified.
+func normalizeFieldValue(value any, path []string, fn func(string) string) any {
+	switch v := value.(type) {
+	case string:
+		if len(path) == 0 {
+			return fn(v)
+		}
+	case bson.A:
+		return normalizeFieldSlice(v, path, fn)
+	case []any:
+		return []any(normalizeFieldSlice(v, path, fn))
+	case []string:
+		if len(path) == 0 {
+			result := make([]string, len(v))
+			for i, s := range v {
+				result[i] = fn(s)
+			}
+			return result
+		}
+	case bson.D:
+		if len(path) == 0 {
+			return v
+		}
+		result := slices.Clone(v)
+		for i, elem := range result {
+			if elem.Key == path[0] {
+				result[i].Value = normalizeFieldValue(elem.Value, path[1:], fn)
+			}
+		}
+		return result
+	case bson.M:
+		return bson.M(normalizeFieldMap(v, path, fn))
+	case map[string]any:
+		return normalizeFieldMap(v, path, fn)
+	}
+	return value
+}
+
+// normalizeFieldSlice normalizes every element of an array
+func normalizeFieldSlice(values []any, path []string, fn func(string) string) bson.A {
+	result := make(bson.A, len(values))
+	for i, elem := range values {
+		result[i] = normalizeFieldValue(elem, path, fn)
+	}
+	return result
+}
+
+// normalizeFieldMap normalizes the field at path in a map document
+func normalizeFieldMap(doc map[string]any, path []string, fn func(string) string) map[string]any {
+	if len(path) == 0 {
+		return doc
+	}
+	fieldValue, ok := doc[path[0]]
+	if !ok {
+		return doc
+	}
+	result := make(map[string]any, len(doc))
+	for key, v := range doc {
+		result[key] = v
+	}
+	result[path[0]] = normalizeFieldValue(fieldValue, path[1:], fn)
+	return result
+}
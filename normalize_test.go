@@ -0,0 +1,251 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/update"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestNormalizeFields(t *testing.T) {
+	col := newTestCollection("users").NormalizeField("email", strings.ToLower).NormalizeField("contact.email", strings.ToLower)
+
+	type contact struct {
+		Email string `bson:"email"`
+	}
+	type user struct {
+		Name    string  `bson:"name"`
+		Email   string  `bson:"email"`
+		Contact contact `bson:"contact"`
+	}
+
+	tests := []struct {
+		name     string
+		document any
+		expected any
+	}{
+		{
+			"bson.M",
+			bson.M{"name": "Ada", "email": "Ada@Example.com", "contact": bson.M{"email": "OPS@Example.com"}},
+			bson.M{"name": "Ada", "email": "ada@example.com", "contact": bson.M{"email": "ops@example.com"}},
+		},
+		{
+			"bson.D",
+			bson.D{{Key: "name", Value: "Ada"}, {Key: "email", Value: "Ada@Example.com"}},
+			bson.D{{Key: "name", Value: "Ada"}, {Key: "email", Value: "ada@example.com"}},
+		},
+		{
+			"struct",
+			user{Name: "Ada", Email: "Ada@Example.com", Contact: contact{Email: "OPS@Example.com"}},
+			bson.D{
+				{Key: "name", Value: "Ada"},
+				{Key: "email", Value: "ada@example.com"},
+				{Key: "contact", Value: bson.D{{Key: "email", Value: "ops@example.com"}}},
+			},
+		},
+		{
+			"array of strings",
+			bson.M{"email": bson.A{"A@Example.com", "b@example.com"}},
+			bson.M{"email": bson.A{"a@example.com", "b@example.com"}},
+		},
+		{
+			"array of subdocuments",
+			bson.M{"contact": bson.A{bson.M{"email": "A@Example.com"}, bson.M{"phone": "1"}}},
+			bson.M{"contact": bson.A{bson.M{"email": "a@example.com"}, bson.M{"phone": "1"}}},
+		},
+		{
+			"non-string values are kept",
+			bson.M{"email": nil, "contact": "none"},
+			bson.M{"email": nil, "contact": "none"},
+		},
+		{
+			"missing fields",
+			bson.M{"name": "Ada"},
+			bson.M{"name": "Ada"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := col.normalizeFields(tt.document)
+			if err != nil {
+				t.Fatalf("normalizeFields failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	// The caller's document is left as is
+	doc := bson.M{"email": "Ada@Example.com", "contact": bson.M{"email": "OPS@Example.com"}}
+	if _, err := col.normalizeFields(doc); err != nil {
+		t.Fatalf("normalizeFields failed: %v", err)
+	}
+	if doc["email"] != "Ada@Example.com" || doc["contact"].(bson.M)["email"] != "OPS@Example.com" {
+		t.Errorf("Expected the original document to be unchanged, got %v", doc)
+	}
+}
+
+func TestNormalizeUpdate(t *testing.T) {
+	col := newTestCollection("users").NormalizeField("email", strings.ToLower).NormalizeField("contact.email", strings.ToLower)
+
+	builder := update.Set("email", "Ada@Example.com").
+		Set("email_verified", "YES").
+		Set("contact", bson.M{"email": "OPS@Example.com"}).
+		SetOnInsert("contact.email", "Sales@Example.com").
+		Inc("logins", 1)
+	got := col.normalizeUpdate(builder.Build())
+
+	expected := bson.M{
+		"$set": bson.M{
+			"email":          "ada@example.com",
+			"email_verified": "YES",
+			"contact":        bson.M{"email": "ops@example.com"},
+		},
+		"$setOnInsert": bson.M{"contact.email": "sales@example.com"},
+		"$inc":         bson.M{"logins": 1},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if builder.Build()["$set"].(bson.M)["email"] != "Ada@Example.com" {
+		t.Error("Expected the update builder to be unchanged")
+	}
+
+	tests := []struct {
+		name     string
+		update   bson.M
+		expected bson.M
+	}{
+		{"array element", bson.M{"$set": bson.M{"email.0": "A@X.com"}}, bson.M{"$set": bson.M{"email.0": "a@x.com"}}},
+		{"positional", bson.M{"$set": bson.M{"email.$": "A@X.com"}}, bson.M{"$set": bson.M{"email.$": "a@x.com"}}},
+		{"push", update.Push("email", "A@X.com").Build(), bson.M{"$push": bson.M{"email": "a@x.com"}}},
+		{"push each", update.PushEach("email", "A@X.com", "B@X.com").Build(), bson.M{"$push": bson.M{"email": bson.M{"$each": []any{"a@x.com", "b@x.com"}}}}},
+		{"unset", bson.M{"$unset": bson.M{"email": "X"}}, bson.M{"$unset": bson.M{"email": "X"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := col.normalizeUpdate(tt.update); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeFieldLeavesOriginalHandle(t *testing.T) {
+	base := newTestCollection("users")
+	emails := base.NormalizeField("email", strings.ToLower)
+	names := emails.NormalizeField("name", strings.TrimSpace)
+	codes := emails.NormalizeField("code", strings.ToUpper)
+
+	if len(base.normalizers) != 0 {
+		t.Errorf("Expected the original handle to be unchanged, got %d normalizers", len(base.normalizers))
+	}
+	if len(names.normalizers) != 2 || names.normalizers[1].path[0] != "name" {
+		t.Errorf("Expected email and name normalizers, got %v", names.normalizers)
+	}
+	if len(codes.normalizers) != 2 || codes.normalizers[1].path[0] != "code" {
+		t.Errorf("Expected handles derived from the same handle not to share normalizers, got %v", codes.normalizers)
+	}
+
+	doc := bson.M{"email": "A@X.com"}
+	if got, _ := base.normalizeFields(doc); !reflect.DeepEqual(got, doc) {
+		t.Errorf("Expected no normalization without NormalizeField, got %v", got)
+	}
+}
+
+func TestNormalizeFieldKeepsULIDChecks(t *testing.T) {
+	col := newTestCollection("users").NormalizeField("email", strings.ToLower).NormalizeField("contact.email", strings.ToLower)
+	col.client.config.IDMode = IDModeULID
+	ctx := context.Background()
+
+	type objectIDUser struct {
+		ID    bson.ObjectID `bson:"_id,omitempty"`
+		Email string        `bson:"email"`
+	}
+	user := objectIDUser{Email: "Ada@Example.com"}
+
+	if _, err := col.InsertOne(ctx, user); !errors.Is(err, ErrULIDIncompatibleType) {
+		t.Errorf("InsertOne: expected ErrULIDIncompatibleType, got %v", err)
+	}
+	if _, err := col.InsertMany(ctx, []any{user}); !errors.Is(err, ErrULIDIncompatibleType) {
+		t.Errorf("InsertMany: expected ErrULIDIncompatibleType, got %v", err)
+	}
+
+	// Compatible structs get their ULID before normalization converts them
+	type stringIDUser struct {
+		ID    string `bson:"_id,omitempty"`
+		Email string `bson:"email"`
+	}
+	prepared, err := col.prepareDocumentForInsert(&stringIDUser{Email: "Ada@Example.com"})
+	if err != nil {
+		t.Fatalf("prepareDocumentForInsert failed: %v", err)
+	}
+	normalized, err := col.normalizeFields(prepared)
+	if err != nil {
+		t.Fatalf("normalizeFields failed: %v", err)
+	}
+	doc := normalized.(bson.D)
+	if id, _ := doc[0].Value.(string); doc[0].Key != "_id" || len(id) != 26 {
+		t.Errorf("Expected a ULID _id, got %v", doc)
+	}
+	if doc[1].Value != "ada@example.com" {
+		t.Errorf("Expected the email to be normalized, got %v", doc)
+	}
+}
+
+func TestNormalizeFieldIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	users := client.Collection("test_normalize_field").NormalizeField("email", strings.ToLower)
+	_ = users.Drop(ctx)
+	defer func() {
+		_ = users.Drop(ctx)
+	}()
+
+	if _, err := users.CreateIndex(ctx, IndexUnique("email")); err != nil {
+		t.Fatalf("CreateIndex failed: %v", err)
+	}
+
+	result, err := users.InsertOne(ctx, bson.M{"name": "Ada", "email": "Ada@Example.com"})
+	if err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+
+	var stored bson.M
+	if err := users.FindByID(ctx, result.InsertedID).Decode(&stored); err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if stored["email"] != "ada@example.com" {
+		t.Errorf("Expected a lowercased email, got %v", stored["email"])
+	}
+
+	// The unique index now rejects the same address in another case
+	if _, err := users.InsertOne(ctx, bson.M{"name": "Ada", "email": "ADA@example.COM"}); !IsDuplicateKeyError(err) {
+		t.Errorf("Expected a duplicate key error, got %v", err)
+	}
+
+	if _, err := users.UpdateOne(ctx, filter.Eq("_id", result.InsertedID), update.Set("email", "Countess@Example.com")); err != nil {
+		t.Fatalf("UpdateOne failed: %v", err)
+	}
+	count, err := users.CountDocuments(ctx, filter.Eq("email", "countess@example.com"))
+	if err != nil {
+		t.Fatalf("CountDocuments failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected the updated email to be lowercased, got %d matches", count)
+	}
+}
@@ -2,6 +2,8 @@ package mongodb
 
 import (
 	"crypto/tls"
+	"os"
+	"strconv"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/event"
@@ -39,6 +41,20 @@ func WithCredentials(username, password string) Option {
 func WithDatabase(name string) Option {
 	return func(c *Config) {
 		c.Database = name
+		c.databaseSet = name != ""
+	}
+}
+
+// WithStrictDatabase makes NewClient fail with ErrDatabaseNotSet unless a database name is
+// configured explicitly via WithDatabase or MONGODB_DATABASE. This prevents data from
+// silently landing in the default "app" database when the setting is forgotten.
+//
+// The setting is kept when FromEnv or FromEnvWithPrefix is applied after it, overriding
+// MONGODB_STRICT_DATABASE.
+func WithStrictDatabase(enabled bool) Option {
+	return func(c *Config) {
+		c.StrictDatabase = enabled
+		c.strictDatabaseOption = &enabled
 	}
 }
 
@@ -49,6 +65,60 @@ func WithAppName(name string) Option {
 	}
 }
 
+// WithAppNameSuffix appends a suffix to the application name (separated by "-") so that
+// currentOp output and server logs show which instance issued an operation.
+// The suffix is kept separate from AppName, so it can be combined with WithAppName or FromEnv in any order.
+func WithAppNameSuffix(suffix string) Option {
+	return func(c *Config) {
+		c.AppNameSuffix = suffix
+	}
+}
+
+// WithInstanceAppNameSuffix appends "<hostname>-<pid>" to the application name.
+// In Kubernetes the hostname is the pod name, which makes each replica distinguishable.
+func WithInstanceAppNameSuffix() Option {
+	return func(c *Config) {
+		c.AppNameSuffix = instanceAppNameSuffix()
+	}
+}
+
+// instanceAppNameSuffix returns "<hostname>-<pid>", or just the pid if the hostname is unavailable
+func instanceAppNameSuffix() string {
+	pid := strconv.Itoa(os.Getpid())
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return pid
+	}
+	return hostname + "-" + pid
+}
+
+// WithLazyConnect makes NewClient succeed even when MongoDB is unreachable at startup, so
+// services can boot before their database. The driver client is kept and reconnects in the
+// background; until the server is available, operations fail with the driver's server
+// selection error and are not retried. Only when no driver client could be created at all
+// (for example, the SRV lookup of a mongodb+srv URI failed) do client methods attempt to
+// create it once more before failing with ErrNotConnected. Collection and Database handles
+// obtained while there is no driver client are unbound and their operations return
+// ErrNotConnected; request new handles once the client has connected.
+func WithLazyConnect(enabled bool) Option {
+	return func(c *Config) {
+		c.LazyConnect = enabled
+	}
+}
+
+// WithPoolSaturationAlert raises an early warning of pool exhaustion. When the share of
+// MaxPoolSize checked out stays at or above threshold (e.g. 0.9) for at least sustained, the
+// periodic health check calls handler once, or logs a warning when handler is nil. The alert
+// is re-armed when utilization drops below the threshold. Saturation is sampled at each
+// health check, so sustained is effectively rounded up to the health check interval.
+func WithPoolSaturationAlert(threshold float64, sustained time.Duration, handler func(PoolSaturation)) Option {
+	return func(c *Config) {
+		c.PoolSaturationThreshold = threshold
+		c.PoolSaturationDuration = sustained
+		c.OnPoolSaturation = handler
+	}
+}
+
 // WithMaxPoolSize sets the maximum number of connections in the pool
 func WithMaxPoolSize(size int) Option {
 	return func(c *Config) {
@@ -63,6 +133,72 @@ func WithMinPoolSize(size int) Option {
 	}
 }
 
+// WithWarmPool enables pre-establishing MinPoolSize connections right after connecting.
+// The driver otherwise opens connections lazily, which makes the first requests after startup slow.
+func WithWarmPool(enabled bool) Option {
+	return func(c *Config) {
+		c.WarmPool = enabled
+	}
+}
+
+// WithOperationRetry retries FindOne, CountDocuments, Distinct and DistinctCount, and the
+// opening of Find and Aggregate cursors, up to attempts times in total when they fail with a
+// transient error (network errors, timeouts, errors labelled retryable by the server and
+// NotPrimary errors returned while a new primary is being elected), waiting backoff between
+// attempts. Failures while iterating an open cursor are not retried.
+//
+// Retries stay within the caller's context deadline: the time remaining is divided evenly
+// across the remaining attempts, so a 30s context with 3 attempts gives each attempt about 10s
+// instead of letting every attempt wait the full 30s. Without a deadline each attempt is only
+// bounded by the socket timeout. A value of 1 or less disables retries.
+func WithOperationRetry(attempts int, backoff time.Duration) Option {
+	return func(c *Config) {
+		c.OperationRetryAttempts = attempts
+		c.OperationRetryBackoff = backoff
+	}
+}
+
+// WithWriteRateLimit throttles bulk writes to opsPerSecond operations per second using a token
+// bucket, to keep bulk jobs from overwhelming a shared primary. InsertMany (including
+// PreparedInserter and CopyTo batches) counts one operation per document, BulkWrite one per
+// model and UpdateMany or UpdateManyPipeline one per call. Waiting respects context cancellation. A value of 0 or
+// less disables rate limiting.
+func WithWriteRateLimit(opsPerSecond int) Option {
+	return func(c *Config) {
+		c.WriteRateLimit = opsPerSecond
+	}
+}
+
+// WithMaxDocumentSize makes InsertOne, InsertMany and ReplaceOne fail with ErrDocumentTooLarge
+// when a document's marshaled BSON is larger than maxBytes, catching runaway documents before
+// the server rejects them at its 16MB limit. A value of 0 or less disables the check.
+func WithMaxDocumentSize(maxBytes int) Option {
+	return func(c *Config) {
+		c.MaxDocumentSize = maxBytes
+	}
+}
+
+// WithPreciseCount controls how CountDocuments handles an empty filter. By default the count
+// is read from the collection metadata with estimatedDocumentCount, which is fast but may be
+// off after an unclean shutdown or, on sharded clusters, while orphaned documents exist.
+// Enabling precise counts always scans the collection (or its _id index) for an exact count.
+func WithPreciseCount(enabled bool) Option {
+	return func(c *Config) {
+		c.PreciseCount = enabled
+	}
+}
+
+// WithMaxTimeMS sets a server-side time limit (maxTimeMS) applied automatically to find,
+// aggregate, count, distinct, update, delete and findAndModify operations, so that no single
+// operation can run longer than limit whatever deadline the caller's context has; the
+// operation then fails with a MaxTimeMSExpired error. Use WithOperationMaxTime to override
+// it for a single call. A value of 0 or less disables the limit.
+func WithMaxTimeMS(limit time.Duration) Option {
+	return func(c *Config) {
+		c.MaxTime = limit
+	}
+}
+
 // WithMaxIdleTime sets the maximum time a connection can remain idle
 func WithMaxIdleTime(duration time.Duration) Option {
 	return func(c *Config) {
@@ -92,6 +228,23 @@ func WithTLSConfig(config *tls.Config) Option {
 	}
 }
 
+// WithAutoEncryption enables Client-Side Field Level Encryption. Client creation fails with
+// ErrInvalidAutoEncryption when the key vault namespace or KMS provider credentials are
+// incomplete. The library must be built with the "cse" build tag.
+//
+// Example:
+//
+//	client, err := mongodb.NewClient(mongodb.WithAutoEncryption(mongodb.AutoEncryptionOptions{
+//	    KeyVaultNamespace: "encryption.__keyVault",
+//	    KMSProviders:      map[string]map[string]any{"local": {"key": masterKey}},
+//	    SchemaMap:         map[string]any{"app.patients": patientSchema},
+//	}))
+func WithAutoEncryption(opts AutoEncryptionOptions) Option {
+	return func(c *Config) {
+		c.AutoEncryption = &opts
+	}
+}
+
 // WithMonitor sets a custom command monitor for APM integration.
 // This allows users to plug in monitoring tools like Datadog, OpenTelemetry, etc.
 // The monitor receives events for all database commands (find, insert, update, etc.)
@@ -171,6 +324,15 @@ func WithServerSelectionTimeout(duration time.Duration) Option {
 	}
 }
 
+// WithHeartbeatInterval sets how often the driver checks each server's state. A shorter
+// interval detects primary step-downs and elections sooner, speeding up recovery after a
+// failover at the cost of more monitoring traffic. The driver minimum is 500ms.
+func WithHeartbeatInterval(interval time.Duration) Option {
+	return func(c *Config) {
+		c.HeartbeatInterval = interval
+	}
+}
+
 // WithEnvPrefix sets a custom prefix for environment variables
 func WithEnvPrefix(prefix string) Option {
 	return func(c *Config) {
@@ -191,13 +353,25 @@ func WithConnectionName(name string) Option {
 
 // WithDirectConnection enables or disables direct connection mode
 // When enabled, connects directly to a single MongoDB instance without replica set discovery
-// Note: This only takes effect when connecting to a single host
+// Note: This only takes effect when connecting to a single host; with several hosts the driver
+// keeps discovering the replica set topology, so reconnects follow membership changes
 func WithDirectConnection(enabled bool) Option {
 	return func(c *Config) {
 		c.DirectConnection = enabled
 	}
 }
 
+// WithServerAPIVersion declares the Stable API version (options.ServerAPIVersion1 is "1") sent
+// with every command. In strict mode the server rejects commands that are not part of that
+// version with an APIStrictError, which IsAPIStrictError recognizes; the client also logs a
+// warning naming the rejected command.
+func WithServerAPIVersion(version string, strict bool) Option {
+	return func(c *Config) {
+		c.ServerAPIVersion = version
+		c.ServerAPIStrict = strict
+	}
+}
+
 // WithLogger sets a custom logger implementation for the MongoDB client
 // If not provided, the client will use a NopLogger that produces no output
 func WithLogger(logger Logger) Option {
@@ -206,6 +380,16 @@ func WithLogger(logger Logger) Option {
 	}
 }
 
+// WithQueryLoggingOnError adds the filter, update or pipeline of an operation to its error log
+// entry when the operation fails, giving post-mortem context without logging every query.
+// Successful operations are logged as usual. Query values may contain personal data, so make
+// sure error logs are handled accordingly.
+func WithQueryLoggingOnError(enabled bool) Option {
+	return func(c *Config) {
+		c.LogQueriesOnError = enabled
+	}
+}
+
 // ReadPreference represents MongoDB read preference options
 type ReadPreference string
 
@@ -233,18 +417,29 @@ func FromEnv() Option {
 		// Load environment variables into the config
 		envConfig, err := loadConfigFromEnv("")
 		if err == nil {
-			*c = *envConfig
+			applyEnvConfig(c, envConfig)
 		}
 	}
 }
 
+// applyEnvConfig replaces c with the configuration loaded from the environment, keeping the
+// strictness set by an earlier WithStrictDatabase
+func applyEnvConfig(c *Config, envConfig *Config) {
+	strict := c.strictDatabaseOption
+	*c = *envConfig
+	if strict != nil {
+		c.StrictDatabase = *strict
+		c.strictDatabaseOption = strict
+	}
+}
+
 // FromEnvWithPrefix returns an option that loads configuration from environment variables with a custom prefix
 func FromEnvWithPrefix(prefix string) Option {
 	return func(c *Config) {
 		// Load environment variables with prefix into the config
 		envConfig, err := loadConfigFromEnv(prefix)
 		if err == nil {
-			*c = *envConfig
+			applyEnvConfig(c, envConfig)
 		}
 	}
 }
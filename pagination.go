@@ -0,0 +1,170 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// PaginatedResult represents one page of an aggregation along with the total match count
+type PaginatedResult struct {
+	Documents   []bson.M `json:"documents" bson:"documents"`
+	TotalCount  int64    `json:"total_count" bson:"total_count"`
+	Page        int      `json:"page" bson:"page"`
+	PageSize    int      `json:"page_size" bson:"page_size"`
+	TotalPages  int      `json:"total_pages" bson:"total_pages"`
+	HasNext     bool     `json:"has_next" bson:"has_next"`
+	HasPrevious bool     `json:"has_previous" bson:"has_previous"`
+
+	data bson.RawValue
+}
+
+// Decode decodes the documents of the page into results, which must be a pointer to a slice
+func (r *PaginatedResult) Decode(results any) error {
+	if r.data.Type == 0 {
+		return nil
+	}
+	return r.data.Unmarshal(results)
+}
+
+// paginatedFacet is the shape of the single document produced by the pagination $facet stage
+type paginatedFacet struct {
+	Data       bson.RawValue `bson:"data"`
+	TotalCount []struct {
+		Count int64 `bson:"count"`
+	} `bson:"totalCount"`
+}
+
+// AggregatePaginated runs basePipeline and returns a single page of its output together with
+// the total number of results, in one round trip. The base pipeline is wrapped in a $facet stage
+// that computes both the page (via $skip/$limit) and the total count.
+//
+// Pages are 1-based. Include a $sort stage in basePipeline for stable page boundaries.
+// The base pipeline builder is not modified.
+//
+// Example:
+//
+//	base := pipeline.New().Match(filter.Eq("status", "active")).Sort(bson.D{{Key: "created_at", Value: -1}})
+//	page, err := col.AggregatePaginated(ctx, base, 2, 20)
+//	fmt.Printf("page %d of %d (%d total)\n", page.Page, page.TotalPages, page.TotalCount)
+func (col *Collection) AggregatePaginated(ctx context.Context, basePipeline *pipeline.Builder, page, pageSize int, opts ...options.Lister[options.AggregateOptions]) (*PaginatedResult, error) {
+	if page < 1 {
+		return nil, fmt.Errorf("page must be at least 1, got %d", page)
+	}
+	if pageSize < 1 {
+		return nil, fmt.Errorf("page size must be at least 1, got %d", pageSize)
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	if basePipeline != nil {
+		if err := basePipeline.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	pipelineDoc := buildPaginatedPipeline(basePipeline, page, pageSize)
+	if err := col.checkAggregateWritable(pipelineDoc); err != nil {
+		return nil, err
+	}
+
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	cursor, err := col.collection.Aggregate(ctx, pipelineDoc, opts...)
+	if err != nil {
+		col.client.incrementFailureCount()
+		col.errorLogger(ctx, "pipeline", pipelineDoc).Error("Failed to run paginated aggregation",
+			"error", err.Error(),
+			"collection", col.name)
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var facet paginatedFacet
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&facet); err != nil {
+			return nil, fmt.Errorf("failed to decode paginated result: %w", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		col.client.incrementFailureCount()
+		return nil, err
+	}
+
+	var documents []bson.M
+	if facet.Data.Type != 0 {
+		if err := facet.Data.Unmarshal(&documents); err != nil {
+			return nil, fmt.Errorf("failed to decode paginated documents: %w", err)
+		}
+	}
+	if documents == nil {
+		documents = []bson.M{}
+	}
+
+	var total int64
+	if len(facet.TotalCount) > 0 {
+		total = facet.TotalCount[0].Count
+	}
+
+	col.client.incrementOperationCount()
+
+	result := newPaginatedResult(documents, total, page, pageSize)
+	result.data = facet.Data
+
+	col.logger(ctx).Debug("Paginated aggregation completed",
+		"collection", col.name,
+		"page", page,
+		"page_size", pageSize,
+		"total", int(total))
+
+	return result, nil
+}
+
+// buildPaginatedPipeline wraps the stages of basePipeline in a $facet stage producing
+// a "data" page and a "totalCount" count. The base builder is not modified.
+func buildPaginatedPipeline(basePipeline *pipeline.Builder, page, pageSize int) bson.A {
+	pipelineDoc := bson.A{}
+	if basePipeline != nil {
+		pipelineDoc = basePipeline.ToBSONArray()
+	}
+
+	skip := int64(page-1) * int64(pageSize)
+	return append(pipelineDoc, bson.M{
+		"$facet": bson.M{
+			"data": bson.A{
+				bson.M{"$skip": skip},
+				bson.M{"$limit": int64(pageSize)},
+			},
+			"totalCount": bson.A{
+				bson.M{"$count": "count"},
+			},
+		},
+	})
+}
+
+// newPaginatedResult computes page metadata for a page of documents
+func newPaginatedResult(documents []bson.M, total int64, page, pageSize int) *PaginatedResult {
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return &PaginatedResult{
+		Documents:   documents,
+		TotalCount:  total,
+		Page:        page,
+		PageSize:    pageSize,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}
+}
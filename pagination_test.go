@@ -0,0 +1,154 @@
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestBuildPaginatedPipeline(t *testing.T) {
+	base := pipeline.New().Match(filter.Eq("status", "active")).Sort(bson.D{{Key: "n", Value: 1}})
+
+	result := buildPaginatedPipeline(base, 3, 10)
+
+	if len(result) != 3 {
+		t.Fatalf("Expected 3 stages, got %d", len(result))
+	}
+	if len(base.Build()) != 2 {
+		t.Errorf("Base pipeline was modified: %v", base.Build())
+	}
+
+	expected := bson.M{
+		"$facet": bson.M{
+			"data":       bson.A{bson.M{"$skip": int64(20)}, bson.M{"$limit": int64(10)}},
+			"totalCount": bson.A{bson.M{"$count": "count"}},
+		},
+	}
+	if !reflect.DeepEqual(result[2], expected) {
+		t.Errorf("Expected %v, got %v", expected, result[2])
+	}
+
+	if len(buildPaginatedPipeline(nil, 1, 5)) != 1 {
+		t.Error("Expected only the $facet stage for a nil base pipeline")
+	}
+}
+
+func TestNewPaginatedResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		total       int64
+		page        int
+		pageSize    int
+		totalPages  int
+		hasNext     bool
+		hasPrevious bool
+	}{
+		{name: "First page", total: 25, page: 1, pageSize: 10, totalPages: 3, hasNext: true},
+		{name: "Middle page", total: 25, page: 2, pageSize: 10, totalPages: 3, hasNext: true, hasPrevious: true},
+		{name: "Last partial page", total: 25, page: 3, pageSize: 10, totalPages: 3, hasPrevious: true},
+		{name: "Exact multiple", total: 20, page: 2, pageSize: 10, totalPages: 2, hasPrevious: true},
+		{name: "No results", total: 0, page: 1, pageSize: 10, totalPages: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newPaginatedResult([]bson.M{}, tt.total, tt.page, tt.pageSize)
+			if result.TotalPages != tt.totalPages {
+				t.Errorf("Expected %d total pages, got %d", tt.totalPages, result.TotalPages)
+			}
+			if result.HasNext != tt.hasNext {
+				t.Errorf("Expected HasNext=%v, got %v", tt.hasNext, result.HasNext)
+			}
+			if result.HasPrevious != tt.hasPrevious {
+				t.Errorf("Expected HasPrevious=%v, got %v", tt.hasPrevious, result.HasPrevious)
+			}
+		})
+	}
+}
+
+func TestAggregatePaginatedRejectsInvalidPage(t *testing.T) {
+	col := newTestCollection("users")
+
+	if _, err := col.AggregatePaginated(context.Background(), nil, 0, 10); err == nil {
+		t.Error("Expected error for page 0")
+	}
+	if _, err := col.AggregatePaginated(context.Background(), nil, 1, 0); err == nil {
+		t.Error("Expected error for page size 0")
+	}
+}
+
+func TestAggregatePaginatedIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_aggregate_paginated")
+	_, _ = col.DeleteMany(ctx, nil)
+	defer func() {
+		_, _ = col.DeleteMany(ctx, nil)
+	}()
+
+	docs := make([]any, 0, 25)
+	for i := 1; i <= 25; i++ {
+		status := "active"
+		if i%5 == 0 {
+			status = "inactive"
+		}
+		docs = append(docs, bson.M{"n": i, "status": status})
+	}
+	if _, err := col.InsertMany(ctx, docs); err != nil {
+		t.Fatalf("Failed to seed collection: %v", err)
+	}
+
+	// 20 active documents, sorted by n
+	base := pipeline.New().
+		Match(filter.Eq("status", "active")).
+		Sort(bson.D{{Key: "n", Value: 1}})
+
+	page, err := col.AggregatePaginated(ctx, base, 3, 8)
+	if err != nil {
+		t.Fatalf("AggregatePaginated failed: %v", err)
+	}
+
+	if page.TotalCount != 20 {
+		t.Errorf("Expected total count 20, got %d", page.TotalCount)
+	}
+	if page.TotalPages != 3 {
+		t.Errorf("Expected 3 total pages, got %d", page.TotalPages)
+	}
+	if len(page.Documents) != 4 {
+		t.Fatalf("Expected 4 documents on the last page, got %d", len(page.Documents))
+	}
+	if page.HasNext || !page.HasPrevious {
+		t.Errorf("Unexpected navigation flags: HasNext=%v HasPrevious=%v", page.HasNext, page.HasPrevious)
+	}
+
+	var typed []struct {
+		N int `bson:"n"`
+	}
+	if err := page.Decode(&typed); err != nil {
+		t.Fatalf("Failed to decode page: %v", err)
+	}
+	// Active values of n are 1..24 excluding multiples of 5; the last page starts at the 17th
+	if len(typed) != 4 || typed[0].N != 21 || typed[3].N != 24 {
+		t.Errorf("Unexpected page contents: %v", typed)
+	}
+
+	// A page past the end returns no documents but still reports the total
+	empty, err := col.AggregatePaginated(ctx, base, 10, 8)
+	if err != nil {
+		t.Fatalf("AggregatePaginated failed: %v", err)
+	}
+	if len(empty.Documents) != 0 || empty.TotalCount != 20 {
+		t.Errorf("Expected empty page with total 20, got %d documents and total %d", len(empty.Documents), empty.TotalCount)
+	}
+}
@@ -0,0 +1,44 @@
+package pipeline
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+
+// Expression helpers build aggregation expressions for use inside stages such as
+// $addFields, $project, $set or $group. Unlike the Builder methods they do not add stages.
+
+// Function returns a $function expression that evaluates a custom JavaScript function
+// on the server. body is the function source, args are the arguments passed to it
+// (field paths such as "$price" or literals), and lang is the function language;
+// an empty lang defaults to "js", the only language supported by MongoDB.
+//
+// Caveats:
+//   - Server-side JavaScript must be enabled (security.javascriptEnabled); it is
+//     disabled on some deployments and tiers for security reasons.
+//   - Never build body from user input: it is executed as code on the server.
+//   - JavaScript runs much slower than native operators, cannot use indexes and
+//     blocks pipeline optimizations; prefer native expressions where possible.
+//
+// Example:
+//
+//	pipeline.New().AddFields(bson.M{
+//	    "slug": pipeline.Function(
+//	        `function(name) { return name.toLowerCase().replace(/\s+/g, "-") }`,
+//	        []any{"$name"}, ""),
+//	})
+func Function(body string, args []any, lang string) bson.M {
+	if lang == "" {
+		lang = "js"
+	}
+
+	fnArgs := bson.A{}
+	for _, arg := range args {
+		fnArgs = append(fnArgs, arg)
+	}
+
+	return bson.M{
+		"$function": bson.M{
+			"body": body,
+			"args": fnArgs,
+			"lang": lang,
+		},
+	}
+}
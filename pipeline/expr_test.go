@@ -0,0 +1,40 @@
+package pipeline
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestFunction(t *testing.T) {
+	body := "function(a, b) { return a + b }"
+
+	result := Function(body, []any{"$price", 10}, "js")
+	expected := bson.M{
+		"$function": bson.M{
+			"body": body,
+			"args": bson.A{"$price", 10},
+			"lang": "js",
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	// Defaults: empty lang is js and nil args become an empty array
+	result = Function("function() { return 1 }", nil, "")
+	fn := result["$function"].(bson.M)
+	if fn["lang"] != "js" {
+		t.Errorf("Expected default lang js, got %v", fn["lang"])
+	}
+	if args, ok := fn["args"].(bson.A); !ok || len(args) != 0 {
+		t.Errorf("Expected empty args array, got %v", fn["args"])
+	}
+
+	// Usable inside a stage
+	stage := New().AddFields(bson.M{"total": Function(body, []any{"$a", "$b"}, "")}).Build()[0]
+	if _, ok := stage["$addFields"].(bson.M)["total"].(bson.M)["$function"]; !ok {
+		t.Errorf("Expected $function inside $addFields, got %v", stage)
+	}
+}
@@ -0,0 +1,127 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ErrStageNotFirst is returned by Validate when a stage that MongoDB only accepts as the first
+// stage of a pipeline, such as $geoNear, appears later
+var ErrStageNotFirst = errors.New("stage must be the first stage of the pipeline")
+
+// firstOnlyStages are the stages MongoDB only accepts at the start of a pipeline
+var firstOnlyStages = map[string]bool{
+	"$geoNear":      true,
+	"$collStats":    true,
+	"$indexStats":   true,
+	"$changeStream": true,
+	"$currentOp":    true,
+	"$documents":    true,
+}
+
+// defaultGeoNearDistanceField is used when GeoNearOptions.DistanceField is empty
+const defaultGeoNearDistanceField = "distance"
+
+// GeoNearOptions configures a $geoNear stage
+type GeoNearOptions struct {
+	// Near is the point to measure distances from, usually a GeoJSON point built with Point
+	Near any
+
+	// DistanceField is the output field holding the computed distance; defaults to "distance"
+	DistanceField string
+
+	// MaxDistance limits results to documents within this distance, in meters for GeoJSON
+	// points; 0 means no limit
+	MaxDistance float64
+
+	// MinDistance excludes documents closer than this distance; 0 means no minimum
+	MinDistance float64
+
+	// Query restricts the documents considered, like a $match applied before the search
+	Query *filter.Builder
+
+	// Spherical computes distances on a sphere; required for 2d indexes queried with
+	// spherical geometry and implied for 2dsphere indexes
+	Spherical bool
+
+	// Key is the geospatial indexed field to use when the collection has several geo indexes
+	Key string
+}
+
+// Point returns a GeoJSON point for the given coordinates. Note that GeoJSON lists the
+// longitude before the latitude.
+func Point(longitude, latitude float64) bson.M {
+	return bson.M{"type": "Point", "coordinates": bson.A{longitude, latitude}}
+}
+
+// GeoNear adds a $geoNear stage returning documents sorted by distance from opts.Near, with
+// the distance in opts.DistanceField. $geoNear requires a 2dsphere (or 2d) index and must be
+// the first stage of the pipeline; use the standalone GeoNear to start a pipeline with it,
+// and Validate to check the order of a pipeline assembled elsewhere.
+func (b *Builder) GeoNear(opts GeoNearOptions) *Builder {
+	distanceField := opts.DistanceField
+	if distanceField == "" {
+		distanceField = defaultGeoNearDistanceField
+	}
+
+	geoNear := bson.M{
+		"near":          opts.Near,
+		"distanceField": distanceField,
+	}
+	if opts.MaxDistance > 0 {
+		geoNear["maxDistance"] = opts.MaxDistance
+	}
+	if opts.MinDistance > 0 {
+		geoNear["minDistance"] = opts.MinDistance
+	}
+	if opts.Query != nil {
+		if query := opts.Query.Build(); len(query) > 0 {
+			geoNear["query"] = query
+		}
+	}
+	if opts.Spherical {
+		geoNear["spherical"] = true
+	}
+	if opts.Key != "" {
+		geoNear["key"] = opts.Key
+	}
+
+	b.stages = append(b.stages, bson.M{"$geoNear": geoNear})
+	return b
+}
+
+// GeoNear creates a pipeline starting with a $geoNear stage (standalone function).
+//
+// Example:
+//
+//	nearest := pipeline.GeoNear(pipeline.GeoNearOptions{
+//		Near:        pipeline.Point(-73.98, 40.75),
+//		MaxDistance: 2000,
+//		Query:       filter.Eq("type", "cafe"),
+//	}).Limit(10)
+func GeoNear(opts GeoNearOptions) *Builder {
+	return New().GeoNear(opts)
+}
+
+// Validate checks that stages MongoDB only accepts first, such as $geoNear, are at the start
+// of the pipeline, and reports unwind options that did not follow an $unwind (see
+// PreserveEmpty). It reports the problem without a round trip to the server.
+func (b *Builder) Validate() error {
+	if b.err != nil {
+		return b.err
+	}
+	for i, stage := range b.stages {
+		if i == 0 {
+			continue
+		}
+		for name := range stage {
+			if firstOnlyStages[name] {
+				return fmt.Errorf("%w: %s found at position %d", ErrStageNotFirst, name, i)
+			}
+		}
+	}
+	return nil
+}
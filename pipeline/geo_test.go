@@ -0,0 +1,74 @@
+package pipeline
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestGeoNear(t *testing.T) {
+	stages := GeoNear(GeoNearOptions{
+		Near:          Point(-73.98, 40.75),
+		DistanceField: "dist.calculated",
+		MaxDistance:   2000,
+		Query:         filter.Eq("type", "cafe"),
+		Spherical:     true,
+	}).Limit(10).Build()
+
+	if len(stages) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(stages))
+	}
+	expected := bson.M{"$geoNear": bson.M{
+		"near":          bson.M{"type": "Point", "coordinates": bson.A{-73.98, 40.75}},
+		"distanceField": "dist.calculated",
+		"maxDistance":   float64(2000),
+		"query":         bson.M{"type": "cafe"},
+		"spherical":     true,
+	}}
+	if !reflect.DeepEqual(stages[0], expected) {
+		t.Errorf("Expected %v, got %v", expected, stages[0])
+	}
+}
+
+func TestGeoNearDefaults(t *testing.T) {
+	stages := New().GeoNear(GeoNearOptions{Near: Point(1, 2), Query: filter.New()}).Build()
+
+	expected := bson.M{"$geoNear": bson.M{
+		"near":          bson.M{"type": "Point", "coordinates": bson.A{float64(1), float64(2)}},
+		"distanceField": "distance",
+	}}
+	if !reflect.DeepEqual(stages[0], expected) {
+		t.Errorf("Expected only near and default distance field, got %v", stages[0])
+	}
+}
+
+func TestValidateFirstStage(t *testing.T) {
+	near := GeoNearOptions{Near: Point(0, 0)}
+
+	tests := []struct {
+		name     string
+		pipeline *Builder
+		valid    bool
+	}{
+		{"empty", New(), true},
+		{"geoNear first", GeoNear(near).Limit(5), true},
+		{"no restricted stages", MatchRaw(bson.M{"a": 1}).Limit(5), true},
+		{"geoNear after match", MatchRaw(bson.M{"a": 1}).GeoNear(near), false},
+		{"collStats later", Limit(1).Raw(bson.M{"$collStats": bson.M{}}), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pipeline.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid pipeline, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrStageNotFirst) {
+				t.Errorf("Expected ErrStageNotFirst, got %v", err)
+			}
+		})
+	}
+}
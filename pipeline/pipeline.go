@@ -1,6 +1,9 @@
 package pipeline
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/cloudresty/go-mongodb/v2/filter"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -8,8 +11,15 @@ import (
 // Builder represents a fluent aggregation pipeline builder
 type Builder struct {
 	stages []bson.M
+
+	// err records a misuse found while building, reported by Validate
+	err error
 }
 
+// ErrNoUnwindStage is reported by Validate when PreserveEmpty or WithIndex does not directly
+// follow an $unwind stage
+var ErrNoUnwindStage = errors.New("unwind option must directly follow an $unwind stage")
+
 // New creates a new pipeline builder
 func New() *Builder {
 	return &Builder{
@@ -31,16 +41,10 @@ func (b *Builder) ToBSONArray() bson.A {
 	return result
 }
 
-// Match adds a $match stage to the pipeline
+// Match adds a $match stage to the pipeline. A nil or empty filter adds {$match: {}}, which
+// matches every document.
 func (b *Builder) Match(filterBuilder *filter.Builder) *Builder {
-	var matchDoc bson.M
-	if filterBuilder != nil {
-		matchDoc = filterBuilder.Build()
-	} else {
-		matchDoc = bson.M{}
-	}
-
-	b.stages = append(b.stages, bson.M{"$match": matchDoc})
+	b.stages = append(b.stages, filterBuilder.ToMatchStage())
 	return b
 }
 
@@ -93,6 +97,64 @@ func (b *Builder) Group(id any, fields bson.M) *Builder {
 	return b
 }
 
+// GroupByDateTrunc adds a $group stage bucketing documents by dateField truncated with
+// $dateTrunc (MongoDB 5.0+), the usual "metrics per hour/day" rollup. unit is a $dateTrunc
+// unit such as "minute", "hour", "day", "week" or "month", and binSize groups several units
+// per bucket (e.g. 15 with "minute"); a binSize below 1 uses 1. The bucket start is the _id
+// of each group, and accumulators are added as the other group fields.
+//
+// Example:
+//
+//	hourly := pipeline.GroupByDateTrunc("created_at", "hour", 1, bson.M{
+//		"orders":  bson.M{"$sum": 1},
+//		"revenue": bson.M{"$sum": "$total"},
+//	}).Sort(bson.D{{Key: "_id", Value: 1}})
+func (b *Builder) GroupByDateTrunc(dateField string, unit string, binSize int, accumulators bson.M) *Builder {
+	if !strings.HasPrefix(dateField, "$") {
+		dateField = "$" + dateField
+	}
+	if binSize < 1 {
+		binSize = 1
+	}
+
+	bucket := bson.M{
+		"$dateTrunc": bson.M{
+			"date":    dateField,
+			"unit":    unit,
+			"binSize": binSize,
+		},
+	}
+	return b.Group(bucket, accumulators)
+}
+
+// SortByCount adds a $sortByCount stage, which groups documents by expression (e.g.
+// "$category") and sorts the groups by their count in descending order. It is equivalent to a
+// $group with {"count": {"$sum": 1}} followed by a descending $sort on count; each output
+// document has the form {"_id": <value>, "count": <n>}.
+func (b *Builder) SortByCount(expression any) *Builder {
+	b.stages = append(b.stages, bson.M{"$sortByCount": expression})
+	return b
+}
+
+// SortByComputed adds an $addFields stage computing a sort key into field, followed by a $sort
+// stage on it in the given direction (1 ascending, -1 descending). Further sort keys (e.g. a date
+// to break ties) go in the same $sort stage, after the computed key. The computed field stays in
+// the output; remove it with Unset if it should not be returned.
+//
+// Example:
+//
+//	// Urgent tickets first, then the newest
+//	pipeline.SortByComputed("_priority",
+//	    expr.Cond(expr.Eq("$severity", "urgent"), 0, 1), 1,
+//	    bson.E{Key: "created_at", Value: -1})
+func (b *Builder) SortByComputed(field string, expression any, direction int, then ...bson.E) *Builder {
+	sortDoc := make(bson.D, 0, len(then)+1)
+	sortDoc = append(sortDoc, bson.E{Key: field, Value: direction})
+	sortDoc = append(sortDoc, then...)
+
+	return b.AddFields(bson.M{field: expression}).Sort(sortDoc)
+}
+
 // Lookup adds a $lookup stage to the pipeline
 func (b *Builder) Lookup(from, localField, foreignField, as string) *Builder {
 	b.stages = append(b.stages, bson.M{
@@ -125,6 +187,44 @@ func (b *Builder) UnwindWithOptions(path string, preserveNullAndEmptyArrays bool
 	return b
 }
 
+// PreserveEmpty makes the preceding $unwind stage also output documents whose array is
+// missing, null or empty (preserveNullAndEmptyArrays). It is the fluent equivalent of the
+// positional UnwindWithOptions:
+//
+//	pipeline.New().Unwind("$tags").PreserveEmpty().WithIndex("tagIndex")
+//
+// If the previous stage is not an $unwind, the pipeline is left unchanged and Validate
+// returns ErrNoUnwindStage.
+func (b *Builder) PreserveEmpty() *Builder {
+	return b.setUnwindOption("preserveNullAndEmptyArrays", true)
+}
+
+// WithIndex makes the preceding $unwind stage store each element's array index in the given
+// field (includeArrayIndex). Like PreserveEmpty, it must directly follow Unwind.
+func (b *Builder) WithIndex(field string) *Builder {
+	return b.setUnwindOption("includeArrayIndex", field)
+}
+
+// setUnwindOption sets an option on the last stage, converting the short $unwind form
+// {"$unwind": path} to the document form
+func (b *Builder) setUnwindOption(key string, value any) *Builder {
+	if len(b.stages) > 0 {
+		last := b.stages[len(b.stages)-1]
+		switch unwind := last["$unwind"].(type) {
+		case string:
+			b.stages[len(b.stages)-1] = bson.M{"$unwind": bson.M{"path": unwind, key: value}}
+			return b
+		case bson.M:
+			unwind[key] = value
+			return b
+		}
+	}
+	if b.err == nil {
+		b.err = ErrNoUnwindStage
+	}
+	return b
+}
+
 // AddFields adds an $addFields stage to the pipeline
 func (b *Builder) AddFields(fields bson.M) *Builder {
 	b.stages = append(b.stages, bson.M{"$addFields": fields})
@@ -137,6 +237,52 @@ func (b *Builder) ReplaceRoot(newRoot any) *Builder {
 	return b
 }
 
+// ReplaceWith adds a $replaceWith stage to the pipeline. It is the shorter form of
+// ReplaceRoot (MongoDB 4.2+), taking the replacement expression directly, e.g. "$address"
+// to promote a subdocument or a $mergeObjects expression to fill in defaults.
+func (b *Builder) ReplaceWith(expression any) *Builder {
+	b.stages = append(b.stages, bson.M{"$replaceWith": expression})
+	return b
+}
+
+// Set adds a $set stage to the pipeline. $set is an alias of $addFields and is the usual
+// stage in update pipelines, where expressions can reference existing fields, e.g.
+// Set(bson.M{"total": bson.M{"$multiply": bson.A{"$price", "$qty"}}}).
+func (b *Builder) Set(fields bson.M) *Builder {
+	b.stages = append(b.stages, bson.M{"$set": fields})
+	return b
+}
+
+// Unset adds an $unset stage removing the given fields from the documents
+func (b *Builder) Unset(fields ...string) *Builder {
+	b.stages = append(b.stages, bson.M{"$unset": fields})
+	return b
+}
+
+// SetNow adds a $set stage setting each field to the server time ($$NOW). Add it as the last
+// stage of an update pipeline to maintain a timestamp such as "updated_at"; as a separate
+// final stage it applies after any $replaceWith or $project instead of conflicting with them.
+func (b *Builder) SetNow(fields ...string) *Builder {
+	set := bson.M{}
+	for _, field := range fields {
+		set[field] = "$$NOW"
+	}
+	return b.Set(set)
+}
+
+// SetIfUnset adds a $set stage that sets field to value only when the document does not
+// have the field yet, leaving an existing value (including null) untouched. It is meant for
+// update pipelines (UpdateOnePipeline, UpdateManyPipeline, MongoDB 4.2+): unlike
+// $setOnInsert, it also fills the field in on existing documents. The value is wrapped in
+// $literal so strings starting with "$" are not read as field paths.
+func (b *Builder) SetIfUnset(field string, value any) *Builder {
+	return b.Set(bson.M{field: bson.M{"$cond": bson.M{
+		"if":   bson.M{"$eq": bson.A{bson.M{"$type": "$" + field}, "missing"}},
+		"then": bson.M{"$literal": value},
+		"else": "$" + field,
+	}}})
+}
+
 // Facet adds a $facet stage to the pipeline
 func (b *Builder) Facet(facets map[string][]bson.M) *Builder {
 	b.stages = append(b.stages, bson.M{"$facet": facets})
@@ -155,12 +301,27 @@ func (b *Builder) Sample(size int64) *Builder {
 	return b
 }
 
-// Raw adds a custom stage to the pipeline
+// Raw adds an arbitrary stage to the pipeline at its current position.
+// Use it for stages that do not have a typed helper yet (e.g. stages added in newer
+// MongoDB versions), so you are not blocked waiting for a wrapper.
 func (b *Builder) Raw(stage bson.M) *Builder {
 	b.stages = append(b.stages, stage)
 	return b
 }
 
+// ChangeStreamMatch adds a $match stage on the change event operationType, so that a change
+// stream only emits the given event types (e.g. "insert", "update", "delete").
+// With no operation types, the stage matches every event.
+func (b *Builder) ChangeStreamMatch(operationTypes ...string) *Builder {
+	matchDoc := bson.M{}
+	if len(operationTypes) > 0 {
+		matchDoc["operationType"] = bson.M{"$in": operationTypes}
+	}
+
+	b.stages = append(b.stages, bson.M{"$match": matchDoc})
+	return b
+}
+
 // Helper functions for common pipeline operations
 
 // Match creates a $match stage (standalone function)
@@ -202,3 +363,33 @@ func Skip(skip int64) *Builder {
 func Group(id any, fields bson.M) *Builder {
 	return New().Group(id, fields)
 }
+
+// Unwind creates a pipeline starting with an $unwind stage (standalone function)
+func Unwind(path string) *Builder {
+	return New().Unwind(path)
+}
+
+// SortByCount creates a pipeline starting with a $sortByCount stage (standalone function)
+func SortByCount(expression any) *Builder {
+	return New().SortByCount(expression)
+}
+
+// SortByComputed creates a pipeline starting with a computed sort key and a $sort on it (standalone function)
+func SortByComputed(field string, expression any, direction int, then ...bson.E) *Builder {
+	return New().SortByComputed(field, expression, direction, then...)
+}
+
+// GroupByDateTrunc creates a pipeline starting with a $group on a $dateTrunc bucket (standalone function)
+func GroupByDateTrunc(dateField string, unit string, binSize int, accumulators bson.M) *Builder {
+	return New().GroupByDateTrunc(dateField, unit, binSize, accumulators)
+}
+
+// ChangeStreamMatch creates a $match stage on the change event operationType (standalone function)
+func ChangeStreamMatch(operationTypes ...string) *Builder {
+	return New().ChangeStreamMatch(operationTypes...)
+}
+
+// Raw creates a pipeline starting with an arbitrary stage (standalone function)
+func Raw(stage bson.M) *Builder {
+	return New().Raw(stage)
+}
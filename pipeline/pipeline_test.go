@@ -1,6 +1,8 @@
 package pipeline
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/cloudresty/go-mongodb/v2/filter"
@@ -48,6 +50,20 @@ func TestMatch(t *testing.T) {
 	if len(stages2) != 1 {
 		t.Errorf("Expected 1 stage, got %d", len(stages2))
 	}
+
+	// Empty builders match everything instead of producing a nil $match document
+	for _, empty := range []*filter.Builder{filter.New(), {}} {
+		stages := New().Match(empty).Build()
+		if !reflect.DeepEqual(stages[0], bson.M{"$match": bson.M{}}) {
+			t.Errorf("Expected empty $match, got %v", stages[0])
+		}
+	}
+
+	// Match and ToMatchStage are inverses
+	f := filter.Eq("status", "active")
+	if stage := New().Match(f).Build()[0]; !reflect.DeepEqual(stage, f.ToMatchStage()) {
+		t.Errorf("Expected %v, got %v", f.ToMatchStage(), stage)
+	}
 }
 
 func TestMatchRaw(t *testing.T) {
@@ -328,3 +344,239 @@ func TestComplexPipeline(t *testing.T) {
 		}
 	}
 }
+
+func TestRaw(t *testing.T) {
+	densify := bson.M{"$densify": bson.M{"field": "day", "range": bson.M{"step": 1, "bounds": "full"}}}
+
+	pipeline := New().
+		Match(filter.Eq("status", "active")).
+		Raw(densify).
+		Sort(bson.D{{Key: "day", Value: 1}}).
+		Raw(bson.M{"$set": bson.M{"filled": true}}).
+		Limit(10)
+
+	stages := pipeline.Build()
+	expectedStages := []string{"$match", "$densify", "$sort", "$set", "$limit"}
+	if len(stages) != len(expectedStages) {
+		t.Fatalf("Expected %d stages, got %d", len(expectedStages), len(stages))
+	}
+	for i, expectedStage := range expectedStages {
+		if _, ok := stages[i][expectedStage]; !ok {
+			t.Errorf("Expected stage %d to be %s, got %v", i, expectedStage, stages[i])
+		}
+	}
+
+	// ToBSONArray preserves the same order
+	bsonArray := pipeline.ToBSONArray()
+	for i, expectedStage := range expectedStages {
+		stage, ok := bsonArray[i].(bson.M)
+		if !ok {
+			t.Fatalf("Element %d should be bson.M", i)
+		}
+		if _, ok := stage[expectedStage]; !ok {
+			t.Errorf("Expected array element %d to be %s", i, expectedStage)
+		}
+	}
+
+	// Standalone function
+	stages = Raw(densify).Limit(1).Build()
+	if len(stages) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(stages))
+	}
+	if _, ok := stages[0]["$densify"]; !ok {
+		t.Error("Expected first stage to be $densify")
+	}
+}
+
+func TestChangeStreamMatch(t *testing.T) {
+	stages := ChangeStreamMatch("insert", "update").Build()
+	if len(stages) != 1 {
+		t.Fatalf("Expected 1 stage, got %d", len(stages))
+	}
+
+	expected := bson.M{"$match": bson.M{"operationType": bson.M{"$in": []string{"insert", "update"}}}}
+	if !reflect.DeepEqual(stages[0], expected) {
+		t.Errorf("Expected %v, got %v", expected, stages[0])
+	}
+
+	// Without operation types every event matches
+	all := New().ChangeStreamMatch().Build()
+	if !reflect.DeepEqual(all[0], bson.M{"$match": bson.M{}}) {
+		t.Errorf("Expected empty $match, got %v", all[0])
+	}
+
+	// Further stages can follow the operation type filter
+	chained := ChangeStreamMatch("delete").Project(bson.M{"documentKey": 1}).Build()
+	if len(chained) != 2 {
+		t.Errorf("Expected 2 stages, got %d", len(chained))
+	}
+}
+
+func TestReplaceWith(t *testing.T) {
+	// Promote a nested subdocument to the root
+	stages := New().ReplaceWith("$address").Build()
+	if len(stages) != 1 {
+		t.Fatalf("Expected 1 stage, got %d", len(stages))
+	}
+	if !reflect.DeepEqual(stages[0], bson.M{"$replaceWith": "$address"}) {
+		t.Errorf("Expected $replaceWith field path, got %v", stages[0])
+	}
+
+	// Merge defaults into the promoted subdocument
+	merge := bson.M{"$mergeObjects": bson.A{bson.M{"country": "unknown"}, "$address"}}
+	stages = New().Match(filter.Exists("address", true)).ReplaceWith(merge).Build()
+	if len(stages) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(stages))
+	}
+	expected := bson.M{"$replaceWith": bson.M{"$mergeObjects": bson.A{bson.M{"country": "unknown"}, "$address"}}}
+	if !reflect.DeepEqual(stages[1], expected) {
+		t.Errorf("Expected %v, got %v", expected, stages[1])
+	}
+}
+
+func TestSetAndUnset(t *testing.T) {
+	total := bson.M{"$multiply": bson.A{"$price", "$qty"}}
+	stages := New().Set(bson.M{"total": total}).Unset("draft", "tmp").Build()
+	if len(stages) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(stages))
+	}
+	if !reflect.DeepEqual(stages[0], bson.M{"$set": bson.M{"total": total}}) {
+		t.Errorf("Expected $set stage, got %v", stages[0])
+	}
+	if !reflect.DeepEqual(stages[1], bson.M{"$unset": []string{"draft", "tmp"}}) {
+		t.Errorf("Expected $unset stage, got %v", stages[1])
+	}
+}
+
+func TestSetNow(t *testing.T) {
+	stages := New().ReplaceWith("$doc").SetNow("updated_at", "synced_at").Build()
+	if len(stages) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(stages))
+	}
+	expected := bson.M{"$set": bson.M{"updated_at": "$$NOW", "synced_at": "$$NOW"}}
+	if !reflect.DeepEqual(stages[1], expected) {
+		t.Errorf("Expected %v, got %v", expected, stages[1])
+	}
+}
+
+func TestGroupByDateTrunc(t *testing.T) {
+	accumulators := bson.M{
+		"orders":  bson.M{"$sum": 1},
+		"revenue": bson.M{"$sum": "$total"},
+	}
+	stages := GroupByDateTrunc("created_at", "minute", 15, accumulators).Build()
+	if len(stages) != 1 {
+		t.Fatalf("Expected 1 stage, got %d", len(stages))
+	}
+
+	expected := bson.M{"$group": bson.M{
+		"_id": bson.M{"$dateTrunc": bson.M{
+			"date":    "$created_at",
+			"unit":    "minute",
+			"binSize": 15,
+		}},
+		"orders":  bson.M{"$sum": 1},
+		"revenue": bson.M{"$sum": "$total"},
+	}}
+	if !reflect.DeepEqual(stages[0], expected) {
+		t.Errorf("Expected %v, got %v", expected, stages[0])
+	}
+
+	// Field paths are accepted as is and binSize defaults to 1
+	stages = New().MatchRaw(bson.M{}).GroupByDateTrunc("$ts", "day", 0, nil).Build()
+	key := stages[1]["$group"].(bson.M)["_id"]
+	expectedKey := bson.M{"$dateTrunc": bson.M{"date": "$ts", "unit": "day", "binSize": 1}}
+	if !reflect.DeepEqual(key, expectedKey) {
+		t.Errorf("Expected %v, got %v", expectedKey, key)
+	}
+	if len(stages[1]["$group"].(bson.M)) != 1 {
+		t.Errorf("Expected only the _id key without accumulators, got %v", stages[1])
+	}
+}
+
+func TestSortByCount(t *testing.T) {
+	stages := Match(filter.Eq("status", "active")).SortByCount("$category").Limit(5).Build()
+	if len(stages) != 3 {
+		t.Fatalf("Expected 3 stages, got %d", len(stages))
+	}
+	if !reflect.DeepEqual(stages[1], bson.M{"$sortByCount": "$category"}) {
+		t.Errorf("Expected $sortByCount stage, got %v", stages[1])
+	}
+
+	// Expressions other than field paths are passed through
+	expr := bson.M{"$year": "$created_at"}
+	stages = SortByCount(expr).Build()
+	if !reflect.DeepEqual(stages[0], bson.M{"$sortByCount": bson.M{"$year": "$created_at"}}) {
+		t.Errorf("Expected $sortByCount with expression, got %v", stages[0])
+	}
+}
+
+func TestSortByComputed(t *testing.T) {
+	priority := bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$severity", "urgent"}}, 0, 1}}
+
+	stages := Match(filter.Eq("status", "open")).
+		SortByComputed("_priority", priority, 1, bson.E{Key: "created_at", Value: -1}).
+		Build()
+	if len(stages) != 3 {
+		t.Fatalf("Expected 3 stages, got %d", len(stages))
+	}
+	if !reflect.DeepEqual(stages[1], bson.M{"$addFields": bson.M{"_priority": priority}}) {
+		t.Errorf("Expected $addFields computing the sort key, got %v", stages[1])
+	}
+	expectedSort := bson.M{"$sort": bson.D{{Key: "_priority", Value: 1}, {Key: "created_at", Value: -1}}}
+	if !reflect.DeepEqual(stages[2], expectedSort) {
+		t.Errorf("Expected %v, got %v", expectedSort, stages[2])
+	}
+
+	// Standalone form without tie-breakers
+	stages = SortByComputed("score", bson.M{"$add": bson.A{"$likes", "$shares"}}, -1).Build()
+	if len(stages) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(stages))
+	}
+	if !reflect.DeepEqual(stages[1], bson.M{"$sort": bson.D{{Key: "score", Value: -1}}}) {
+		t.Errorf("Expected a $sort on the computed key, got %v", stages[1])
+	}
+}
+
+func TestUnwindFluentOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		fluent     *Builder
+		positional *Builder
+	}{
+		{"both options", New().Unwind("$tags").PreserveEmpty().WithIndex("idx"), New().UnwindWithOptions("$tags", true, "idx")},
+		{"options in either order", Unwind("$tags").WithIndex("idx").PreserveEmpty(), New().UnwindWithOptions("$tags", true, "idx")},
+		{"preserve only", New().Unwind("$items").PreserveEmpty(), New().UnwindWithOptions("$items", true, "")},
+		{"index only", New().Unwind("$items").WithIndex("position"), New().UnwindWithOptions("$items", false, "position")},
+		{"no options", Unwind("$items"), New().Unwind("$items")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.fluent.Build(), tt.positional.Build()) {
+				t.Errorf("Expected %v, got %v", tt.positional.Build(), tt.fluent.Build())
+			}
+			if err := tt.fluent.Validate(); err != nil {
+				t.Errorf("Expected valid pipeline, got %v", err)
+			}
+		})
+	}
+
+	// Later stages are unaffected
+	stages := New().Unwind("$tags").PreserveEmpty().Group("$tags", bson.M{"n": bson.M{"$sum": 1}}).Build()
+	if len(stages) != 2 || stages[1]["$group"] == nil {
+		t.Errorf("Expected $unwind followed by $group, got %v", stages)
+	}
+}
+
+func TestUnwindOptionWithoutUnwind(t *testing.T) {
+	for _, b := range []*Builder{
+		New().PreserveEmpty(),
+		New().Match(filter.Eq("status", "active")).WithIndex("idx"),
+		New().Unwind("$tags").Limit(5).PreserveEmpty(),
+	} {
+		if err := b.Validate(); !errors.Is(err, ErrNoUnwindStage) {
+			t.Errorf("Expected ErrNoUnwindStage for %v, got %v", b.Build(), err)
+		}
+	}
+}
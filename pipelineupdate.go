@@ -0,0 +1,232 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrInvalidUpdatePipeline is returned when an update pipeline is empty or contains a stage
+// that MongoDB does not allow in updates
+var ErrInvalidUpdatePipeline = errors.New("invalid update pipeline")
+
+// updatePipelineStages are the stages MongoDB accepts in an aggregation pipeline update
+var updatePipelineStages = map[string]bool{
+	"$addFields":   true,
+	"$set":         true,
+	"$project":     true,
+	"$unset":       true,
+	"$replaceRoot": true,
+	"$replaceWith": true,
+}
+
+// updatePipeline builds the pipeline for an update and checks that every stage is allowed
+func updatePipeline(pipelineBuilder *pipeline.Builder) (bson.A, error) {
+	if pipelineBuilder == nil {
+		return nil, fmt.Errorf("%w: pipeline is empty", ErrInvalidUpdatePipeline)
+	}
+
+	stages := pipelineBuilder.Build()
+	if len(stages) == 0 {
+		return nil, fmt.Errorf("%w: pipeline is empty", ErrInvalidUpdatePipeline)
+	}
+
+	pipelineDoc := make(bson.A, 0, len(stages))
+	for i, stage := range stages {
+		for name := range stage {
+			if !updatePipelineStages[name] {
+				return nil, fmt.Errorf("%w: stage %d (%s) is not allowed in updates", ErrInvalidUpdatePipeline, i, name)
+			}
+		}
+		pipelineDoc = append(pipelineDoc, stage)
+	}
+	return pipelineDoc, nil
+}
+
+// withUpdatedAtStage appends a final $set stage setting updated_at to the server time. As a
+// separate last stage it applies after any $replaceWith or $project stage instead of
+// conflicting with it or being dropped by it.
+func withUpdatedAtStage(pipelineDoc bson.A) bson.A {
+	return append(pipelineDoc, bson.M{"$set": bson.M{"updated_at": "$$NOW"}})
+}
+
+// UpdateOnePipeline updates a single document with an aggregation pipeline (MongoDB 4.2+).
+// Unlike update operators, pipeline stages can compute new values from the document's
+// existing fields. Only $set/$addFields, $unset, $project and $replaceRoot/$replaceWith
+// stages are allowed; other stages return ErrInvalidUpdatePipeline without contacting the
+// server. Timestamps are not added; use ReplaceOnePipeline to rebuild a document from its own
+// fields with updated_at maintained.
+//
+// Example:
+//
+//	total := pipeline.New().Set(bson.M{"total": bson.M{"$multiply": bson.A{"$price", "$qty"}}})
+//	result, err := col.UpdateOnePipeline(ctx, filter.Eq("_id", id), total)
+func (col *Collection) UpdateOnePipeline(ctx context.Context, filterBuilder *filter.Builder, pipelineBuilder *pipeline.Builder, opts ...options.Lister[options.UpdateOneOptions]) (*UpdateResult, error) {
+	if err := col.checkWritable("UpdateOnePipeline"); err != nil {
+		return nil, err
+	}
+
+	pipelineDoc, err := updatePipeline(pipelineBuilder)
+	if err != nil {
+		return nil, err
+	}
+	return col.updateOnePipeline(ctx, "UpdateOnePipeline", filterBuilder, pipelineDoc, opts...)
+}
+
+// ReplaceOnePipeline replaces a single document with the result of an aggregation expression
+// (MongoDB 4.2+), the expression-based counterpart of ReplaceOne. The replacement can
+// reference the current document's fields, e.g. to promote a subdocument or fill in defaults
+// with $mergeObjects. It runs as an update pipeline of a $replaceWith stage followed by a
+// final $set of updated_at to the server time, so the timestamp survives the replacement.
+//
+// Example:
+//
+//	withDefaults := bson.M{"$mergeObjects": bson.A{bson.M{"status": "active"}, "$$ROOT"}}
+//	result, err := col.ReplaceOnePipeline(ctx, filter.Eq("_id", id), withDefaults)
+func (col *Collection) ReplaceOnePipeline(ctx context.Context, filterBuilder *filter.Builder, replacement any, opts ...options.Lister[options.UpdateOneOptions]) (*UpdateResult, error) {
+	if err := col.checkWritable("ReplaceOnePipeline"); err != nil {
+		return nil, err
+	}
+
+	if replacement == nil {
+		return nil, fmt.Errorf("%w: replacement is empty", ErrInvalidUpdatePipeline)
+	}
+	pipelineDoc := withUpdatedAtStage(bson.A{bson.M{"$replaceWith": replacement}})
+	return col.updateOnePipeline(ctx, "ReplaceOnePipeline", filterBuilder, pipelineDoc, opts...)
+}
+
+// updateOnePipeline runs an already validated update pipeline against a single document
+func (col *Collection) updateOnePipeline(ctx context.Context, op string, filterBuilder *filter.Builder, pipelineDoc bson.A, opts ...options.Lister[options.UpdateOneOptions]) (*UpdateResult, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
+	// Build filter document
+	filterDoc := bson.M{}
+	if filterBuilder != nil {
+		filterDoc = filterBuilder.Build()
+	}
+	if err := col.checkShardKey(ctx, filterDoc, op); err != nil {
+		return nil, err
+	}
+
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	result, err := col.collection.UpdateOne(ctx, filterDoc, pipelineDoc, opts...)
+	if err != nil {
+		col.client.incrementFailureCount()
+		col.errorLogger(ctx, "filter", filterDoc, "update", pipelineDoc).Error("Failed to update document with pipeline",
+			"error", err.Error(),
+			"collection", col.name)
+		return nil, err
+	}
+
+	col.client.incrementOperationCount()
+
+	updateResult := &UpdateResult{
+		MatchedCount:  result.MatchedCount,
+		ModifiedCount: result.ModifiedCount,
+		UpsertedCount: result.UpsertedCount,
+		UpsertedID:    result.UpsertedID,
+		Duration:      time.Since(start),
+	}
+
+	col.logger(ctx).Debug("Document updated with pipeline successfully",
+		"collection", col.name,
+		"stages", len(pipelineDoc),
+		"matched", int(updateResult.MatchedCount),
+		"modified", int(updateResult.ModifiedCount))
+
+	return updateResult, nil
+}
+
+// UpdateManyPipeline updates all matching documents with an aggregation pipeline (MongoDB
+// 4.2+), e.g. to normalize a field across a collection in a single command. The same stage
+// restrictions as UpdateOnePipeline apply. A final $set stage setting updated_at to the
+// server time is appended to the pipeline, so it applies after any $replaceWith or $project
+// stage instead of conflicting with it.
+//
+// Example:
+//
+//	normalize := pipeline.New().
+//		Set(bson.M{"email": bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$email"}}}})
+//	result, err := col.UpdateManyPipeline(ctx, filter.Exists("email", true), normalize)
+func (col *Collection) UpdateManyPipeline(ctx context.Context, filterBuilder *filter.Builder, pipelineBuilder *pipeline.Builder, opts ...options.Lister[options.UpdateManyOptions]) (*UpdateResult, error) {
+	if err := col.checkWritable("UpdateManyPipeline"); err != nil {
+		return nil, err
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	ctx, cancel := col.applyMaxTime(ctx)
+	defer cancel()
+
+	// Build filter document
+	filterDoc := bson.M{}
+	if filterBuilder != nil {
+		filterDoc = filterBuilder.Build()
+	}
+	if err := col.checkShardKey(ctx, filterDoc, "UpdateManyPipeline"); err != nil {
+		return nil, err
+	}
+
+	pipelineDoc, err := updatePipeline(pipelineBuilder)
+	if err != nil {
+		return nil, err
+	}
+	pipelineDoc = withUpdatedAtStage(pipelineDoc)
+
+	if err := col.client.waitForWrites(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	result, err := col.collection.UpdateMany(ctx, filterDoc, pipelineDoc, opts...)
+	if err != nil {
+		col.client.incrementFailureCount()
+		col.errorLogger(ctx, "filter", filterDoc, "update", pipelineDoc).Error("Failed to update documents with pipeline",
+			"error", err.Error(),
+			"collection", col.name)
+		return nil, err
+	}
+
+	col.client.incrementOperationCount()
+
+	updateResult := &UpdateResult{
+		MatchedCount:  result.MatchedCount,
+		ModifiedCount: result.ModifiedCount,
+		UpsertedCount: result.UpsertedCount,
+		UpsertedID:    result.UpsertedID,
+		Duration:      time.Since(start),
+	}
+
+	col.logger(ctx).Debug("Documents updated with pipeline successfully",
+		"collection", col.name,
+		"stages", len(pipelineDoc),
+		"matched", int(updateResult.MatchedCount),
+		"modified", int(updateResult.ModifiedCount))
+
+	return updateResult, nil
+}
@@ -0,0 +1,269 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+func TestUpdatePipelineValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		pipeline *pipeline.Builder
+		valid    bool
+	}{
+		{"nil pipeline", nil, false},
+		{"empty pipeline", pipeline.New(), false},
+		{"set stage", pipeline.New().Set(bson.M{"total": 1}), true},
+		{"all update stages", pipeline.New().
+			AddFields(bson.M{"a": 1}).
+			Unset("b").
+			Project(bson.M{"c": 1}).
+			ReplaceRoot("$c").
+			ReplaceWith("$$ROOT"), true},
+		{"match stage", pipeline.New().Set(bson.M{"a": 1}).MatchRaw(bson.M{"a": 1}), false},
+		{"group stage", pipeline.New().Group("$a", nil), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipelineDoc, err := updatePipeline(tt.pipeline)
+			if tt.valid {
+				if err != nil {
+					t.Fatalf("Expected valid pipeline, got %v", err)
+				}
+				if len(pipelineDoc) != len(tt.pipeline.Build()) {
+					t.Errorf("Expected %d stages, got %d", len(tt.pipeline.Build()), len(pipelineDoc))
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidUpdatePipeline) {
+				t.Errorf("Expected ErrInvalidUpdatePipeline, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUpdateOnePipelineComputesFromFields(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_update_pipeline")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	_, err := col.InsertMany(ctx, []any{
+		bson.M{"sku": "a", "price": 2.5, "qty": int32(4)},
+		bson.M{"sku": "b", "price": 10.0, "qty": int32(3)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to seed collection: %v", err)
+	}
+
+	total := pipeline.New().Set(bson.M{"total": bson.M{"$multiply": bson.A{"$price", "$qty"}}})
+	result, err := col.UpdateOnePipeline(ctx, filter.Eq("sku", "a"), total)
+	if err != nil {
+		t.Fatalf("UpdateOnePipeline failed: %v", err)
+	}
+	if result.MatchedCount != 1 || result.ModifiedCount != 1 {
+		t.Errorf("Expected one document modified, got %+v", result)
+	}
+
+	var doc struct {
+		Total float64 `bson:"total"`
+	}
+	if err := col.FindOne(ctx, filter.Eq("sku", "a")).Decode(&doc); err != nil {
+		t.Fatalf("Failed to read updated document: %v", err)
+	}
+	if doc.Total != 10 {
+		t.Errorf("Expected total 10, got %v", doc.Total)
+	}
+
+	// Only the matched document is updated
+	count, err := col.CountDocuments(ctx, filter.Exists("total", true))
+	if err != nil {
+		t.Fatalf("CountDocuments failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected one document with a total, got %d", count)
+	}
+}
+
+func TestUpdatePipelineKeepsTimestampStageLast(t *testing.T) {
+	p := pipeline.New().
+		Set(bson.M{"email": bson.M{"$toLower": "$email"}}).
+		ReplaceWith(bson.M{"$mergeObjects": bson.A{"$$ROOT", bson.M{"normalized": true}}})
+
+	pipelineDoc, err := updatePipeline(p)
+	if err != nil {
+		t.Fatalf("updatePipeline failed: %v", err)
+	}
+	pipelineDoc = withUpdatedAtStage(pipelineDoc)
+	if len(pipelineDoc) != 3 {
+		t.Fatalf("Expected 3 stages, got %d", len(pipelineDoc))
+	}
+	if _, ok := pipelineDoc[1].(bson.M)["$replaceWith"]; !ok {
+		t.Errorf("Expected user stages to keep their order, got %v", pipelineDoc[1])
+	}
+	last, ok := pipelineDoc[2].(bson.M)
+	if !ok {
+		t.Fatalf("Expected bson.M stage, got %T", pipelineDoc[2])
+	}
+	set, _ := last["$set"].(bson.M)
+	if set["updated_at"] != "$$NOW" {
+		t.Errorf("Expected final $set of updated_at, got %v", last)
+	}
+}
+
+func TestReplaceOnePipelineRejectsNilReplacement(t *testing.T) {
+	col := newTestCollection("test_replace_pipeline")
+
+	_, err := col.ReplaceOnePipeline(context.Background(), filter.Eq("_id", "a"), nil)
+	if !errors.Is(err, ErrInvalidUpdatePipeline) {
+		t.Errorf("Expected ErrInvalidUpdatePipeline, got %v", err)
+	}
+}
+
+func TestUpdateManyPipelineIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// Record the update statements sent to the server
+	var mu sync.Mutex
+	var sent []bson.Raw
+	client, err := NewClient(FromEnv(), WithMonitor(&event.CommandMonitor{
+		Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+			if evt.CommandName != "update" {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			sent = append(sent, evt.Command)
+		},
+	}))
+	if err != nil {
+		t.Skipf("Could not connect to MongoDB: %v", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_update_many_pipeline")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	_, err = col.InsertMany(ctx, []any{
+		bson.M{"email": "  Alice@Example.COM "},
+		bson.M{"email": "BOB@example.com"},
+		bson.M{"name": "no email"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to seed collection: %v", err)
+	}
+
+	normalize := pipeline.New().
+		Set(bson.M{"email": bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$email"}}}})
+	result, err := col.UpdateManyPipeline(ctx, filter.Exists("email", true), normalize)
+	if err != nil {
+		t.Fatalf("UpdateManyPipeline failed: %v", err)
+	}
+	if result.MatchedCount != 2 || result.ModifiedCount != 2 {
+		t.Errorf("Expected two documents modified, got %+v", result)
+	}
+
+	count, err := col.CountDocuments(ctx, filter.In("email", "alice@example.com", "bob@example.com").
+		And(filter.Exists("updated_at", true)))
+	if err != nil {
+		t.Fatalf("CountDocuments failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected two normalized documents with updated_at, got %d", count)
+	}
+
+	// The pipeline reaches the server as an array with the timestamp stage last
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent) != 1 {
+		t.Fatalf("Expected one update command, got %d", len(sent))
+	}
+	statement := sent[0].Lookup("updates", "0").Document()
+	stages, ok := statement.Lookup("u").ArrayOK()
+	if !ok {
+		t.Fatalf("Expected update sent as a pipeline array, got %v", statement.Lookup("u"))
+	}
+	values, err := stages.Values()
+	if err != nil {
+		t.Fatalf("Failed to read pipeline stages: %v", err)
+	}
+	if len(values) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(values))
+	}
+	if now := values[1].Document().Lookup("$set", "updated_at").StringValue(); now != "$$NOW" {
+		t.Errorf("Expected final stage to set updated_at to $$NOW, got %q", now)
+	}
+}
+
+func TestReplaceOnePipelineIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_replace_one_pipeline")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	_, err := col.InsertOne(ctx, bson.M{"sku": "a", "details": bson.M{"sku": "a", "color": "red"}})
+	if err != nil {
+		t.Fatalf("Failed to seed collection: %v", err)
+	}
+
+	// Promote the subdocument, keeping the _id
+	promote := bson.M{"$mergeObjects": bson.A{bson.M{"_id": "$_id"}, "$details"}}
+	result, err := col.ReplaceOnePipeline(ctx, filter.Eq("sku", "a"), promote)
+	if err != nil {
+		t.Fatalf("ReplaceOnePipeline failed: %v", err)
+	}
+	if result.ModifiedCount != 1 {
+		t.Errorf("Expected one document modified, got %+v", result)
+	}
+
+	var doc bson.M
+	if err := col.FindOne(ctx, filter.Eq("sku", "a")).Decode(&doc); err != nil {
+		t.Fatalf("Failed to read replaced document: %v", err)
+	}
+	if doc["color"] != "red" {
+		t.Errorf("Expected promoted color field, got %v", doc)
+	}
+	if _, ok := doc["details"]; ok {
+		t.Errorf("Expected details to be replaced, got %v", doc)
+	}
+	if _, ok := doc["updated_at"]; !ok {
+		t.Errorf("Expected updated_at to survive the replacement, got %v", doc)
+	}
+}
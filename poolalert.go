@@ -0,0 +1,70 @@
+package mongodb
+
+import "time"
+
+// PoolSaturation describes a connection pool that has stayed near its limit
+type PoolSaturation struct {
+	// ActiveConnections is the number of checked-out connections when the alert fired
+	ActiveConnections int `json:"active_connections"`
+	// MaxPoolSize is the configured pool limit
+	MaxPoolSize uint64 `json:"max_pool_size"`
+	// Utilization is ActiveConnections / MaxPoolSize
+	Utilization float64 `json:"utilization"`
+	// Since is when the pool was first seen above the threshold
+	Since time.Time `json:"since"`
+}
+
+// checkPoolSaturation samples the pool-monitor counters and raises an alert once the share of
+// checked-out connections has stayed at or above PoolSaturationThreshold for at least
+// PoolSaturationDuration. The alert fires once per saturation episode; it is re-armed when
+// utilization drops below the threshold. It is called from the periodic health check.
+func (c *Client) checkPoolSaturation(now time.Time) {
+	threshold := c.config.PoolSaturationThreshold
+	maxPoolSize := c.config.MaxPoolSize
+	if threshold <= 0 || maxPoolSize == 0 {
+		return
+	}
+
+	c.poolStats.Lock()
+	active := c.poolStats.activeConnections
+	utilization := float64(active) / float64(maxPoolSize)
+
+	if utilization < threshold {
+		c.poolStats.saturatedSince = time.Time{}
+		c.poolStats.saturationAlerted = false
+		c.poolStats.Unlock()
+		return
+	}
+
+	if c.poolStats.saturatedSince.IsZero() {
+		c.poolStats.saturatedSince = now
+	}
+	since := c.poolStats.saturatedSince
+	fire := !c.poolStats.saturationAlerted && now.Sub(since) >= c.config.PoolSaturationDuration
+	if fire {
+		c.poolStats.saturationAlerted = true
+	}
+	c.poolStats.Unlock()
+
+	if !fire {
+		return
+	}
+
+	saturation := PoolSaturation{
+		ActiveConnections: active,
+		MaxPoolSize:       maxPoolSize,
+		Utilization:       utilization,
+		Since:             since,
+	}
+
+	if c.config.OnPoolSaturation != nil {
+		c.config.OnPoolSaturation(saturation)
+		return
+	}
+
+	c.config.Logger.Warn("Connection pool is near saturation",
+		"active_connections", active,
+		"max_pool_size", maxPoolSize,
+		"utilization", utilization,
+		"since", since)
+}
@@ -0,0 +1,95 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+// checkOut simulates the pool monitor seeing n new connections checked out
+func checkOut(c *Client, first, n int64) {
+	for id := first; id < first+n; id++ {
+		c.handlePoolEvent(&event.PoolEvent{Type: event.ConnectionCreated, ConnectionID: id})
+		c.handlePoolEvent(&event.PoolEvent{Type: event.ConnectionCheckedOut, ConnectionID: id})
+	}
+}
+
+// checkIn simulates the pool monitor seeing n connections returned to the pool
+func checkIn(c *Client, first, n int64) {
+	for id := first; id < first+n; id++ {
+		c.handlePoolEvent(&event.PoolEvent{Type: event.ConnectionCheckedIn, ConnectionID: id})
+	}
+}
+
+func TestPoolSaturationAlert(t *testing.T) {
+	var alerts []PoolSaturation
+	c := newTestClient(WithMaxPoolSize(10), WithPoolSaturationAlert(0.8, time.Minute, func(s PoolSaturation) {
+		alerts = append(alerts, s)
+	}))
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Below the threshold nothing happens
+	checkOut(c, 1, 5)
+	c.checkPoolSaturation(start)
+	if len(alerts) != 0 {
+		t.Fatalf("Expected no alert at 50%% utilization, got %v", alerts)
+	}
+
+	// Crossing the threshold starts the clock but does not alert yet
+	checkOut(c, 6, 4)
+	c.checkPoolSaturation(start)
+	c.checkPoolSaturation(start.Add(30 * time.Second))
+	if len(alerts) != 0 {
+		t.Fatalf("Expected no alert before the sustained duration, got %v", alerts)
+	}
+
+	// Sustained saturation alerts once
+	c.checkPoolSaturation(start.Add(time.Minute))
+	c.checkPoolSaturation(start.Add(2 * time.Minute))
+	if len(alerts) != 1 {
+		t.Fatalf("Expected exactly one alert, got %d", len(alerts))
+	}
+	alert := alerts[0]
+	if alert.ActiveConnections != 9 || alert.MaxPoolSize != 10 || alert.Utilization != 0.9 {
+		t.Errorf("Unexpected alert: %+v", alert)
+	}
+	if !alert.Since.Equal(start) {
+		t.Errorf("Expected saturation since %v, got %v", start, alert.Since)
+	}
+
+	// Dropping below the threshold re-arms the alert
+	checkIn(c, 1, 5)
+	c.checkPoolSaturation(start.Add(3 * time.Minute))
+	checkOut(c, 11, 5)
+	c.checkPoolSaturation(start.Add(4 * time.Minute))
+	c.checkPoolSaturation(start.Add(5 * time.Minute))
+	if len(alerts) != 2 {
+		t.Fatalf("Expected a second alert after re-arming, got %d", len(alerts))
+	}
+	if !alerts[1].Since.Equal(start.Add(4 * time.Minute)) {
+		t.Errorf("Expected new episode to start at 4m, got %v", alerts[1].Since)
+	}
+}
+
+func TestPoolSaturationAlertLogsWithoutHandler(t *testing.T) {
+	logger := &warnRecorder{}
+	c := newTestClient(WithLogger(logger), WithMaxPoolSize(10), WithPoolSaturationAlert(0.8, 0, nil))
+
+	checkOut(c, 1, 10)
+	c.checkPoolSaturation(time.Now())
+	if len(logger.warnings) != 1 {
+		t.Errorf("Expected one saturation warning, got %v", logger.warnings)
+	}
+}
+
+func TestPoolSaturationAlertDisabled(t *testing.T) {
+	logger := &warnRecorder{}
+	c := newTestClient(WithLogger(logger), WithMaxPoolSize(10))
+
+	checkOut(c, 1, 10)
+	c.checkPoolSaturation(time.Now())
+	if len(logger.warnings) != 0 {
+		t.Errorf("Expected no warning when disabled, got %v", logger.warnings)
+	}
+}
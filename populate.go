@@ -0,0 +1,193 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Populate resolves references from docs to another collection of the same database without
+// an aggregation: it collects the localField values of all docs, loads the matching documents
+// of the from collection with a single $in query on foreignField, and stores them in each
+// document under as. This is the application-side equivalent of $lookup and avoids issuing one
+// query per document (N+1) when the documents are already loaded.
+//
+// Like $lookup, as is always set to an array: the matching documents, or an empty array when
+// the reference is missing or matches nothing. localField may hold a single value or an array
+// of values, and both fields may be dotted paths into embedded documents. Values match when
+// they are equal BSON values, with integers of different sizes treated as equal.
+//
+// Example:
+//
+//	var recent []bson.M
+//	if err := result.All(ctx, &recent); err != nil {
+//	    return err
+//	}
+//	// Each post gets an "author" array holding its user document
+//	err := posts.Populate(ctx, recent, "author_id", "users", "_id", "author")
+func (col *Collection) Populate(ctx context.Context, docs []bson.M, localField, from, foreignField, as string) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	ids := populateIDs(docs, localField)
+
+	if err := col.checkConnected(); err != nil {
+		return err
+	}
+
+	var related []bson.M
+	if len(ids) > 0 {
+		foreign := &Collection{
+			collection: col.collection.Database().Collection(from),
+			client:     col.client,
+			name:       from,
+		}
+
+		result, err := foreign.Find(ctx, filter.In(foreignField, ids...))
+		if err != nil {
+			return fmt.Errorf("failed to populate %s from %s: %w", as, from, err)
+		}
+		if err := result.All(ctx, &related); err != nil {
+			return fmt.Errorf("failed to populate %s from %s: %w", as, from, err)
+		}
+	}
+
+	attachPopulated(docs, localField, foreignField, as, related)
+
+	col.logger(ctx).Debug("Documents populated",
+		"collection", col.name,
+		"from", from,
+		"documents", len(docs),
+		"related", len(related))
+
+	return nil
+}
+
+// populateIDs returns the distinct values of localField across docs, flattening arrays
+func populateIDs(docs []bson.M, localField string) []any {
+	seen := map[string]bool{}
+	var ids []any
+	for _, doc := range docs {
+		value, ok := lookupPath(doc, localField)
+		if !ok {
+			continue
+		}
+		for _, id := range flattenValues(value) {
+			key, ok := populateKey(id)
+			if !ok || seen[key] {
+				continue
+			}
+			seen[key] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// attachPopulated stores in each document under as the related documents whose foreignField
+// matches its localField value(s)
+func attachPopulated(docs []bson.M, localField, foreignField, as string, related []bson.M) {
+	byKey := map[string][]bson.M{}
+	for _, doc := range related {
+		value, ok := lookupPath(doc, foreignField)
+		if !ok {
+			continue
+		}
+		added := map[string]bool{}
+		for _, id := range flattenValues(value) {
+			key, ok := populateKey(id)
+			if !ok || added[key] {
+				continue
+			}
+			added[key] = true
+			byKey[key] = append(byKey[key], doc)
+		}
+	}
+
+	for _, doc := range docs {
+		matches := bson.A{}
+		if value, ok := lookupPath(doc, localField); ok {
+			for _, id := range flattenValues(value) {
+				key, ok := populateKey(id)
+				if !ok {
+					continue
+				}
+				for _, match := range byKey[key] {
+					matches = append(matches, match)
+				}
+			}
+		}
+		doc[as] = matches
+	}
+}
+
+// lookupPath returns the value at a dotted path in a document, descending into embedded
+// documents decoded as bson.M or bson.D
+func lookupPath(doc bson.M, path string) (any, bool) {
+	var current any = doc
+	for part := range strings.SplitSeq(path, ".") {
+		switch d := current.(type) {
+		case bson.M:
+			value, ok := d[part]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case bson.D:
+			found := false
+			for _, e := range d {
+				if e.Key == part {
+					current, found = e.Value, true
+					break
+				}
+			}
+			if !found {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// flattenValues returns the elements of an array value, or the value itself
+func flattenValues(value any) []any {
+	switch value.(type) {
+	case nil:
+		return nil
+	case bson.D:
+		return []any{value}
+	}
+	v := reflect.ValueOf(value)
+	if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Type().Elem().Kind() == reflect.Uint8 {
+		return []any{value}
+	}
+	values := make([]any, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values
+}
+
+// populateKey returns a comparable key for a reference value, so that values equal in BSON
+// match whatever Go type they were decoded or built as
+func populateKey(value any) (string, bool) {
+	t, data, err := bson.MarshalValue(value)
+	if err != nil {
+		return "", false
+	}
+	return rawValueKey(bson.RawValue{Type: t, Value: data}), true
+}
@@ -0,0 +1,124 @@
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestAttachPopulated(t *testing.T) {
+	ada := bson.M{"_id": int32(1), "name": "ada"}
+	bob := bson.M{"_id": int32(2), "name": "bob"}
+	cyd := bson.M{"_id": int32(3), "name": "cyd"}
+
+	posts := []bson.M{
+		{"title": "first", "author_id": 1},
+		{"title": "second", "author_id": int64(2)},
+		{"title": "pair", "author_id": bson.A{1, 3}},
+		{"title": "deleted author", "author_id": 99},
+		{"title": "anonymous"},
+		{"title": "null author", "author_id": nil},
+	}
+
+	ids := populateIDs(posts, "author_id")
+	if len(ids) != 4 {
+		t.Errorf("Expected 4 distinct ids (1, 2, 3, 99), got %v", ids)
+	}
+
+	attachPopulated(posts, "author_id", "_id", "author", []bson.M{ada, bob, cyd})
+
+	expected := []bson.A{
+		{ada},
+		{bob},
+		{ada, cyd},
+		{},
+		{},
+		{},
+	}
+	for i, post := range posts {
+		if !reflect.DeepEqual(post["author"], expected[i]) {
+			t.Errorf("%s: expected %v, got %v", post["title"], expected[i], post["author"])
+		}
+	}
+}
+
+func TestAttachPopulatedNestedFields(t *testing.T) {
+	orders := []bson.M{
+		{"_id": "o1", "customer": bson.M{"ref": "c1"}},
+		{"_id": "o2", "customer": bson.D{{Key: "ref", Value: "c2"}}},
+	}
+	customers := []bson.M{
+		{"_id": "x", "codes": bson.A{"c1", "c2"}},
+		{"_id": "y", "codes": bson.A{"c2"}},
+	}
+
+	attachPopulated(orders, "customer.ref", "codes", "customers", customers)
+
+	if got := orders[0]["customers"].(bson.A); len(got) != 1 || got[0].(bson.M)["_id"] != "x" {
+		t.Errorf("Expected o1 to match customer x, got %v", got)
+	}
+	if got := orders[1]["customers"].(bson.A); len(got) != 2 {
+		t.Errorf("Expected o2 to match customers x and y, got %v", got)
+	}
+}
+
+func TestPopulateKey(t *testing.T) {
+	id := bson.NewObjectID()
+	a, _ := populateKey(id)
+	b, _ := populateKey(id)
+	if a != b {
+		t.Error("Expected equal ObjectIDs to have the same key")
+	}
+
+	one, _ := populateKey(1)
+	oneInt32, _ := populateKey(int32(1))
+	oneInt64, _ := populateKey(int64(1))
+	oneDouble, _ := populateKey(1.0)
+	oneString, _ := populateKey("1")
+	if one != oneInt32 || one != oneInt64 || one != oneDouble {
+		t.Error("Expected integral numbers of any type to match")
+	}
+	if one == oneString {
+		t.Error("Expected a string not to match an integer")
+	}
+}
+
+func TestPopulateIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		if err := client.Close(); err != nil {
+			t.Logf("Failed to close client: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	posts := client.Collection("test_populate_posts")
+	users := client.Collection("test_populate_users")
+	_ = users.Drop(ctx)
+	defer func() { _ = users.Drop(ctx) }()
+
+	if _, err := users.InsertMany(ctx, []any{
+		bson.M{"_id": "u1", "name": "ada"},
+		bson.M{"_id": "u2", "name": "bob"},
+	}); err != nil {
+		t.Fatalf("Failed to seed users: %v", err)
+	}
+
+	docs := []bson.M{
+		{"title": "a", "author_id": "u1"},
+		{"title": "b", "author_id": "u2"},
+		{"title": "c", "author_id": "missing"},
+	}
+	if err := posts.Populate(ctx, docs, "author_id", "test_populate_users", "_id", "author"); err != nil {
+		t.Fatalf("Populate failed: %v", err)
+	}
+
+	if author := docs[0]["author"].(bson.A); len(author) != 1 || author[0].(bson.M)["name"] != "ada" {
+		t.Errorf("Expected ada as author of a, got %v", author)
+	}
+	if author := docs[2]["author"].(bson.A); len(author) != 0 {
+		t.Errorf("Expected no author for a missing reference, got %v", author)
+	}
+}
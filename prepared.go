@@ -0,0 +1,243 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/cloudresty/ulid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// idKey is the raw BSON key of the document ID
+var idKey = []byte("_id")
+
+// PreparedInserter inserts documents of a single struct type T with a reused BSON encoder.
+//
+// The regular InsertOne/InsertMany paths inspect every document and, for struct values in ULID
+// mode, marshal to bson.M and back before the driver marshals again. A PreparedInserter resolves
+// the struct's ID field once, encodes each document exactly once into a reused buffer and splices
+// the generated ULID into the encoded bytes. This is intended for high-volume ingestion of one
+// document type; see BenchmarkPreparedInsertEncoding for the difference.
+//
+// A PreparedInserter is safe for concurrent use. Documents are passed by value and are never
+// modified, so generated IDs are only reported through the insert results.
+type PreparedInserter[T any] struct {
+	col  *Collection
+	info *idFieldInfo
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+	enc *bson.Encoder
+}
+
+// PreparedInsert returns a PreparedInserter for struct type T on the collection.
+// Go does not allow type parameters on methods, so the collection is passed explicitly.
+//
+// Returns an error if T is not a struct type, or if IDMode is ULID and T has an ID field
+// that cannot hold a ULID (see ErrULIDIncompatibleType).
+//
+// Example:
+//
+//	inserter, err := mongodb.PreparedInsert[Event](client.Collection("events"))
+//	if err != nil {
+//	    return err
+//	}
+//	result, err := inserter.InsertMany(ctx, events)
+func PreparedInsert[T any](col *Collection) (*PreparedInserter[T], error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("prepared insert requires a struct type, got %s", t)
+	}
+
+	var info *idFieldInfo
+	if cached, ok := idFieldCache.Load(t); ok {
+		info = cached.(*idFieldInfo)
+	} else {
+		info = findIDFieldInfo(t)
+		idFieldCache.Store(t, info)
+	}
+
+	if col.client.config.IDMode == IDModeULID && info.hasIDField &&
+		!info.isStringType && info.fieldTypeName != "interface {}" {
+		return nil, fmt.Errorf("%w: field type is %s; use string or interface{}", ErrULIDIncompatibleType, info.fieldTypeName)
+	}
+
+	p := &PreparedInserter[T]{
+		col:  col,
+		info: info,
+	}
+	p.enc = bson.NewEncoder(bson.NewDocumentWriter(&p.buf))
+	return p, nil
+}
+
+// InsertOne inserts a single document
+func (p *PreparedInserter[T]) InsertOne(ctx context.Context, document T, opts ...options.Lister[options.InsertOneOptions]) (*InsertOneResult, error) {
+	if err := p.col.checkWritable("InsertOne"); err != nil {
+		return nil, err
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	raw, id, generated, err := p.encode(document)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	result, err := p.col.collection.InsertOne(ctx, raw, opts...)
+	if err != nil {
+		p.col.client.incrementFailureCount()
+		p.col.logger(ctx).Error("Failed to insert prepared document",
+			"error", err.Error(),
+			"collection", p.col.name)
+		return nil, err
+	}
+
+	p.col.client.incrementOperationCount()
+
+	insertResult := &InsertOneResult{
+		InsertedID:   result.InsertedID,
+		WasGenerated: generated || id == nil,
+	}
+	if insertResult.WasGenerated {
+		insertResult.GeneratedAt = time.Now()
+	}
+	return insertResult, nil
+}
+
+// InsertMany inserts multiple documents
+func (p *PreparedInserter[T]) InsertMany(ctx context.Context, documents []T, opts ...options.Lister[options.InsertManyOptions]) (*InsertManyResult, error) {
+	if err := p.col.checkWritable("InsertMany"); err != nil {
+		return nil, err
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	now := time.Now()
+	rawDocs := make([]any, len(documents))
+	ids := make([]any, len(documents))
+
+	for i := range documents {
+		raw, id, _, err := p.encode(documents[i])
+		if err != nil {
+			return nil, err
+		}
+		rawDocs[i] = raw
+		ids[i] = id
+	}
+
+	if err := p.col.client.waitForWrites(ctx, len(rawDocs)); err != nil {
+		return nil, err
+	}
+
+	if err := p.col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	result, err := p.col.collection.InsertMany(ctx, rawDocs, opts...)
+	if err != nil {
+		p.col.client.incrementFailureCount()
+		p.col.logger(ctx).Error("Failed to insert prepared documents",
+			"error", err.Error(),
+			"collection", p.col.name)
+		return nil, err
+	}
+
+	// Fill in IDs assigned by the driver for documents without one
+	for i, id := range ids {
+		if id == nil && i < len(result.InsertedIDs) {
+			ids[i] = result.InsertedIDs[i]
+		}
+	}
+
+	p.col.client.incrementOperationCount()
+	p.col.logger(ctx).Debug("Prepared documents inserted successfully",
+		"collection", p.col.name,
+		"count", len(rawDocs))
+
+	return &InsertManyResult{
+		InsertedIDs:   ids,
+		InsertedCount: int64(len(rawDocs)),
+		GeneratedAt:   now,
+	}, nil
+}
+
+// encode marshals a document with the reused encoder and, in ULID mode, injects a ULID
+// when the document has no ID. It returns the encoded document, its ID (nil if the driver
+// will assign one) and whether the ID was generated here.
+func (p *PreparedInserter[T]) encode(document T) (bson.Raw, any, bool, error) {
+	var id any
+	if p.info.hasIDField {
+		field := reflect.ValueOf(&document).Elem().Field(p.info.fieldIndex)
+		if !field.IsZero() {
+			id = field.Interface()
+		}
+	}
+
+	generateID := id == nil && p.col.client.config.IDMode == IDModeULID
+	var generatedID string
+	if generateID {
+		var err error
+		generatedID, err = ulid.New()
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("failed to generate ULID: %w", err)
+		}
+		id = generatedID
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.buf.Reset()
+	if err := p.enc.Encode(document); err != nil {
+		return nil, nil, false, fmt.Errorf("failed to marshal document: %w", err)
+	}
+	encoded := p.buf.Bytes()
+
+	// The buffer is reused, so every document gets its own copy
+	if !generateID {
+		return bson.Raw(bytes.Clone(encoded)), id, false, nil
+	}
+	return spliceID(encoded, generatedID), id, true, nil
+}
+
+// spliceID returns a copy of the encoded document with _id set to id as its first element.
+// Any existing (zero-valued) _id element is dropped.
+func spliceID(encoded []byte, id string) bson.Raw {
+	dst := make([]byte, 0, len(encoded)+len(idKey)+len(id)+7)
+	idx, dst := bsoncore.AppendDocumentStart(dst)
+	dst = bsoncore.AppendStringElement(dst, "_id", id)
+
+	// Copy elements, skipping the 4-byte length prefix and trailing null byte
+	rest := encoded[4 : len(encoded)-1]
+	for len(rest) > 0 {
+		elem, remaining, ok := bsoncore.ReadElement(rest)
+		if !ok {
+			break
+		}
+		if !bytes.Equal(elem.KeyBytes(), idKey) {
+			dst = append(dst, elem...)
+		}
+		rest = remaining
+	}
+
+	dst, _ = bsoncore.AppendDocumentEnd(dst, idx)
+	return bson.Raw(dst)
+}
@@ -0,0 +1,204 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/ulid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type preparedEvent struct {
+	ID        string    `bson:"_id"`
+	Type      string    `bson:"type"`
+	Source    string    `bson:"source"`
+	Count     int       `bson:"count"`
+	Tags      []string  `bson:"tags"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func newPreparedEvent(i int) preparedEvent {
+	return preparedEvent{
+		Type:      "click",
+		Source:    "web",
+		Count:     i,
+		Tags:      []string{"a", "b", "c"},
+		CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestPreparedInsertEncode(t *testing.T) {
+	inserter, err := PreparedInsert[preparedEvent](newTestCollection("events", withIDMode(IDModeULID)))
+	if err != nil {
+		t.Fatalf("PreparedInsert failed: %v", err)
+	}
+
+	raw, id, generated, err := inserter.encode(newPreparedEvent(7))
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	if !generated {
+		t.Error("Expected ID to be generated")
+	}
+	if err := raw.Validate(); err != nil {
+		t.Fatalf("Encoded document is invalid: %v", err)
+	}
+
+	elements, err := raw.Elements()
+	if err != nil {
+		t.Fatalf("Failed to read elements: %v", err)
+	}
+	if elements[0].Key() != "_id" {
+		t.Errorf("Expected _id as first element, got %s", elements[0].Key())
+	}
+	idCount := 0
+	for _, elem := range elements {
+		if elem.Key() == "_id" {
+			idCount++
+		}
+	}
+	if idCount != 1 {
+		t.Errorf("Expected exactly one _id element, got %d", idCount)
+	}
+
+	var decoded preparedEvent
+	if err := bson.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("Failed to decode encoded document: %v", err)
+	}
+	if decoded.ID != id {
+		t.Errorf("Expected _id %v, got %s", id, decoded.ID)
+	}
+	if _, err := ulid.Parse(decoded.ID); err != nil {
+		t.Errorf("Expected generated ULID, got %s: %v", decoded.ID, err)
+	}
+	if decoded.Count != 7 || len(decoded.Tags) != 3 {
+		t.Errorf("Document fields were not preserved: %+v", decoded)
+	}
+
+	// Caller-provided IDs are kept as-is
+	event := newPreparedEvent(1)
+	event.ID = "evt-1"
+	raw, id, generated, err = inserter.encode(event)
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	if generated || id != "evt-1" || raw.Lookup("_id").StringValue() != "evt-1" {
+		t.Errorf("Expected provided ID evt-1, got id=%v generated=%v", id, generated)
+	}
+
+	// Encoded documents must not share the reused buffer
+	first, _, _, _ := inserter.encode(newPreparedEvent(1))
+	firstCopy := append(bson.Raw(nil), first...)
+	_, _, _, _ = inserter.encode(newPreparedEvent(2))
+	if !bytes.Equal(first, firstCopy) {
+		t.Error("Encoded document was modified by a later encode")
+	}
+}
+
+func TestPreparedInsertRejectsInvalidTypes(t *testing.T) {
+	if _, err := PreparedInsert[*preparedEvent](newTestCollection("events", withIDMode(IDModeULID))); err == nil {
+		t.Error("Expected error for pointer type")
+	}
+
+	type objectIDDoc struct {
+		ID   int64  `bson:"_id"`
+		Name string `bson:"name"`
+	}
+	if _, err := PreparedInsert[objectIDDoc](newTestCollection("events", withIDMode(IDModeULID))); !errors.Is(err, ErrULIDIncompatibleType) {
+		t.Errorf("Expected ErrULIDIncompatibleType, got %v", err)
+	}
+	if _, err := PreparedInsert[objectIDDoc](newTestCollection("events", withIDMode(IDModeObjectID))); err != nil {
+		t.Errorf("Expected non-ULID mode to accept any ID type, got %v", err)
+	}
+}
+
+func TestPreparedInsertIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_prepared_insert")
+	_, _ = col.DeleteMany(ctx, nil)
+	defer func() {
+		_, _ = col.DeleteMany(ctx, nil)
+	}()
+
+	inserter, err := PreparedInsert[preparedEvent](col)
+	if err != nil {
+		t.Fatalf("PreparedInsert failed: %v", err)
+	}
+
+	events := make([]preparedEvent, 50)
+	for i := range events {
+		events[i] = newPreparedEvent(i)
+	}
+	result, err := inserter.InsertMany(ctx, events)
+	if err != nil {
+		t.Fatalf("InsertMany failed: %v", err)
+	}
+	if result.InsertedCount != 50 || len(result.InsertedIDs) != 50 {
+		t.Fatalf("Expected 50 inserted documents, got %d", result.InsertedCount)
+	}
+
+	var stored preparedEvent
+	if err := col.FindByID(ctx, result.InsertedIDs[10]).Decode(&stored); err != nil {
+		t.Fatalf("Failed to read back document: %v", err)
+	}
+	if stored.Count != 10 {
+		t.Errorf("Expected count 10, got %d", stored.Count)
+	}
+
+	one, err := inserter.InsertOne(ctx, newPreparedEvent(99))
+	if err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+	if !one.WasGenerated {
+		t.Error("Expected generated ID")
+	}
+	count, err := col.CountDocuments(ctx, filter.Eq("count", 99))
+	if err != nil || count != 1 {
+		t.Errorf("Expected prepared InsertOne to be stored, count=%d err=%v", count, err)
+	}
+}
+
+// BenchmarkNaiveInsertEncoding measures the regular insert path for struct values in ULID
+// mode: marshal/unmarshal to bson.M to add the ID, then the driver's marshal of the map.
+func BenchmarkNaiveInsertEncoding(b *testing.B) {
+	col := newTestCollection("events", withIDMode(IDModeULID))
+	event := newPreparedEvent(1)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		doc, err := col.prepareDocumentForInsert(event)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := bson.Marshal(doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPreparedInsertEncoding measures the prepared path, which encodes each document once
+func BenchmarkPreparedInsertEncoding(b *testing.B) {
+	inserter, err := PreparedInsert[preparedEvent](newTestCollection("events", withIDMode(IDModeULID)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	event := newPreparedEvent(1)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, _, _, err := inserter.encode(event); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// queryErrorLogger adds the query of an operation to the Error entries of the wrapped Logger
+type queryErrorLogger struct {
+	Logger
+	query []any
+}
+
+// Error implements Logger.Error
+func (l queryErrorLogger) Error(msg string, fields ...any) {
+	l.Logger.Error(msg, append(fields[:len(fields):len(fields)], l.query...)...)
+}
+
+// errorLogger returns the operation logger like logger, which also adds the query fields
+// (name/value pairs such as "filter", filterDoc) to error entries when LogQueriesOnError is
+// enabled. Other levels never include the query.
+func (col *Collection) errorLogger(ctx context.Context, query ...any) Logger {
+	logger := col.logger(ctx)
+	if !col.client.config.LogQueriesOnError || len(query) == 0 {
+		return logger
+	}
+
+	fields := make([]any, len(query))
+	for i, value := range query {
+		if i%2 == 1 {
+			value = queryLogValue(value)
+		}
+		fields[i] = value
+	}
+	return queryErrorLogger{Logger: logger, query: fields}
+}
+
+// queryLogValue renders a query document as relaxed Extended JSON, which reads the same as
+// the query in the shell
+func queryLogValue(value any) string {
+	if value == nil {
+		return "null"
+	}
+	// Pipelines are arrays, which cannot be marshaled as top-level documents
+	data, err := bson.MarshalExtJSON(bson.M{"v": value}, false, false)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	// Strip the {"v": ...} wrapper
+	return string(data[5 : len(data)-1])
+}
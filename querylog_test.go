@@ -0,0 +1,141 @@
+package mongodb
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/update"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestQueryLogValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{"filter", bson.M{"status": "open"}, `{"status":"open"}`},
+		{"operators", bson.M{"total": bson.M{"$gte": int32(100)}}, `{"total":{"$gte":100}}`},
+		{"pipeline", bson.A{bson.M{"$match": bson.M{}}, bson.M{"$limit": int32(5)}}, `[{"$match":{}},{"$limit":5}]`},
+		{"nil", nil, "null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryLogValue(tt.value); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestErrorLoggerAddsQueryOnlyToErrors(t *testing.T) {
+	recorder := &logRecorder{}
+	col := newTestCollection("orders", WithLogger(recorder), WithQueryLoggingOnError(true))
+	config := col.client.config
+
+	fields := []any{"collection", "orders"}
+	logger := col.errorLogger(context.Background(), "filter", bson.M{"status": "open"})
+	logger.Debug("debug", fields...)
+	logger.Info("info", fields...)
+	logger.Warn("warn", fields...)
+	logger.Error("error", fields...)
+
+	if len(recorder.entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(recorder.entries))
+	}
+	for _, entry := range recorder.entries {
+		query, ok := entry.field("filter")
+		if entry.level == "error" {
+			if query != `{"status":"open"}` {
+				t.Errorf("Expected the filter on the error entry, got fields %v", entry.fields)
+			}
+		} else if ok {
+			t.Errorf("Expected no filter on the %s entry, got fields %v", entry.level, entry.fields)
+		}
+	}
+	if len(fields) != 2 {
+		t.Errorf("Expected caller fields to be unchanged, got %v", fields)
+	}
+
+	// Disabled by default
+	config.LogQueriesOnError = false
+	if col.errorLogger(context.Background(), "filter", bson.M{}) != Logger(recorder) {
+		t.Error("Expected the plain logger when query logging is disabled")
+	}
+}
+
+func TestFailedOperationLogsQuery(t *testing.T) {
+	// Connect does not perform I/O, so operations fail on server selection
+	driverClient, err := mongo.Connect(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatalf("Failed to create driver client: %v", err)
+	}
+	defer func() {
+		_ = driverClient.Disconnect(context.Background())
+	}()
+
+	recorder := &logRecorder{}
+	col := newTestCollection("orders", WithLogger(recorder), WithQueryLoggingOnError(true))
+	col.client.client = driverClient
+	col.collection = driverClient.Database("shop").Collection("orders")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = col.UpdateOne(ctx, filter.Eq("customer", "c-42"), update.Set("status", "shipped"))
+	if err == nil {
+		t.Fatal("Expected UpdateOne to fail without a server")
+	}
+
+	var failure *logEntry
+	for i, entry := range recorder.entries {
+		if entry.level == "error" {
+			failure = &recorder.entries[i]
+		} else if _, ok := entry.field("filter"); ok {
+			t.Errorf("Expected no filter on the %s entry %q", entry.level, entry.msg)
+		}
+	}
+	if failure == nil {
+		t.Fatal("Expected an error entry")
+	}
+	if query, _ := failure.field("filter"); query != `{"customer":"c-42"}` {
+		t.Errorf("Expected the filter on the error entry, got fields %v", failure.fields)
+	}
+	if query, _ := failure.field("update"); !strings.Contains(query.(string), `"status":"shipped"`) {
+		t.Errorf("Expected the update on the error entry, got fields %v", failure.fields)
+	}
+}
+
+func TestSuccessfulOperationOmitsQuery(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	recorder := &logRecorder{}
+	col := client.Collection("test_query_logging")
+	col.client = newTestClient(WithLogger(recorder), WithQueryLoggingOnError(true))
+	col.client.client = client.client
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	if _, err := col.CountDocuments(ctx, filter.Eq("customer", "c-42")); err != nil {
+		t.Fatalf("CountDocuments failed: %v", err)
+	}
+	for _, entry := range recorder.entries {
+		if _, ok := entry.field("filter"); ok {
+			t.Errorf("Expected no filter on the %s entry %q", entry.level, entry.msg)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/update"
+	"github.com/cloudresty/ulid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Job states stored in the status field of queue documents
+const (
+	JobStatusPending    = "pending"
+	JobStatusProcessing = "processing"
+	JobStatusDead       = "dead"
+)
+
+const (
+	// DefaultQueueLease is how long a dequeued job stays claimed before it is redelivered
+	DefaultQueueLease = 30 * time.Second
+	// DefaultQueueMaxAttempts is how many deliveries a job gets before Nack moves it to dead
+	DefaultQueueMaxAttempts = 5
+)
+
+var (
+	// ErrQueueEmpty is returned by Dequeue when no job is ready to be claimed
+	ErrQueueEmpty = errors.New("queue has no jobs ready")
+	// ErrLeaseLost is returned by Ack and Nack when the job's lease expired and the job was
+	// redelivered to another consumer, or the job was already acknowledged
+	ErrLeaseLost = errors.New("job lease is no longer held")
+)
+
+// Queue is a simple at-least-once job queue stored in a collection.
+//
+// Dequeue atomically claims the oldest ready job with FindOneAndUpdate and leases it for the
+// lease duration. A consumer must Ack the job to remove it, or Nack it to make it available
+// again; a job whose lease expires without either is redelivered to the next Dequeue. Jobs
+// that are nacked after MaxAttempts deliveries are moved to the dead state, where they are
+// kept for inspection (see DeadLetters) instead of being redelivered.
+//
+// Use a regular collection: capped collections do not allow the deletes done by Ack.
+// Call EnsureIndexes once so that Dequeue does not scan the collection.
+type Queue struct {
+	col         *Collection
+	lease       time.Duration
+	maxAttempts int
+}
+
+// Job is a queue entry returned by Dequeue
+type Job struct {
+	ID        any       `bson:"_id" json:"id"`
+	Payload   bson.Raw  `bson:"payload" json:"-"`
+	Status    string    `bson:"status" json:"status"`
+	Attempts  int       `bson:"attempts" json:"attempts"`
+	VisibleAt time.Time `bson:"visible_at" json:"visible_at"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	LastError string    `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	LeaseID   string    `bson:"lease_id,omitempty" json:"-"`
+}
+
+// Decode decodes the job payload into v
+func (j *Job) Decode(v any) error {
+	return bson.Unmarshal(j.Payload, v)
+}
+
+// AsQueue returns a Queue backed by the collection, using DefaultQueueLease and
+// DefaultQueueMaxAttempts.
+//
+// Example:
+//
+//	queue := client.Collection("jobs").AsQueue()
+//	_, err := queue.Enqueue(ctx, EmailJob{To: "user@example.com"})
+//
+//	job, err := queue.Dequeue(ctx)
+//	if errors.Is(err, mongodb.ErrQueueEmpty) {
+//	    return nil
+//	}
+//	if err := send(job); err != nil {
+//	    return queue.Nack(ctx, job, time.Minute, err)
+//	}
+//	return queue.Ack(ctx, job)
+func (col *Collection) AsQueue() *Queue {
+	return &Queue{
+		col:         col,
+		lease:       DefaultQueueLease,
+		maxAttempts: DefaultQueueMaxAttempts,
+	}
+}
+
+// WithLease returns a copy of the queue that claims jobs for the given duration
+func (q *Queue) WithLease(lease time.Duration) *Queue {
+	clone := *q
+	clone.lease = lease
+	return &clone
+}
+
+// WithMaxAttempts returns a copy of the queue that moves jobs to the dead state when they
+// are nacked after n deliveries
+func (q *Queue) WithMaxAttempts(n int) *Queue {
+	clone := *q
+	clone.maxAttempts = n
+	return &clone
+}
+
+// EnsureIndexes creates the index used by Dequeue to find ready jobs
+func (q *Queue) EnsureIndexes(ctx context.Context) error {
+	_, err := q.col.CreateIndex(ctx, IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "visible_at", Value: 1}},
+	})
+	return err
+}
+
+// Enqueue adds a job with the given payload, ready for immediate delivery
+func (q *Queue) Enqueue(ctx context.Context, payload any) (*InsertOneResult, error) {
+	return q.EnqueueAt(ctx, payload, time.Now())
+}
+
+// EnqueueAt adds a job with the given payload that is not delivered before visibleAt
+func (q *Queue) EnqueueAt(ctx context.Context, payload any, visibleAt time.Time) (*InsertOneResult, error) {
+	return q.col.InsertOne(ctx, bson.M{
+		"payload":    payload,
+		"status":     JobStatusPending,
+		"attempts":   0,
+		"visible_at": visibleAt,
+		"created_at": time.Now(),
+	})
+}
+
+// Dequeue claims the oldest ready job, which is either pending or a processing job whose
+// lease has expired. Returns ErrQueueEmpty if no job is ready.
+func (q *Queue) Dequeue(ctx context.Context) (*Job, error) {
+	leaseID, err := ulid.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate lease ID: %w", err)
+	}
+
+	now := time.Now()
+	opts := FindOneAndUpdateOpts().
+		SetReturnDocument(ReturnAfter).
+		SetSort(bson.D{{Key: "visible_at", Value: 1}})
+
+	var job Job
+	err = q.col.FindOneAndUpdate(ctx, q.readyFilter(now), q.claimUpdate(now, leaseID), opts).Decode(&job)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, ErrQueueEmpty
+		}
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Ack removes a completed job from the queue.
+// Returns ErrLeaseLost if the job is no longer leased by this delivery.
+func (q *Queue) Ack(ctx context.Context, job *Job) error {
+	result, err := q.col.DeleteOne(ctx, q.leaseFilter(job))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Nack releases a failed job so that it is redelivered after retryAfter. If the job has
+// already been delivered MaxAttempts times it is moved to the dead state instead. The
+// cause, if not nil, is recorded in the job's last_error field.
+// Returns ErrLeaseLost if the job is no longer leased by this delivery.
+func (q *Queue) Nack(ctx context.Context, job *Job, retryAfter time.Duration, cause error) error {
+	result, err := q.col.UpdateOne(ctx, q.leaseFilter(job), q.releaseUpdate(job, time.Now().Add(retryAfter), cause))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// DeadLetters returns the jobs that exhausted their delivery attempts
+func (q *Queue) DeadLetters(ctx context.Context) (*FindResult, error) {
+	return q.col.Find(ctx, filter.Eq("status", JobStatusDead))
+}
+
+// readyFilter matches pending jobs and processing jobs with an expired lease that are visible at now
+func (q *Queue) readyFilter(now time.Time) *filter.Builder {
+	return filter.In("status", JobStatusPending, JobStatusProcessing).
+		And(filter.Lte("visible_at", now))
+}
+
+// claimUpdate leases a job to a consumer until now plus the lease duration
+func (q *Queue) claimUpdate(now time.Time, leaseID string) *update.Builder {
+	return update.Set("status", JobStatusProcessing).
+		Set("visible_at", now.Add(q.lease)).
+		Set("lease_id", leaseID).
+		Inc("attempts", 1)
+}
+
+// leaseFilter matches the job only while it is still leased by the delivery that returned it
+func (q *Queue) leaseFilter(job *Job) *filter.Builder {
+	return filter.Eq("_id", job.ID).
+		And(filter.Eq("status", JobStatusProcessing), filter.Eq("lease_id", job.LeaseID))
+}
+
+// releaseUpdate returns a leased job to the pending state, or to the dead state when its
+// delivery attempts are exhausted
+func (q *Queue) releaseUpdate(job *Job, visibleAt time.Time, cause error) *update.Builder {
+	status := JobStatusPending
+	if q.maxAttempts > 0 && job.Attempts >= q.maxAttempts {
+		status = JobStatusDead
+	}
+
+	u := update.Set("status", status).
+		Set("visible_at", visibleAt).
+		Unset("lease_id")
+	if cause != nil {
+		u = u.Set("last_error", cause.Error())
+	}
+	return u
+}
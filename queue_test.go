@@ -0,0 +1,148 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type queueTestPayload struct {
+	To string `bson:"to"`
+}
+
+func TestQueueReleaseUpdate(t *testing.T) {
+	queue := newTestCollection("events", withIDMode(IDModeULID)).AsQueue().WithMaxAttempts(3)
+	visibleAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	retry := queue.releaseUpdate(&Job{Attempts: 2}, visibleAt, errors.New("smtp timeout")).Build()
+	set := retry["$set"].(bson.M)
+	if set["status"] != JobStatusPending {
+		t.Errorf("Expected job to return to pending, got %v", set["status"])
+	}
+	if set["visible_at"] != visibleAt || set["last_error"] != "smtp timeout" {
+		t.Errorf("Unexpected release update: %v", retry)
+	}
+	if _, ok := retry["$unset"].(bson.M)["lease_id"]; !ok {
+		t.Errorf("Expected lease_id to be unset, got %v", retry)
+	}
+
+	dead := queue.releaseUpdate(&Job{Attempts: 3}, visibleAt, nil).Build()
+	if dead["$set"].(bson.M)["status"] != JobStatusDead {
+		t.Errorf("Expected job to be dead after max attempts, got %v", dead)
+	}
+	if _, ok := dead["$set"].(bson.M)["last_error"]; ok {
+		t.Errorf("Expected no last_error without a cause, got %v", dead)
+	}
+}
+
+func TestQueueClaimUpdate(t *testing.T) {
+	queue := newTestCollection("events", withIDMode(IDModeULID)).AsQueue().WithLease(time.Minute)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	claim := queue.claimUpdate(now, "lease-1").Build()
+	set := claim["$set"].(bson.M)
+	if set["status"] != JobStatusProcessing || set["lease_id"] != "lease-1" {
+		t.Errorf("Unexpected claim update: %v", claim)
+	}
+	if set["visible_at"] != now.Add(time.Minute) {
+		t.Errorf("Expected lease to end at %v, got %v", now.Add(time.Minute), set["visible_at"])
+	}
+	if claim["$inc"].(bson.M)["attempts"] != 1 {
+		t.Errorf("Expected attempts to be incremented, got %v", claim)
+	}
+}
+
+func TestQueueIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_queue")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	queue := col.AsQueue().WithLease(200 * time.Millisecond).WithMaxAttempts(2)
+	if err := queue.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("EnsureIndexes failed: %v", err)
+	}
+
+	if _, err := queue.Dequeue(ctx); !errors.Is(err, ErrQueueEmpty) {
+		t.Fatalf("Expected ErrQueueEmpty on empty queue, got %v", err)
+	}
+
+	if _, err := queue.Enqueue(ctx, queueTestPayload{To: "a@example.com"}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	// Claim: the job is leased and not visible to other consumers
+	job, err := queue.Dequeue(ctx)
+	if err != nil {
+		t.Fatalf("Dequeue failed: %v", err)
+	}
+	var payload queueTestPayload
+	if err := job.Decode(&payload); err != nil || payload.To != "a@example.com" {
+		t.Fatalf("Unexpected payload %+v: %v", payload, err)
+	}
+	if job.Status != JobStatusProcessing || job.Attempts != 1 {
+		t.Errorf("Expected processing job with 1 attempt, got %s/%d", job.Status, job.Attempts)
+	}
+	if _, err := queue.Dequeue(ctx); !errors.Is(err, ErrQueueEmpty) {
+		t.Errorf("Expected leased job to be hidden, got %v", err)
+	}
+
+	// Lease expiry: the job is redelivered and the stale lease can no longer ack
+	time.Sleep(300 * time.Millisecond)
+	redelivered, err := queue.Dequeue(ctx)
+	if err != nil {
+		t.Fatalf("Expected redelivery after lease expiry: %v", err)
+	}
+	if redelivered.ID != job.ID || redelivered.Attempts != 2 {
+		t.Errorf("Expected same job on second attempt, got %v/%d", redelivered.ID, redelivered.Attempts)
+	}
+	if err := queue.Ack(ctx, job); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Expected ErrLeaseLost for expired lease, got %v", err)
+	}
+
+	// Nack after max attempts moves the job to the dead letters
+	if err := queue.Nack(ctx, redelivered, 0, errors.New("bounced")); err != nil {
+		t.Fatalf("Nack failed: %v", err)
+	}
+	if _, err := queue.Dequeue(ctx); !errors.Is(err, ErrQueueEmpty) {
+		t.Errorf("Expected dead job not to be redelivered, got %v", err)
+	}
+	dead, err := queue.DeadLetters(ctx)
+	if err != nil {
+		t.Fatalf("DeadLetters failed: %v", err)
+	}
+	var deadJobs []Job
+	if err := dead.All(ctx, &deadJobs); err != nil {
+		t.Fatalf("Failed to decode dead letters: %v", err)
+	}
+	if len(deadJobs) != 1 || deadJobs[0].LastError != "bounced" {
+		t.Errorf("Expected one dead job with last error, got %+v", deadJobs)
+	}
+
+	// Ack removes a completed job
+	if _, err := queue.Enqueue(ctx, queueTestPayload{To: "b@example.com"}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	job, err = queue.Dequeue(ctx)
+	if err != nil {
+		t.Fatalf("Dequeue failed: %v", err)
+	}
+	if err := queue.Ack(ctx, job); err != nil {
+		t.Fatalf("Ack failed: %v", err)
+	}
+	if err := queue.Ack(ctx, job); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Expected second Ack to report ErrLeaseLost, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket that refills at rate tokens per second up to burst tokens.
+//
+// A request for n tokens reserves them immediately, letting the bucket go into debt, and then
+// waits until the debt is paid off. Batches larger than the burst are therefore allowed but
+// delay later writes accordingly, which keeps the long-run throughput at the configured rate.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a limiter allowing opsPerSecond operations per second with a burst
+// of one second's worth of operations
+func newRateLimiter(opsPerSecond int) *rateLimiter {
+	return &rateLimiter{
+		rate:   float64(opsPerSecond),
+		burst:  float64(opsPerSecond),
+		tokens: float64(opsPerSecond),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until n operations may proceed. If ctx is done first, the reserved tokens are
+// returned to the bucket and the context error is returned.
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+
+	delay := l.reserve(float64(n))
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release(float64(n))
+		return ctx.Err()
+	}
+}
+
+// reserve takes n tokens and returns how long to wait until the bucket is out of debt
+func (l *rateLimiter) reserve(n float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	l.tokens -= n
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// release returns n unused tokens to the bucket
+func (l *rateLimiter) release(n float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = min(l.burst, l.tokens+n)
+}
+
+// waitForWrites applies the client's write rate limit, if any, to n write operations
+func (c *Client) waitForWrites(ctx context.Context, n int) error {
+	if c.writeLimiter == nil {
+		return nil
+	}
+	if err := c.writeLimiter.wait(ctx, n); err != nil {
+		c.config.Logger.Warn("Write rate limit wait aborted",
+			"operations", n,
+			"error", err.Error())
+		return err
+	}
+	return nil
+}
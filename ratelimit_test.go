@@ -0,0 +1,86 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestRateLimiterThroughput(t *testing.T) {
+	const rate = 1000
+	limiter := newRateLimiter(rate)
+	ctx := context.Background()
+
+	start := time.Now()
+	total := 0
+	for total < 1500 {
+		if err := limiter.wait(ctx, 50); err != nil {
+			t.Fatalf("wait failed: %v", err)
+		}
+		total += 50
+	}
+	elapsed := time.Since(start)
+
+	// The first second's worth is the burst; the remaining 500 operations need 500ms
+	if elapsed < 450*time.Millisecond {
+		t.Errorf("Expected throttling to take at least 450ms, took %v", elapsed)
+	}
+	if allowed := rate + rate*elapsed.Seconds(); float64(total) > allowed {
+		t.Errorf("Throughput exceeded the limit: %d operations in %v (allowed %.0f)", total, elapsed, allowed)
+	}
+}
+
+func TestRateLimiterRespectsCancellation(t *testing.T) {
+	limiter := newRateLimiter(10)
+	if err := limiter.wait(context.Background(), 10); err != nil {
+		t.Fatalf("Expected burst to pass without waiting: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := limiter.wait(ctx, 20)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected wait to stop at the deadline, took %v", elapsed)
+	}
+
+	// Tokens reserved by the cancelled wait are returned to the bucket
+	if delay := limiter.reserve(1); delay > 200*time.Millisecond {
+		t.Errorf("Expected cancelled reservation to be released, next delay is %v", delay)
+	}
+}
+
+func TestWriteRateLimitAppliesToBulkWrites(t *testing.T) {
+	col := newTestCollection("events", withIDMode(IDModeObjectID))
+	col.client.writeLimiter = newRateLimiter(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The limiter rejects the batch before it reaches the driver
+	docs := []any{bson.M{"n": 1}, bson.M{"n": 2}}
+	if _, err := col.InsertMany(ctx, docs); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected InsertMany to be throttled, got %v", err)
+	}
+
+	// Drain the bucket so that the single UpdateMany operation has to wait
+	if err := col.client.waitForWrites(context.Background(), 1); err != nil {
+		t.Fatalf("Failed to drain bucket: %v", err)
+	}
+	if _, err := col.UpdateMany(ctx, nil, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected UpdateMany to be throttled, got %v", err)
+	}
+
+	// Without a limit no wait happens
+	col.client.writeLimiter = nil
+	if err := col.client.waitForWrites(ctx, 1000); err != nil {
+		t.Errorf("Expected no rate limiting, got %v", err)
+	}
+}
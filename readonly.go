@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ErrReadOnly is returned by write operations on a collection handle obtained with ReadOnly
+var ErrReadOnly = errors.New("collection is read-only")
+
+// ReadOnly returns a collection handle whose write operations fail with ErrReadOnly without
+// contacting the server. Use it to enforce read-only access in reporting code paths or when
+// connected to an analytics node. The original handle is left unchanged.
+//
+// Rejected operations are inserts, updates (including pipeline updates and upserts),
+// replacements, deletes (including soft deletes and Restore), FindOneAnd*, BulkWrite,
+// index creation and removal, Drop, EnableChangeStreamPreAndPostImages, BackfillTimestamps,
+// CopyTo with a read-only target, and aggregations containing a $merge or $out stage.
+// Operations performed via Raw() or Indexes() are not guarded.
+//
+// Example:
+//
+//	reports := client.Collection("orders").ReadOnly()
+//	_, err := reports.DeleteMany(ctx, nil) // errors.Is(err, ErrReadOnly)
+func (col *Collection) ReadOnly() *Collection {
+	clone := *col
+	clone.readOnly = true
+	return &clone
+}
+
+// IsReadOnly reports whether the handle was obtained with ReadOnly
+func (col *Collection) IsReadOnly() bool {
+	return col.readOnly
+}
+
+// checkWritable rejects a write operation on a read-only handle
+func (col *Collection) checkWritable(operation string) error {
+	if !col.readOnly {
+		return nil
+	}
+	return fmt.Errorf("%w: %s on collection %s", ErrReadOnly, operation, col.name)
+}
+
+// checkAggregateWritable rejects aggregations that write their output with $merge or $out
+// on a read-only handle
+func (col *Collection) checkAggregateWritable(pipelineDoc any) error {
+	if !col.readOnly {
+		return nil
+	}
+	if stage := writeStage(pipelineDoc); stage != "" {
+		return col.checkWritable("Aggregate with " + stage)
+	}
+	return nil
+}
+
+// writeStage returns "$merge" or "$out" if the pipeline contains such a stage, or an empty
+// string otherwise
+func writeStage(pipelineDoc any) string {
+	var stages []any
+	switch p := pipelineDoc.(type) {
+	case bson.A:
+		stages = p
+	case []any:
+		stages = p
+	case []bson.M:
+		for _, stage := range p {
+			stages = append(stages, stage)
+		}
+	case []bson.D:
+		for _, stage := range p {
+			stages = append(stages, stage)
+		}
+	case mongo.Pipeline:
+		for _, stage := range p {
+			stages = append(stages, stage)
+		}
+	}
+
+	for _, stage := range stages {
+		var keys []string
+		switch s := stage.(type) {
+		case bson.M:
+			for key := range s {
+				keys = append(keys, key)
+			}
+		case map[string]any:
+			for key := range s {
+				keys = append(keys, key)
+			}
+		case bson.D:
+			for _, elem := range s {
+				keys = append(keys, elem.Key)
+			}
+		}
+		for _, key := range keys {
+			if key == "$merge" || key == "$out" {
+				return key
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,177 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"github.com/cloudresty/go-mongodb/v2/update"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestReadOnlyRejectsWrites(t *testing.T) {
+	ctx := context.Background()
+	// Without a driver collection, any operation that reached the driver would panic
+	col := newTestCollection("reports").ReadOnly()
+	byID := filter.Eq("_id", "a")
+	set := update.Set("status", "done")
+	out := pipeline.New().MatchRaw(bson.M{}).Raw(bson.M{"$out": "archive"})
+
+	writes := map[string]func() error{
+		"InsertOne":  func() error { _, err := col.InsertOne(ctx, bson.M{"a": 1}); return err },
+		"InsertMany": func() error { _, err := col.InsertMany(ctx, []any{bson.M{"a": 1}}); return err },
+		"InsertManyWithOptions": func() error {
+			_, err := col.InsertManyWithOptions(ctx, []any{bson.M{"a": 1}}, nil)
+			return err
+		},
+		"UpdateOne":  func() error { _, err := col.UpdateOne(ctx, byID, set); return err },
+		"UpdateMany": func() error { _, err := col.UpdateMany(ctx, byID, set); return err },
+		"UpdateByID": func() error { _, err := col.UpdateByID(ctx, "a", set); return err },
+		"UpdateOneRequired": func() error {
+			return col.UpdateOneRequired(ctx, byID, set)
+		},
+		"UpdateWithVersion": func() error { _, err := col.UpdateWithVersion(ctx, "a", 1, set); return err },
+		"UpdateOnePipeline": func() error {
+			_, err := col.UpdateOnePipeline(ctx, byID, pipeline.New().SetNow("updated_at"))
+			return err
+		},
+		"UpdateManyPipeline": func() error {
+			_, err := col.UpdateManyPipeline(ctx, byID, pipeline.New().SetNow("updated_at"))
+			return err
+		},
+		"ReplaceOnePipeline": func() error {
+			_, err := col.ReplaceOnePipeline(ctx, byID, "$$ROOT")
+			return err
+		},
+		"UpsertByField": func() error {
+			_, err := col.UpsertByField(ctx, "email", "a@example.com", bson.M{"name": "a"})
+			return err
+		},
+		"ReplaceOne":          func() error { _, err := col.ReplaceOne(ctx, byID, bson.M{"a": 1}); return err },
+		"DeleteOne":           func() error { _, err := col.DeleteOne(ctx, byID); return err },
+		"DeleteMany":          func() error { _, err := col.DeleteMany(ctx, byID); return err },
+		"DeleteByID":          func() error { _, err := col.DeleteByID(ctx, "a"); return err },
+		"DeleteOneRequired":   func() error { return col.DeleteOneRequired(ctx, byID) },
+		"FindOneAndUpdate":    func() error { return col.FindOneAndUpdate(ctx, byID, set).Err() },
+		"FindOneAndReplace":   func() error { return col.FindOneAndReplace(ctx, byID, bson.M{"a": 1}).Err() },
+		"FindOneAndDelete":    func() error { return col.FindOneAndDelete(ctx, byID).Err() },
+		"UpdateByIDReturning": func() error { return col.UpdateByIDReturning(ctx, "a", set).Err() },
+		"BulkWrite": func() error {
+			_, err := col.BulkWrite(ctx, []mongo.WriteModel{mongo.NewDeleteOneModel().SetFilter(bson.M{})})
+			return err
+		},
+		"CreateIndex": func() error {
+			_, err := col.CreateIndex(ctx, IndexModel{Keys: bson.D{{Key: "a", Value: 1}}})
+			return err
+		},
+		"CreateIndexes": func() error {
+			_, err := col.CreateIndexes(ctx, []IndexModel{{Keys: bson.D{{Key: "a", Value: 1}}}})
+			return err
+		},
+		"DropIndex": func() error { return col.DropIndex(ctx, "a_1") },
+		"Drop":      func() error { return col.Drop(ctx) },
+		"EnableChangeStreamPreAndPostImages": func() error {
+			return col.EnableChangeStreamPreAndPostImages(ctx)
+		},
+		"Restore": func() error {
+			_, err := col.WithSoftDelete("deleted_at").Restore(ctx, byID)
+			return err
+		},
+		"PurgeDeleted": func() error {
+			_, err := col.WithSoftDelete("deleted_at").PurgeDeleted(ctx, time.Now())
+			return err
+		},
+		"BackfillTimestamps": func() error { _, err := col.BackfillTimestamps(ctx, 10); return err },
+		"AggregateWithPipeline $out": func() error {
+			_, err := col.AggregateWithPipeline(ctx, out)
+			return err
+		},
+		"Aggregate $merge": func() error {
+			_, err := col.Aggregate(ctx, mongo.Pipeline{{{Key: "$merge", Value: bson.M{"into": "archive"}}}})
+			return err
+		},
+		"PreparedInsert InsertOne": func() error {
+			inserter, err := PreparedInsert[preparedEvent](col)
+			if err != nil {
+				return err
+			}
+			_, err = inserter.InsertOne(ctx, newPreparedEvent(1))
+			return err
+		},
+		"PreparedInsert InsertMany": func() error {
+			inserter, err := PreparedInsert[preparedEvent](col)
+			if err != nil {
+				return err
+			}
+			_, err = inserter.InsertMany(ctx, []preparedEvent{newPreparedEvent(1)})
+			return err
+		},
+		"CopyTo read-only target": func() error {
+			source := &Collection{name: "orders", client: col.client}
+			_, err := source.CopyTo(ctx, col, nil, 10)
+			return err
+		},
+	}
+
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			if err := write(); !errors.Is(err, ErrReadOnly) {
+				t.Errorf("Expected ErrReadOnly, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReadOnlyHandle(t *testing.T) {
+	base := newTestCollection("orders")
+	readOnly := base.ReadOnly()
+
+	if !readOnly.IsReadOnly() {
+		t.Error("Expected read-only handle")
+	}
+	if base.IsReadOnly() {
+		t.Error("Expected original handle to stay writable")
+	}
+
+	// Derived handles keep the read-only mode
+	if !readOnly.WithSoftDelete("deleted_at").IsReadOnly() {
+		t.Error("Expected derived handle to stay read-only")
+	}
+
+	// Writable handles are not affected by the guard
+	if err := base.checkWritable("InsertOne"); err != nil {
+		t.Errorf("Expected writable handle, got %v", err)
+	}
+	if err := base.checkAggregateWritable(bson.A{bson.M{"$out": "archive"}}); err != nil {
+		t.Errorf("Expected writable handle to allow $out, got %v", err)
+	}
+}
+
+func TestWriteStage(t *testing.T) {
+	tests := []struct {
+		name     string
+		pipeline any
+		expected string
+	}{
+		{"nil", nil, ""},
+		{"read-only bson.A", bson.A{bson.M{"$match": bson.M{}}}, ""},
+		{"out in bson.A", bson.A{bson.M{"$match": bson.M{}}, bson.M{"$out": "x"}}, "$out"},
+		{"merge in []bson.M", []bson.M{{"$merge": bson.M{"into": "x"}}}, "$merge"},
+		{"merge in []bson.D", []bson.D{{{Key: "$merge", Value: "x"}}}, "$merge"},
+		{"out in mongo.Pipeline", mongo.Pipeline{{{Key: "$out", Value: "x"}}}, "$out"},
+		{"out in []any of bson.D", []any{bson.D{{Key: "$out", Value: "x"}}}, "$out"},
+		{"read-only builder", pipeline.New().Limit(5).ToBSONArray(), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := writeStage(tt.pipeline); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package mongodb
+
+import "context"
+
+// requestIDKey is the context key of the request ID included in operation logs
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying a request ID. Collection operations
+// passed the context add it to their log entries as the "request_id" field, so database
+// operations can be correlated with the HTTP request (or job) that issued them.
+//
+// Example:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    ctx := mongodb.ContextWithRequestID(r.Context(), r.Header.Get("X-Request-ID"))
+//	    result, err := users.FindOne(ctx, filter.Eq("email", email))
+//	    ...
+//	}
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID set with ContextWithRequestID, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// requestIDLogger adds a request_id field to every entry of the wrapped Logger
+type requestIDLogger struct {
+	Logger
+	requestID string
+}
+
+// Info implements Logger.Info
+func (l requestIDLogger) Info(msg string, fields ...any) {
+	l.Logger.Info(msg, l.withRequestID(fields)...)
+}
+
+// Warn implements Logger.Warn
+func (l requestIDLogger) Warn(msg string, fields ...any) {
+	l.Logger.Warn(msg, l.withRequestID(fields)...)
+}
+
+// Error implements Logger.Error
+func (l requestIDLogger) Error(msg string, fields ...any) {
+	l.Logger.Error(msg, l.withRequestID(fields)...)
+}
+
+// Debug implements Logger.Debug
+func (l requestIDLogger) Debug(msg string, fields ...any) {
+	l.Logger.Debug(msg, l.withRequestID(fields)...)
+}
+
+// withRequestID appends the request_id field to the structured fields of a log entry
+func (l requestIDLogger) withRequestID(fields []any) []any {
+	return append(fields[:len(fields):len(fields)], "request_id", l.requestID)
+}
+
+// logger returns the client logger for an operation, adding the request ID carried by ctx
+func (col *Collection) logger(ctx context.Context) Logger {
+	return col.client.logger(ctx)
+}
+
+// logger returns the configured logger, adding the request ID carried by ctx
+func (c *Client) logger(ctx context.Context) Logger {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return requestIDLogger{Logger: c.config.Logger, requestID: id}
+	}
+	return c.config.Logger
+}
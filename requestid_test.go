@@ -0,0 +1,164 @@
+package mongodb
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// logEntry is a log message recorded by logRecorder
+type logEntry struct {
+	level  string
+	msg    string
+	fields []any
+}
+
+// logRecorder is a Logger that records every entry
+type logRecorder struct {
+	entries []logEntry
+}
+
+func (l *logRecorder) Info(msg string, fields ...any) {
+	l.entries = append(l.entries, logEntry{"info", msg, fields})
+}
+
+func (l *logRecorder) Warn(msg string, fields ...any) {
+	l.entries = append(l.entries, logEntry{"warn", msg, fields})
+}
+
+func (l *logRecorder) Error(msg string, fields ...any) {
+	l.entries = append(l.entries, logEntry{"error", msg, fields})
+}
+
+func (l *logRecorder) Debug(msg string, fields ...any) {
+	l.entries = append(l.entries, logEntry{"debug", msg, fields})
+}
+
+// field returns the value of a structured field of the entry
+func (e logEntry) field(key string) (any, bool) {
+	for i := 0; i+1 < len(e.fields); i += 2 {
+		if e.fields[i] == key {
+			return e.fields[i+1], true
+		}
+	}
+	return nil, false
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	if _, ok := RequestIDFromContext(context.Background()); ok {
+		t.Error("Expected no request ID on a plain context")
+	}
+	var nilCtx context.Context
+	if _, ok := RequestIDFromContext(nilCtx); ok {
+		t.Error("Expected no request ID on a nil context")
+	}
+	if _, ok := RequestIDFromContext(ContextWithRequestID(context.Background(), "")); ok {
+		t.Error("Expected an empty request ID to be ignored")
+	}
+
+	ctx := ContextWithRequestID(context.Background(), "req-42")
+	if id, ok := RequestIDFromContext(ctx); !ok || id != "req-42" {
+		t.Errorf("Expected req-42, got %q (%v)", id, ok)
+	}
+}
+
+func TestCollectionLoggerAddsRequestID(t *testing.T) {
+	recorder := &logRecorder{}
+	col := newTestCollection("orders", WithLogger(recorder))
+
+	if col.logger(context.Background()) != Logger(recorder) {
+		t.Error("Expected the client logger without a request ID")
+	}
+
+	fields := []any{"collection", "orders"}
+	logger := col.logger(ContextWithRequestID(context.Background(), "req-1"))
+	logger.Debug("debug", fields...)
+	logger.Info("info", fields...)
+	logger.Warn("warn", fields...)
+	logger.Error("error", fields...)
+
+	if len(recorder.entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(recorder.entries))
+	}
+	for _, entry := range recorder.entries {
+		if id, _ := entry.field("request_id"); id != "req-1" {
+			t.Errorf("Expected request_id on %s entry, got fields %v", entry.level, entry.fields)
+		}
+		if name, _ := entry.field("collection"); name != "orders" {
+			t.Errorf("Expected existing fields to be kept, got %v", entry.fields)
+		}
+	}
+
+	// The caller's fields must not be modified
+	if !slices.Equal(fields, []any{"collection", "orders"}) {
+		t.Errorf("Expected caller fields to be unchanged, got %v", fields)
+	}
+}
+
+func TestOperationLogsIncludeRequestID(t *testing.T) {
+	// Connect does not perform I/O, so the operation fails on server selection
+	driverClient, err := mongo.Connect(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatalf("Failed to create driver client: %v", err)
+	}
+	defer func() {
+		_ = driverClient.Disconnect(context.Background())
+	}()
+
+	recorder := &logRecorder{}
+	col := newTestCollection("orders", WithLogger(recorder))
+	col.client.client = driverClient
+	col.collection = driverClient.Database("shop").Collection("orders")
+
+	ctx, cancel := context.WithTimeout(ContextWithRequestID(context.Background(), "req-7"), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := col.CountDocuments(ctx, filter.Eq("status", "open")); err == nil {
+		t.Fatal("Expected CountDocuments to fail without a server")
+	}
+
+	if len(recorder.entries) == 0 {
+		t.Fatal("Expected the operation to log")
+	}
+	for _, entry := range recorder.entries {
+		if id, _ := entry.field("request_id"); id != "req-7" {
+			t.Errorf("Expected request_id on %q, got fields %v", entry.msg, entry.fields)
+		}
+	}
+}
+
+func TestCheckLogsIncludeRequestID(t *testing.T) {
+	recorder := &logRecorder{}
+	col := newTestCollection("orders",
+		WithLogger(recorder),
+		WithMaxDocumentSize(16),
+		WithOperationRetry(2, 0)).
+		WithShardKey(bson.D{{Key: "tenant_id", Value: 1}})
+	ctx := ContextWithRequestID(context.Background(), "req-9")
+
+	_ = col.checkShardKey(ctx, bson.M{"status": "open"}, "Find")
+	_ = col.checkDocumentSize(ctx, bson.M{"name": "larger than sixteen bytes"}, "test document")
+	calls := 0
+	_ = col.client.runWithRetry(ctx, "Find", func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return errTransient
+		}
+		return nil
+	})
+
+	if len(recorder.entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %v", recorder.entries)
+	}
+	for _, entry := range recorder.entries {
+		if id, _ := entry.field("request_id"); id != "req-9" {
+			t.Errorf("Expected request_id on %q, got fields %v", entry.msg, entry.fields)
+		}
+	}
+}
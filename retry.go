@@ -0,0 +1,99 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// runWithRetry runs fn, retrying transient failures up to OperationRetryAttempts times in
+// total. When ctx has a deadline, each attempt gets an equal share of the time remaining, so
+// the whole operation, including backoff, finishes within the caller's deadline.
+func (c *Client) runWithRetry(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	attempts := c.config.OperationRetryAttempts
+	if attempts <= 1 {
+		return fn(ctx)
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		attemptCtx, cancel := attemptContext(ctx, attempts-attempt+1)
+		err = fn(attemptCtx)
+		cancel()
+
+		if err == nil || attempt == attempts || ctx.Err() != nil || !isRetryableError(err) {
+			return err
+		}
+
+		backoff := c.config.OperationRetryBackoff
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= backoff {
+			// No time left for another attempt after backing off
+			return err
+		}
+
+		c.logger(ctx).Warn("Retrying operation after transient error",
+			"operation", operation,
+			"attempt", attempt,
+			"max_attempts", attempts,
+			"error", err.Error())
+
+		if backoff > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+	}
+	return err
+}
+
+// openCursor opens a cursor with open, retrying transient failures the way runWithRetry does
+// for single-result reads. Only opening is retried: once the cursor exists, getMore failures
+// are returned by the cursor itself.
+func (col *Collection) openCursor(ctx context.Context, operation string, open func(ctx context.Context) (*mongo.Cursor, error)) (*mongo.Cursor, error) {
+	var cursor *mongo.Cursor
+	err := col.client.runWithRetry(ctx, operation, func(ctx context.Context) error {
+		var err error
+		cursor, err = open(ctx)
+		return err
+	})
+	return cursor, err
+}
+
+// attemptContext derives the context for one attempt. With a deadline on ctx, the attempt
+// gets the time remaining divided by the number of attempts left.
+func attemptContext(ctx context.Context, attemptsLeft int) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok || attemptsLeft <= 1 {
+		return context.WithCancel(ctx)
+	}
+	budget := time.Until(deadline) / time.Duration(attemptsLeft)
+	return context.WithTimeout(ctx, budget)
+}
+
+// notPrimaryErrorCodes are returned by a primary that stepped down while an election is
+// choosing its successor: NotWritablePrimary (10107) and NotPrimaryNoSecondaryOk (13435)
+var notPrimaryErrorCodes = []int{10107, 13435}
+
+// isRetryableError reports whether an operation failure is transient and worth retrying
+func isRetryableError(err error) bool {
+	if mongo.IsNetworkError(err) || mongo.IsTimeout(err) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorLabel("RetryableWriteError") {
+		return true
+	}
+
+	// The next attempt is routed to the newly elected primary
+	return slices.ContainsFunc(mongo.ErrorCodes(err), func(code int) bool {
+		return slices.Contains(notPrimaryErrorCodes, code)
+	})
+}
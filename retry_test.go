@@ -0,0 +1,254 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var errTransient = mongo.CommandError{Code: 6, Message: "connection reset", Labels: []string{"NetworkError"}}
+
+func TestRetryBudgetStaysWithinDeadline(t *testing.T) {
+	c := newTestClient(WithOperationRetry(3, 10*time.Millisecond))
+
+	const deadline = 300 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), deadline)
+	defer cancel()
+
+	// Every attempt hangs until its context expires, like an unresponsive server
+	var budgets []time.Duration
+	start := time.Now()
+	err := c.runWithRetry(ctx, "Find", func(ctx context.Context) error {
+		attemptDeadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("Expected attempt context to have a deadline")
+		}
+		budgets = append(budgets, time.Until(attemptDeadline))
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if elapsed > deadline+50*time.Millisecond {
+		t.Errorf("Expected retries to finish within the %v deadline, took %v", deadline, elapsed)
+	}
+	if len(budgets) != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", len(budgets))
+	}
+	if budgets[0] > deadline/3+10*time.Millisecond {
+		t.Errorf("Expected first attempt to get about a third of the deadline, got %v", budgets[0])
+	}
+}
+
+func TestRetrySucceedsAfterTransientError(t *testing.T) {
+	c := newTestClient(WithOperationRetry(3, time.Millisecond))
+
+	calls := 0
+	err := c.runWithRetry(context.Background(), "CountDocuments", func(ctx context.Context) error {
+		calls++
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("Expected no attempt deadline without a caller deadline")
+		}
+		if calls == 1 {
+			return errTransient
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected success on retry, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestRetrySucceedsAfterPrimaryStepDown(t *testing.T) {
+	c := newTestClient(WithOperationRetry(3, time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// The first attempt reaches the old primary while the election is in progress
+	stepDown := mongo.CommandError{Code: 10107, Name: "NotWritablePrimary", Message: "not primary"}
+	calls := 0
+	err := c.runWithRetry(ctx, "FindOne", func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			return stepDown
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected success on retry, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestFindCursorRetriesAfterPrimaryStepDown(t *testing.T) {
+	col := newTestCollection("orders", WithOperationRetry(3, time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Opening the cursor first reaches a primary that stepped down without a successor yet
+	stepDown := mongo.CommandError{Code: 13435, Name: "NotPrimaryNoSecondaryOk", Message: "not primary and secondaryOk=false"}
+	calls := 0
+	cursor, err := col.openCursor(ctx, "Find", func(ctx context.Context) (*mongo.Cursor, error) {
+		calls++
+		if calls == 1 {
+			return nil, stepDown
+		}
+		return mongo.NewCursorFromDocuments([]any{bson.M{"status": "paid"}}, nil, nil)
+	})
+	if err != nil {
+		t.Fatalf("Expected the cursor to open on retry, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls)
+	}
+
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil || len(docs) != 1 {
+		t.Errorf("Expected the retried cursor to return 1 document, got %d (err: %v)", len(docs), err)
+	}
+}
+
+func TestFindRetriesNotPrimaryIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, err := NewClient(FromEnv(), WithOperationRetry(3, 10*time.Millisecond))
+	if err != nil {
+		t.Skipf("Could not connect to MongoDB: %v", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx := context.Background()
+	col := client.Collection("test_find_retry")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+	if _, err := col.InsertOne(ctx, bson.M{"status": "paid"}); err != nil {
+		t.Fatalf("Failed to seed collection: %v", err)
+	}
+
+	// Fail the next two finds with NotWritablePrimary, as a primary stepping down would; the
+	// driver retries a read once itself, so only the second failure reaches runWithRetry
+	admin := client.Raw().Database("admin")
+	failPoint := bson.D{
+		{Key: "configureFailPoint", Value: "failCommand"},
+		{Key: "mode", Value: bson.D{{Key: "times", Value: 2}}},
+		{Key: "data", Value: bson.D{
+			{Key: "failCommands", Value: bson.A{"find"}},
+			{Key: "errorCode", Value: 10107},
+		}},
+	}
+	if err := admin.RunCommand(ctx, failPoint).Err(); err != nil {
+		t.Skipf("Fail points are not enabled on this server: %v", err)
+	}
+	defer func() {
+		_ = admin.RunCommand(ctx, bson.D{
+			{Key: "configureFailPoint", Value: "failCommand"},
+			{Key: "mode", Value: "off"},
+		}).Err()
+	}()
+
+	cursor, err := col.Find(ctx, filter.Eq("status", "paid"))
+	if err != nil {
+		t.Fatalf("Expected Find to succeed after retrying NotPrimary, got %v", err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil || len(docs) != 1 {
+		t.Errorf("Expected 1 document, got %d (err: %v)", len(docs), err)
+	}
+}
+
+func TestRetryStopsOnPermanentError(t *testing.T) {
+	c := newTestClient(WithOperationRetry(3, time.Millisecond))
+
+	calls := 0
+	err := c.runWithRetry(context.Background(), "FindOne", func(ctx context.Context) error {
+		calls++
+		return mongo.ErrNoDocuments
+	})
+	if !errors.Is(err, mongo.ErrNoDocuments) || calls != 1 {
+		t.Errorf("Expected a single attempt returning ErrNoDocuments, got %d attempts and %v", calls, err)
+	}
+}
+
+func TestRetryGivesUpWhenBackoffExceedsDeadline(t *testing.T) {
+	c := newTestClient(WithOperationRetry(3, time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	start := time.Now()
+	err := c.runWithRetry(ctx, "Distinct", func(ctx context.Context) error {
+		calls++
+		return errTransient
+	})
+	if !mongo.IsNetworkError(err) || calls != 1 {
+		t.Errorf("Expected to give up after one attempt, got %d attempts and %v", calls, err)
+	}
+	if time.Since(start) > 50*time.Millisecond {
+		t.Error("Expected not to wait for a backoff that outlasts the deadline")
+	}
+}
+
+func TestRetryDisabled(t *testing.T) {
+	c := newTestClient(WithOperationRetry(1, 0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	calls := 0
+	err := c.runWithRetry(ctx, "FindOne", func(attemptCtx context.Context) error {
+		calls++
+		if attemptCtx != ctx {
+			t.Error("Expected the caller context to be used unchanged")
+		}
+		return errTransient
+	})
+	if !mongo.IsNetworkError(err) || calls != 1 {
+		t.Errorf("Expected a single attempt, got %d attempts and %v", calls, err)
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{"network error", errTransient, true},
+		{"deadline exceeded", context.DeadlineExceeded, true},
+		{"retryable write label", mongo.CommandError{Code: 91, Labels: []string{"RetryableWriteError"}}, true},
+		{"not writable primary", mongo.CommandError{Code: 10107, Name: "NotWritablePrimary"}, true},
+		{"not primary no secondary ok", mongo.CommandError{Code: 13435, Name: "NotPrimaryNoSecondaryOk"}, true},
+		{"no documents", mongo.ErrNoDocuments, false},
+		{"duplicate key", mongo.CommandError{Code: 11000}, false},
+		{"canceled", context.Canceled, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.err); got != tt.retryable {
+				t.Errorf("Expected %v, got %v", tt.retryable, got)
+			}
+		})
+	}
+}
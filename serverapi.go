@@ -0,0 +1,45 @@
+package mongodb
+
+import (
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// apiStrictErrorCode is the server error code of a command rejected because it is not part of
+// the Stable API version declared in strict mode
+const apiStrictErrorCode = 323
+
+// IsAPIStrictError reports whether err is an APIStrictError: the client declares a Stable API
+// version in strict mode (WithServerAPIVersion) and the command is not part of that version.
+// Such commands must be replaced by a versioned alternative (e.g. an aggregation with $count
+// instead of the count command) or run from a client without strict mode.
+//
+// Example:
+//
+//	if _, err := db.RunCommand(ctx, bson.D{{Key: "collStats", Value: "orders"}}).Raw(); err != nil {
+//	    if mongodb.IsAPIStrictError(err) {
+//	        return fmt.Errorf("collStats is not in the Stable API, use $collStats: %w", err)
+//	    }
+//	    return err
+//	}
+func IsAPIStrictError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return slices.Contains(mongo.ErrorCodes(err), apiStrictErrorCode)
+}
+
+// reportAPIStrictFailure logs a warning naming the command when a command fails because it is
+// not part of the declared Stable API version, since the server error alone does not say how
+// to resolve it
+func (c *Client) reportAPIStrictFailure(evt *event.CommandFailedEvent) {
+	if !IsAPIStrictError(evt.Failure) {
+		return
+	}
+	c.config.Logger.Warn("Command is not part of the Stable API; use a versioned alternative or disable strict mode",
+		"command", evt.CommandName,
+		"database", evt.DatabaseName,
+		"api_version", c.config.ServerAPIVersion)
+}
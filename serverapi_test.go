@@ -0,0 +1,90 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// apiStrictError is the error reported by a server for the count command with apiStrict:true
+var apiStrictError = mongo.CommandError{
+	Code:    apiStrictErrorCode,
+	Name:    "APIStrictError",
+	Message: "Provided apiStrict:true, but the command count is not in API Version 1",
+}
+
+func TestIsAPIStrictError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"strict error", apiStrictError, true},
+		{"wrapped strict error", fmt.Errorf("count orders: %w", apiStrictError), true},
+		{"other command error", mongo.CommandError{Code: 26, Name: "NamespaceNotFound"}, false},
+		{"plain error", errors.New("APIStrictError"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAPIStrictError(tt.err); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAPIStrictFailureIsLogged(t *testing.T) {
+	logger := &warnRecorder{}
+	var forwarded int
+	client := newTestClient(WithLogger(logger), WithServerAPIVersion("1", true), WithMonitor(&event.CommandMonitor{
+		Failed: func(context.Context, *event.CommandFailedEvent) { forwarded++ },
+	}))
+	monitor := client.commandMonitor()
+
+	failed := func(err error) *event.CommandFailedEvent {
+		return &event.CommandFailedEvent{
+			CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "count", DatabaseName: "shop"},
+			Failure:              err,
+		}
+	}
+	monitor.Failed(context.Background(), failed(mongo.CommandError{Code: 26}))
+	if len(logger.warnings) != 0 {
+		t.Errorf("Expected no warning for other failures, got %v", logger.warnings)
+	}
+
+	monitor.Failed(context.Background(), failed(apiStrictError))
+	if len(logger.warnings) != 1 {
+		t.Fatalf("Expected a warning for the strict-mode rejection, got %v", logger.warnings)
+	}
+	if forwarded != 2 {
+		t.Errorf("Expected both failures to reach the user monitor, got %d", forwarded)
+	}
+}
+
+func TestWithServerAPIVersion(t *testing.T) {
+	config := newDefaultConfig()
+	WithServerAPIVersion(string(options.ServerAPIVersion1), true)(config)
+
+	opts := (&Client{config: config}).buildClientOptions()
+	if opts.ServerAPIOptions == nil {
+		t.Fatal("Expected Stable API options to be set")
+	}
+	if opts.ServerAPIOptions.ServerAPIVersion != options.ServerAPIVersion1 {
+		t.Errorf("Expected API version 1, got %q", opts.ServerAPIOptions.ServerAPIVersion)
+	}
+	if opts.ServerAPIOptions.Strict == nil || !*opts.ServerAPIOptions.Strict {
+		t.Error("Expected strict mode")
+	}
+
+	// Disabled by default
+	if opts := (&Client{config: newDefaultConfig()}).buildClientOptions(); opts.ServerAPIOptions != nil {
+		t.Errorf("Expected no Stable API options by default, got %+v", opts.ServerAPIOptions)
+	}
+}
@@ -0,0 +1,115 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ErrShardKeyMissing is returned in strict shard key mode when a filter does not include
+// every shard key field, which would make mongos broadcast the operation to all shards
+var ErrShardKeyMissing = errors.New("filter does not include the shard key")
+
+// WithShardKey returns a collection handle that checks filters against the shard key of a
+// sharded collection. Operations whose filter does not include every shard key field are
+// broadcast by mongos to all shards; they are logged as a warning but still executed.
+// Use WithStrictShardKey to reject them instead. The original handle is left unchanged.
+//
+// Filters are checked on Find, FindOne, Update*, ReplaceOne, Delete*, CountDocuments,
+// Distinct and FindOneAnd* operations. A shard key field counts as included when it is a
+// top-level filter field or a field of a top-level $and condition.
+//
+// Example:
+//
+//	orders := client.Collection("orders").WithShardKey(bson.D{{Key: "tenant_id", Value: 1}})
+func (col *Collection) WithShardKey(key bson.D) *Collection {
+	clone := *col
+	clone.shardKey = key
+	clone.strictShardKey = false
+	return &clone
+}
+
+// WithStrictShardKey returns a collection handle like WithShardKey, except that operations
+// whose filter does not include the shard key fail with ErrShardKeyMissing without being sent
+func (col *Collection) WithStrictShardKey(key bson.D) *Collection {
+	clone := col.WithShardKey(key)
+	clone.strictShardKey = true
+	return clone
+}
+
+// ShardKey returns the shard key set with WithShardKey, or nil if none is set
+func (col *Collection) ShardKey() bson.D {
+	return col.shardKey
+}
+
+// checkShardKey warns about, or in strict mode rejects, filters that would be broadcast
+// to all shards because they do not include every shard key field
+func (col *Collection) checkShardKey(ctx context.Context, filterDoc bson.M, operation string) error {
+	if len(col.shardKey) == 0 {
+		return nil
+	}
+
+	missing := missingShardKeyFields(filterDoc, col.shardKey)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if col.strictShardKey {
+		col.logger(ctx).Error("Rejected operation without shard key",
+			"collection", col.name,
+			"operation", operation,
+			"missing", missing)
+		return fmt.Errorf("%w: %s is missing %v", ErrShardKeyMissing, operation, missing)
+	}
+
+	col.logger(ctx).Warn("Operation without shard key will be broadcast to all shards",
+		"collection", col.name,
+		"operation", operation,
+		"missing", missing)
+	return nil
+}
+
+// missingShardKeyFields returns the shard key fields that the filter does not constrain
+func missingShardKeyFields(filterDoc bson.M, key bson.D) []string {
+	var missing []string
+	for _, elem := range key {
+		if !filterHasField(filterDoc, elem.Key) {
+			missing = append(missing, elem.Key)
+		}
+	}
+	return missing
+}
+
+// filterHasField reports whether field is a top-level field of the filter or of one of its
+// top-level $and conditions. Fields under $or or $nor do not target a single shard.
+func filterHasField(filterDoc bson.M, field string) bool {
+	if _, ok := filterDoc[field]; ok {
+		return true
+	}
+
+	switch conditions := filterDoc["$and"].(type) {
+	case []bson.M:
+		for _, cond := range conditions {
+			if filterHasField(cond, field) {
+				return true
+			}
+		}
+	case bson.A:
+		for _, cond := range conditions {
+			if m, ok := cond.(bson.M); ok && filterHasField(m, field) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// errorFindOneResult returns a FindOneResult whose Decode and Err report err
+func errorFindOneResult(err error) *FindOneResult {
+	return &FindOneResult{
+		result: mongo.NewSingleResultFromDocument(bson.D{}, err, nil),
+	}
+}
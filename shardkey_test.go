@@ -0,0 +1,110 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/update"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// warnRecorder is a Logger that records warning messages
+type warnRecorder struct {
+	NopLogger
+	warnings []string
+}
+
+func (l *warnRecorder) Warn(msg string, fields ...any) {
+	l.warnings = append(l.warnings, msg)
+}
+
+func TestMissingShardKeyFields(t *testing.T) {
+	key := bson.D{{Key: "tenant_id", Value: 1}, {Key: "order_id", Value: 1}}
+
+	tests := []struct {
+		name    string
+		filter  *filter.Builder
+		missing []string
+	}{
+		{"full shard key", filter.Eq("tenant_id", "t1").And(filter.Eq("order_id", "o1")), nil},
+		{"shard key with extra fields", filter.Eq("tenant_id", "t1").And(filter.Eq("order_id", "o1"), filter.Eq("status", "open")), nil},
+		{"range on shard key", filter.Eq("tenant_id", "t1").And(filter.Gte("order_id", "o1")), nil},
+		{"prefix only", filter.Eq("tenant_id", "t1"), []string{"order_id"}},
+		{"no shard key", filter.Eq("status", "open"), []string{"tenant_id", "order_id"}},
+		{"shard key under $or", filter.Or(filter.Eq("tenant_id", "t1"), filter.Eq("order_id", "o1")), []string{"tenant_id", "order_id"}},
+		{"empty filter", filter.New(), []string{"tenant_id", "order_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missing := missingShardKeyFields(tt.filter.Build(), key)
+			if !slices.Equal(missing, tt.missing) {
+				t.Errorf("Expected missing %v, got %v", tt.missing, missing)
+			}
+		})
+	}
+
+	// Raw $and arrays are also inspected
+	raw := bson.M{"$and": bson.A{bson.M{"tenant_id": "t1"}, bson.M{"order_id": "o1"}}}
+	if missing := missingShardKeyFields(raw, key); len(missing) != 0 {
+		t.Errorf("Expected bson.A $and to include the shard key, missing %v", missing)
+	}
+}
+
+func TestStrictShardKeyRejectsBroadcast(t *testing.T) {
+	col := newTestCollection("events", withIDMode(IDModeULID)).WithStrictShardKey(bson.D{{Key: "tenant_id", Value: "hashed"}})
+	ctx := context.Background()
+
+	if _, err := col.Find(ctx, filter.Eq("status", "open")); !errors.Is(err, ErrShardKeyMissing) {
+		t.Errorf("Find: expected ErrShardKeyMissing, got %v", err)
+	}
+	if _, err := col.UpdateMany(ctx, nil, update.Set("status", "closed")); !errors.Is(err, ErrShardKeyMissing) {
+		t.Errorf("UpdateMany: expected ErrShardKeyMissing, got %v", err)
+	}
+	if _, err := col.DeleteOne(ctx, filter.Eq("_id", "o1")); !errors.Is(err, ErrShardKeyMissing) {
+		t.Errorf("DeleteOne: expected ErrShardKeyMissing, got %v", err)
+	}
+	if _, err := col.CountDocuments(ctx, nil); !errors.Is(err, ErrShardKeyMissing) {
+		t.Errorf("CountDocuments: expected ErrShardKeyMissing, got %v", err)
+	}
+
+	var doc bson.M
+	if err := col.FindOne(ctx, filter.Eq("_id", "o1")).Decode(&doc); !errors.Is(err, ErrShardKeyMissing) {
+		t.Errorf("FindOne: expected ErrShardKeyMissing, got %v", err)
+	}
+	if err := col.FindOneAndUpdate(ctx, filter.Eq("_id", "o1"), update.Set("status", "closed")).Err(); !errors.Is(err, ErrShardKeyMissing) {
+		t.Errorf("FindOneAndUpdate: expected ErrShardKeyMissing, got %v", err)
+	}
+
+	if err := col.checkShardKey(context.Background(), filter.Eq("tenant_id", "t1").Build(), "Find"); err != nil {
+		t.Errorf("Expected filter with shard key to pass, got %v", err)
+	}
+}
+
+func TestShardKeyWarnsOnBroadcast(t *testing.T) {
+	logger := &warnRecorder{}
+	base := newTestCollection("orders", WithLogger(logger))
+	col := base.WithShardKey(bson.D{{Key: "tenant_id", Value: 1}})
+
+	if err := col.checkShardKey(context.Background(), bson.M{"status": "open"}, "Find"); err != nil {
+		t.Errorf("Expected non-strict mode to allow broadcast, got %v", err)
+	}
+	if len(logger.warnings) != 1 {
+		t.Errorf("Expected one warning, got %v", logger.warnings)
+	}
+
+	if err := col.checkShardKey(context.Background(), bson.M{"tenant_id": "t1"}, "Find"); err != nil || len(logger.warnings) != 1 {
+		t.Errorf("Expected targeted filter without warning, got err=%v warnings=%v", err, logger.warnings)
+	}
+
+	// The original handle is unchanged
+	if base.ShardKey() != nil {
+		t.Errorf("Expected base handle without shard key, got %v", base.ShardKey())
+	}
+	if err := base.checkShardKey(context.Background(), bson.M{}, "Find"); err != nil || len(logger.warnings) != 1 {
+		t.Errorf("Expected no validation without shard key, got err=%v warnings=%v", err, logger.warnings)
+	}
+}
@@ -0,0 +1,118 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// AggregateSnapshot runs the pipeline and writes its output with $out into a collection of the
+// same database named after t, which builds rolling snapshot collections for periodic jobs.
+// Returns the name of the collection written.
+//
+// In namePattern, each {...} placeholder holds a Go time layout formatted with t, so
+// "metrics_{2006_01}" becomes "metrics_2024_01" for January 2024 and "daily_{2006-01-02}"
+// one collection per day. t is used as given; pass t.UTC() for names independent of the
+// local time zone. As with $out, an existing collection of the same name is replaced, so
+// running the job twice in one period overwrites that period's snapshot. A nil pipeline
+// snapshots the whole collection.
+//
+// Example:
+//
+//	p := pipeline.New().Group("$sensor", bson.M{"avg": bson.M{"$avg": "$value"}})
+//	name, err := readings.AggregateSnapshot(ctx, p, "metrics_{2006_01_02}", time.Now().UTC())
+func (col *Collection) AggregateSnapshot(ctx context.Context, pipelineBuilder *pipeline.Builder, namePattern string, t time.Time) (string, error) {
+	if err := col.checkWritable("AggregateSnapshot"); err != nil {
+		return "", err
+	}
+
+	name, err := snapshotCollectionName(namePattern, t)
+	if err != nil {
+		return "", err
+	}
+
+	pipelineDoc := bson.A{}
+	if pipelineBuilder != nil {
+		if err := pipelineBuilder.Validate(); err != nil {
+			return "", err
+		}
+		pipelineDoc = pipelineBuilder.ToBSONArray()
+	}
+	pipelineDoc, err = snapshotPipeline(pipelineDoc, name)
+	if err != nil {
+		return "", err
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	// $out runs with the aggregate command, the cursor is always empty
+	cursor, err := col.Aggregate(ctx, pipelineDoc)
+	if err != nil {
+		return "", err
+	}
+	_ = cursor.Close(ctx)
+
+	col.logger(ctx).Debug("Aggregation snapshot written",
+		"collection", col.name,
+		"snapshot", name)
+
+	return name, nil
+}
+
+// snapshotCollectionName formats the {...} time layouts of namePattern with t
+func snapshotCollectionName(namePattern string, t time.Time) (string, error) {
+	var name strings.Builder
+	rest := namePattern
+	layouts := 0
+	for {
+		start := strings.IndexAny(rest, "{}")
+		if start < 0 {
+			name.WriteString(rest)
+			break
+		}
+		if rest[start] == '}' {
+			return "", fmt.Errorf("snapshot name pattern %q has an unmatched }", namePattern)
+		}
+		end := strings.IndexAny(rest[start+1:], "{}")
+		if end < 0 || rest[start+1+end] == '{' {
+			return "", fmt.Errorf("snapshot name pattern %q has an unmatched {", namePattern)
+		}
+		layout := rest[start+1 : start+1+end]
+		if layout == "" {
+			return "", fmt.Errorf("snapshot name pattern %q has an empty {} placeholder", namePattern)
+		}
+		name.WriteString(rest[:start])
+		name.WriteString(t.Format(layout))
+		rest = rest[start+end+2:]
+		layouts++
+	}
+
+	// Without a time layout every run would overwrite the same collection
+	if layouts == 0 {
+		return "", fmt.Errorf("snapshot name pattern %q has no {...} time layout", namePattern)
+	}
+	result := name.String()
+	if strings.ContainsAny(result, "$\x00") || strings.HasPrefix(result, "system.") {
+		return "", fmt.Errorf("invalid snapshot collection name %q", result)
+	}
+	return result, nil
+}
+
+// snapshotPipeline appends the $out stage writing to the snapshot collection name
+func snapshotPipeline(stages bson.A, name string) (bson.A, error) {
+	if stage := writeStage(stages); stage != "" {
+		return nil, errors.New("snapshot pipeline must not contain a " + stage + " stage")
+	}
+	out := make(bson.A, 0, len(stages)+1)
+	out = append(out, stages...)
+	return append(out, bson.M{"$out": name}), nil
+}
@@ -0,0 +1,123 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestSnapshotCollectionName(t *testing.T) {
+	at := time.Date(2024, 1, 7, 15, 4, 0, 0, time.UTC)
+
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{"metrics_{2006_01}", "metrics_2024_01"},
+		{"daily_{2006-01-02}", "daily_2024-01-07"},
+		{"{2006}_orders_{01}", "2024_orders_01"},
+		{"hourly.{2006010215}", "hourly.2024010715"},
+		// Text outside placeholders is kept even if it looks like a layout
+		{"Jan_{2006}", "Jan_2024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			name, err := snapshotCollectionName(tt.pattern, at)
+			if err != nil {
+				t.Fatalf("snapshotCollectionName failed: %v", err)
+			}
+			if name != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, name)
+			}
+		})
+	}
+
+	for _, pattern := range []string{"metrics", "metrics_{2006", "metrics_2006}", "metrics_{}", "{2006{01}}", "$out_{2006}", "system.{2006}"} {
+		if name, err := snapshotCollectionName(pattern, at); err == nil {
+			t.Errorf("Expected an error for %q, got %q", pattern, name)
+		}
+	}
+}
+
+func TestSnapshotPipeline(t *testing.T) {
+	stages := pipeline.New().Match(nil).Group("$sensor", bson.M{"avg": bson.M{"$avg": "$value"}}).ToBSONArray()
+
+	got, err := snapshotPipeline(stages, "metrics_2024_01")
+	if err != nil {
+		t.Fatalf("snapshotPipeline failed: %v", err)
+	}
+	if len(got) != len(stages)+1 {
+		t.Fatalf("Expected %d stages, got %v", len(stages)+1, got)
+	}
+	if !reflect.DeepEqual(got[:len(stages)], stages) {
+		t.Errorf("Expected the pipeline stages first, got %v", got)
+	}
+	if last := got[len(got)-1]; !reflect.DeepEqual(last, bson.M{"$out": "metrics_2024_01"}) {
+		t.Errorf("Expected a final $out stage, got %v", last)
+	}
+
+	if _, err := snapshotPipeline(bson.A{bson.M{"$merge": "other"}}, "metrics_2024_01"); err == nil {
+		t.Error("Expected an error for a pipeline that already writes its output")
+	}
+}
+
+func TestAggregateSnapshotReadOnly(t *testing.T) {
+	col := newTestCollection("readings").ReadOnly()
+
+	if _, err := col.AggregateSnapshot(context.Background(), nil, "metrics_{2006_01}", time.Now()); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, got %v", err)
+	}
+}
+
+func TestAggregateSnapshotIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_snapshot_readings")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	_, err := col.InsertMany(ctx, []any{
+		bson.M{"sensor": "a", "value": 1},
+		bson.M{"sensor": "a", "value": 3},
+		bson.M{"sensor": "b", "value": 5},
+	})
+	if err != nil {
+		t.Fatalf("InsertMany failed: %v", err)
+	}
+
+	p := pipeline.New().Group("$sensor", bson.M{"avg": bson.M{"$avg": "$value"}})
+	name, err := col.AggregateSnapshot(ctx, p, "test_snapshot_{2006_01}", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("AggregateSnapshot failed: %v", err)
+	}
+	if name != "test_snapshot_2024_01" {
+		t.Errorf("Expected test_snapshot_2024_01, got %q", name)
+	}
+
+	snapshot := client.Collection(name)
+	defer func() {
+		_ = snapshot.Drop(ctx)
+	}()
+
+	count, err := snapshot.CountDocuments(ctx, nil)
+	if err != nil {
+		t.Fatalf("CountDocuments failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 snapshot documents, got %d", count)
+	}
+}
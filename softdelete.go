@@ -0,0 +1,303 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrSoftDeleteDisabled is returned by soft-delete helpers such as Restore and PurgeDeleted
+// when called on a collection handle that was not created with WithSoftDelete.
+var ErrSoftDeleteDisabled = errors.New("soft delete mode is not enabled on this collection")
+
+// WithSoftDelete returns a collection handle with soft-delete mode enabled on the given field.
+//
+// In soft-delete mode:
+//   - DeleteOne, DeleteMany, DeleteByID and FindOneAndDelete set {field: now} instead of
+//     removing documents; the collation, hint, comment and let options of the delete apply
+//     to the update
+//   - Find, FindOne and their variants, CountDocuments, Distinct and FindOneAnd* operations
+//     automatically exclude documents where the field is set
+//
+// Use FindIncludingDeleted to bypass the exclusion. Aggregations and operations performed via
+// Raw() are not filtered. The original collection handle is left unchanged.
+//
+// Example:
+//
+//	users := client.Collection("users").WithSoftDelete("deleted_at")
+//	_, err := users.DeleteByID(ctx, id) // sets deleted_at instead of removing
+func (col *Collection) WithSoftDelete(field string) *Collection {
+	clone := *col
+	clone.softDeleteField = field
+	return &clone
+}
+
+// SoftDeleteField returns the soft-delete field name, or an empty string if soft-delete mode is disabled
+func (col *Collection) SoftDeleteField() string {
+	return col.softDeleteField
+}
+
+// FindIncludingDeleted finds multiple documents like Find, but also returns soft-deleted documents
+func (col *Collection) FindIncludingDeleted(ctx context.Context, filterBuilder *filter.Builder, opts ...options.Lister[options.FindOptions]) (*FindResult, error) {
+	return col.withoutSoftDelete().Find(ctx, filterBuilder, opts...)
+}
+
+// withoutSoftDelete returns a copy of the collection handle with soft-delete mode disabled
+func (col *Collection) withoutSoftDelete() *Collection {
+	if col.softDeleteField == "" {
+		return col
+	}
+	clone := *col
+	clone.softDeleteField = ""
+	return &clone
+}
+
+// excludeSoftDeleted returns a filter that additionally excludes soft-deleted documents.
+// The input filter is never modified, since it may be shared by a reused filter builder.
+// Matching on null covers documents where the field is missing or explicitly null.
+func (col *Collection) excludeSoftDeleted(filterDoc bson.M) bson.M {
+	if col.softDeleteField == "" {
+		return filterDoc
+	}
+
+	notDeleted := bson.M{col.softDeleteField: nil}
+	if len(filterDoc) == 0 {
+		return notDeleted
+	}
+
+	// Avoid clobbering a caller condition on the same field
+	if _, exists := filterDoc[col.softDeleteField]; exists {
+		return bson.M{"$and": []bson.M{filterDoc, notDeleted}}
+	}
+
+	combined := make(bson.M, len(filterDoc)+1)
+	for k, v := range filterDoc {
+		combined[k] = v
+	}
+	combined[col.softDeleteField] = nil
+	return combined
+}
+
+// softDeleteOptions are the delete options that carry over to the soft-delete update
+type softDeleteOptions struct {
+	Collation *options.Collation
+	Comment   any
+	Hint      any
+	Let       any
+}
+
+// softDeleteUpdateOptions is implemented by the UpdateOne and UpdateMany option builders
+type softDeleteUpdateOptions[B any] interface {
+	SetCollation(c *options.Collation) B
+	SetComment(comment any) B
+	SetHint(h any) B
+	SetLet(l any) B
+}
+
+// withSoftDeleteOptions sets the delete options that carry over to the soft-delete update on
+// an update options builder
+func withSoftDeleteOptions[B softDeleteUpdateOptions[B]](updateOpts B, opts softDeleteOptions) B {
+	if opts.Collation != nil {
+		updateOpts.SetCollation(opts.Collation)
+	}
+	if opts.Comment != nil {
+		updateOpts.SetComment(opts.Comment)
+	}
+	if opts.Hint != nil {
+		updateOpts.SetHint(opts.Hint)
+	}
+	if opts.Let != nil {
+		updateOpts.SetLet(opts.Let)
+	}
+	return updateOpts
+}
+
+// resolveOptions applies option builders to a zero options struct
+func resolveOptions[T any](opts []options.Lister[T]) (*T, error) {
+	resolved := new(T)
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		for _, apply := range opt.List() {
+			if err := apply(resolved); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// softDelete marks matching documents as deleted by setting the soft-delete field to the current time
+func (col *Collection) softDelete(ctx context.Context, filterDoc bson.M, many bool, opts softDeleteOptions) (*DeleteResult, error) {
+	filterDoc = col.excludeSoftDeleted(filterDoc)
+	updateDoc := col.softDeleteUpdate()
+
+	start := time.Now()
+	var result *mongo.UpdateResult
+	var err error
+	if many {
+		result, err = col.collection.UpdateMany(ctx, filterDoc, updateDoc, withSoftDeleteOptions(options.UpdateMany(), opts))
+	} else {
+		result, err = col.collection.UpdateOne(ctx, filterDoc, updateDoc, withSoftDeleteOptions(options.UpdateOne(), opts))
+	}
+	if err != nil {
+		col.client.incrementFailureCount()
+		col.errorLogger(ctx, "filter", filterDoc).Error("Failed to soft delete documents",
+			"error", err.Error(),
+			"collection", col.name)
+		return nil, err
+	}
+	col.client.incrementOperationCount()
+	modified := result.ModifiedCount
+
+	col.logger(ctx).Debug("Documents soft deleted successfully",
+		"collection", col.name,
+		"field", col.softDeleteField,
+		"deleted", int(modified))
+
+	return &DeleteResult{
+		DeletedCount: modified,
+		Duration:     time.Since(start),
+	}, nil
+}
+
+// softDeleteUpdate returns the update that marks documents as deleted now
+func (col *Collection) softDeleteUpdate() bson.M {
+	return bson.M{"$set": bson.M{col.softDeleteField: time.Now()}}
+}
+
+// Restore undeletes soft-deleted documents matching the filter by unsetting the soft-delete field.
+// Only documents that are currently soft-deleted are matched.
+// Returns ErrSoftDeleteDisabled if the collection is not in soft-delete mode.
+func (col *Collection) Restore(ctx context.Context, filterBuilder *filter.Builder) (*UpdateResult, error) {
+	if err := col.checkWritable("Restore"); err != nil {
+		return nil, err
+	}
+
+	if col.softDeleteField == "" {
+		return nil, ErrSoftDeleteDisabled
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	// Build filter document
+	filterDoc := bson.M{}
+	if filterBuilder != nil {
+		filterDoc = filterBuilder.Build()
+	}
+	filterDoc = col.onlySoftDeleted(filterDoc, bson.M{"$ne": nil})
+
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	result, err := col.collection.UpdateMany(ctx, filterDoc, bson.M{"$unset": bson.M{col.softDeleteField: ""}})
+	if err != nil {
+		col.errorLogger(ctx, "filter", filterDoc).Error("Failed to restore documents",
+			"error", err.Error(),
+			"collection", col.name)
+		return nil, err
+	}
+
+	col.logger(ctx).Debug("Documents restored successfully",
+		"collection", col.name,
+		"restored", int(result.ModifiedCount))
+
+	return &UpdateResult{
+		MatchedCount:  result.MatchedCount,
+		ModifiedCount: result.ModifiedCount,
+		UpsertedCount: result.UpsertedCount,
+		UpsertedID:    result.UpsertedID,
+		Duration:      time.Since(start),
+	}, nil
+}
+
+// PurgeDeleted permanently removes documents that were soft-deleted strictly before olderThan.
+// This is intended for retention jobs, e.g. PurgeDeleted(ctx, time.Now().AddDate(0, 0, -30)).
+// Returns ErrSoftDeleteDisabled if the collection is not in soft-delete mode.
+func (col *Collection) PurgeDeleted(ctx context.Context, olderThan time.Time) (*DeleteResult, error) {
+	if err := col.checkWritable("PurgeDeleted"); err != nil {
+		return nil, err
+	}
+
+	if col.softDeleteField == "" {
+		return nil, ErrSoftDeleteDisabled
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	filterDoc := col.onlySoftDeleted(bson.M{}, bson.M{"$lt": olderThan})
+
+	if err := col.checkConnected(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	result, err := col.collection.DeleteMany(ctx, filterDoc)
+	if err != nil {
+		col.errorLogger(ctx, "filter", filterDoc).Error("Failed to purge deleted documents",
+			"error", err.Error(),
+			"collection", col.name)
+		return nil, err
+	}
+
+	col.logger(ctx).Info("Soft-deleted documents purged",
+		"collection", col.name,
+		"older_than", olderThan,
+		"purged", int(result.DeletedCount))
+
+	return &DeleteResult{
+		DeletedCount: result.DeletedCount,
+		Duration:     time.Since(start),
+	}, nil
+}
+
+// onlySoftDeleted returns a filter that additionally requires the soft-delete field to match condition.
+// The input filter is never modified.
+func (col *Collection) onlySoftDeleted(filterDoc bson.M, condition bson.M) bson.M {
+	deleted := bson.M{col.softDeleteField: condition}
+	if len(filterDoc) == 0 {
+		return deleted
+	}
+	return bson.M{"$and": []bson.M{filterDoc, deleted}}
+}
+
+// softFindOneAndDelete marks the first document matching filterDoc as deleted and returns it
+// as it was before, carrying over the sort and projection of the delete
+func (col *Collection) softFindOneAndDelete(ctx context.Context, filterDoc bson.M, deleteOpts *options.FindOneAndDeleteOptionsBuilder) *FindOneResult {
+	resolved, err := resolveOptions([]options.Lister[options.FindOneAndDeleteOptions]{deleteOpts})
+	if err != nil {
+		return errorFindOneResult(err)
+	}
+
+	updateOpts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	if resolved.Sort != nil {
+		updateOpts.SetSort(resolved.Sort)
+	}
+	if resolved.Projection != nil {
+		updateOpts.SetProjection(resolved.Projection)
+	}
+
+	result := col.collection.FindOneAndUpdate(ctx, filterDoc, col.softDeleteUpdate(), updateOpts)
+
+	col.client.incrementOperationCount()
+
+	return &FindOneResult{
+		result: result,
+	}
+}
@@ -0,0 +1,312 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/update"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestExcludeSoftDeleted(t *testing.T) {
+	col := newTestCollection("users").WithSoftDelete("deleted_at")
+
+	tests := []struct {
+		name     string
+		filter   bson.M
+		expected bson.M
+	}{
+		{
+			name:     "Empty filter",
+			filter:   bson.M{},
+			expected: bson.M{"deleted_at": nil},
+		},
+		{
+			name:     "Filter on other fields",
+			filter:   bson.M{"status": "active"},
+			expected: bson.M{"status": "active", "deleted_at": nil},
+		},
+		{
+			name:   "Filter on the soft-delete field",
+			filter: bson.M{"deleted_at": bson.M{"$lt": 5}},
+			expected: bson.M{"$and": []bson.M{
+				{"deleted_at": bson.M{"$lt": 5}},
+				{"deleted_at": nil},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := bson.M{}
+			for k, v := range tt.filter {
+				original[k] = v
+			}
+
+			result := col.excludeSoftDeleted(tt.filter)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+			if !reflect.DeepEqual(tt.filter, original) {
+				t.Errorf("Input filter was mutated: %v", tt.filter)
+			}
+		})
+	}
+
+	// Collections without soft-delete mode pass filters through untouched
+	plain := newTestCollection("users")
+	f := bson.M{"status": "active"}
+	if result := plain.excludeSoftDeleted(f); !reflect.DeepEqual(result, f) {
+		t.Errorf("Expected filter to be unchanged, got %v", result)
+	}
+	if plain.SoftDeleteField() != "" {
+		t.Error("WithSoftDelete should not modify the original collection")
+	}
+}
+
+func TestSoftDeleteIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	base := client.Collection("test_soft_delete")
+	_, _ = base.DeleteMany(ctx, nil)
+	defer func() {
+		_, _ = base.DeleteMany(ctx, nil)
+	}()
+
+	col := base.WithSoftDelete("deleted_at")
+
+	_, err := col.InsertMany(ctx, []any{
+		bson.M{"_id": "u1", "name": "Alice"},
+		bson.M{"_id": "u2", "name": "Bob"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert documents: %v", err)
+	}
+
+	result, err := col.DeleteByID(ctx, "u1")
+	if err != nil {
+		t.Fatalf("Soft delete failed: %v", err)
+	}
+	if result.DeletedCount != 1 {
+		t.Errorf("Expected 1 soft-deleted document, got %d", result.DeletedCount)
+	}
+
+	// Soft-deleted documents are hidden by default
+	count, err := col.CountDocuments(ctx, nil)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 visible document, got %d", count)
+	}
+	if err := col.FindByID(ctx, "u1").Err(); !IsNotFoundError(err) {
+		t.Errorf("Expected soft-deleted document to be hidden, got err=%v", err)
+	}
+
+	// But still present and visible when explicitly requested
+	cursor, err := col.FindIncludingDeleted(ctx, nil)
+	if err != nil {
+		t.Fatalf("FindIncludingDeleted failed: %v", err)
+	}
+	var all []bson.M
+	if err := cursor.All(ctx, &all); err != nil {
+		t.Fatalf("Failed to decode documents: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 documents including deleted, got %d", len(all))
+	}
+
+	// Clearing the field restores the document
+	if _, err := base.UpdateOne(ctx, filter.Eq("_id", "u1"), update.Unset("deleted_at")); err != nil {
+		t.Fatalf("Failed to restore document: %v", err)
+	}
+	if err := col.FindByID(ctx, "u1").Err(); err != nil {
+		t.Errorf("Expected restored document to be visible, got err=%v", err)
+	}
+}
+
+func TestResolveOptions(t *testing.T) {
+	collation := &options.Collation{Locale: "en", Strength: 2}
+	resolved, err := resolveOptions([]options.Lister[options.DeleteOneOptions]{
+		options.DeleteOne().SetCollation(collation),
+		nil,
+		options.DeleteOne().SetHint("name_1"),
+	})
+	if err != nil {
+		t.Fatalf("resolveOptions failed: %v", err)
+	}
+	if resolved.Collation != collation || resolved.Hint != "name_1" {
+		t.Errorf("Expected collation and hint to be resolved, got %+v", resolved)
+	}
+}
+
+func TestWithSoftDeleteOptions(t *testing.T) {
+	collation := &options.Collation{Locale: "en", Strength: 2}
+	opts := softDeleteOptions{Collation: collation, Comment: "cleanup", Let: bson.M{"cutoff": 10}}
+
+	one, err := resolveOptions([]options.Lister[options.UpdateOneOptions]{withSoftDeleteOptions(options.UpdateOne(), opts)})
+	if err != nil {
+		t.Fatalf("resolveOptions failed: %v", err)
+	}
+	many, err := resolveOptions([]options.Lister[options.UpdateManyOptions]{withSoftDeleteOptions(options.UpdateMany(), opts)})
+	if err != nil {
+		t.Fatalf("resolveOptions failed: %v", err)
+	}
+
+	if one.Collation != collation || one.Comment != "cleanup" || one.Let == nil || one.Hint != nil {
+		t.Errorf("Expected the delete options on UpdateOne, got %+v", one)
+	}
+	if many.Collation != collation || many.Comment != "cleanup" || many.Let == nil || many.Hint != nil {
+		t.Errorf("Expected the delete options on UpdateMany, got %+v", many)
+	}
+}
+
+func TestSoftDeleteOptionsIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	base := client.Collection("test_soft_delete_options")
+	_, _ = base.DeleteMany(ctx, nil)
+	defer func() {
+		_, _ = base.DeleteMany(ctx, nil)
+	}()
+
+	col := base.WithSoftDelete("deleted_at")
+	_, err := base.InsertMany(ctx, []any{
+		bson.M{"_id": "u1", "name": "Alice", "rank": 1},
+		bson.M{"_id": "u2", "name": "Bob", "rank": 2},
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert documents: %v", err)
+	}
+
+	// The case-insensitive collation of the delete applies to the soft-delete update
+	caseInsensitive := options.DeleteOne().SetCollation(&options.Collation{Locale: "en", Strength: 2})
+	result, err := col.DeleteOne(ctx, filter.Eq("name", "alice"), caseInsensitive)
+	if err != nil {
+		t.Fatalf("DeleteOne failed: %v", err)
+	}
+	if result.DeletedCount != 1 {
+		t.Errorf("Expected the collation to match Alice, got %d deleted", result.DeletedCount)
+	}
+
+	// FindOneAndDelete marks the document instead of removing it
+	var deleted bson.M
+	if err := col.FindOneAndDelete(ctx, nil, FindOneAndDeleteOpts().SetSort(bson.D{{Key: "rank", Value: -1}})).Decode(&deleted); err != nil {
+		t.Fatalf("FindOneAndDelete failed: %v", err)
+	}
+	if deleted["_id"] != "u2" || deleted["deleted_at"] != nil {
+		t.Errorf("Expected the document before deletion, got %v", deleted)
+	}
+	if count, _ := col.CountDocuments(ctx, nil); count != 0 {
+		t.Errorf("Expected no visible documents, got %d", count)
+	}
+	if count, _ := base.CountDocuments(ctx, nil); count != 2 {
+		t.Errorf("Expected both documents to be kept, got %d", count)
+	}
+}
+
+func TestSoftDeleteHelpersRequireMode(t *testing.T) {
+	col := newTestCollection("users")
+
+	if _, err := col.Restore(context.Background(), nil); !errors.Is(err, ErrSoftDeleteDisabled) {
+		t.Errorf("Expected ErrSoftDeleteDisabled from Restore, got %v", err)
+	}
+	if _, err := col.PurgeDeleted(context.Background(), time.Now()); !errors.Is(err, ErrSoftDeleteDisabled) {
+		t.Errorf("Expected ErrSoftDeleteDisabled from PurgeDeleted, got %v", err)
+	}
+}
+
+func TestOnlySoftDeleted(t *testing.T) {
+	col := newTestCollection("users").WithSoftDelete("deleted_at")
+	cutoff := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	purge := col.onlySoftDeleted(bson.M{}, bson.M{"$lt": cutoff})
+	expected := bson.M{"deleted_at": bson.M{"$lt": cutoff}}
+	if !reflect.DeepEqual(purge, expected) {
+		t.Errorf("Expected %v, got %v", expected, purge)
+	}
+
+	restore := col.onlySoftDeleted(bson.M{"_id": "u1"}, bson.M{"$ne": nil})
+	expected = bson.M{"$and": []bson.M{{"_id": "u1"}, {"deleted_at": bson.M{"$ne": nil}}}}
+	if !reflect.DeepEqual(restore, expected) {
+		t.Errorf("Expected %v, got %v", expected, restore)
+	}
+}
+
+func TestRestoreAndPurgeIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	base := client.Collection("test_soft_delete_restore")
+	_, _ = base.DeleteMany(ctx, nil)
+	defer func() {
+		_, _ = base.DeleteMany(ctx, nil)
+	}()
+
+	col := base.WithSoftDelete("deleted_at")
+	cutoff := time.Now().Add(-24 * time.Hour).Truncate(time.Millisecond)
+
+	_, err := base.InsertMany(ctx, []any{
+		bson.M{"_id": "old", "deleted_at": cutoff.Add(-time.Hour)},
+		bson.M{"_id": "boundary", "deleted_at": cutoff},
+		bson.M{"_id": "recent", "deleted_at": time.Now()},
+		bson.M{"_id": "live"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert documents: %v", err)
+	}
+
+	// Purge only removes documents deleted strictly before the cutoff
+	purged, err := col.PurgeDeleted(ctx, cutoff)
+	if err != nil {
+		t.Fatalf("PurgeDeleted failed: %v", err)
+	}
+	if purged.DeletedCount != 1 {
+		t.Errorf("Expected 1 purged document, got %d", purged.DeletedCount)
+	}
+	if err := base.FindByID(ctx, "boundary").Err(); err != nil {
+		t.Errorf("Expected document deleted at the cutoff to survive the purge, got err=%v", err)
+	}
+
+	// Restore only touches soft-deleted documents
+	restored, err := col.Restore(ctx, filter.In("_id", "recent", "live"))
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if restored.ModifiedCount != 1 {
+		t.Errorf("Expected 1 restored document, got %d", restored.ModifiedCount)
+	}
+	if err := col.FindByID(ctx, "recent").Err(); err != nil {
+		t.Errorf("Expected restored document to be visible, got err=%v", err)
+	}
+
+	count, err := col.CountDocuments(ctx, nil)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 visible documents after restore, got %d", count)
+	}
+}
@@ -0,0 +1,134 @@
+// Package sse streams MongoDB query and aggregation results to HTTP clients as Server-Sent
+// Events, for dashboards that consume them with the browser EventSource API.
+//
+// Each document is sent as one event whose data is the document encoded as relaxed
+// Extended JSON. When all results have been sent, a final "end" event carries the number of
+// documents streamed, so clients can close the EventSource instead of letting it reconnect.
+// If reading results fails midway, an "error" event carries the error message.
+//
+// Example:
+//
+//	http.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
+//		p := pipeline.New().Group("$status", bson.M{"count": bson.M{"$sum": 1}})
+//		if err := sse.Aggregate(r.Context(), orders, p, w); err != nil {
+//			log.Printf("stats stream failed: %v", err)
+//		}
+//	})
+package sse
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	mongodb "github.com/cloudresty/go-mongodb/v2"
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Event names used besides the default "message" event of each result
+const (
+	EventEnd   = "end"
+	EventError = "error"
+)
+
+// Results is a cursor over raw documents, implemented by *mongodb.AggregateResult and
+// *mongodb.FindResult
+type Results interface {
+	Next(ctx context.Context) bool
+	Current() bson.Raw
+	Err() error
+	Close(ctx context.Context) error
+}
+
+var (
+	_ Results = (*mongodb.AggregateResult)(nil)
+	_ Results = (*mongodb.FindResult)(nil)
+)
+
+// Aggregate runs the pipeline on the collection and streams each result to w as a
+// Server-Sent Event. If the aggregation cannot be started, nothing is written to w and the
+// error is returned so the handler can still send an HTTP error response.
+func Aggregate(ctx context.Context, col *mongodb.Collection, pipelineBuilder *pipeline.Builder, w http.ResponseWriter) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	results, err := col.AggregateWithPipeline(ctx, pipelineBuilder)
+	if err != nil {
+		return err
+	}
+
+	return Stream(ctx, results, w)
+}
+
+// Stream writes each document of results to w as a Server-Sent Event, flushing after every
+// event, and closes results when done. It stops early when ctx is cancelled, which happens
+// when the HTTP client disconnects if ctx is the request context.
+func Stream(ctx context.Context, results Results, w http.ResponseWriter) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	defer func() {
+		_ = results.Close(ctx)
+	}()
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	send := func(event, data string) error {
+		if err := writeEvent(w, event, data); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	count := 0
+	for results.Next(ctx) {
+		data, err := bson.MarshalExtJSON(results.Current(), false, false)
+		if err != nil {
+			err = fmt.Errorf("failed to encode result: %w", err)
+			_ = send(EventError, err.Error())
+			return err
+		}
+		if err := send("", string(data)); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := results.Err(); err != nil {
+		_ = send(EventError, err.Error())
+		return err
+	}
+
+	return send(EventEnd, strconv.Itoa(count))
+}
+
+// writeEvent writes a single event; an empty event name uses the default "message" type.
+// Multi-line data is split across data fields as required by the SSE format.
+func writeEvent(w http.ResponseWriter, event, data string) error {
+	var b strings.Builder
+	if event != "" {
+		b.WriteString("event: ")
+		b.WriteString(event)
+		b.WriteByte('\n')
+	}
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: ")
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+
+	_, err := w.Write([]byte(b.String()))
+	return err
+}
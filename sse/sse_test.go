@@ -0,0 +1,105 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// sliceResults is a Results implementation over in-memory documents
+type sliceResults struct {
+	docs   []bson.Raw
+	pos    int
+	err    error
+	closed bool
+}
+
+func newSliceResults(t *testing.T, docs ...any) *sliceResults {
+	t.Helper()
+	results := &sliceResults{pos: -1}
+	for _, doc := range docs {
+		data, err := bson.Marshal(doc)
+		if err != nil {
+			t.Fatalf("Failed to marshal document: %v", err)
+		}
+		results.docs = append(results.docs, data)
+	}
+	return results
+}
+
+func (r *sliceResults) Next(ctx context.Context) bool {
+	r.pos++
+	return r.pos < len(r.docs)
+}
+
+func (r *sliceResults) Current() bson.Raw { return r.docs[r.pos] }
+
+func (r *sliceResults) Err() error { return r.err }
+
+func (r *sliceResults) Close(ctx context.Context) error {
+	r.closed = true
+	return nil
+}
+
+func TestStreamEventFormat(t *testing.T) {
+	results := newSliceResults(t,
+		bson.D{{Key: "_id", Value: "open"}, {Key: "count", Value: int32(12)}},
+		bson.D{{Key: "_id", Value: "closed"}, {Key: "count", Value: int32(3)}},
+	)
+	recorder := httptest.NewRecorder()
+
+	if err := Stream(context.Background(), results, recorder); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	expected := "data: {\"_id\":\"open\",\"count\":12}\n\n" +
+		"data: {\"_id\":\"closed\",\"count\":3}\n\n" +
+		"event: end\ndata: 2\n\n"
+	if body := recorder.Body.String(); body != expected {
+		t.Errorf("Unexpected event stream:\n%q\nexpected:\n%q", body, expected)
+	}
+
+	if ct := recorder.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected text/event-stream content type, got %q", ct)
+	}
+	if cc := recorder.Header().Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("Expected no-cache, got %q", cc)
+	}
+	if !recorder.Flushed {
+		t.Error("Expected events to be flushed")
+	}
+	if !results.closed {
+		t.Error("Expected results to be closed")
+	}
+}
+
+func TestStreamReportsCursorError(t *testing.T) {
+	results := newSliceResults(t, bson.D{{Key: "n", Value: int32(1)}})
+	results.err = errors.New("cursor killed")
+	recorder := httptest.NewRecorder()
+
+	if err := Stream(context.Background(), results, recorder); err == nil || err.Error() != "cursor killed" {
+		t.Fatalf("Expected cursor error, got %v", err)
+	}
+
+	expected := "data: {\"n\":1}\n\n" +
+		"event: error\ndata: cursor killed\n\n"
+	if body := recorder.Body.String(); body != expected {
+		t.Errorf("Unexpected event stream:\n%q\nexpected:\n%q", body, expected)
+	}
+}
+
+func TestWriteEventSplitsMultilineData(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	if err := writeEvent(recorder, "error", "first\nsecond"); err != nil {
+		t.Fatalf("writeEvent failed: %v", err)
+	}
+
+	expected := "event: error\ndata: first\ndata: second\n\n"
+	if body := recorder.Body.String(); body != expected {
+		t.Errorf("Expected %q, got %q", expected, body)
+	}
+}
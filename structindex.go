@@ -0,0 +1,258 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrIndexConflict is returned by EnsureIndex when an index on the same keys already exists
+// but differs in uniqueness, so it cannot be reused and the server would reject creating it
+var ErrIndexConflict = errors.New("index exists with different options")
+
+// EnsureIndex creates the index unless an index on the same keys already exists, in which
+// case the name of the existing index is returned. Unlike CreateIndex it does not fail when
+// the existing index has a different name, so it is safe to call on every startup. Only the
+// keys and the unique option are compared; other options of an existing index are left as
+// they are.
+func (col *Collection) EnsureIndex(ctx context.Context, model IndexModel) (string, error) {
+	if err := col.checkWritable("EnsureIndex"); err != nil {
+		return "", err
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	if err := col.checkConnected(); err != nil {
+		return "", err
+	}
+
+	cursor, err := col.collection.Indexes().List(ctx)
+	if err != nil {
+		col.logger(ctx).Error("Failed to list indexes",
+			"error", err.Error(),
+			"collection", col.name)
+		return "", err
+	}
+
+	var existing []struct {
+		Name   string `bson:"name"`
+		Key    bson.D `bson:"key"`
+		Unique bool   `bson:"unique"`
+	}
+	if err := cursor.All(ctx, &existing); err != nil {
+		return "", fmt.Errorf("failed to decode indexes: %w", err)
+	}
+
+	unique, err := indexIsUnique(model)
+	if err != nil {
+		return "", err
+	}
+
+	for _, index := range existing {
+		if !sameIndexKeys(index.Key, model.Keys) {
+			continue
+		}
+		if index.Unique != unique {
+			return "", fmt.Errorf("index %s on collection %s (unique=%v): %w", index.Name, col.name, index.Unique, ErrIndexConflict)
+		}
+
+		col.logger(ctx).Debug("Index already exists",
+			"collection", col.name,
+			"index", index.Name)
+		return index.Name, nil
+	}
+
+	return col.CreateIndex(ctx, model)
+}
+
+// EnsureIndexesFromStruct creates the indexes declared with `index` tags on the fields of a
+// struct, so index definitions live next to the model. v is a struct or a pointer to one; its
+// value is not used. Each tagged field gets a single-field index on its bson field name, with
+// comma-separated tag options:
+//
+//   - asc (default) or desc: index direction
+//   - unique: unique index
+//   - sparse: only index documents that have the field
+//
+// Fields of embedded structs tagged `bson:",inline"` are included. Indexes are created with
+// EnsureIndex, so calling this on every startup is safe. The names of the indexes are returned
+// in field order.
+//
+// Example:
+//
+//	type User struct {
+//	    ID        string    `bson:"_id"`
+//	    Email     string    `bson:"email" index:"unique"`
+//	    CreatedAt time.Time `bson:"created_at" index:"desc"`
+//	}
+//
+//	names, err := users.EnsureIndexesFromStruct(ctx, User{})
+func (col *Collection) EnsureIndexesFromStruct(ctx context.Context, v any) ([]string, error) {
+	models, err := indexModelsFromStruct(v)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(models))
+	for _, model := range models {
+		name, err := col.EnsureIndex(ctx, model)
+		if err != nil {
+			return names, err
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// indexModelsFromStruct builds the index models declared by the `index` tags of a struct type
+func indexModelsFromStruct(v any) ([]IndexModel, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("index tags require a struct, got %T", v)
+	}
+
+	var models []IndexModel
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, flags, _ := strings.Cut(field.Tag.Get("bson"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if strings.Contains(","+flags+",", ",inline,") {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				nested, err := indexModelsFromStruct(reflect.Zero(fieldType).Interface())
+				if err != nil {
+					return nil, err
+				}
+				models = append(models, nested...)
+				continue
+			}
+		}
+
+		tag, ok := field.Tag.Lookup("index")
+		if !ok {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		model, err := indexModelFromTag(name, tag)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		models = append(models, model)
+	}
+
+	return models, nil
+}
+
+// indexModelFromTag builds the index model for a field from its `index` tag options
+func indexModelFromTag(field, tag string) (IndexModel, error) {
+	direction := 1
+	var unique, sparse bool
+
+	for option := range strings.SplitSeq(tag, ",") {
+		switch strings.TrimSpace(option) {
+		case "", "asc":
+		case "desc":
+			direction = -1
+		case "unique":
+			unique = true
+		case "sparse":
+			sparse = true
+		default:
+			return IndexModel{}, fmt.Errorf("unknown index tag option %q", option)
+		}
+	}
+
+	model := IndexModel{Keys: bson.D{{Key: field, Value: direction}}}
+	if unique || sparse {
+		model.Options = options.Index()
+		if unique {
+			model.Options.SetUnique(true)
+		}
+		if sparse {
+			model.Options.SetSparse(true)
+		}
+	}
+	return model, nil
+}
+
+// indexIsUnique reports whether the options of an index model request a unique index
+func indexIsUnique(model IndexModel) (bool, error) {
+	if model.Options == nil {
+		return false, nil
+	}
+	resolved := &options.IndexOptions{}
+	for _, apply := range model.Options.List() {
+		if err := apply(resolved); err != nil {
+			return false, err
+		}
+	}
+	return resolved.Unique != nil && *resolved.Unique, nil
+}
+
+// sameIndexKeys reports whether two index key documents have the same fields in the same
+// order with the same index types, treating numeric directions of any BSON number type as equal
+func sameIndexKeys(a, b bson.D) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Key != b[i].Key {
+			return false
+		}
+		x, xNumeric := indexDirection(a[i].Value)
+		y, yNumeric := indexDirection(b[i].Value)
+		if xNumeric != yNumeric {
+			return false
+		}
+		if xNumeric {
+			if x != y {
+				return false
+			}
+		} else if !reflect.DeepEqual(a[i].Value, b[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// indexDirection returns a numeric index key value as float64
+func indexDirection(value any) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,136 @@
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type indexedAudit struct {
+	CreatedBy string `bson:"created_by" index:"asc"`
+}
+
+type indexedUser struct {
+	ID        string       `bson:"_id"`
+	Email     string       `bson:"email" index:"unique"`
+	Username  string       `bson:"username,omitempty" index:"unique,sparse"`
+	CreatedAt time.Time    `bson:"created_at" index:"desc"`
+	Country   string       `index:""`
+	Ignored   string       `bson:"-" index:"asc"`
+	Notes     string       `bson:"notes"`
+	Audit     indexedAudit `bson:",inline"`
+}
+
+func TestIndexModelsFromStruct(t *testing.T) {
+	models, err := indexModelsFromStruct(&indexedUser{})
+	if err != nil {
+		t.Fatalf("indexModelsFromStruct failed: %v", err)
+	}
+
+	expectedKeys := []bson.D{
+		{{Key: "email", Value: 1}},
+		{{Key: "username", Value: 1}},
+		{{Key: "created_at", Value: -1}},
+		{{Key: "country", Value: 1}},
+		{{Key: "created_by", Value: 1}},
+	}
+	if len(models) != len(expectedKeys) {
+		t.Fatalf("Expected %d index models, got %d: %v", len(expectedKeys), len(models), models)
+	}
+	for i, model := range models {
+		if !reflect.DeepEqual(model.Keys, expectedKeys[i]) {
+			t.Errorf("Model %d: expected keys %v, got %v", i, expectedKeys[i], model.Keys)
+		}
+	}
+
+	email := resolveIndexOptions(t, models[0])
+	if email.Unique == nil || !*email.Unique || email.Sparse != nil {
+		t.Errorf("Expected a unique, non-sparse email index, got %+v", email)
+	}
+	username := resolveIndexOptions(t, models[1])
+	if username.Unique == nil || !*username.Unique || username.Sparse == nil || !*username.Sparse {
+		t.Errorf("Expected a unique sparse username index, got %+v", username)
+	}
+	if models[2].Options != nil {
+		t.Error("Expected no options for a plain descending index")
+	}
+}
+
+func TestIndexModelsFromStructErrors(t *testing.T) {
+	if _, err := indexModelsFromStruct("users"); err == nil {
+		t.Error("Expected an error for a non-struct value")
+	}
+
+	type badTag struct {
+		Email string `bson:"email" index:"uniq"`
+	}
+	_, err := indexModelsFromStruct(badTag{})
+	if err == nil || !strings.Contains(err.Error(), "Email") || !strings.Contains(err.Error(), "uniq") {
+		t.Errorf("Expected an error naming the field and option, got %v", err)
+	}
+}
+
+func TestSameIndexKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     bson.D
+		expected bool
+	}{
+		{"Server int32 matches int", bson.D{{Key: "email", Value: int32(1)}}, bson.D{{Key: "email", Value: 1}}, true},
+		{"Double direction", bson.D{{Key: "at", Value: -1.0}}, bson.D{{Key: "at", Value: -1}}, true},
+		{"Different direction", bson.D{{Key: "at", Value: int32(1)}}, bson.D{{Key: "at", Value: -1}}, false},
+		{"Different order", bson.D{{Key: "a", Value: 1}, {Key: "b", Value: 1}}, bson.D{{Key: "b", Value: 1}, {Key: "a", Value: 1}}, false},
+		{"Prefix only", bson.D{{Key: "a", Value: 1}}, bson.D{{Key: "a", Value: 1}, {Key: "b", Value: 1}}, false},
+		{"Index type", bson.D{{Key: "loc", Value: "2dsphere"}}, bson.D{{Key: "loc", Value: "2dsphere"}}, true},
+		{"Index type vs direction", bson.D{{Key: "id", Value: "hashed"}}, bson.D{{Key: "id", Value: 1}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameIndexKeys(tt.a, tt.b); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestEnsureIndexesFromStructIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		if err := client.Close(); err != nil {
+			t.Logf("Failed to close client: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	collection := client.Collection("test_struct_indexes")
+	_ = collection.Drop(ctx)
+	defer func() { _ = collection.Drop(ctx) }()
+
+	// An existing index on the same keys is reused even with a different name
+	existing, err := collection.CreateIndex(ctx, IndexWithName("by_created_at", IndexDesc("created_at")))
+	if err != nil {
+		t.Fatalf("CreateIndex failed: %v", err)
+	}
+
+	names, err := collection.EnsureIndexesFromStruct(ctx, indexedUser{})
+	if err != nil {
+		t.Fatalf("EnsureIndexesFromStruct failed: %v", err)
+	}
+	if len(names) != 5 || names[2] != existing {
+		t.Errorf("Expected 5 indexes reusing %s, got %v", existing, names)
+	}
+
+	// A second call is a no-op
+	again, err := collection.EnsureIndexesFromStruct(ctx, indexedUser{})
+	if err != nil {
+		t.Fatalf("Second EnsureIndexesFromStruct failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, again) {
+		t.Errorf("Expected the same index names, got %v and %v", names, again)
+	}
+}
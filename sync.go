@@ -0,0 +1,248 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// SyncResult reports the outcome of SyncReplace
+type SyncResult struct {
+	Inserted  int64         `json:"inserted"`
+	Updated   int64         `json:"updated"`
+	Deleted   int64         `json:"deleted"`
+	Unchanged int64         `json:"unchanged"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// syncDocument is a desired document prepared for diffing
+type syncDocument struct {
+	original any
+	key      bson.RawValue
+	content  bson.D // document without _id
+	encoded  []byte // content marshalled, for comparison
+}
+
+// SyncReplace makes the collection match the desired documents, keyed by keyField: documents
+// whose key is new are inserted, documents whose content differs are replaced (keeping their
+// _id), and documents whose key is no longer in the desired set are deleted. Documents that
+// already match are left untouched and counted as unchanged. Documents without keyField are
+// not part of the synced set and are never deleted.
+//
+// Every desired document must have a unique, non-null keyField, which may be the dotted path
+// of an embedded field (meta.sku); keyField should be backed by a unique index. Contents are
+// compared ignoring the order of top-level fields; the order within embedded documents is
+// significant, as it is for the server. The sync is not atomic: run it in a transaction (see
+// WithTransaction) if readers must not see intermediate states.
+//
+// Example:
+//
+//	result, err := col.SyncReplace(ctx, []any{
+//		bson.M{"sku": "A-1", "name": "Widget", "price": 10},
+//		bson.M{"sku": "B-2", "name": "Gadget", "price": 25},
+//	}, "sku")
+func (col *Collection) SyncReplace(ctx context.Context, desired []any, keyField string) (*SyncResult, error) {
+	if err := col.checkWritable("SyncReplace"); err != nil {
+		return nil, err
+	}
+	if keyField == "" || keyField == "_id" {
+		return nil, fmt.Errorf("SyncReplace: key field must be a field other than _id, got %q", keyField)
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	docs, keys, err := prepareSyncDocuments(desired, keyField)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := col.syncExisting(ctx, keys, keyField)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{}
+	models := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+		current, found := existing[rawValueKey(doc.key)]
+		switch {
+		case !found:
+			models = append(models, mongo.NewInsertOneModel().SetDocument(doc.original))
+		case bytes.Equal(current.encoded, doc.encoded):
+			result.Unchanged++
+		default:
+			models = append(models, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"_id": current.id}).
+				SetReplacement(doc.content))
+		}
+	}
+
+	if len(models) > 0 {
+		bulk, err := col.BulkWrite(ctx, models)
+		if err != nil {
+			return nil, fmt.Errorf("SyncReplace: failed to write documents: %w", err)
+		}
+		result.Inserted = bulk.InsertedCount
+		result.Updated = bulk.ModifiedCount
+	}
+
+	// Delete keyed documents that are no longer desired
+	removed := filter.Exists(keyField, true).And(filter.Nin(keyField, keys...))
+	deleted, err := col.DeleteMany(ctx, removed)
+	if err != nil {
+		return nil, fmt.Errorf("SyncReplace: failed to delete documents: %w", err)
+	}
+	result.Deleted = deleted.DeletedCount
+	result.Duration = time.Since(start)
+
+	col.logger(ctx).Info("Collection synced",
+		"collection", col.name,
+		"key_field", keyField,
+		"inserted", result.Inserted,
+		"updated", result.Updated,
+		"deleted", result.Deleted,
+		"unchanged", result.Unchanged)
+
+	return result, nil
+}
+
+// existingSyncDocument is a stored document matched by key
+type existingSyncDocument struct {
+	id      any
+	encoded []byte
+}
+
+// syncExisting loads the stored documents whose key is in keys, indexed by key
+func (col *Collection) syncExisting(ctx context.Context, keys []any, keyField string) (map[string]existingSyncDocument, error) {
+	existing := make(map[string]existingSyncDocument, len(keys))
+	if len(keys) == 0 {
+		return existing, nil
+	}
+
+	cursor, err := col.Find(ctx, filter.In(keyField, keys...))
+	if err != nil {
+		return nil, fmt.Errorf("SyncReplace: failed to read existing documents: %w", err)
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	for cursor.Next(ctx) {
+		raw := cursor.Current()
+		key, err := raw.LookupErr(syncKeyPath(keyField)...)
+		if err != nil {
+			continue
+		}
+		var doc bson.D
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return nil, fmt.Errorf("SyncReplace: failed to decode existing document: %w", err)
+		}
+		id, content := splitID(doc)
+		encoded, err := syncEncode(content)
+		if err != nil {
+			return nil, fmt.Errorf("SyncReplace: failed to encode existing document: %w", err)
+		}
+		existing[rawValueKey(key)] = existingSyncDocument{id: id, encoded: encoded}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("SyncReplace: failed to read existing documents: %w", err)
+	}
+	return existing, nil
+}
+
+// prepareSyncDocuments extracts the key of each desired document and its content without _id,
+// rejecting documents without a key and duplicate keys
+func prepareSyncDocuments(desired []any, keyField string) ([]syncDocument, []any, error) {
+	docs := make([]syncDocument, 0, len(desired))
+	keys := make([]any, 0, len(desired))
+	seen := make(map[string]int, len(desired))
+
+	for i, original := range desired {
+		raw, err := bson.Marshal(original)
+		if err != nil {
+			return nil, nil, fmt.Errorf("SyncReplace: document %d: %w", i, err)
+		}
+		key, err := bson.Raw(raw).LookupErr(syncKeyPath(keyField)...)
+		if err != nil || key.Type == bson.TypeNull {
+			return nil, nil, fmt.Errorf("SyncReplace: document %d has no %q value", i, keyField)
+		}
+		if first, dup := seen[rawValueKey(key)]; dup {
+			return nil, nil, fmt.Errorf("SyncReplace: documents %d and %d have the same %q value %s", first, i, keyField, key)
+		}
+		seen[rawValueKey(key)] = i
+
+		var doc bson.D
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return nil, nil, fmt.Errorf("SyncReplace: document %d: %w", i, err)
+		}
+		_, content := splitID(doc)
+		encoded, err := syncEncode(content)
+		if err != nil {
+			return nil, nil, fmt.Errorf("SyncReplace: document %d: %w", i, err)
+		}
+
+		docs = append(docs, syncDocument{original: original, key: key, content: content, encoded: encoded})
+		keys = append(keys, key)
+	}
+	return docs, keys, nil
+}
+
+// syncKeyPath splits keyField into the path of an embedded key such as meta.sku
+func syncKeyPath(keyField string) []string {
+	return strings.Split(keyField, ".")
+}
+
+// splitID returns the _id of a document and the document without it
+func splitID(doc bson.D) (any, bson.D) {
+	var id any
+	content := make(bson.D, 0, len(doc))
+	for _, elem := range doc {
+		if elem.Key == "_id" {
+			id = elem.Value
+			continue
+		}
+		content = append(content, elem)
+	}
+	return id, content
+}
+
+// syncEncode marshals a document with its top-level fields sorted, for order-insensitive comparison
+func syncEncode(content bson.D) ([]byte, error) {
+	sorted := slices.Clone(content)
+	slices.SortFunc(sorted, func(a, b bson.E) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return bson.Marshal(sorted)
+}
+
+// rawValueKey returns a comparable representation of a BSON value, used to match sync keys and
+// populate references. Integral numbers map to the same key whatever their BSON type, matching
+// how the server compares them.
+func rawValueKey(key bson.RawValue) string {
+	switch key.Type {
+	case bson.TypeInt32:
+		return "n" + strconv.FormatInt(int64(key.Int32()), 10)
+	case bson.TypeInt64:
+		return "n" + strconv.FormatInt(key.Int64(), 10)
+	case bson.TypeDouble:
+		if f := key.Double(); f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+			return "n" + strconv.FormatInt(int64(f), 10)
+		}
+	}
+	return string(rune(key.Type)) + string(key.Value)
+}
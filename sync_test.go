@@ -0,0 +1,194 @@
+package mongodb
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestPrepareSyncDocuments(t *testing.T) {
+	type product struct {
+		ID    string `bson:"_id,omitempty"`
+		SKU   string `bson:"sku"`
+		Price int    `bson:"price"`
+	}
+
+	docs, keys, err := prepareSyncDocuments([]any{
+		bson.M{"_id": "ignored", "sku": "A-1", "price": 10},
+		product{SKU: "B-2", Price: 25},
+	}, "sku")
+	if err != nil {
+		t.Fatalf("prepareSyncDocuments failed: %v", err)
+	}
+	if len(docs) != 2 || len(keys) != 2 {
+		t.Fatalf("Expected 2 documents and keys, got %d and %d", len(docs), len(keys))
+	}
+	for _, doc := range docs {
+		for _, elem := range doc.content {
+			if elem.Key == "_id" {
+				t.Errorf("Expected _id to be stripped from content, got %v", doc.content)
+			}
+		}
+	}
+	if docs[1].key.StringValue() != "B-2" {
+		t.Errorf("Expected key B-2, got %v", docs[1].key)
+	}
+
+	// A dotted key field reads the embedded value
+	docs, _, err = prepareSyncDocuments([]any{
+		bson.M{"meta": bson.M{"sku": "A-1"}, "price": 10},
+		bson.M{"meta": bson.M{"sku": "B-2"}, "price": 25},
+	}, "meta.sku")
+	if err != nil {
+		t.Fatalf("prepareSyncDocuments with an embedded key failed: %v", err)
+	}
+	if docs[0].key.StringValue() != "A-1" || docs[1].key.StringValue() != "B-2" {
+		t.Errorf("Expected embedded keys A-1 and B-2, got %v and %v", docs[0].key, docs[1].key)
+	}
+	if _, _, err := prepareSyncDocuments([]any{bson.M{"meta.sku": "A-1"}}, "meta.sku"); err == nil {
+		t.Error("Expected a top-level field named meta.sku not to count as the embedded key")
+	}
+
+	tests := []struct {
+		name    string
+		desired []any
+		errText string
+	}{
+		{"missing key", []any{bson.M{"sku": "A-1"}, bson.M{"name": "no sku"}}, "document 1 has no"},
+		{"null key", []any{bson.M{"sku": nil}}, "document 0 has no"},
+		{"duplicate key", []any{bson.M{"sku": "A-1"}, bson.M{"sku": "B-2"}, bson.M{"sku": "A-1"}}, "documents 0 and 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := prepareSyncDocuments(tt.desired, "sku")
+			if err == nil || !strings.Contains(err.Error(), tt.errText) {
+				t.Errorf("Expected error containing %q, got %v", tt.errText, err)
+			}
+		})
+	}
+}
+
+func TestRawValueKeyNormalizesNumbers(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{
+		{Key: "i32", Value: int32(7)},
+		{Key: "i64", Value: int64(7)},
+		{Key: "f", Value: 7.0},
+		{Key: "frac", Value: 7.5},
+		{Key: "s", Value: "7"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	doc := bson.Raw(raw)
+
+	key := rawValueKey(doc.Lookup("i32"))
+	if rawValueKey(doc.Lookup("i64")) != key || rawValueKey(doc.Lookup("f")) != key {
+		t.Error("Expected integral numbers of any BSON type to share a key")
+	}
+	if rawValueKey(doc.Lookup("frac")) == key || rawValueKey(doc.Lookup("s")) == key {
+		t.Error("Expected fractional numbers and strings to have distinct keys")
+	}
+}
+
+func TestSyncReplace(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_sync_replace")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	_, err := col.InsertMany(ctx, []any{
+		bson.D{{Key: "sku", Value: "keep"}, {Key: "price", Value: int32(10)}},
+		bson.D{{Key: "sku", Value: "change"}, {Key: "price", Value: int32(20)}, {Key: "legacy", Value: true}},
+		bson.D{{Key: "sku", Value: "remove"}, {Key: "price", Value: int32(30)}},
+		bson.D{{Key: "note", Value: "unkeyed documents are left alone"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to seed collection: %v", err)
+	}
+
+	var before struct {
+		ID any `bson:"_id"`
+	}
+	if err := col.FindOne(ctx, filter.Eq("sku", "change")).Decode(&before); err != nil {
+		t.Fatalf("Failed to read document: %v", err)
+	}
+
+	result, err := col.SyncReplace(ctx, []any{
+		bson.D{{Key: "sku", Value: "keep"}, {Key: "price", Value: int32(10)}},
+		bson.D{{Key: "sku", Value: "change"}, {Key: "price", Value: int32(25)}},
+		bson.D{{Key: "sku", Value: "add"}, {Key: "price", Value: int32(40)}},
+	}, "sku")
+	if err != nil {
+		t.Fatalf("SyncReplace failed: %v", err)
+	}
+
+	if result.Inserted != 1 || result.Updated != 1 || result.Deleted != 1 || result.Unchanged != 1 {
+		t.Errorf("Expected 1 inserted, 1 updated, 1 deleted, 1 unchanged, got %+v", result)
+	}
+
+	var changed bson.M
+	if err := col.FindOne(ctx, filter.Eq("sku", "change")).Decode(&changed); err != nil {
+		t.Fatalf("Failed to read replaced document: %v", err)
+	}
+	if changed["price"] != int32(25) {
+		t.Errorf("Expected price 25, got %v", changed["price"])
+	}
+	if _, exists := changed["legacy"]; exists {
+		t.Error("Expected replaced document to drop fields missing from the desired document")
+	}
+	if changed["_id"] != before.ID {
+		t.Errorf("Expected _id %v to be kept, got %v", before.ID, changed["_id"])
+	}
+
+	if count, _ := col.CountDocuments(ctx, filter.Eq("sku", "remove")); count != 0 {
+		t.Error("Expected removed document to be deleted")
+	}
+	if count, _ := col.CountDocuments(ctx, filter.Exists("note", true)); count != 1 {
+		t.Error("Expected document without the key field to be kept")
+	}
+
+	// A second sync with the same documents changes nothing
+	result, err = col.SyncReplace(ctx, []any{
+		bson.D{{Key: "sku", Value: "keep"}, {Key: "price", Value: int32(10)}},
+		bson.D{{Key: "sku", Value: "change"}, {Key: "price", Value: int32(25)}},
+		bson.D{{Key: "sku", Value: "add"}, {Key: "price", Value: int32(40)}},
+	}, "sku")
+	if err != nil {
+		t.Fatalf("Second SyncReplace failed: %v", err)
+	}
+	if result.Inserted != 0 || result.Updated != 0 || result.Deleted != 0 || result.Unchanged != 3 {
+		t.Errorf("Expected an idempotent second sync, got %+v", result)
+	}
+}
+
+func TestSyncEncodeIgnoresTopLevelOrder(t *testing.T) {
+	a, err := syncEncode(bson.D{{Key: "name", Value: "x"}, {Key: "price", Value: int32(1)}})
+	if err != nil {
+		t.Fatalf("syncEncode failed: %v", err)
+	}
+	b, err := syncEncode(bson.D{{Key: "price", Value: int32(1)}, {Key: "name", Value: "x"}})
+	if err != nil {
+		t.Fatalf("syncEncode failed: %v", err)
+	}
+	if string(a) != string(b) {
+		t.Error("Expected top-level field order to be ignored")
+	}
+
+	c, _ := syncEncode(bson.D{{Key: "name", Value: "x"}, {Key: "price", Value: int32(2)}})
+	if string(a) == string(c) {
+		t.Error("Expected different values to encode differently")
+	}
+}
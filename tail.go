@@ -0,0 +1,299 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"github.com/cloudresty/go-mongodb/v2/update"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// DefaultTailRetryBackoff is how long TailCollection waits before reopening a change stream
+// that failed with a transient error
+const DefaultTailRetryBackoff = time.Second
+
+// ErrNoTailHandler is returned by TailCollection when TailOptions has no Handler
+var ErrNoTailHandler = errors.New("tail options require a handler")
+
+// ResumeTokenStore persists change stream resume tokens so that tailing can continue where it
+// left off after a process restart
+type ResumeTokenStore interface {
+	// LoadResumeToken returns the last saved token for key, or nil if none has been saved
+	LoadResumeToken(ctx context.Context, key string) (bson.Raw, error)
+	// SaveResumeToken records token as the position to resume from for key
+	SaveResumeToken(ctx context.Context, key string, token bson.Raw) error
+}
+
+// TailOptions configures TailCollection
+type TailOptions struct {
+	// Store persists the resume token; required for resuming across restarts. Without a
+	// store, tailing starts from the current time on every call.
+	Store ResumeTokenStore
+
+	// Key identifies the consumer in the store; defaults to the collection name. Use a
+	// distinct key for each independent consumer of the same collection.
+	Key string
+
+	// Pipeline filters or reshapes the events on the server; nil watches all events
+	Pipeline *pipeline.Builder
+
+	// ChangeStreamOptions are applied when opening the stream, e.g.
+	// ChangeStreamPrePostImages(). The resume position is set by TailCollection.
+	ChangeStreamOptions *options.ChangeStreamOptionsBuilder
+
+	// Handler is called for each event. The event's resume token is saved only after the
+	// handler returns nil, so an event may be delivered again after a crash (at-least-once).
+	// Returning an error stops tailing and is returned by TailCollection.
+	Handler func(ctx context.Context, event *ChangeEvent) error
+
+	// RetryBackoff is the wait before reopening the stream after a transient error such as
+	// a primary election; defaults to DefaultTailRetryBackoff
+	RetryBackoff time.Duration
+}
+
+// changeEventStream is the subset of *mongo.ChangeStream used by TailCollection
+type changeEventStream interface {
+	Next(ctx context.Context) bool
+	Decode(v any) error
+	ResumeToken() bson.Raw
+	Err() error
+	Close(ctx context.Context) error
+}
+
+// TailCollection consumes the collection's change stream until ctx is cancelled or the
+// handler fails, persisting the resume token after every handled event. On start it resumes
+// after the token saved in the store, so a restarted process continues with the first event
+// it has not handled. Transient failures such as network errors and primary elections close
+// the stream, which is then reopened from the last saved token after RetryBackoff.
+//
+// TailCollection returns ctx.Err() when ctx is cancelled.
+//
+// Example:
+//
+//	err := mongodb.TailCollection(ctx, orders, mongodb.TailOptions{
+//		Store: mongodb.NewCollectionTokenStore(client.Collection("resume_tokens")),
+//		Key:   "orders-indexer",
+//		Handler: func(ctx context.Context, event *mongodb.ChangeEvent) error {
+//			return index(ctx, event)
+//		},
+//	})
+func TailCollection(ctx context.Context, col *Collection, opts TailOptions) error {
+	open := func(ctx context.Context, token bson.Raw) (changeEventStream, error) {
+		var streamOpts []options.Lister[options.ChangeStreamOptions]
+		if opts.ChangeStreamOptions != nil {
+			streamOpts = append(streamOpts, opts.ChangeStreamOptions)
+		}
+		if token != nil {
+			// startAfter, unlike resumeAfter, can also resume after an invalidate event
+			streamOpts = append(streamOpts, options.ChangeStream().SetStartAfter(token))
+		}
+		return col.WatchWithPipeline(ctx, opts.Pipeline, streamOpts...)
+	}
+
+	if opts.Key == "" {
+		opts.Key = col.name
+	}
+	return tail(ctx, col.client.config.Logger, opts, open)
+}
+
+// tail runs the TailCollection loop over streams created by open
+func tail(ctx context.Context, logger Logger, opts TailOptions, open func(ctx context.Context, token bson.Raw) (changeEventStream, error)) error {
+	if opts.Handler == nil {
+		return ErrNoTailHandler
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	backoff := opts.RetryBackoff
+	if backoff <= 0 {
+		backoff = DefaultTailRetryBackoff
+	}
+
+	for {
+		var token bson.Raw
+		if opts.Store != nil {
+			var err error
+			token, err = opts.Store.LoadResumeToken(ctx, opts.Key)
+			if err != nil {
+				return fmt.Errorf("failed to load resume token: %w", err)
+			}
+		}
+
+		err := tailStream(ctx, opts, token, open)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var handlerErr *tailHandlerError
+		if errors.As(err, &handlerErr) {
+			return handlerErr.err
+		}
+		if !isResumableTailError(err) {
+			return err
+		}
+
+		logger.Warn("Change stream interrupted, resuming",
+			"key", opts.Key,
+			"backoff", backoff,
+			"error", err.Error())
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// tailStream opens one stream and handles its events until it fails or ctx is cancelled
+func tailStream(ctx context.Context, opts TailOptions, token bson.Raw, open func(ctx context.Context, token bson.Raw) (changeEventStream, error)) error {
+	stream, err := open(ctx, token)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = stream.Close(context.Background())
+	}()
+
+	for stream.Next(ctx) {
+		var event ChangeEvent
+		if err := stream.Decode(&event); err != nil {
+			return fmt.Errorf("failed to decode change event: %w", err)
+		}
+		if err := opts.Handler(ctx, &event); err != nil {
+			return &tailHandlerError{err: err}
+		}
+		if opts.Store != nil {
+			if err := opts.Store.SaveResumeToken(ctx, opts.Key, stream.ResumeToken()); err != nil {
+				return fmt.Errorf("failed to save resume token: %w", err)
+			}
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return err
+	}
+	// The stream ended without an error, e.g. after the collection was dropped; reopen it
+	return errChangeStreamClosed
+}
+
+// errChangeStreamClosed signals that a stream ended without an error and should be reopened
+var errChangeStreamClosed = errors.New("change stream closed")
+
+// tailHandlerError wraps a handler failure so that it is never treated as transient
+type tailHandlerError struct {
+	err error
+}
+
+func (e *tailHandlerError) Error() string { return e.err.Error() }
+
+func (e *tailHandlerError) Unwrap() error { return e.err }
+
+// isResumableTailError reports whether tailing should reopen the stream after err
+func isResumableTailError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, errChangeStreamClosed) || isRetryableError(err) {
+		return true
+	}
+
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		return serverErr.HasErrorLabel("ResumableChangeStreamError")
+	}
+	return false
+}
+
+// collectionTokenStore stores resume tokens as {_id: key, token, updated_at} documents
+type collectionTokenStore struct {
+	col *Collection
+}
+
+// NewCollectionTokenStore returns a ResumeTokenStore that keeps one document per key in col
+func NewCollectionTokenStore(col *Collection) ResumeTokenStore {
+	return &collectionTokenStore{col: col}
+}
+
+func (s *collectionTokenStore) LoadResumeToken(ctx context.Context, key string) (bson.Raw, error) {
+	var doc struct {
+		Token bson.Raw `bson:"token"`
+	}
+	err := s.col.FindOne(ctx, filter.Eq("_id", key)).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc.Token, nil
+}
+
+func (s *collectionTokenStore) SaveResumeToken(ctx context.Context, key string, token bson.Raw) error {
+	_, err := s.col.UpdateOne(ctx, filter.Eq("_id", key),
+		update.Set("token", token).Set("updated_at", time.Now()),
+		options.UpdateOne().SetUpsert(true))
+	return err
+}
+
+// fileTokenStore stores each resume token as raw BSON in its own file
+type fileTokenStore struct {
+	dir string
+}
+
+// NewFileTokenStore returns a ResumeTokenStore that writes one file per key in dir. Files are
+// replaced atomically, so a crash mid-write leaves the previous token intact.
+func NewFileTokenStore(dir string) ResumeTokenStore {
+	return &fileTokenStore{dir: dir}
+}
+
+func (s *fileTokenStore) path(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key)+".token")
+}
+
+func (s *fileTokenStore) LoadResumeToken(ctx context.Context, key string) (bson.Raw, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	token := bson.Raw(data)
+	if err := token.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid resume token in %s: %w", s.path(key), err)
+	}
+	return token, nil
+}
+
+func (s *fileTokenStore) SaveResumeToken(ctx context.Context, key string, token bson.Raw) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".token-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(token); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
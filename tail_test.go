@@ -0,0 +1,285 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// memoryTokenStore is a ResumeTokenStore kept in memory, standing in for a persistent store
+// that outlives the process
+type memoryTokenStore struct {
+	tokens map[string]bson.Raw
+	saves  int
+}
+
+func newMemoryTokenStore() *memoryTokenStore {
+	return &memoryTokenStore{tokens: map[string]bson.Raw{}}
+}
+
+func (s *memoryTokenStore) LoadResumeToken(ctx context.Context, key string) (bson.Raw, error) {
+	return s.tokens[key], nil
+}
+
+func (s *memoryTokenStore) SaveResumeToken(ctx context.Context, key string, token bson.Raw) error {
+	s.tokens[key] = token
+	s.saves++
+	return nil
+}
+
+// fakeOplog serves change streams over a fixed list of events, starting after the event
+// whose resume token is passed to open
+type fakeOplog struct {
+	events   []bson.Raw
+	failAt   int // position at which the next opened stream fails with failWith, or -1
+	failWith error
+	opened   []bson.Raw
+}
+
+func newFakeOplog(t *testing.T, n int) *fakeOplog {
+	t.Helper()
+	oplog := &fakeOplog{failAt: -1}
+	for i := 1; i <= n; i++ {
+		raw, err := bson.Marshal(bson.M{
+			"_id":           bson.M{"_data": fmt.Sprintf("token-%d", i)},
+			"operationType": OperationInsert,
+			"documentKey":   bson.M{"_id": i},
+		})
+		if err != nil {
+			t.Fatalf("Failed to marshal event: %v", err)
+		}
+		oplog.events = append(oplog.events, raw)
+	}
+	return oplog
+}
+
+func (o *fakeOplog) open(ctx context.Context, token bson.Raw) (changeEventStream, error) {
+	o.opened = append(o.opened, token)
+	stream := &fakeChangeStream{oplog: o, pos: -1}
+	for i, event := range o.events {
+		if token != nil && bson.Raw(event.Lookup("_id").Document()).String() == token.String() {
+			stream.pos = i
+		}
+	}
+	return stream, nil
+}
+
+type fakeChangeStream struct {
+	oplog *fakeOplog
+	pos   int
+	err   error
+}
+
+func (s *fakeChangeStream) Next(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if s.pos+1 == s.oplog.failAt {
+		s.err = s.oplog.failWith
+		s.oplog.failAt = -1
+		return false
+	}
+	if s.pos+1 >= len(s.oplog.events) {
+		return false
+	}
+	s.pos++
+	return true
+}
+
+func (s *fakeChangeStream) Decode(v any) error {
+	return bson.Unmarshal(s.oplog.events[s.pos], v)
+}
+
+func (s *fakeChangeStream) ResumeToken() bson.Raw {
+	return s.oplog.events[s.pos].Lookup("_id").Document()
+}
+
+func (s *fakeChangeStream) Err() error { return s.err }
+
+func (s *fakeChangeStream) Close(ctx context.Context) error { return nil }
+
+// eventKey returns the documentKey _id of an event
+func eventKey(t *testing.T, event *ChangeEvent) int32 {
+	t.Helper()
+	return event.DocumentKey.Lookup("_id").Int32()
+}
+
+func TestTailPersistsResumeToken(t *testing.T) {
+	oplog := newFakeOplog(t, 3)
+	store := newMemoryTokenStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var seen []int32
+	err := tail(ctx, NopLogger{}, TailOptions{
+		Store: store,
+		Key:   "orders-indexer",
+		Handler: func(ctx context.Context, event *ChangeEvent) error {
+			seen = append(seen, eventKey(t, event))
+			if len(seen) == 3 {
+				cancel()
+			}
+			return nil
+		},
+	}, oplog.open)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	if len(seen) != 3 {
+		t.Fatalf("Expected 3 events, got %v", seen)
+	}
+	if store.saves != 3 {
+		t.Errorf("Expected a token saved per event, got %d saves", store.saves)
+	}
+	token := store.tokens["orders-indexer"]
+	if token == nil || token.Lookup("_data").StringValue() != "token-3" {
+		t.Errorf("Expected token of the last event to be stored, got %v", token)
+	}
+	if oplog.opened[0] != nil {
+		t.Errorf("Expected first open without a resume token, got %v", oplog.opened[0])
+	}
+}
+
+func TestTailResumesAfterRestart(t *testing.T) {
+	oplog := newFakeOplog(t, 4)
+	store := newMemoryTokenStore()
+	crash := errors.New("process crashed")
+
+	// First run handles two events and fails on the third before its token is saved
+	var firstRun []int32
+	err := tail(context.Background(), NopLogger{}, TailOptions{
+		Store: store,
+		Key:   "consumer",
+		Handler: func(ctx context.Context, event *ChangeEvent) error {
+			if len(firstRun) == 2 {
+				return crash
+			}
+			firstRun = append(firstRun, eventKey(t, event))
+			return nil
+		},
+	}, oplog.open)
+	if !errors.Is(err, crash) {
+		t.Fatalf("Expected handler error to be returned, got %v", err)
+	}
+	if len(oplog.opened) != 1 {
+		t.Fatalf("Expected handler errors not to reopen the stream, opened %d times", len(oplog.opened))
+	}
+
+	// Second run resumes after the last handled event, redelivering the failed one
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var secondRun []int32
+	err = tail(ctx, NopLogger{}, TailOptions{
+		Store: store,
+		Key:   "consumer",
+		Handler: func(ctx context.Context, event *ChangeEvent) error {
+			secondRun = append(secondRun, eventKey(t, event))
+			if len(secondRun) == 2 {
+				cancel()
+			}
+			return nil
+		},
+	}, oplog.open)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	if resumed := oplog.opened[1]; resumed == nil || resumed.Lookup("_data").StringValue() != "token-2" {
+		t.Errorf("Expected to resume after token-2, got %v", resumed)
+	}
+	if fmt.Sprint(firstRun) != "[1 2]" || fmt.Sprint(secondRun) != "[3 4]" {
+		t.Errorf("Expected [1 2] then [3 4], got %v then %v", firstRun, secondRun)
+	}
+}
+
+func TestTailReopensAfterTransientError(t *testing.T) {
+	oplog := newFakeOplog(t, 4)
+	oplog.failAt = 2
+	oplog.failWith = mongo.CommandError{Code: 10107, Message: "not primary", Labels: []string{"NetworkError"}}
+	store := newMemoryTokenStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var seen []int32
+	err := tail(ctx, NopLogger{}, TailOptions{
+		Store:        store,
+		RetryBackoff: time.Millisecond,
+		Handler: func(ctx context.Context, event *ChangeEvent) error {
+			seen = append(seen, eventKey(t, event))
+			if len(seen) == 4 {
+				cancel()
+			}
+			return nil
+		},
+	}, oplog.open)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	if fmt.Sprint(seen) != "[1 2 3 4]" {
+		t.Errorf("Expected every event exactly once, got %v", seen)
+	}
+	if len(oplog.opened) != 2 || oplog.opened[1].Lookup("_data").StringValue() != "token-2" {
+		t.Errorf("Expected the stream to be reopened after token-2, got %v", oplog.opened)
+	}
+}
+
+func TestTailStopsOnPermanentError(t *testing.T) {
+	oplog := newFakeOplog(t, 2)
+	oplog.failAt = 1
+	oplog.failWith = mongo.CommandError{Code: 13, Message: "unauthorized"}
+
+	err := tail(context.Background(), NopLogger{}, TailOptions{
+		RetryBackoff: time.Millisecond,
+		Handler: func(ctx context.Context, event *ChangeEvent) error {
+			return nil
+		},
+	}, oplog.open)
+
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Code != 13 {
+		t.Fatalf("Expected the permanent error to be returned, got %v", err)
+	}
+	if len(oplog.opened) != 1 {
+		t.Errorf("Expected no reopen after a permanent error, opened %d times", len(oplog.opened))
+	}
+}
+
+func TestTailRequiresHandler(t *testing.T) {
+	oplog := newFakeOplog(t, 1)
+	if err := tail(context.Background(), NopLogger{}, TailOptions{}, oplog.open); !errors.Is(err, ErrNoTailHandler) {
+		t.Errorf("Expected ErrNoTailHandler, got %v", err)
+	}
+}
+
+func TestFileTokenStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileTokenStore(t.TempDir())
+
+	token, err := store.LoadResumeToken(ctx, "orders/indexer")
+	if err != nil || token != nil {
+		t.Fatalf("Expected no token before the first save, got %v, %v", token, err)
+	}
+
+	for _, data := range []string{"first", "second"} {
+		raw, _ := bson.Marshal(bson.M{"_data": data})
+		if err := store.SaveResumeToken(ctx, "orders/indexer", raw); err != nil {
+			t.Fatalf("SaveResumeToken failed: %v", err)
+		}
+	}
+
+	// A new store over the same directory sees the last token, as after a restart
+	token, err = NewFileTokenStore(store.(*fileTokenStore).dir).LoadResumeToken(ctx, "orders/indexer")
+	if err != nil {
+		t.Fatalf("LoadResumeToken failed: %v", err)
+	}
+	if token.Lookup("_data").StringValue() != "second" {
+		t.Errorf("Expected the last saved token, got %v", token)
+	}
+}
@@ -0,0 +1,145 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/mongoid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// defaultBackfillBatchSize is used by BackfillTimestamps when batchSize is not positive
+const defaultBackfillBatchSize = 500
+
+// BackfillTimestamps sets created_at on documents that do not have one, deriving it from the
+// time embedded in their ULID _id. updated_at is set to the same time when it is also missing;
+// existing updated_at values are left untouched.
+//
+// Documents are processed in _id order in batches of batchSize. Documents whose _id is not a
+// ULID string are skipped. The returned count is the number of documents updated, including
+// on error. A nil ctx defaults to a 10 minute timeout for the whole backfill.
+//
+// Example:
+//
+//	updated, err := client.Collection("orders").BackfillTimestamps(ctx, 1000)
+func (col *Collection) BackfillTimestamps(ctx context.Context, batchSize int) (int64, error) {
+	if err := col.checkWritable("BackfillTimestamps"); err != nil {
+		return 0, err
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+	}
+
+	if batchSize <= 0 {
+		batchSize = defaultBackfillBatchSize
+	}
+
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(batchSize)).
+		SetProjection(bson.M{"_id": 1, "updated_at": 1})
+
+	if err := col.checkConnected(); err != nil {
+		return 0, err
+	}
+
+	var updated int64
+	var lastID string
+
+	for {
+		// ULID strings sort by time, so paging on _id visits every candidate exactly once,
+		// including the ones that are skipped
+		filterDoc := bson.M{
+			"created_at": bson.M{"$exists": false},
+			"_id":        bson.M{"$type": "string"},
+		}
+		if lastID != "" {
+			filterDoc["_id"] = bson.M{"$type": "string", "$gt": lastID}
+		}
+
+		var docs []struct {
+			ID        string `bson:"_id"`
+			UpdatedAt any    `bson:"updated_at"`
+		}
+		cursor, err := col.collection.Find(ctx, filterDoc, findOpts)
+		if err == nil {
+			err = cursor.All(ctx, &docs)
+		}
+		if err != nil {
+			col.client.incrementFailureCount()
+			col.logger(ctx).Error("Failed to find documents to backfill",
+				"error", err.Error(),
+				"collection", col.name,
+				"updated", updated)
+			return updated, err
+		}
+		if len(docs) == 0 {
+			break
+		}
+		lastID = docs[len(docs)-1].ID
+
+		models := make([]mongo.WriteModel, 0, len(docs))
+		for _, doc := range docs {
+			created, ok := timeFromULID(doc.ID)
+			if !ok {
+				continue
+			}
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": doc.ID, "created_at": bson.M{"$exists": false}}).
+				SetUpdate(backfillUpdate(created, doc.UpdatedAt != nil)))
+		}
+
+		if len(models) > 0 {
+			if err := col.client.waitForWrites(ctx, len(models)); err != nil {
+				return updated, err
+			}
+
+			result, err := col.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+			if result != nil {
+				updated += result.ModifiedCount
+			}
+			if err != nil {
+				col.client.incrementFailureCount()
+				col.logger(ctx).Error("Failed to backfill timestamps",
+					"error", err.Error(),
+					"collection", col.name,
+					"updated", updated)
+				return updated, err
+			}
+			col.client.incrementOperationCount()
+		}
+
+		if len(docs) < batchSize {
+			break
+		}
+	}
+
+	col.logger(ctx).Debug("Timestamps backfilled successfully",
+		"collection", col.name,
+		"count", updated)
+
+	return updated, nil
+}
+
+// timeFromULID returns the creation time embedded in a ULID string
+func timeFromULID(id string) (time.Time, bool) {
+	parsed, err := mongoid.ParseULID(id)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed.Time(), true
+}
+
+// backfillUpdate builds the $set document for a backfilled document
+func backfillUpdate(created time.Time, hasUpdatedAt bool) bson.M {
+	set := bson.M{"created_at": created}
+	if !hasUpdatedAt {
+		set["updated_at"] = created
+	}
+	return bson.M{"$set": set}
+}
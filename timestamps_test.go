@@ -0,0 +1,126 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestTimeFromULID(t *testing.T) {
+	created := time.Date(2023, 4, 5, 6, 7, 8, 9_000_000, time.UTC)
+
+	got, ok := timeFromULID(GenerateULIDFromTime(created))
+	if !ok || !got.Equal(created) {
+		t.Errorf("Expected %v, got %v (ok=%v)", created, got, ok)
+	}
+
+	for _, id := range []string{"", "order-1", "not-a-valid-ulid-string-xx"} {
+		if _, ok := timeFromULID(id); ok {
+			t.Errorf("Expected %q to be rejected", id)
+		}
+	}
+}
+
+func TestBackfillUpdate(t *testing.T) {
+	created := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+
+	set := backfillUpdate(created, false)["$set"].(bson.M)
+	if set["created_at"] != created || set["updated_at"] != created {
+		t.Errorf("Expected created_at and updated_at to be set, got %v", set)
+	}
+
+	set = backfillUpdate(created, true)["$set"].(bson.M)
+	if _, exists := set["updated_at"]; exists || set["created_at"] != created {
+		t.Errorf("Expected only created_at to be set, got %v", set)
+	}
+}
+
+func TestBackfillTimestampsIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	col := client.Collection("test_backfill_timestamps")
+	_ = col.Drop(ctx)
+	defer func() {
+		_ = col.Drop(ctx)
+	}()
+
+	base := time.Now().Add(-72 * time.Hour).Truncate(time.Millisecond)
+	updatedAt := base.Add(time.Hour)
+	existing := time.Now().Truncate(time.Millisecond)
+
+	docs := make([]any, 0, 12)
+	ids := make([]string, 10)
+	for i := range ids {
+		ids[i] = GenerateULIDFromTime(base.Add(time.Duration(i) * time.Minute))
+		doc := bson.M{"_id": ids[i], "seq": i}
+		if i == 0 {
+			doc["updated_at"] = updatedAt
+		}
+		docs = append(docs, doc)
+	}
+	docs = append(docs,
+		bson.M{"_id": "legacy-id", "seq": 10},
+		bson.M{"_id": GenerateULIDFromTime(base), "seq": 11, "created_at": existing})
+
+	if _, err := col.collection.InsertMany(ctx, docs); err != nil {
+		t.Fatalf("Failed to seed collection: %v", err)
+	}
+
+	// A batch size smaller than the data exercises paging past skipped documents
+	updated, err := col.BackfillTimestamps(ctx, 3)
+	if err != nil {
+		t.Fatalf("BackfillTimestamps failed: %v", err)
+	}
+	if updated != 10 {
+		t.Errorf("Expected 10 backfilled documents, got %d", updated)
+	}
+
+	for i, id := range ids {
+		var doc struct {
+			CreatedAt time.Time `bson:"created_at"`
+			UpdatedAt time.Time `bson:"updated_at"`
+		}
+		if err := col.FindOne(ctx, filter.Eq("_id", id)).Decode(&doc); err != nil {
+			t.Fatalf("Failed to find document %s: %v", id, err)
+		}
+
+		ulidTime, _ := timeFromULID(id)
+		if !doc.CreatedAt.Equal(ulidTime) {
+			t.Errorf("Document %d: expected created_at %v from ULID, got %v", i, ulidTime, doc.CreatedAt)
+		}
+		expectedUpdated := ulidTime
+		if i == 0 {
+			expectedUpdated = updatedAt
+		}
+		if !doc.UpdatedAt.Equal(expectedUpdated) {
+			t.Errorf("Document %d: expected updated_at %v, got %v", i, expectedUpdated, doc.UpdatedAt)
+		}
+	}
+
+	var legacy bson.M
+	if err := col.FindOne(ctx, filter.Eq("_id", "legacy-id")).Decode(&legacy); err != nil {
+		t.Fatalf("Failed to find legacy document: %v", err)
+	}
+	if _, exists := legacy["created_at"]; exists {
+		t.Errorf("Expected non-ULID document to be skipped, got %v", legacy)
+	}
+
+	count, err := col.CountDocuments(ctx, filter.Eq("created_at", existing))
+	if err != nil || count != 1 {
+		t.Errorf("Expected existing created_at to be preserved, count=%d err=%v", count, err)
+	}
+
+	// A second run finds nothing to do
+	if updated, err := col.BackfillTimestamps(ctx, 0); err != nil || updated != 0 {
+		t.Errorf("Expected idempotent backfill, got updated=%d err=%v", updated, err)
+	}
+}
@@ -0,0 +1,79 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ErrTransactionsUnsupported is returned by WithTransaction when the server is a standalone
+// instance; transactions require a replica set or a sharded cluster
+var ErrTransactionsUnsupported = errors.New("transactions require a replica set or sharded cluster")
+
+// illegalOperationCode is the server error code returned when a transaction is started on a
+// standalone server
+const illegalOperationCode = 20
+
+// SupportsTransactions reports whether the server the client is connected to supports
+// transactions, by inspecting the topology reported by the hello command: replica set
+// members and mongos routers do, standalone servers do not. Use it to skip transactional
+// code paths or tests instead of matching error strings.
+//
+// Example:
+//
+//	ok, err := client.SupportsTransactions(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	if !ok {
+//	    return saveWithoutTransaction(ctx, order)
+//	}
+func (c *Client) SupportsTransactions(ctx context.Context) (bool, error) {
+	client, _, err := c.connection()
+	if err != nil {
+		return false, err
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+	}
+
+	var hello bson.M
+	err = client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		return false, fmt.Errorf("failed to get server topology: %w", err)
+	}
+
+	return transactionsSupported(hello), nil
+}
+
+// transactionsSupported reports whether a hello response describes a server that supports
+// transactions: a replica set member (setName) or a mongos router (msg "isdbgrid"), with
+// sessions enabled (logicalSessionTimeoutMinutes)
+func transactionsSupported(hello bson.M) bool {
+	if _, ok := hello["logicalSessionTimeoutMinutes"]; !ok {
+		return false
+	}
+	if setName, ok := hello["setName"].(string); ok && setName != "" {
+		return true
+	}
+	msg, _ := hello["msg"].(string)
+	return msg == "isdbgrid"
+}
+
+// isTransactionsUnsupportedError reports whether err is the server's rejection of a
+// transaction on a standalone instance
+func isTransactionsUnsupportedError(err error) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	return serverErr.HasErrorCode(illegalOperationCode) &&
+		serverErr.HasErrorMessage("Transaction numbers are only allowed on a replica set member or mongos")
+}
@@ -0,0 +1,113 @@
+package mongodb
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestTransactionsSupported(t *testing.T) {
+	tests := []struct {
+		name     string
+		hello    bson.M
+		expected bool
+	}{
+		{
+			name: "Standalone",
+			hello: bson.M{
+				"isWritablePrimary":            true,
+				"maxWireVersion":               int32(21),
+				"logicalSessionTimeoutMinutes": int32(30),
+				"ok":                           1.0,
+			},
+			expected: false,
+		},
+		{
+			name: "Replica set primary",
+			hello: bson.M{
+				"isWritablePrimary":            true,
+				"setName":                      "rs0",
+				"hosts":                        bson.A{"mongo-0:27017", "mongo-1:27017"},
+				"logicalSessionTimeoutMinutes": int32(30),
+				"ok":                           1.0,
+			},
+			expected: true,
+		},
+		{
+			name: "Replica set secondary",
+			hello: bson.M{
+				"isWritablePrimary":            false,
+				"secondary":                    true,
+				"setName":                      "rs0",
+				"logicalSessionTimeoutMinutes": int32(30),
+				"ok":                           1.0,
+			},
+			expected: true,
+		},
+		{
+			name: "Mongos router",
+			hello: bson.M{
+				"isWritablePrimary":            true,
+				"msg":                          "isdbgrid",
+				"logicalSessionTimeoutMinutes": int32(30),
+				"ok":                           1.0,
+			},
+			expected: true,
+		},
+		{
+			name: "Replica set without sessions",
+			hello: bson.M{
+				"setName": "rs0",
+				"ok":      1.0,
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Round-trip through BSON so values have the types a real response decodes to
+			raw, err := bson.Marshal(tt.hello)
+			if err != nil {
+				t.Fatalf("Failed to marshal hello fixture: %v", err)
+			}
+			var hello bson.M
+			if err := bson.Unmarshal(raw, &hello); err != nil {
+				t.Fatalf("Failed to decode hello fixture: %v", err)
+			}
+
+			if supported := transactionsSupported(hello); supported != tt.expected {
+				t.Errorf("Expected transactions supported %v, got %v", tt.expected, supported)
+			}
+		})
+	}
+}
+
+func TestIsTransactionsUnsupportedError(t *testing.T) {
+	standalone := mongo.CommandError{
+		Code:    illegalOperationCode,
+		Name:    "IllegalOperation",
+		Message: "Transaction numbers are only allowed on a replica set member or mongos",
+	}
+	if !isTransactionsUnsupportedError(standalone) {
+		t.Error("Expected the standalone rejection to be recognized")
+	}
+
+	writeErr := mongo.WriteException{WriteErrors: []mongo.WriteError{{
+		Code:    illegalOperationCode,
+		Message: "Transaction numbers are only allowed on a replica set member or mongos",
+	}}}
+	if !isTransactionsUnsupportedError(writeErr) {
+		t.Error("Expected the standalone rejection of a write to be recognized")
+	}
+
+	other := mongo.CommandError{Code: illegalOperationCode, Message: "cannot run command in this state"}
+	if isTransactionsUnsupportedError(other) {
+		t.Error("Expected other IllegalOperation errors not to be treated as unsupported transactions")
+	}
+	if isTransactionsUnsupportedError(errors.New("connection refused")) {
+		t.Error("Expected non-server errors not to be treated as unsupported transactions")
+	}
+}
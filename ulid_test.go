@@ -79,6 +79,224 @@ func TestULIDDocumentGeneration(t *testing.T) {
 	}
 }
 
+// TestDocumentHasID tests detection of caller-provided IDs
+func TestDocumentHasID(t *testing.T) {
+	type withID struct {
+		ID   string `bson:"_id"`
+		Name string `bson:"name"`
+	}
+	type withObjectID struct {
+		ID bson.ObjectID `bson:"_id"`
+	}
+	type withoutID struct {
+		Name string `bson:"name"`
+	}
+
+	tests := []struct {
+		name     string
+		document any
+		expected bool
+	}{
+		{name: "bson.M with _id", document: bson.M{"_id": "a"}, expected: true},
+		{name: "bson.M without _id", document: bson.M{"name": "a"}, expected: false},
+		{name: "map with _id", document: map[string]any{"_id": 1}, expected: true},
+		{name: "bson.D with _id", document: bson.D{{Key: "_id", Value: "a"}}, expected: true},
+		{name: "bson.D without _id", document: bson.D{{Key: "name", Value: "a"}}, expected: false},
+		{name: "Struct with ID set", document: &withID{ID: "custom"}, expected: true},
+		{name: "Struct with zero ID", document: &withID{Name: "a"}, expected: false},
+		{name: "Struct with ObjectID", document: withObjectID{ID: bson.NewObjectID()}, expected: true},
+		{name: "Struct without ID field", document: withoutID{Name: "a"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := documentHasID(tt.document); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestInsertOneProvidedVsGeneratedID tests that InsertOneResult reflects caller-provided IDs
+func TestInsertOneProvidedVsGeneratedID(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	client, err := NewClient(FromEnv())
+	if err != nil {
+		t.Skipf("Could not create client: %v", err)
+	}
+	defer func() {
+		_ = client.Close() // Ignore error during cleanup
+	}()
+
+	collection := client.Collection("test_insert_provided_id")
+	ctx := context.Background()
+	cleanupTestCollection(t, client, "test_insert_provided_id")
+	defer cleanupTestCollection(t, client, "test_insert_provided_id")
+
+	// Generated ULID
+	generated, err := collection.InsertOne(ctx, bson.M{"name": "generated"})
+	if err != nil {
+		t.Fatalf("Failed to insert document: %v", err)
+	}
+	if !generated.WasGenerated || generated.GeneratedAt.IsZero() {
+		t.Errorf("Expected generated ID with timestamp, got %+v", generated)
+	}
+	if _, ok := generated.InsertedID.(string); !ok {
+		t.Errorf("Expected generated ULID string, got %T", generated.InsertedID)
+	}
+
+	// Caller-provided ObjectID
+	objectID := bson.NewObjectID()
+	provided, err := collection.InsertOne(ctx, bson.M{"_id": objectID, "name": "provided"})
+	if err != nil {
+		t.Fatalf("Failed to insert document: %v", err)
+	}
+	if provided.WasGenerated || !provided.GeneratedAt.IsZero() {
+		t.Errorf("Expected caller-provided ID to be reported, got %+v", provided)
+	}
+	if provided.InsertedID != objectID {
+		t.Errorf("Expected InsertedID %v, got %v", objectID, provided.InsertedID)
+	}
+
+	// Caller-provided custom string ID on a struct
+	type account struct {
+		ID   string `bson:"_id"`
+		Name string `bson:"name"`
+	}
+	custom, err := collection.InsertOne(ctx, &account{ID: "acct-42", Name: "custom"})
+	if err != nil {
+		t.Fatalf("Failed to insert document: %v", err)
+	}
+	if custom.WasGenerated || custom.InsertedID != "acct-42" {
+		t.Errorf("Expected custom ID acct-42 to be reported as provided, got %+v", custom)
+	}
+}
+
+// TestPrepareMapDocumentLeavesCallerMapUnchanged tests that ULIDs are added to a copy of map documents
+func TestPrepareMapDocumentLeavesCallerMapUnchanged(t *testing.T) {
+	col := newTestCollection("events", withIDMode(IDModeULID))
+	documents := map[string]any{
+		"bson.M":         bson.M{"name": "alice"},
+		"map[string]any": map[string]any{"name": "alice"},
+	}
+
+	for name, document := range documents {
+		t.Run(name, func(t *testing.T) {
+			doc, err := col.prepareDocumentForInsert(document)
+			if err != nil {
+				t.Fatalf("prepareDocumentForInsert failed: %v", err)
+			}
+			prepared, ok := doc.(bson.M)
+			if !ok {
+				t.Fatalf("Expected bson.M, got %T", doc)
+			}
+			id, ok := prepared["_id"].(string)
+			if !ok || len(id) != 26 {
+				t.Errorf("Expected a ULID _id, got %v", prepared["_id"])
+			}
+			if prepared["name"] != "alice" {
+				t.Errorf("Expected fields to be copied, got %v", prepared)
+			}
+
+			var original map[string]any
+			switch m := document.(type) {
+			case bson.M:
+				original = m
+			case map[string]any:
+				original = m
+			}
+			if _, exists := original["_id"]; exists || len(original) != 1 {
+				t.Errorf("Expected caller map to be unchanged, got %v", original)
+			}
+		})
+	}
+
+	// A caller-provided _id is kept as is
+	withID := bson.M{"_id": "custom", "name": "alice"}
+	doc, err := col.prepareDocumentForInsert(withID)
+	if err != nil {
+		t.Fatalf("prepareDocumentForInsert failed: %v", err)
+	}
+	if doc.(bson.M)["_id"] != "custom" {
+		t.Errorf("Expected the provided _id, got %v", doc)
+	}
+}
+
+// TestPrepareMapDocumentEncodesOnce tests that map documents get their ULID without being
+// marshaled, so the driver's marshal is the only encoding on insert
+func TestPrepareMapDocumentEncodesOnce(t *testing.T) {
+	col := newTestCollection("events", withIDMode(IDModeULID))
+	document := newMapInsertDocument()
+
+	prepareAllocs := testing.AllocsPerRun(100, func() {
+		if _, err := col.prepareDocumentForInsert(document); err != nil {
+			t.Fatal(err)
+		}
+	})
+	marshalAllocs := testing.AllocsPerRun(100, func() {
+		if _, err := bson.Marshal(document); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if prepareAllocs >= marshalAllocs {
+		t.Errorf("Expected preparation to allocate less than one marshal (%v), got %v", marshalAllocs, prepareAllocs)
+	}
+}
+
+// newMapInsertDocument returns a map document like the preparedEvent used by the insert benchmarks
+func newMapInsertDocument() bson.M {
+	return bson.M{
+		"type":       "click",
+		"source":     "web",
+		"count":      1,
+		"tags":       []string{"a", "b", "c"},
+		"created_at": time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// BenchmarkMapInsertEncoding measures the insert path for bson.M values in ULID mode, which
+// adds the ID to a copy of the map and encodes it once
+func BenchmarkMapInsertEncoding(b *testing.B) {
+	col := newTestCollection("events", withIDMode(IDModeULID))
+	document := newMapInsertDocument()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		doc, err := col.prepareDocumentForInsert(document)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := bson.Marshal(doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkMapInsertRoundTripEncoding measures the marshal/unmarshal fallback used for
+// non-pointer structs, applied to the same map, for comparison with BenchmarkMapInsertEncoding
+func BenchmarkMapInsertRoundTripEncoding(b *testing.B) {
+	document := newMapInsertDocument()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		data, err := bson.Marshal(document)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var docMap bson.M
+		if err := bson.Unmarshal(data, &docMap); err != nil {
+			b.Fatal(err)
+		}
+		docMap["_id"] = generateULID()
+		if _, err := bson.Marshal(docMap); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 // TestULIDUniqueness tests that multiple ULIDs are unique
 func TestULIDUniqueness(t *testing.T) {
 	if testing.Short() {
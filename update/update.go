@@ -1,13 +1,25 @@
 package update
 
 import (
+	"bytes"
 	"fmt"
+	"slices"
 
 	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/internal/bsonutil"
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-// Builder represents a fluent update builder for MongoDB update operations
+// Builder represents a fluent update builder for MongoDB update operations.
+//
+// The method versions (Set, Inc, SetOnInsert, ...) modify the receiver in place and return it
+// for chaining. A builder that is cached and reused as a base therefore accumulates fields
+// across calls; call Clone first to derive an independent update:
+//
+//	base := update.SetOnInsert("created_at", now)
+//	u1 := base.Clone().Set("status", "active")
+//	u2 := base.Clone().Set("status", "pending") // base and u1 are unaffected
 type Builder struct {
 	update bson.M
 }
@@ -32,6 +44,33 @@ func (b *Builder) ToBSONM() bson.M {
 	return b.Build()
 }
 
+// Clone returns a deep copy of the builder that shares no state with the original
+func (b *Builder) Clone() *Builder {
+	if b == nil {
+		return New()
+	}
+	update := bsonutil.DeepCopyM(b.update)
+	if update == nil {
+		update = bson.M{}
+	}
+	return &Builder{update: update}
+}
+
+// Operators returns the sorted update operators currently present, e.g. ["$inc", "$set"].
+// It is meant for debugging and validation layers, e.g. rejecting updates that use $unset.
+func (b *Builder) Operators() []string {
+	if b == nil {
+		return []string{}
+	}
+
+	operators := make([]string, 0, len(b.update))
+	for operator := range b.update {
+		operators = append(operators, operator)
+	}
+	slices.Sort(operators)
+	return operators
+}
+
 // Field Update Operators
 
 // Set sets the value of a field
@@ -72,6 +111,16 @@ func (b *Builder) SetMap(fields map[string]any) *Builder {
 	return b
 }
 
+// SetMany sets multiple fields under a single $set; it is equivalent to SetMap
+func SetMany(fields bson.M) *Builder {
+	return SetMap(fields)
+}
+
+// SetMany sets multiple fields under a single $set (method version)
+func (b *Builder) SetMany(fields bson.M) *Builder {
+	return b.SetMap(fields)
+}
+
 // SetStruct sets all fields from a struct.
 // Returns an error if the document cannot be marshaled to BSON.
 func SetStruct(document any) (*Builder, error) {
@@ -117,6 +166,67 @@ func (b *Builder) SetStruct(document any) (*Builder, error) {
 	return b, nil
 }
 
+// SetStructNonZero sets only the non-zero fields of a struct, for partial "patch" updates.
+// Fields are omitted as if every field had the bson omitempty option: zero numbers, empty
+// strings, false, nil pointers, empty slices and maps, zero time.Time values and zero nested
+// structs are left untouched in the stored document.
+//
+// To set a field to its zero value, use a pointer field (a non-nil pointer to a zero value is
+// included) or an explicit Set. A non-zero nested struct replaces the whole subdocument.
+// Returns an error if the document cannot be marshaled to BSON.
+//
+// Example:
+//
+//	type UserPatch struct {
+//	    Name  string `bson:"name"`
+//	    Email string `bson:"email"`
+//	    Age   *int   `bson:"age"`
+//	}
+//	u, err := update.SetStructNonZero(UserPatch{Email: "new@example.com"}) // {"$set": {"email": ...}}
+func SetStructNonZero(document any) (*Builder, error) {
+	return New().SetStructNonZero(document)
+}
+
+// SetStructNonZero sets only the non-zero fields of a struct (method version).
+// Returns an error if the document cannot be marshaled to BSON.
+func (b *Builder) SetStructNonZero(document any) (*Builder, error) {
+	fields, err := marshalNonZeroFields(document)
+	if err != nil {
+		return nil, err
+	}
+
+	// Leave the builder unchanged rather than adding an empty $set
+	if len(fields) == 0 {
+		return b, nil
+	}
+
+	if b.update["$set"] == nil {
+		b.update["$set"] = bson.M{}
+	}
+	for k, v := range fields {
+		b.update["$set"].(bson.M)[k] = v
+	}
+
+	return b, nil
+}
+
+// marshalNonZeroFields converts a document to bson.M, omitting empty values and zero structs
+func marshalNonZeroFields(document any) (bson.M, error) {
+	var buf bytes.Buffer
+	enc := bson.NewEncoder(bson.NewDocumentWriter(&buf))
+	enc.OmitEmpty()
+	enc.OmitZeroStruct()
+	if err := enc.Encode(document); err != nil {
+		return nil, fmt.Errorf("failed to marshal document: %w", err)
+	}
+
+	var fields bson.M
+	if err := bson.Unmarshal(buf.Bytes(), &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal document: %w", err)
+	}
+	return fields, nil
+}
+
 // Unset removes the specified fields
 func Unset(fields ...string) *Builder {
 	unsetDoc := bson.M{}
@@ -155,6 +265,27 @@ func (b *Builder) Inc(field string, value any) *Builder {
 	return b
 }
 
+// IncMany increments multiple fields under a single $inc, e.g.
+// IncMany(bson.M{"views": 1, "score": 5})
+func IncMany(fields bson.M) *Builder {
+	return New().IncMany(fields)
+}
+
+// IncMany increments multiple fields under a single $inc (method version).
+// An empty map leaves the update unchanged.
+func (b *Builder) IncMany(fields bson.M) *Builder {
+	if len(fields) == 0 {
+		return b
+	}
+	if b.update["$inc"] == nil {
+		b.update["$inc"] = bson.M{}
+	}
+	for k, v := range fields {
+		b.update["$inc"].(bson.M)[k] = v
+	}
+	return b
+}
+
 // Mul multiplies the value of a field by a specified amount
 func Mul(field string, value any) *Builder {
 	return &Builder{
@@ -171,6 +302,37 @@ func (b *Builder) Mul(field string, value any) *Builder {
 	return b
 }
 
+// SetIfGreater sets field to value only if value is greater than the current value, or if
+// the field does not exist yet (sugar over $max), e.g. SetIfGreater("high_score", score)
+func SetIfGreater(field string, value any) *Builder {
+	return &Builder{
+		update: bson.M{"$max": bson.M{field: value}},
+	}
+}
+
+// SetIfGreater sets field to value only if value is greater than the current value (method version)
+func (b *Builder) SetIfGreater(field string, value any) *Builder {
+	if b.update["$max"] == nil {
+		b.update["$max"] = bson.M{}
+	}
+	b.update["$max"].(bson.M)[field] = value
+	return b
+}
+
+// SetIfUnset sets field to value only when the document does not have the field yet. The
+// update operators cannot express this condition ($setOnInsert only applies when an upsert
+// inserts), so it returns an update pipeline with a $cond expression instead of an update
+// Builder: pass it to UpdateOnePipeline or UpdateManyPipeline (MongoDB 4.2+), and chain
+// further stages on it as needed.
+//
+// Example:
+//
+//	p := update.SetIfUnset("plan", "free").SetIfUnset("quota", 100)
+//	_, err := col.UpdateManyPipeline(ctx, filter.New(), p)
+func SetIfUnset(field string, value any) *pipeline.Builder {
+	return pipeline.New().SetIfUnset(field, value)
+}
+
 // Rename renames a field
 func Rename(from, to string) *Builder {
 	return &Builder{
@@ -226,6 +388,8 @@ func (b *Builder) SetOnInsertMap(fields map[string]any) *Builder {
 }
 
 // SetOnInsertStruct sets all fields from a struct only when an upsert inserts a document.
+// Zero-value fields are included unless their bson tag has the omitempty option; use
+// SetOnInsertStructNonZero to leave out every zero-value field.
 // Returns an error if the document cannot be marshaled to BSON.
 func SetOnInsertStruct(document any) (*Builder, error) {
 	// Convert struct to bson.M
@@ -270,6 +434,39 @@ func (b *Builder) SetOnInsertStruct(document any) (*Builder, error) {
 	return b, nil
 }
 
+// SetOnInsertStructNonZero sets only the non-zero fields of a struct when an upsert inserts
+// a document, so that inserted documents are not seeded with empty values the caller did not
+// set. Fields are omitted as in SetStructNonZero, as if every field had the bson omitempty
+// option; use pointer fields to seed zero values explicitly.
+// Returns an error if the document cannot be marshaled to BSON.
+func SetOnInsertStructNonZero(document any) (*Builder, error) {
+	return New().SetOnInsertStructNonZero(document)
+}
+
+// SetOnInsertStructNonZero sets only the non-zero fields of a struct when an upsert inserts
+// a document (method version).
+// Returns an error if the document cannot be marshaled to BSON.
+func (b *Builder) SetOnInsertStructNonZero(document any) (*Builder, error) {
+	fields, err := marshalNonZeroFields(document)
+	if err != nil {
+		return nil, err
+	}
+
+	// Leave the builder unchanged rather than adding an empty $setOnInsert
+	if len(fields) == 0 {
+		return b, nil
+	}
+
+	if b.update["$setOnInsert"] == nil {
+		b.update["$setOnInsert"] = bson.M{}
+	}
+	for k, v := range fields {
+		b.update["$setOnInsert"].(bson.M)[k] = v
+	}
+
+	return b, nil
+}
+
 // Array Update Operators
 
 // Push appends a value to an array
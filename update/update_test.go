@@ -365,6 +365,114 @@ func TestSetStruct(t *testing.T) {
 	}
 }
 
+func TestSetStructNonZero(t *testing.T) {
+	type Address struct {
+		City string `bson:"city"`
+	}
+	type Patch struct {
+		Name      string    `bson:"name"`
+		Email     string    `bson:"email,omitempty"`
+		Age       int       `bson:"age"`
+		Active    bool      `bson:"active"`
+		Score     *int      `bson:"score"`
+		Tags      []string  `bson:"tags"`
+		Address   Address   `bson:"address"`
+		UpdatedAt time.Time `bson:"updated_at"`
+		Ignored   string    `bson:"-"`
+	}
+
+	zero := 0
+	patch := Patch{
+		Email:   "new@example.com",
+		Score:   &zero,
+		Ignored: "never",
+	}
+
+	u, err := SetStructNonZero(patch)
+	if err != nil {
+		t.Fatalf("SetStructNonZero failed: %v", err)
+	}
+
+	expected := bson.M{"$set": bson.M{"email": "new@example.com", "score": int32(0)}}
+	if !equalBSON(u.Build(), expected) {
+		t.Errorf("Expected %v, got %v", expected, u.Build())
+	}
+
+	// Set fields are included, zero fields are left out
+	patch = Patch{Name: "Ada", Age: 36, Active: true, Tags: []string{"admin"}, Address: Address{City: "London"}}
+	u, err = New().Set("version", 2).SetStructNonZero(patch)
+	if err != nil {
+		t.Fatalf("SetStructNonZero failed: %v", err)
+	}
+	set := u.Build()["$set"].(bson.M)
+	for _, field := range []string{"name", "age", "active", "tags", "address", "version"} {
+		if _, ok := set[field]; !ok {
+			t.Errorf("Expected %s in $set, got %v", field, set)
+		}
+	}
+	for _, field := range []string{"email", "score", "updated_at", "Ignored"} {
+		if _, ok := set[field]; ok {
+			t.Errorf("Expected %s to be omitted, got %v", field, set)
+		}
+	}
+
+	// An all-zero struct adds nothing
+	u, err = SetStructNonZero(Patch{})
+	if err != nil {
+		t.Fatalf("SetStructNonZero failed: %v", err)
+	}
+	if len(u.Build()) != 0 {
+		t.Errorf("Expected empty update for zero struct, got %v", u.Build())
+	}
+
+	if _, err := SetStructNonZero(42); err == nil {
+		t.Error("Expected error for non-document value")
+	}
+}
+
+func TestSetOnInsertStructNonZero(t *testing.T) {
+	type Account struct {
+		Plan    string `bson:"plan"`
+		Credits int    `bson:"credits"`
+		Region  string `bson:"region,omitempty"`
+		Trial   *bool  `bson:"trial"`
+	}
+
+	trial := false
+	account := Account{Plan: "free", Trial: &trial}
+
+	// SetOnInsertStruct seeds zero values unless the field is tagged omitempty
+	all, err := SetOnInsertStruct(account)
+	if err != nil {
+		t.Fatalf("SetOnInsertStruct failed: %v", err)
+	}
+	expectedAll := bson.M{"$setOnInsert": bson.M{"plan": "free", "credits": int32(0), "trial": false}}
+	if !equalBSON(all.Build(), expectedAll) {
+		t.Errorf("SetOnInsertStruct: expected %v, got %v", expectedAll, all.Build())
+	}
+
+	// SetOnInsertStructNonZero leaves out every zero value, except through non-nil pointers
+	nonZero, err := New().Set("last_seen", "now").SetOnInsertStructNonZero(account)
+	if err != nil {
+		t.Fatalf("SetOnInsertStructNonZero failed: %v", err)
+	}
+	expectedNonZero := bson.M{
+		"$set":         bson.M{"last_seen": "now"},
+		"$setOnInsert": bson.M{"plan": "free", "trial": false},
+	}
+	if !equalBSON(nonZero.Build(), expectedNonZero) {
+		t.Errorf("SetOnInsertStructNonZero: expected %v, got %v", expectedNonZero, nonZero.Build())
+	}
+
+	empty, err := SetOnInsertStructNonZero(Account{})
+	if err != nil {
+		t.Fatalf("SetOnInsertStructNonZero failed: %v", err)
+	}
+	if len(empty.Build()) != 0 {
+		t.Errorf("Expected empty update for zero struct, got %v", empty.Build())
+	}
+}
+
 func TestCombinedSetAndSetOnInsert(t *testing.T) {
 	u := New().
 		Set("updated_at", time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)).
@@ -400,3 +508,127 @@ func equalBSON(a, b bson.M) bool {
 	// Use deep equality check for robust comparison
 	return reflect.DeepEqual(a, b)
 }
+
+func TestClone(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := SetOnInsert("created_at", createdAt)
+
+	u1 := base.Clone().Set("status", "active")
+	u2 := base.Clone().Set("status", "pending").Inc("attempts", 1)
+
+	expectedBase := bson.M{"$setOnInsert": bson.M{"created_at": createdAt}}
+	if !equalBSON(base.Build(), expectedBase) {
+		t.Errorf("Base update accumulated fields: %v", base.Build())
+	}
+
+	expected1 := bson.M{
+		"$setOnInsert": bson.M{"created_at": createdAt},
+		"$set":         bson.M{"status": "active"},
+	}
+	if !equalBSON(u1.Build(), expected1) {
+		t.Errorf("Expected %v, got %v", expected1, u1.Build())
+	}
+
+	expected2 := bson.M{
+		"$setOnInsert": bson.M{"created_at": createdAt},
+		"$set":         bson.M{"status": "pending"},
+		"$inc":         bson.M{"attempts": 1},
+	}
+	if !equalBSON(u2.Build(), expected2) {
+		t.Errorf("Expected %v, got %v", expected2, u2.Build())
+	}
+
+	// Adding to an operator that already exists in the base must not leak back
+	u3 := base.Clone().SetOnInsert("owner", "system")
+	if _, leaked := base.Build()["$setOnInsert"].(bson.M)["owner"]; leaked {
+		t.Error("Clone shares nested operator documents with the original")
+	}
+	if len(u3.Build()["$setOnInsert"].(bson.M)) != 2 {
+		t.Errorf("Expected 2 $setOnInsert fields, got %v", u3.Build())
+	}
+
+	var nilBuilder *Builder
+	if len(nilBuilder.Clone().Build()) != 0 {
+		t.Error("Expected empty update when cloning nil builder")
+	}
+}
+
+func TestOperators(t *testing.T) {
+	var nilBuilder *Builder
+	if got := nilBuilder.Operators(); len(got) != 0 {
+		t.Errorf("Expected no operators for nil builder, got %v", got)
+	}
+	if got := New().Operators(); len(got) != 0 {
+		t.Errorf("Expected no operators for empty update, got %v", got)
+	}
+
+	u := Set("status", "done").Inc("count", 1).Set("owner", "bob").Unset("draft")
+	expected := []string{"$inc", "$set", "$unset"}
+	if got := u.Operators(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestIncManyAndSetMany(t *testing.T) {
+	u := IncMany(bson.M{"views": 1, "score": 5}).
+		Inc("shares", 2).
+		SetMany(bson.M{"status": "hot", "rank": 3}).
+		Set("flag", true)
+
+	expected := bson.M{
+		"$inc": bson.M{"views": 1, "score": 5, "shares": 2},
+		"$set": bson.M{"status": "hot", "rank": 3, "flag": true},
+	}
+	if !reflect.DeepEqual(u.Build(), expected) {
+		t.Errorf("Expected all fields under single $inc/$set, got %v", u.Build())
+	}
+
+	// Standalone SetMany followed by IncMany on the same builder
+	u = SetMany(bson.M{"a": 1, "b": 2}).IncMany(bson.M{"c": 1})
+	expected = bson.M{
+		"$set": bson.M{"a": 1, "b": 2},
+		"$inc": bson.M{"c": 1},
+	}
+	if !reflect.DeepEqual(u.Build(), expected) {
+		t.Errorf("Expected %v, got %v", expected, u.Build())
+	}
+
+	// An empty map adds no operator
+	if doc := New().IncMany(bson.M{}).Build(); len(doc) != 0 {
+		t.Errorf("Expected empty update, got %v", doc)
+	}
+}
+
+func TestSetIfGreater(t *testing.T) {
+	u := SetIfGreater("high_score", 90).SetIfGreater("level", 3).Set("player", "ada")
+
+	expected := bson.M{
+		"$max": bson.M{"high_score": 90, "level": 3},
+		"$set": bson.M{"player": "ada"},
+	}
+	if !reflect.DeepEqual(u.Build(), expected) {
+		t.Errorf("Expected %v, got %v", expected, u.Build())
+	}
+}
+
+func TestSetIfUnset(t *testing.T) {
+	stages := SetIfUnset("plan", "free").SetIfUnset("currency", "$USD").Build()
+	if len(stages) != 2 {
+		t.Fatalf("Expected 2 pipeline stages, got %d", len(stages))
+	}
+
+	expected := bson.M{"$set": bson.M{"plan": bson.M{"$cond": bson.M{
+		"if":   bson.M{"$eq": bson.A{bson.M{"$type": "$plan"}, "missing"}},
+		"then": bson.M{"$literal": "free"},
+		"else": "$plan",
+	}}}}
+	if !reflect.DeepEqual(stages[0], expected) {
+		t.Errorf("Expected %v, got %v", expected, stages[0])
+	}
+
+	// Values that look like field paths are kept literal
+	cond := stages[1]["$set"].(bson.M)["currency"].(bson.M)["$cond"].(bson.M)
+	if !reflect.DeepEqual(cond["then"], bson.M{"$literal": "$USD"}) {
+		t.Errorf("Expected literal value, got %v", cond["then"])
+	}
+}
@@ -0,0 +1,151 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidURI is returned by ParseURI for malformed or unsupported connection strings
+var ErrInvalidURI = errors.New("invalid connection string")
+
+// ParseURI decomposes a MongoDB connection string into a Config, starting from the same
+// defaults as NewClient. This lets you start from a URI and adjust individual settings
+// before creating the client with NewClientWithConfig.
+//
+// Both mongodb:// and mongodb+srv:// are supported; credentials and the database name are
+// percent-decoded. When credentials are given without authSource, AuthDatabase is the
+// database from the path, or "admin", as the driver would use. Driver options that have no
+// Config field (retryWrites, authMechanism, timeoutMS, tlsCAFile, ...) are kept and passed
+// through to the driver, which validates them when the client is created. Options provided
+// by SRV TXT records are applied by the driver at connect time and are not reflected here.
+//
+// Example:
+//
+//	config, err := mongodb.ParseURI(os.Getenv("MONGODB_URI"))
+//	if err != nil {
+//	    return err
+//	}
+//	config.AppName = "billing"
+//	client, err := mongodb.NewClientWithConfig(config)
+func ParseURI(uri string) (*Config, error) {
+	config := newDefaultConfig()
+
+	rest, ok := strings.CutPrefix(uri, "mongodb://")
+	if !ok {
+		if rest, ok = strings.CutPrefix(uri, "mongodb+srv://"); !ok {
+			return nil, fmt.Errorf("%w: scheme must be mongodb:// or mongodb+srv://", ErrInvalidURI)
+		}
+		config.SRV = true
+	}
+
+	rest, query, _ := strings.Cut(rest, "?")
+	authority, path, _ := strings.Cut(rest, "/")
+
+	// Credentials must have reserved characters percent-encoded, so the last @ ends them
+	hasCredentials := false
+	if at := strings.LastIndex(authority, "@"); at >= 0 {
+		username, password, _ := strings.Cut(authority[:at], ":")
+		var err error
+		if config.Username, err = url.PathUnescape(username); err != nil {
+			return nil, fmt.Errorf("%w: username: %v", ErrInvalidURI, err)
+		}
+		if config.Password, err = url.PathUnescape(password); err != nil {
+			return nil, fmt.Errorf("%w: password: %v", ErrInvalidURI, err)
+		}
+		hasCredentials = config.Username != ""
+		authority = authority[at+1:]
+	}
+
+	if authority == "" {
+		return nil, fmt.Errorf("%w: no hosts", ErrInvalidURI)
+	}
+	if config.SRV && (strings.Contains(authority, ",") || strings.Contains(authority, ":")) {
+		return nil, fmt.Errorf("%w: mongodb+srv requires a single host name without a port", ErrInvalidURI)
+	}
+	config.Hosts = authority
+
+	if path != "" {
+		database, err := url.PathUnescape(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: database: %v", ErrInvalidURI, err)
+		}
+		config.Database = database
+		config.databaseSet = true
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: options: %v", ErrInvalidURI, err)
+	}
+
+	authSourceSet := false
+	for key, list := range values {
+		value := list[len(list)-1]
+		// Option names are case-insensitive
+		switch strings.ToLower(key) {
+		case "authsource":
+			config.AuthDatabase = value
+			authSourceSet = true
+		case "replicaset":
+			config.ReplicaSet = value
+		case "appname":
+			config.AppName = value
+		case "compressors":
+			config.CompressionEnabled = value != ""
+			config.CompressionAlgorithm = value
+		case "readpreference":
+			config.ReadPreference = value
+		case "w":
+			config.WriteConcern = value
+		case "readconcernlevel":
+			config.ReadConcern = value
+		case "directconnection":
+			config.DirectConnection, err = strconv.ParseBool(value)
+		case "tls", "ssl":
+			config.TLSEnabled, err = strconv.ParseBool(value)
+		case "maxpoolsize":
+			config.MaxPoolSize, err = strconv.ParseUint(value, 10, 64)
+		case "minpoolsize":
+			config.MinPoolSize, err = strconv.ParseUint(value, 10, 64)
+		case "maxidletimems":
+			config.MaxConnIdleTime, err = parseMilliseconds(value)
+		case "connecttimeoutms":
+			config.ConnectTimeout, err = parseMilliseconds(value)
+		case "serverselectiontimeoutms":
+			config.ServerSelectTimeout, err = parseMilliseconds(value)
+		case "sockettimeoutms":
+			config.SocketTimeout, err = parseMilliseconds(value)
+		default:
+			if config.uriOptions == nil {
+				config.uriOptions = url.Values{}
+			}
+			config.uriOptions[key] = list
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: option %s: %v", ErrInvalidURI, key, err)
+		}
+	}
+
+	if hasCredentials && !authSourceSet && config.databaseSet {
+		config.AuthDatabase = config.Database
+	}
+
+	if config.ReadPreference != "" && !isValidReadPreference(config.ReadPreference) {
+		return nil, fmt.Errorf("%w: invalid read preference %q", ErrInvalidURI, config.ReadPreference)
+	}
+
+	return config, nil
+}
+
+// parseMilliseconds parses a non-negative integer number of milliseconds
+func parseMilliseconds(value string) (time.Duration, error) {
+	ms, err := strconv.ParseUint(value, 10, 63)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
@@ -0,0 +1,208 @@
+package mongodb
+
+import (
+	"errors"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// documentValidationFailureCode is the server error code of a write rejected by the
+// collection validator
+const documentValidationFailureCode = 121
+
+// FieldError describes one validator rule that a rejected document did not satisfy
+type FieldError struct {
+	// Field is the dotted path of the offending field, with array indexes as path elements
+	// (e.g. "items.2.qty"); it is empty for rules on the whole document
+	Field string `json:"field"`
+	// Rule is the schema keyword or query operator that failed, e.g. "required", "bsonType",
+	// "minimum", "enum" or "$gt"
+	Rule string `json:"rule"`
+	// Reason is the server explanation, e.g. "comparison failed" or "type did not match"
+	Reason string `json:"reason,omitempty"`
+	// Description is the description given to the property in the $jsonSchema, if any
+	Description string `json:"description,omitempty"`
+	// Value is the value the server considered, if reported
+	Value any `json:"value,omitempty"`
+}
+
+// IsValidationError reports whether err is a write rejected by the collection validator
+// (DocumentValidationFailure)
+func IsValidationError(err error) bool {
+	_, ok := validationErrInfo(err)
+	return ok
+}
+
+// ValidationErrorDetails extracts the rules a document failed from a write rejected by the
+// collection validator, in a form suitable for API responses. The boolean reports whether err
+// is a DocumentValidationFailure at all; the details are reported by MongoDB 5.0 and later, so
+// the slice is empty for older servers.
+//
+// $jsonSchema validators report the failing property paths; for query-operator validators
+// (e.g. {price: {$gt: 0}}) the field is taken from the failing clause.
+//
+// Example:
+//
+//	_, err := products.InsertOne(ctx, product)
+//	if fields, ok := mongodb.ValidationErrorDetails(err); ok {
+//	    return c.JSON(http.StatusUnprocessableEntity, fields)
+//	}
+func ValidationErrorDetails(err error) ([]FieldError, bool) {
+	errInfo, ok := validationErrInfo(err)
+	if !ok {
+		return nil, false
+	}
+
+	fields := []FieldError{}
+	if details, ok := errInfo.Lookup("details").DocumentOK(); ok {
+		collectFieldErrors(details, "", "", &fields)
+	}
+	return fields, true
+}
+
+// validationErrInfo returns the errInfo document of the first DocumentValidationFailure in
+// err; errInfo is empty when the server did not report details
+func validationErrInfo(err error) (bson.Raw, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	var writeErr mongo.WriteException
+	if errors.As(err, &writeErr) {
+		for _, we := range writeErr.WriteErrors {
+			if we.Code == documentValidationFailureCode {
+				return we.Details, true
+			}
+		}
+	}
+
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) {
+		for _, we := range bulkErr.WriteErrors {
+			if we.Code == documentValidationFailureCode {
+				return we.Details, true
+			}
+		}
+	}
+
+	// findAndModify reports validation failures as command errors
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == documentValidationFailureCode {
+		errInfo, _ := cmdErr.Raw.Lookup("errInfo").DocumentOK()
+		return errInfo, true
+	}
+
+	return nil, false
+}
+
+// collectFieldErrors walks a validation failure detail document. $jsonSchema failures nest
+// schemaRulesNotSatisfied, propertiesNotSatisfied and items details down to the failing
+// keywords; query-operator failures nest clausesNotSatisfied down to the failing operators.
+func collectFieldErrors(detail bson.Raw, path, description string, out *[]FieldError) {
+	operator, _ := detail.Lookup("operatorName").StringValueOK()
+
+	switch operator {
+	case "required":
+		for _, name := range rawStrings(detail.Lookup("missingProperties")) {
+			*out = append(*out, FieldError{Field: joinFieldPath(path, name), Rule: operator, Reason: "field is missing"})
+		}
+		return
+	case "additionalProperties":
+		for _, name := range rawStrings(detail.Lookup("additionalProperties")) {
+			*out = append(*out, FieldError{Field: joinFieldPath(path, name), Rule: operator, Reason: "field is not allowed"})
+		}
+		return
+	case "properties":
+		for _, property := range rawDocuments(detail.Lookup("propertiesNotSatisfied")) {
+			name, _ := property.Lookup("propertyName").StringValueOK()
+			desc, _ := property.Lookup("description").StringValueOK()
+			for _, nested := range rawDocuments(property.Lookup("details")) {
+				collectFieldErrors(nested, joinFieldPath(path, name), desc, out)
+			}
+		}
+		return
+	case "items":
+		itemPath := path
+		if index, ok := detail.Lookup("itemIndex").AsInt64OK(); ok {
+			itemPath = joinFieldPath(path, strconv.FormatInt(index, 10))
+		}
+		for _, nested := range rawDocuments(detail.Lookup("details")) {
+			collectFieldErrors(nested, itemPath, description, out)
+		}
+		return
+	}
+
+	nested := false
+	for _, key := range []string{"schemaRulesNotSatisfied", "clausesNotSatisfied", "details"} {
+		for _, child := range rawDocuments(detail.Lookup(key)) {
+			nested = true
+			// Clauses wrap their detail as {index, details: {...}}
+			if inner, ok := child.Lookup("details").DocumentOK(); ok && child.Lookup("operatorName").IsZero() {
+				child = inner
+			}
+			collectFieldErrors(child, path, description, out)
+		}
+	}
+	if nested {
+		return
+	}
+
+	reason, _ := detail.Lookup("reason").StringValueOK()
+	if operator == "" && reason == "" {
+		return
+	}
+
+	fieldErr := FieldError{Field: path, Rule: operator, Reason: reason, Description: description}
+	if fieldErr.Field == "" {
+		// Query operators name the field in their specification, e.g. {price: {$gt: 0}}
+		if spec, ok := detail.Lookup("specifiedAs").DocumentOK(); ok {
+			if elements, err := spec.Elements(); err == nil && len(elements) == 1 && elements[0].Key() != operator {
+				fieldErr.Field = elements[0].Key()
+			}
+		}
+	}
+	if value := detail.Lookup("consideredValue"); !value.IsZero() {
+		var v any
+		if err := value.Unmarshal(&v); err == nil {
+			fieldErr.Value = v
+		}
+	}
+	*out = append(*out, fieldErr)
+}
+
+// joinFieldPath appends a field name to a dotted path
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// rawDocuments returns the documents of an array value, or the value itself if it is a document
+func rawDocuments(value bson.RawValue) []bson.Raw {
+	if doc, ok := value.DocumentOK(); ok {
+		return []bson.Raw{doc}
+	}
+	return planDocuments(value)
+}
+
+// rawStrings returns the strings of an array value
+func rawStrings(value bson.RawValue) []string {
+	array, ok := value.ArrayOK()
+	if !ok {
+		return nil
+	}
+	values, err := array.Values()
+	if err != nil {
+		return nil
+	}
+	var strs []string
+	for _, v := range values {
+		if s, ok := v.StringValueOK(); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
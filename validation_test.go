@@ -0,0 +1,156 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// schemaErrInfo is the errInfo reported by MongoDB 5.0+ for a document failing a $jsonSchema
+// validator on several rules
+func schemaErrInfo(t *testing.T) bson.Raw {
+	t.Helper()
+	raw, err := bson.Marshal(bson.D{
+		{Key: "failingDocumentId", Value: "p1"},
+		{Key: "details", Value: bson.D{
+			{Key: "operatorName", Value: "$jsonSchema"},
+			{Key: "schemaRulesNotSatisfied", Value: bson.A{
+				bson.D{
+					{Key: "operatorName", Value: "properties"},
+					{Key: "propertiesNotSatisfied", Value: bson.A{
+						bson.D{
+							{Key: "propertyName", Value: "price"},
+							{Key: "description", Value: "must be a positive number"},
+							{Key: "details", Value: bson.A{bson.D{
+								{Key: "operatorName", Value: "minimum"},
+								{Key: "specifiedAs", Value: bson.D{{Key: "minimum", Value: 0}}},
+								{Key: "reason", Value: "comparison failed"},
+								{Key: "consideredValue", Value: int32(-5)},
+							}}},
+						},
+						bson.D{
+							{Key: "propertyName", Value: "items"},
+							{Key: "details", Value: bson.A{bson.D{
+								{Key: "operatorName", Value: "items"},
+								{Key: "reason", Value: "At least one item did not match the sub-schema"},
+								{Key: "itemIndex", Value: int32(2)},
+								{Key: "details", Value: bson.A{bson.D{
+									{Key: "operatorName", Value: "properties"},
+									{Key: "propertiesNotSatisfied", Value: bson.A{bson.D{
+										{Key: "propertyName", Value: "qty"},
+										{Key: "details", Value: bson.A{bson.D{
+											{Key: "operatorName", Value: "bsonType"},
+											{Key: "specifiedAs", Value: bson.D{{Key: "bsonType", Value: "int"}}},
+											{Key: "reason", Value: "type did not match"},
+											{Key: "consideredValue", Value: "three"},
+											{Key: "consideredType", Value: "string"},
+										}}},
+									}}},
+								}}},
+							}}},
+						},
+					}},
+				},
+				bson.D{
+					{Key: "operatorName", Value: "required"},
+					{Key: "specifiedAs", Value: bson.D{{Key: "required", Value: bson.A{"name", "price"}}}},
+					{Key: "missingProperties", Value: bson.A{"name"}},
+				},
+				bson.D{
+					{Key: "operatorName", Value: "additionalProperties"},
+					{Key: "specifiedAs", Value: bson.D{{Key: "additionalProperties", Value: false}}},
+					{Key: "additionalProperties", Value: bson.A{"debug"}},
+				},
+			}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal errInfo fixture: %v", err)
+	}
+	return raw
+}
+
+func TestValidationErrorDetailsJSONSchema(t *testing.T) {
+	err := mongo.WriteException{WriteErrors: []mongo.WriteError{{
+		Code:    documentValidationFailureCode,
+		Message: "Document failed validation",
+		Details: schemaErrInfo(t),
+	}}}
+
+	// The error is usually wrapped on its way up
+	fields, ok := ValidationErrorDetails(fmt.Errorf("create product: %w", err))
+	if !ok {
+		t.Fatal("Expected a validation error")
+	}
+
+	expected := []FieldError{
+		{Field: "price", Rule: "minimum", Reason: "comparison failed", Description: "must be a positive number", Value: int32(-5)},
+		{Field: "items.2.qty", Rule: "bsonType", Reason: "type did not match", Value: "three"},
+		{Field: "name", Rule: "required", Reason: "field is missing"},
+		{Field: "debug", Rule: "additionalProperties", Reason: "field is not allowed"},
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Unexpected field errors:\n%+v\nexpected:\n%+v", fields, expected)
+	}
+	if !IsValidationError(err) {
+		t.Error("Expected IsValidationError to report true")
+	}
+}
+
+func TestValidationErrorDetailsQueryOperators(t *testing.T) {
+	response, err := bson.Marshal(bson.D{
+		{Key: "ok", Value: 0},
+		{Key: "code", Value: documentValidationFailureCode},
+		{Key: "errmsg", Value: "Document failed validation"},
+		{Key: "errInfo", Value: bson.D{{Key: "details", Value: bson.D{
+			{Key: "operatorName", Value: "$and"},
+			{Key: "clausesNotSatisfied", Value: bson.A{bson.D{
+				{Key: "index", Value: int32(1)},
+				{Key: "details", Value: bson.D{
+					{Key: "operatorName", Value: "$gt"},
+					{Key: "specifiedAs", Value: bson.D{{Key: "stock", Value: bson.D{{Key: "$gt", Value: 0}}}}},
+					{Key: "reason", Value: "comparison failed"},
+					{Key: "consideredValue", Value: int32(0)},
+				}},
+			}}},
+		}}}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal response fixture: %v", err)
+	}
+
+	// findAndModify reports validation failures as command errors
+	cmdErr := mongo.CommandError{Code: documentValidationFailureCode, Message: "Document failed validation", Raw: response}
+
+	fields, ok := ValidationErrorDetails(cmdErr)
+	if !ok {
+		t.Fatal("Expected a validation error")
+	}
+	expected := []FieldError{{Field: "stock", Rule: "$gt", Reason: "comparison failed", Value: int32(0)}}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, fields)
+	}
+}
+
+func TestValidationErrorDetailsOtherErrors(t *testing.T) {
+	duplicate := mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key"}}}
+
+	for _, err := range []error{nil, errors.New("boom"), duplicate} {
+		if fields, ok := ValidationErrorDetails(err); ok || fields != nil {
+			t.Errorf("Expected no validation details for %v, got %v", err, fields)
+		}
+	}
+
+	// Servers before 5.0 report the failure without details
+	bulk := mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{
+		WriteError: mongo.WriteError{Code: documentValidationFailureCode, Message: "Document failed validation"},
+	}}}
+	fields, ok := ValidationErrorDetails(bulk)
+	if !ok || len(fields) != 0 || fields == nil {
+		t.Errorf("Expected a validation error with an empty detail list, got %v, %v", fields, ok)
+	}
+}
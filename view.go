@@ -0,0 +1,68 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// CreateView creates a read-only view named name that presents the documents of the
+// onCollection collection of the same database through the aggregation pipeline. The
+// pipeline runs every time the view is queried; a nil pipeline exposes the collection as is.
+// Pipelines writing their output with $out or $merge are rejected.
+//
+// Example:
+//
+//	p := pipeline.New().
+//	    Match(filter.Eq("active", true)).
+//	    Project(bson.M{"name": 1, "email": 1})
+//	err := db.CreateView(ctx, "active_users", "users", p)
+func (db *Database) CreateView(ctx context.Context, name, onCollection string, pipelineBuilder *pipeline.Builder) error {
+	cmd, err := createViewCommand(name, onCollection, pipelineBuilder)
+	if err != nil {
+		return err
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	if err := db.checkConnected(); err != nil {
+		return err
+	}
+
+	if err := db.database.RunCommand(ctx, cmd).Err(); err != nil {
+		return fmt.Errorf("failed to create view %s: %w", name, err)
+	}
+	return nil
+}
+
+// createViewCommand builds the create command of a view
+func createViewCommand(name, onCollection string, pipelineBuilder *pipeline.Builder) (bson.D, error) {
+	if name == "" || onCollection == "" {
+		return nil, errors.New("view name and source collection are required")
+	}
+
+	stages := bson.A{}
+	if pipelineBuilder != nil {
+		if err := pipelineBuilder.Validate(); err != nil {
+			return nil, err
+		}
+		stages = pipelineBuilder.ToBSONArray()
+	}
+	if stage := writeStage(stages); stage != "" {
+		return nil, fmt.Errorf("view pipeline must not contain a %s stage", stage)
+	}
+
+	return bson.D{
+		{Key: "create", Value: name},
+		{Key: "viewOn", Value: onCollection},
+		{Key: "pipeline", Value: stages},
+	}, nil
+}
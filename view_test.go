@@ -0,0 +1,114 @@
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cloudresty/go-mongodb/v2/filter"
+	"github.com/cloudresty/go-mongodb/v2/pipeline"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestCreateViewCommand(t *testing.T) {
+	p := pipeline.New().
+		Match(filter.Eq("active", true)).
+		Project(bson.M{"name": 1, "email": 1})
+
+	cmd, err := createViewCommand("active_users", "users", p)
+	if err != nil {
+		t.Fatalf("createViewCommand failed: %v", err)
+	}
+
+	expected := bson.D{
+		{Key: "create", Value: "active_users"},
+		{Key: "viewOn", Value: "users"},
+		{Key: "pipeline", Value: bson.A{
+			bson.M{"$match": bson.M{"active": true}},
+			bson.M{"$project": bson.M{"name": 1, "email": 1}},
+		}},
+	}
+	if !reflect.DeepEqual(cmd, expected) {
+		t.Errorf("Expected %v, got %v", expected, cmd)
+	}
+
+	// A nil pipeline still sends an empty pipeline array
+	cmd, err = createViewCommand("all_users", "users", nil)
+	if err != nil {
+		t.Fatalf("createViewCommand failed: %v", err)
+	}
+	if stages, ok := cmd[2].Value.(bson.A); !ok || len(stages) != 0 {
+		t.Errorf("Expected an empty pipeline array, got %v", cmd[2].Value)
+	}
+}
+
+func TestCreateViewCommandRejectsInvalidViews(t *testing.T) {
+	tests := []struct {
+		name         string
+		view         string
+		onCollection string
+		pipeline     *pipeline.Builder
+	}{
+		{"missing name", "", "users", nil},
+		{"missing source", "active_users", "", nil},
+		{"out stage", "active_users", "users", pipeline.New().MatchRaw(bson.M{}).Raw(bson.M{"$out": "copy"})},
+		{"invalid pipeline", "active_users", "users", pipeline.New().PreserveEmpty()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := createViewCommand(tt.view, tt.onCollection, tt.pipeline); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestCreateViewIntegration(t *testing.T) {
+	client := setupTestClientForEnhanced(t)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	db := client.Database(client.config.Database)
+	users := db.Collection("test_view_users")
+	_ = users.Drop(ctx)
+	_ = db.DropCollection(ctx, "test_view_active_users")
+	defer func() {
+		_ = db.DropCollection(ctx, "test_view_active_users")
+		_ = users.Drop(ctx)
+	}()
+
+	_, err := users.InsertMany(ctx, []any{
+		bson.M{"name": "Ada", "active": true},
+		bson.M{"name": "Grace", "active": false},
+	})
+	if err != nil {
+		t.Fatalf("InsertMany failed: %v", err)
+	}
+
+	p := pipeline.New().Match(filter.Eq("active", true))
+	if err := db.CreateView(ctx, "test_view_active_users", "test_view_users", p); err != nil {
+		t.Fatalf("CreateView failed: %v", err)
+	}
+
+	count, err := db.Collection("test_view_active_users").CountDocuments(ctx, filter.Eq("name", "Ada"))
+	if err != nil {
+		t.Fatalf("CountDocuments failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected the view to expose Ada, got %d documents", count)
+	}
+
+	infos, err := db.ListCollectionsInfo(ctx, filter.Eq("name", "test_view_active_users"))
+	if err != nil {
+		t.Fatalf("ListCollectionsInfo failed: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Type != CollectionTypeView || infos[0].Options.ViewOn != "test_view_users" {
+		t.Errorf("Expected a view on test_view_users, got %+v", infos)
+	}
+}